@@ -0,0 +1,105 @@
+package db
+
+import (
+	"database/sql"
+	"event-booking/models"
+	"strings"
+	"time"
+)
+
+const selectAPIKeys = `
+	SELECT id, user_id, name, prefix, secret_hash, scopes, last_used_at, expires_at, created_at
+	FROM api_keys
+`
+
+func scanAPIKey(row rowScanner) (models.APIKey, error) {
+	var k models.APIKey
+	var scopes string
+	var lastUsedAt, expiresAt sql.NullTime
+	err := row.Scan(&k.Id, &k.UserId, &k.Name, &k.Prefix, &k.SecretHash, &scopes, &lastUsedAt, &expiresAt, &k.CreatedAt)
+	if err != nil {
+		return k, err
+	}
+
+	k.Scopes = strings.Fields(scopes)
+	if lastUsedAt.Valid {
+		k.LastUsedAt = &lastUsedAt.Time
+	}
+	if expiresAt.Valid {
+		k.ExpiresAt = &expiresAt.Time
+	}
+	return k, nil
+}
+
+type SqlAPIKeyRepository struct {
+	db *sql.DB
+}
+
+func NewSqlAPIKeyRepository(database *sql.DB) *SqlAPIKeyRepository {
+	return &SqlAPIKeyRepository{
+		db: database,
+	}
+}
+
+func (r *SqlAPIKeyRepository) CreateAPIKey(k *models.APIKey) error {
+	query := `
+	INSERT INTO api_keys (user_id, name, prefix, secret_hash, scopes, expires_at, created_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?);
+	`
+	result, err := r.db.Exec(query, k.UserId, k.Name, k.Prefix, k.SecretHash, strings.Join(k.Scopes, " "), k.ExpiresAt, k.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	k.Id, err = result.LastInsertId()
+	return err
+}
+
+func (r *SqlAPIKeyRepository) GetAPIKeys(userId int64) ([]models.APIKey, error) {
+	rows, err := r.db.Query(selectAPIKeys+`WHERE user_id = ? ORDER BY id;`, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+
+	return keys, nil
+}
+
+func (r *SqlAPIKeyRepository) GetAPIKeyById(userId, id int64) (models.APIKey, error) {
+	return scanAPIKey(r.db.QueryRow(selectAPIKeys+`WHERE id = ? AND user_id = ?;`, id, userId))
+}
+
+func (r *SqlAPIKeyRepository) GetAPIKeyByPrefix(prefix string) (models.APIKey, error) {
+	return scanAPIKey(r.db.QueryRow(selectAPIKeys+`WHERE prefix = ?;`, prefix))
+}
+
+func (r *SqlAPIKeyRepository) UpdateAPIKey(k *models.APIKey) error {
+	query := `UPDATE api_keys SET name = ?, scopes = ? WHERE id = ? AND user_id = ?;`
+	_, err := r.db.Exec(query, k.Name, strings.Join(k.Scopes, " "), k.Id, k.UserId)
+	return err
+}
+
+// DeleteAPIKey reports false when the user has no key with that id.
+func (r *SqlAPIKeyRepository) DeleteAPIKey(userId, id int64) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM api_keys WHERE id = ? AND user_id = ?;`, id, userId)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func (r *SqlAPIKeyRepository) TouchAPIKey(id int64, usedAt time.Time) error {
+	_, err := r.db.Exec(`UPDATE api_keys SET last_used_at = ? WHERE id = ?;`, usedAt, id)
+	return err
+}
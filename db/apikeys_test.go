@@ -0,0 +1,75 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"event-booking/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAPIKey_RoundTrip(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlAPIKeyRepository(testDB)
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(24 * time.Hour)
+
+	key := models.APIKey{
+		UserId:     1,
+		Name:       "ci",
+		Prefix:     "0123456789ab",
+		SecretHash: "hash",
+		Scopes:     []string{"events:read", "events:write"},
+		ExpiresAt:  &expiresAt,
+		CreatedAt:  now,
+	}
+	require.NoError(t, repo.CreateAPIKey(&key))
+
+	stored, err := repo.GetAPIKeyByPrefix("0123456789ab")
+
+	require.NoError(t, err)
+	assert.Equal(t, key.Id, stored.Id)
+	assert.Equal(t, []string{"events:read", "events:write"}, stored.Scopes)
+	assert.True(t, stored.ExpiresAt.Equal(expiresAt))
+	assert.Nil(t, stored.LastUsedAt)
+
+	require.NoError(t, repo.TouchAPIKey(key.Id, now))
+	stored, err = repo.GetAPIKeyById(1, key.Id)
+	require.NoError(t, err)
+	require.NotNil(t, stored.LastUsedAt)
+	assert.True(t, stored.LastUsedAt.Equal(now))
+}
+
+func TestAPIKeys_ScopedToUser(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlAPIKeyRepository(testDB)
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	key := models.APIKey{UserId: 1, Name: "ci", Prefix: "0123456789ab", SecretHash: "hash", Scopes: []string{"events:read"}, CreatedAt: now}
+	require.NoError(t, repo.CreateAPIKey(&key))
+
+	_, err := repo.GetAPIKeyById(2, key.Id)
+	assert.Error(t, err)
+
+	keys, err := repo.GetAPIKeys(2)
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	deleted, err := repo.DeleteAPIKey(2, key.Id)
+	require.NoError(t, err)
+	assert.False(t, deleted)
+
+	key.Name = "renamed"
+	key.Scopes = []string{"users:read"}
+	require.NoError(t, repo.UpdateAPIKey(&key))
+
+	deleted, err = repo.DeleteAPIKey(1, key.Id)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+}
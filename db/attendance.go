@@ -0,0 +1,101 @@
+package db
+
+import (
+	"event-booking/models"
+	"time"
+)
+
+// selectAttendance counts the approved registrations of events e and how
+// many of them were checked in.
+const selectAttendance = `
+	SELECT e.id, e.name, e.datetime, COUNT(r.id), COUNT(r.checked_in_at)
+	FROM events e
+	LEFT JOIN registrations r ON r.event_id = e.id AND r.status = 'approved'
+`
+
+func scanAttendance(row rowScanner) (models.EventAttendance, error) {
+	var a models.EventAttendance
+	err := row.Scan(&a.EventId, &a.Name, &a.DateTime, &a.Registered, &a.CheckedIn)
+	return a, err
+}
+
+func (r *SqlEventRepository) GetAttendance(orgId, eventId int64) (models.EventAttendance, error) {
+	query := selectAttendance + `WHERE e.id = ? AND e.organization_id = ? GROUP BY e.id;`
+	return scanAttendance(r.db.QueryRow(query, eventId, orgId))
+}
+
+// GetPastAttendance returns the attendance of the organization's events
+// that started before before, most recent first.
+func (r *SqlEventRepository) GetPastAttendance(orgId int64, before time.Time) ([]models.EventAttendance, error) {
+	query := selectAttendance + `
+	WHERE e.organization_id = ? AND e.datetime < ?
+	GROUP BY e.id
+	ORDER BY e.datetime DESC, e.id DESC;
+	`
+	rows, err := r.db.Query(query, orgId, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attendance := []models.EventAttendance{}
+	for rows.Next() {
+		a, err := scanAttendance(rows)
+		if err != nil {
+			return nil, err
+		}
+		attendance = append(attendance, a)
+	}
+	return attendance, nil
+}
+
+// GetAttendeeAttendance counts the approved registrations and no-shows of
+// each user for the organization's events that started before before. Only
+// events where at least one attendee was checked in count, so events that
+// did not use check-in do not turn everyone into a no-show. Guests are left
+// out. Users with the most no-shows come first.
+func (r *SqlEventRepository) GetAttendeeAttendance(orgId int64, before time.Time) ([]models.AttendeeAttendance, error) {
+	query := `
+	SELECT r.user_id, COALESCE(u.email, ''), COUNT(*), COUNT(*) - COUNT(r.checked_in_at) AS no_shows
+	FROM registrations r
+	JOIN events e ON e.id = r.event_id
+	LEFT JOIN users u ON u.id = r.user_id
+	WHERE r.organization_id = ? AND r.status = 'approved' AND e.datetime < ? AND r.user_id IS NOT NULL
+		AND EXISTS (SELECT 1 FROM registrations c WHERE c.event_id = r.event_id AND c.checked_in_at IS NOT NULL)
+	GROUP BY r.user_id
+	ORDER BY no_shows DESC, r.user_id;
+	`
+	rows, err := r.db.Query(query, orgId, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attendees := []models.AttendeeAttendance{}
+	for rows.Next() {
+		var a models.AttendeeAttendance
+		err := rows.Scan(&a.UserId, &a.Email, &a.Events, &a.NoShows)
+		if err != nil {
+			return nil, err
+		}
+		a.NoShowRate = float64(a.NoShows) / float64(a.Events)
+		attendees = append(attendees, a)
+	}
+	return attendees, nil
+}
+
+func (r *SqlOrganizationRepository) GetPastAttendance(orgId int64, before time.Time) ([]models.EventAttendance, error) {
+	return r.eventRepo.GetPastAttendance(orgId, before)
+}
+
+func (r *SqlOrganizationRepository) GetAttendeeAttendance(orgId int64, before time.Time) ([]models.AttendeeAttendance, error) {
+	return r.eventRepo.GetAttendeeAttendance(orgId, before)
+}
+
+func (r *SqlEventRegisterRepository) GetOrganization(id int64) (models.Organization, error) {
+	return r.orgRepo.GetOrganization(id)
+}
+
+func (r *SqlEventRegisterRepository) GetAttendeeAttendance(orgId int64, before time.Time) ([]models.AttendeeAttendance, error) {
+	return r.eventRepo.GetAttendeeAttendance(orgId, before)
+}
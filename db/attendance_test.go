@@ -0,0 +1,133 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"event-booking/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestPastEvent(t *testing.T, repo *SqlEventRepository, orgId int64, name string, start time.Time) int64 {
+	id, err := repo.CreateEvent(&models.Event{
+		Name: name, Description: "Description", Location: "Location",
+		DateTime: start, UserId: 1, OrganizationId: orgId, Visibility: "public",
+	})
+	require.NoError(t, err)
+	return id
+}
+
+// registerTestAttendee registers userId for the event and checks them in
+// when attended.
+func registerTestAttendee(t *testing.T, repo *SqlEventRegisterRepository, orgId, userId, eventId int64, attended bool) {
+	registration := newTestRegistration(orgId, userId, eventId)
+	registered, err := repo.RegisterEvent(registration)
+	require.NoError(t, err)
+	require.True(t, registered)
+	if attended {
+		checkedIn, err := repo.CheckIn(orgId, eventId, registration.Id, userId, time.Now())
+		require.NoError(t, err)
+		require.True(t, checkedIn)
+	}
+}
+
+func TestGetAttendance(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	eventId := createTestOrgEvent(t, eventRepo, orgId, "Conference")
+	registerTestAttendee(t, registerRepo, orgId, 5, eventId, true)
+	registerTestAttendee(t, registerRepo, orgId, 6, eventId, false)
+	pending := newTestRegistration(orgId, 7, eventId)
+	pending.Status = "pending"
+	_, err := registerRepo.RegisterEvent(pending)
+	require.NoError(t, err)
+
+	attendance, err := eventRepo.GetAttendance(orgId, eventId)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Conference", attendance.Name)
+	assert.Equal(t, int64(2), attendance.Registered, "Only approved registrations count")
+	assert.Equal(t, int64(1), attendance.CheckedIn)
+
+	_, err = eventRepo.GetAttendance(orgId+1, eventId)
+	assert.Error(t, err, "Events of other organizations are not visible")
+}
+
+func TestGetAttendeeAttendance_CountsEventsWithCheckIn(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+	userRepo := NewSqlUserRepository(testDB)
+
+	userId, err := userRepo.CreateUser(&models.User{Email: "flaky@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	now := time.Now()
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	otherOrgId := createTestOrganization(t, orgRepo, "Other", 1)
+	first := createTestPastEvent(t, eventRepo, orgId, "First", now.Add(-96*time.Hour))
+	second := createTestPastEvent(t, eventRepo, orgId, "Second", now.Add(-72*time.Hour))
+	withoutCheckIn := createTestPastEvent(t, eventRepo, orgId, "No check-in", now.Add(-60*time.Hour))
+	upcoming := createTestOrgEvent(t, eventRepo, orgId, "Upcoming")
+	elsewhere := createTestPastEvent(t, eventRepo, otherOrgId, "Elsewhere", now.Add(-72*time.Hour))
+
+	registerTestAttendee(t, registerRepo, orgId, userId, first, false)
+	registerTestAttendee(t, registerRepo, orgId, 9, first, true)
+	registerTestAttendee(t, registerRepo, orgId, userId, second, true)
+	registerTestAttendee(t, registerRepo, orgId, 9, second, true)
+	registerTestAttendee(t, registerRepo, orgId, userId, withoutCheckIn, false)
+	registerTestAttendee(t, registerRepo, orgId, userId, upcoming, false)
+	registerTestAttendee(t, registerRepo, otherOrgId, userId, elsewhere, false)
+	registerTestAttendee(t, registerRepo, otherOrgId, 9, elsewhere, true)
+
+	attendees, err := eventRepo.GetAttendeeAttendance(orgId, now.Add(-24*time.Hour))
+
+	require.NoError(t, err)
+	require.Len(t, attendees, 2)
+	assert.Equal(t, models.AttendeeAttendance{
+		UserId: userId, Email: "flaky@example.com", Events: 2, NoShows: 1, NoShowRate: 0.5,
+	}, attendees[0], "Users with the most no-shows come first")
+	assert.Equal(t, int64(9), attendees[1].UserId)
+	assert.Equal(t, int64(0), attendees[1].NoShows)
+
+	events, err := orgRepo.GetPastAttendance(orgId, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, withoutCheckIn, events[0].EventId, "Most recent events come first")
+	assert.Equal(t, first, events[2].EventId)
+	assert.Equal(t, int64(2), events[2].Registered)
+	assert.Equal(t, int64(1), events[2].CheckedIn)
+}
+
+func TestUpdateOrganization_AttendanceSettings(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlOrganizationRepository(testDB)
+	orgId := createTestOrganization(t, repo, "Acme", 1)
+
+	org, err := repo.GetOrganization(orgId)
+	require.NoError(t, err)
+	assert.False(t, org.DeprioritizeNoShows)
+
+	org.DeprioritizeNoShows = true
+	org.NoShowThreshold = 3
+	require.NoError(t, repo.UpdateOrganization(&org))
+
+	membership, err := repo.GetMembership(orgId, 1)
+	require.NoError(t, err)
+	assert.True(t, membership.DeprioritizeNoShows)
+	assert.Equal(t, int64(3), membership.NoShowThreshold)
+	assert.Equal(t, "Acme", membership.Name)
+}
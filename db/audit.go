@@ -0,0 +1,54 @@
+package db
+
+import (
+	"database/sql"
+	"event-booking/models"
+)
+
+type SqlAuditRepository struct {
+	db *sql.DB
+}
+
+func NewSqlAuditRepository(database *sql.DB) *SqlAuditRepository {
+	return &SqlAuditRepository{
+		db: database,
+	}
+}
+
+func (r *SqlAuditRepository) CreateAuditEvent(e *models.AuditEvent) error {
+	query := `
+	INSERT INTO audit_events (action, user_id, subject, ip, details, created_at)
+	VALUES (?, NULLIF(?, 0), ?, ?, ?, ?);
+	`
+	result, err := r.db.Exec(query, e.Action, e.UserId, e.Subject, e.IP, e.Details, e.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	e.Id, err = result.LastInsertId()
+	return err
+}
+
+func (r *SqlAuditRepository) GetAuditEvents(action string) ([]models.AuditEvent, error) {
+	query := `
+	SELECT id, action, COALESCE(user_id, 0), subject, ip, details, created_at
+	FROM audit_events WHERE action = ? ORDER BY id;
+	`
+	rows, err := r.db.Query(query, action)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []models.AuditEvent{}
+	for rows.Next() {
+		var e models.AuditEvent
+		err = rows.Scan(&e.Id, &e.Action, &e.UserId, &e.Subject, &e.IP, &e.Details, &e.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+
+	return events, nil
+}
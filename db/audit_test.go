@@ -0,0 +1,30 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"event-booking/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAuditEvent(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlAuditRepository(testDB)
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	event := models.AuditEvent{Action: "login.lockout", Subject: "account:test@example.com", IP: "127.0.0.1", CreatedAt: now}
+	require.NoError(t, repo.CreateAuditEvent(&event))
+
+	events, err := repo.GetAuditEvents("login.lockout")
+
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, event.Id, events[0].Id)
+	assert.Equal(t, "account:test@example.com", events[0].Subject)
+	assert.Equal(t, int64(0), events[0].UserId)
+}
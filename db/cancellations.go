@@ -0,0 +1,193 @@
+package db
+
+import (
+	"database/sql"
+	"event-booking/models"
+)
+
+// GetCancellationPolicy returns the cancellation policy of the event. Events
+// without a policy get the default one, which refunds in full until the
+// event starts.
+func (r *SqlEventRepository) GetCancellationPolicy(orgId, eventId int64) (models.CancellationPolicy, error) {
+	policy := models.CancellationPolicy{EventId: eventId, OrganizationId: orgId}
+	query := `
+	SELECT full_refund_days, partial_refund_percent FROM cancellation_policies
+	WHERE event_id = ? AND organization_id = ?;
+	`
+	err := r.db.QueryRow(query, eventId, orgId).Scan(&policy.FullRefundDays, &policy.PartialRefundPercent)
+	if err == sql.ErrNoRows {
+		return policy, nil
+	}
+	return policy, err
+}
+
+func (r *SqlEventRepository) SetCancellationPolicy(p *models.CancellationPolicy) error {
+	query := `
+	INSERT INTO cancellation_policies (event_id, organization_id, full_refund_days, partial_refund_percent)
+	VALUES (?, ?, ?, ?)
+	ON CONFLICT(event_id) DO UPDATE SET
+		full_refund_days = excluded.full_refund_days, partial_refund_percent = excluded.partial_refund_percent;
+	`
+	_, err := r.db.Exec(query, p.EventId, p.OrganizationId, p.FullRefundDays, p.PartialRefundPercent)
+	return err
+}
+
+const selectCancellations = `
+	SELECT id, registration_id, user_id, event_id, organization_id, order_id, refund_amount, currency,
+		refund_status, cancelled_at
+	FROM cancellations
+`
+
+func scanCancellation(row rowScanner) (models.Cancellation, error) {
+	var c models.Cancellation
+	var orderId sql.NullInt64
+	err := row.Scan(&c.Id, &c.RegistrationId, &c.UserId, &c.EventId, &c.OrganizationId, &orderId, &c.RefundAmount,
+		&c.Currency, &c.RefundStatus, &c.CancelledAt)
+	c.OrderId = orderId.Int64
+	return c, err
+}
+
+// GetCancellations lists the cancellations of an event, most recent first.
+func (r *SqlEventRepository) GetCancellations(orgId, eventId int64) ([]models.Cancellation, error) {
+	query := selectCancellations + `WHERE event_id = ? AND organization_id = ? ORDER BY cancelled_at DESC, id DESC;`
+	rows, err := r.db.Query(query, eventId, orgId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cancellations := []models.Cancellation{}
+	for rows.Next() {
+		c, err := scanCancellation(rows)
+		if err != nil {
+			return nil, err
+		}
+		cancellations = append(cancellations, c)
+	}
+
+	return cancellations, nil
+}
+
+func (r *SqlEventRegisterRepository) GetCancellations(orgId, eventId int64) ([]models.Cancellation, error) {
+	return r.eventRepo.GetCancellations(orgId, eventId)
+}
+
+// RetryRefund marks the failed refund of a cancellation as pending again
+// before it is retried, so that only one request retries it. It returns
+// false when the refund has not failed.
+func (r *SqlEventRegisterRepository) RetryRefund(orgId, id int64) (bool, error) {
+	query := `UPDATE cancellations SET refund_status = 'pending' WHERE id = ? AND organization_id = ? AND refund_status = 'failed';`
+	result, err := r.db.Exec(query, id, orgId)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func (r *SqlEventRegisterRepository) GetCancellationPolicy(orgId, eventId int64) (models.CancellationPolicy, error) {
+	return r.eventRepo.GetCancellationPolicy(orgId, eventId)
+}
+
+// GetPaidOrder returns the paid order of a registration, which is the order
+// of its group for registrations booked in a paid group.
+func (r *SqlEventRegisterRepository) GetPaidOrder(orgId, registrationId int64) (models.Order, error) {
+	query := selectOrders + `
+		WHERE organization_id = ? AND status = 'paid'
+			AND (registration_id = ?2 OR group_id = (SELECT group_id FROM registrations WHERE id = ?2));
+	`
+	return scanOrder(r.db.QueryRow(query, orgId, registrationId))
+}
+
+// RecordCancellation cancels the registration like CancelRegistration and
+// stores the cancellation in the same transaction. It returns false and
+// changes nothing when the registration was not active anymore.
+func (r *SqlEventRegisterRepository) RecordCancellation(c *models.Cancellation) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	cancelled, err := cancelRegistration(tx, c.OrganizationId, c.RegistrationId)
+	if err != nil || !cancelled {
+		return false, err
+	}
+
+	err = insertCancellation(tx, c)
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// RecordRefund keeps the refund of a registration that ended without being
+// cancelled, such as a rejected one, next to the cancellations, so that its
+// outcome can be stored with SetRefundStatus.
+func (r *SqlEventRegisterRepository) RecordRefund(c *models.Cancellation) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = insertCancellation(tx, c)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func insertCancellation(tx *sql.Tx, c *models.Cancellation) error {
+	var orderId sql.NullInt64
+	if c.OrderId != 0 {
+		orderId = sql.NullInt64{Int64: c.OrderId, Valid: true}
+	}
+	query := `
+		INSERT INTO cancellations (registration_id, user_id, event_id, organization_id, order_id, refund_amount,
+			currency, refund_status, cancelled_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
+	`
+	result, err := tx.Exec(query, c.RegistrationId, c.UserId, c.EventId, c.OrganizationId, orderId, c.RefundAmount,
+		c.Currency, c.RefundStatus, c.CancelledAt)
+	if err != nil {
+		return err
+	}
+
+	c.Id, err = result.LastInsertId()
+	return err
+}
+
+// SetRefundStatus stores the outcome of a cancellation's refund. A
+// successful refund is also added to the refunded amount of the order,
+// which is then refunded. Group orders stay paid for their other seats
+// until all of their amount was refunded.
+func (r *SqlEventRegisterRepository) SetRefundStatus(c models.Cancellation) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE cancellations SET refund_status = ? WHERE id = ? AND organization_id = ?;`
+	_, err = tx.Exec(query, c.RefundStatus, c.Id, c.OrganizationId)
+	if err != nil {
+		return err
+	}
+
+	if c.RefundStatus == "refunded" {
+		query := `
+			UPDATE orders SET refunded_amount = refunded_amount + ?1, updated_at = CURRENT_TIMESTAMP,
+				status = CASE WHEN group_id IS NULL OR refunded_amount + ?1 >= amount THEN 'refunded' ELSE status END
+			WHERE id = ?2 AND organization_id = ?3 AND status = 'paid';
+		`
+		_, err = tx.Exec(query, c.RefundAmount, c.OrderId, c.OrganizationId)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,137 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"event-booking/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCancellationPolicy_DefaultAndSet(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	eventId := createTestOrgEvent(t, eventRepo, orgId, "Conference")
+
+	policy, err := eventRepo.GetCancellationPolicy(orgId, eventId)
+	require.NoError(t, err)
+	assert.Equal(t, models.CancellationPolicy{EventId: eventId, OrganizationId: orgId}, policy)
+
+	for _, days := range []int64{14, 7} {
+		err = eventRepo.SetCancellationPolicy(&models.CancellationPolicy{
+			EventId: eventId, OrganizationId: orgId, FullRefundDays: days, PartialRefundPercent: 50,
+		})
+		require.NoError(t, err)
+	}
+
+	policy, err = eventRepo.GetCancellationPolicy(orgId, eventId)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), policy.FullRefundDays, "Setting the policy again replaces it")
+	assert.Equal(t, int64(50), policy.PartialRefundPercent)
+}
+
+func TestRecordCancellation_RefundsOrder(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+	orderRepo := NewSqlOrderRepository(testDB)
+
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	eventId := createTestOrgEvent(t, eventRepo, orgId, "Conference")
+	registration := newTestRegistration(orgId, 5, eventId)
+	_, err := registerRepo.RegisterEvent(registration)
+	require.NoError(t, err)
+
+	order := newTestOrder(registration, "pi_fake_1")
+	order.Status = "paid"
+	require.NoError(t, orderRepo.CreateOrder(order))
+
+	paid, err := registerRepo.GetPaidOrder(orgId, registration.Id)
+	require.NoError(t, err)
+	assert.Equal(t, order.Id, paid.Id)
+
+	cancellation := &models.Cancellation{
+		RegistrationId: registration.Id, UserId: 5, EventId: eventId, OrganizationId: orgId, OrderId: order.Id,
+		RefundAmount: 1250, Currency: "EUR", RefundStatus: "pending", CancelledAt: time.Now(),
+	}
+	cancelled, err := registerRepo.RecordCancellation(cancellation)
+	require.NoError(t, err)
+	assert.True(t, cancelled)
+
+	cancelled, err = registerRepo.RecordCancellation(&models.Cancellation{
+		RegistrationId: registration.Id, UserId: 5, EventId: eventId, OrganizationId: orgId, RefundStatus: "none",
+		CancelledAt: time.Now(),
+	})
+	require.NoError(t, err)
+	assert.False(t, cancelled, "A registration is only cancelled once")
+
+	_, err = registerRepo.GetRegisteredEventById(orgId, 5, eventId)
+	assert.Error(t, err, "Cancelled registrations are not active")
+
+	cancellation.RefundStatus = "refunded"
+	require.NoError(t, registerRepo.SetRefundStatus(*cancellation))
+
+	refunded, err := orderRepo.GetOrder(orgId, order.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "refunded", refunded.Status)
+	assert.Equal(t, int64(1250), refunded.RefundedAmount)
+
+	cancellations, err := eventRepo.GetCancellations(orgId, eventId)
+	require.NoError(t, err)
+	require.Len(t, cancellations, 1)
+	assert.Equal(t, "refunded", cancellations[0].RefundStatus)
+	assert.Equal(t, order.Id, cancellations[0].OrderId)
+	assert.Equal(t, int64(1250), cancellations[0].RefundAmount)
+}
+
+func TestRecordRefund_RejectedRegistration(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+	orderRepo := NewSqlOrderRepository(testDB)
+
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	eventId := createTestOrgEvent(t, eventRepo, orgId, "Conference")
+	registration := newTestRegistration(orgId, 5, eventId)
+	registration.Status = "pending"
+	_, err := registerRepo.RegisterEvent(registration)
+	require.NoError(t, err)
+
+	order := newTestOrder(registration, "pi_fake_1")
+	order.Status = "paid"
+	require.NoError(t, orderRepo.CreateOrder(order))
+
+	updated, err := registerRepo.SetRegistrationStatus(orgId, eventId, []int64{registration.Id}, "rejected")
+	require.NoError(t, err)
+	require.True(t, updated)
+
+	refund := &models.Cancellation{
+		RegistrationId: registration.Id, UserId: 5, EventId: eventId, OrganizationId: orgId, OrderId: order.Id,
+		RefundAmount: order.Amount, Currency: "EUR", RefundStatus: "pending", CancelledAt: time.Now(),
+	}
+	require.NoError(t, registerRepo.RecordRefund(refund))
+	refund.RefundStatus = "refunded"
+	require.NoError(t, registerRepo.SetRefundStatus(*refund))
+
+	refunded, err := orderRepo.GetOrder(orgId, order.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "refunded", refunded.Status)
+	assert.Equal(t, order.Amount, refunded.RefundedAmount)
+
+	rejected, err := registerRepo.GetRegistration(orgId, registration.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "rejected", rejected.Status, "Recording the refund does not cancel the registration")
+}
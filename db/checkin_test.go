@@ -0,0 +1,50 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckIn_OnlyOnce(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	eventId := createTestOrgEvent(t, eventRepo, orgId, "Conference")
+	otherEventId := createTestOrgEvent(t, eventRepo, orgId, "Meetup")
+	registration := newTestRegistration(orgId, 5, eventId)
+	_, err := registerRepo.RegisterEvent(registration)
+	require.NoError(t, err)
+
+	checkedIn, err := registerRepo.CheckIn(orgId, otherEventId, registration.Id, 5, time.Now())
+	require.NoError(t, err)
+	assert.False(t, checkedIn, "Registrations are only checked in at their event")
+
+	checkedIn, err = registerRepo.CheckIn(orgId, eventId, registration.Id, 6, time.Now())
+	require.NoError(t, err)
+	assert.False(t, checkedIn, "Tickets of another user are not valid")
+
+	at := time.Now().UTC().Truncate(time.Second)
+	checkedIn, err = registerRepo.CheckIn(orgId, eventId, registration.Id, 5, at)
+	require.NoError(t, err)
+	assert.True(t, checkedIn)
+
+	checkedIn, err = registerRepo.CheckIn(orgId, eventId, registration.Id, 5, time.Now())
+	require.NoError(t, err)
+	assert.False(t, checkedIn, "Replayed tickets are rejected")
+
+	saved, err := registerRepo.GetRegistration(orgId, registration.Id)
+	require.NoError(t, err)
+	require.NotNil(t, saved.CheckedInAt)
+	assert.True(t, at.Equal(*saved.CheckedInAt), "The first check-in is kept")
+
+	_, err = registerRepo.GetRegistration(orgId+1, registration.Id)
+	assert.Error(t, err, "Registrations of other organizations are not visible")
+}
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"event-booking/models"
+)
+
+const selectEventMembers = `
+	SELECT em.event_id, em.user_id, em.organization_id, u.email, em.role, em.created_at
+	FROM event_members em
+	JOIN users u ON u.id = em.user_id
+`
+
+func scanEventMember(row rowScanner) (models.EventMember, error) {
+	var m models.EventMember
+	err := row.Scan(&m.EventId, &m.UserId, &m.OrganizationId, &m.Email, &m.Role, &m.CreatedAt)
+	return m, err
+}
+
+func (r *SqlEventRepository) GetEventMembers(orgId, eventId int64) ([]models.EventMember, error) {
+	query := selectEventMembers + `WHERE em.event_id = ? AND em.organization_id = ? ORDER BY em.created_at, em.user_id;`
+	rows, err := r.db.Query(query, eventId, orgId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []models.EventMember{}
+	for rows.Next() {
+		m, err := scanEventMember(rows)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+
+	return members, nil
+}
+
+func (r *SqlEventRepository) GetEventMember(orgId, eventId, userId int64) (models.EventMember, error) {
+	query := selectEventMembers + `WHERE em.event_id = ? AND em.user_id = ? AND em.organization_id = ?;`
+	return scanEventMember(r.db.QueryRow(query, eventId, userId, orgId))
+}
+
+// GetOrganizationMemberByEmail finds the member of organization orgId with
+// email. Users outside the organization are not found.
+func (r *SqlEventRepository) GetOrganizationMemberByEmail(orgId int64, email string) (models.OrganizationMember, error) {
+	query := selectMembers + `WHERE m.organization_id = ? AND u.email = ? COLLATE NOCASE AND u.deleted_at IS NULL;`
+	return scanMember(r.db.QueryRow(query, orgId, email))
+}
+
+func (r *SqlEventRepository) AddEventMember(m *models.EventMember) error {
+	query := `
+	INSERT INTO event_members (event_id, user_id, organization_id, role, created_at)
+	VALUES (?, ?, ?, ?, ?);
+	`
+	_, err := r.db.Exec(query, m.EventId, m.UserId, m.OrganizationId, m.Role, m.CreatedAt)
+	return err
+}
+
+func (r *SqlEventRepository) RemoveEventMember(orgId, eventId, userId int64) (bool, error) {
+	query := `DELETE FROM event_members WHERE event_id = ? AND user_id = ? AND organization_id = ?;`
+	result, err := r.db.Exec(query, eventId, userId, orgId)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
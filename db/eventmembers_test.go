@@ -0,0 +1,86 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"event-booking/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventMembers(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	userRepo := NewSqlUserRepository(testDB)
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	repo := NewSqlEventRepository(testDB)
+
+	ownerId, _ := userRepo.CreateUser(&models.User{Email: "owner@example.com", Password: "password123"})
+	staffId, _ := userRepo.CreateUser(&models.User{Email: "staff@example.com", Password: "password123"})
+	orgId := createTestOrganization(t, orgRepo, "Acme", ownerId)
+	eventId := createTestOrgEvent(t, repo, orgId, "Event")
+
+	err := repo.AddEventMember(&models.EventMember{EventId: eventId, UserId: staffId, OrganizationId: orgId, Role: "checkin_staff", CreatedAt: time.Now()})
+	require.NoError(t, err)
+
+	member, err := repo.GetEventMember(orgId, eventId, staffId)
+	require.NoError(t, err)
+	assert.Equal(t, "checkin_staff", member.Role)
+	assert.Equal(t, "staff@example.com", member.Email)
+
+	members, err := repo.GetEventMembers(orgId, eventId)
+	require.NoError(t, err)
+	assert.Len(t, members, 1)
+
+	removed, err := repo.RemoveEventMember(orgId, eventId, staffId)
+	require.NoError(t, err)
+	assert.True(t, removed)
+
+	_, err = repo.GetEventMember(orgId, eventId, staffId)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestGetOrganizationMemberByEmail_OnlyMembers(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	userRepo := NewSqlUserRepository(testDB)
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	repo := NewSqlEventRepository(testDB)
+
+	ownerId, _ := userRepo.CreateUser(&models.User{Email: "owner@example.com", Password: "password123"})
+	strangerId, _ := userRepo.CreateUser(&models.User{Email: "stranger@example.com", Password: "password123"})
+	orgA := createTestOrganization(t, orgRepo, "Team A", ownerId)
+	createTestOrganization(t, orgRepo, "Team B", strangerId)
+
+	member, err := repo.GetOrganizationMemberByEmail(orgA, "owner@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, ownerId, member.UserId)
+
+	_, err = repo.GetOrganizationMemberByEmail(orgA, "stranger@example.com")
+	assert.ErrorIs(t, err, sql.ErrNoRows, "Members of other organizations must not be found")
+}
+
+func TestDeleteEvent_RemovesMembers(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	repo := NewSqlEventRepository(testDB)
+
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	eventId := createTestOrgEvent(t, repo, orgId, "Event")
+	require.NoError(t, repo.AddEventMember(&models.EventMember{EventId: eventId, UserId: 2, OrganizationId: orgId, Role: "co_organizer", CreatedAt: time.Now()}))
+
+	deleted, err := repo.DeleteEvent(orgId, eventId)
+	require.NoError(t, err)
+	require.True(t, deleted)
+
+	var count int
+	require.NoError(t, testDB.QueryRow(`SELECT COUNT(*) FROM event_members WHERE event_id = ?;`, eventId).Scan(&count))
+	assert.Zero(t, count)
+}
@@ -2,7 +2,6 @@ package db
 
 import (
 	"database/sql"
-	"errors"
 	"event-booking/models"
 )
 
@@ -10,7 +9,31 @@ type SqlEventRepository struct {
 	db *sql.DB
 }
 
-var ErrEventNotFound = errors.New("event could not be found")
+const selectEvents = `
+	SELECT e.id, e.name, e.description, e.location, e.datetime, e.user_id, e.organization_id, e.visibility,
+		e.capacity, e.requires_approval
+	FROM events e
+`
+
+// eventAccessCondition matches events userId has access to beyond their
+// visibility: as the creator, as an event member or as an invitee. It takes
+// the user id four times.
+const eventAccessCondition = `(
+	e.user_id = ?
+	OR EXISTS (SELECT 1 FROM event_members em WHERE em.event_id = e.id AND em.user_id = ?)
+	OR EXISTS (
+		SELECT 1 FROM event_invitations i
+		WHERE i.event_id = e.id
+			AND (i.user_id = ? OR (i.email != '' AND LOWER(i.email) = (SELECT LOWER(email) FROM users WHERE id = ?)))
+	)
+)`
+
+func scanEvent(row rowScanner) (models.Event, error) {
+	var e models.Event
+	err := row.Scan(&e.Id, &e.Name, &e.Description, &e.Location, &e.DateTime, &e.UserId, &e.OrganizationId, &e.Visibility,
+		&e.Capacity, &e.RequiresApproval)
+	return e, err
+}
 
 func NewSqlEventRepository(database *sql.DB) *SqlEventRepository {
 	return &SqlEventRepository{
@@ -20,10 +43,11 @@ func NewSqlEventRepository(database *sql.DB) *SqlEventRepository {
 
 func (r *SqlEventRepository) CreateEvent(e *models.Event) (int64, error) {
 	query := `
-	INSERT INTO events (name, description, location, datetime, user_id)
-	VALUES (?, ?, ?, ?, ?);
+	INSERT INTO events (name, description, location, datetime, user_id, organization_id, visibility, capacity, requires_approval)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
 	`
-	result, err := r.db.Exec(query, e.Name, e.Description, e.Location, e.DateTime, e.UserId)
+	result, err := r.db.Exec(query, e.Name, e.Description, e.Location, e.DateTime, e.UserId, e.OrganizationId, e.Visibility,
+		e.Capacity, e.RequiresApproval)
 
 	if err != nil {
 		return 0, err
@@ -32,9 +56,11 @@ func (r *SqlEventRepository) CreateEvent(e *models.Event) (int64, error) {
 	return result.LastInsertId()
 }
 
-func (r *SqlEventRepository) GetEvents() ([]models.Event, error) {
-	query := `SELECT * FROM events;`
-	rows, err := r.db.Query(query)
+// GetEvents returns the events of organization orgId listed for userId:
+// public events and the unlisted and private events the user has access to.
+func (r *SqlEventRepository) GetEvents(orgId, userId int64) ([]models.Event, error) {
+	query := selectEvents + `WHERE e.organization_id = ? AND (e.visibility = 'public' OR ` + eventAccessCondition + `) ORDER BY e.id;`
+	rows, err := r.db.Query(query, orgId, userId, userId, userId, userId)
 	if err != nil {
 		return nil, err
 	}
@@ -42,8 +68,7 @@ func (r *SqlEventRepository) GetEvents() ([]models.Event, error) {
 
 	events := []models.Event{}
 	for rows.Next() {
-		var e models.Event
-		err = rows.Scan(&e.Id, &e.Name, &e.Description, &e.Location, &e.DateTime, &e.UserId)
+		e, err := scanEvent(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -53,29 +78,109 @@ func (r *SqlEventRepository) GetEvents() ([]models.Event, error) {
 	return events, nil
 }
 
-func (r *SqlEventRepository) GetEventById(id int64) (models.Event, error) {
-	query := `SELECT * FROM events WHERE id = ?`
-	row := r.db.QueryRow(query, id)
+// GetEventById returns the event only when it belongs to organization orgId.
+func (r *SqlEventRepository) GetEventById(orgId, id int64) (models.Event, error) {
+	query := selectEvents + `WHERE e.id = ? AND e.organization_id = ?;`
+	return scanEvent(r.db.QueryRow(query, id, orgId))
+}
 
-	var e models.Event
-	err := row.Scan(&e.Id, &e.Name, &e.Description, &e.Location, &e.DateTime, &e.UserId)
-	return e, err
+// CanAccessEvent reports whether userId created the event, has a role on it
+// or was invited to it.
+func (r *SqlEventRepository) CanAccessEvent(orgId, eventId, userId int64) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM events e WHERE e.id = ? AND e.organization_id = ? AND ` + eventAccessCondition + `);`
+	var access bool
+	err := r.db.QueryRow(query, eventId, orgId, userId, userId, userId, userId).Scan(&access)
+	return access, err
 }
 
 func (r *SqlEventRepository) UpdateEvent(e *models.Event) error {
 	query := `
 	UPDATE events
-	SET name = ?, description = ?, location = ?, datetime = ?
-	WHERE id = ?
+	SET name = ?, description = ?, location = ?, datetime = ?, visibility = ?, capacity = ?, requires_approval = ?
+	WHERE id = ? AND organization_id = ?
 	`
-	_, err := r.db.Exec(query, e.Name, e.Description, e.Location, e.DateTime, e.Id)
+	_, err := r.db.Exec(query, e.Name, e.Description, e.Location, e.DateTime, e.Visibility, e.Capacity, e.RequiresApproval,
+		e.Id, e.OrganizationId)
 	return err
 }
 
-func (r *SqlEventRepository) DeleteEvent(id int64) error {
+// DeleteEvent deletes the event together with its members, invitations,
+// registration questions, ticket types, promo codes and cancellation
+// policy, and cancels pending registration transfers. Events with active
+// registrations that were paid for, or with cancellations whose refund
+// failed, are kept and false is returned; they have to be cancelled and
+// refunded first.
+func (r *SqlEventRepository) DeleteEvent(orgId, id int64) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var paid bool
 	query := `
-	DELETE FROM events WHERE id = ?;
+		SELECT EXISTS (
+			SELECT 1 FROM registrations r
+			JOIN orders o ON (o.registration_id = r.id OR o.group_id = r.group_id) AND o.status = 'paid'
+			WHERE r.event_id = ?1 AND r.organization_id = ?2 AND ` + activeRegistration + `
+		) OR EXISTS (
+			SELECT 1 FROM cancellations WHERE event_id = ?1 AND organization_id = ?2 AND refund_status = 'failed'
+		);
 	`
-	_, err := r.db.Exec(query, id)
-	return err
+	err = tx.QueryRow(query, id, orgId).Scan(&paid)
+	if err != nil || paid {
+		return false, err
+	}
+
+	_, err = tx.Exec(`DELETE FROM event_members WHERE event_id = ? AND organization_id = ?;`, id, orgId)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = tx.Exec(`DELETE FROM event_invitations WHERE event_id = ? AND organization_id = ?;`, id, orgId)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = tx.Exec(`
+		DELETE FROM registration_answers WHERE question_id IN (
+			SELECT id FROM registration_questions WHERE event_id = ? AND organization_id = ?
+		);`, id, orgId)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = tx.Exec(`DELETE FROM registration_questions WHERE event_id = ? AND organization_id = ?;`, id, orgId)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = tx.Exec(`DELETE FROM ticket_types WHERE event_id = ? AND organization_id = ?;`, id, orgId)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = tx.Exec(`DELETE FROM promo_codes WHERE event_id = ? AND organization_id = ?;`, id, orgId)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = tx.Exec(`DELETE FROM cancellation_policies WHERE event_id = ? AND organization_id = ?;`, id, orgId)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE registration_transfers SET status = 'cancelled', decided_at = CURRENT_TIMESTAMP
+		WHERE event_id = ? AND organization_id = ? AND status = 'pending';`, id, orgId)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = tx.Exec(`DELETE FROM events WHERE id = ? AND organization_id = ?;`, id, orgId)
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
 }
@@ -17,11 +17,12 @@ func TestCreateEvent(t *testing.T) {
 	repo := NewSqlEventRepository(testDB)
 
 	event := &models.Event{
-		Name:        "Tech Conference",
-		Description: "Annual tech conference for developers",
-		Location:    "San Francisco",
-		DateTime:    time.Now().Add(24 * time.Hour),
-		UserId:      1,
+		Name:           "Tech Conference",
+		Description:    "Annual tech conference for developers",
+		Location:       "San Francisco",
+		DateTime:       time.Now().Add(24 * time.Hour),
+		UserId:         1,
+		OrganizationId: 1,
 	}
 
 	id, err := repo.CreateEvent(event)
@@ -38,17 +39,17 @@ func TestGetEvents(t *testing.T) {
 
 	event1 := &models.Event{
 		Name: "Event 1", Description: "Description 1", Location: "Location 1",
-		DateTime: time.Now().Add(24 * time.Hour), UserId: 1,
+		DateTime: time.Now().Add(24 * time.Hour), UserId: 1, OrganizationId: 1,
 	}
 	event2 := &models.Event{
 		Name: "Event 2", Description: "Description 2", Location: "Location 2",
-		DateTime: time.Now().Add(48 * time.Hour), UserId: 1,
+		DateTime: time.Now().Add(48 * time.Hour), UserId: 1, OrganizationId: 1,
 	}
 
 	repo.CreateEvent(event1)
 	repo.CreateEvent(event2)
 
-	events, err := repo.GetEvents()
+	events, err := repo.GetEvents(1, 1)
 
 	require.NoError(t, err)
 	assert.Len(t, events, 2)
@@ -62,7 +63,7 @@ func TestGetEvents_EmptyTable(t *testing.T) {
 
 	repo := NewSqlEventRepository(testDB)
 
-	events, err := repo.GetEvents()
+	events, err := repo.GetEvents(1, 1)
 
 	require.NoError(t, err)
 	assert.Empty(t, events)
@@ -75,16 +76,17 @@ func TestGetEventById(t *testing.T) {
 	repo := NewSqlEventRepository(testDB)
 
 	event := &models.Event{
-		Name:        "Test Event",
-		Description: "Test Description",
-		Location:    "Test Location",
-		DateTime:    time.Now().Add(24 * time.Hour),
-		UserId:      1,
+		Name:           "Test Event",
+		Description:    "Test Description",
+		Location:       "Test Location",
+		DateTime:       time.Now().Add(24 * time.Hour),
+		UserId:         1,
+		OrganizationId: 1,
 	}
 
 	id, _ := repo.CreateEvent(event)
 
-	retrievedEvent, err := repo.GetEventById(id)
+	retrievedEvent, err := repo.GetEventById(1, id)
 
 	require.NoError(t, err)
 	assert.Equal(t, event.Name, retrievedEvent.Name)
@@ -99,7 +101,7 @@ func TestGetEventById_NotFound(t *testing.T) {
 
 	repo := NewSqlEventRepository(testDB)
 
-	_, err := repo.GetEventById(999)
+	_, err := repo.GetEventById(1, 999)
 
 	require.Error(t, err)
 }
@@ -111,11 +113,12 @@ func TestUpdateEvent(t *testing.T) {
 	repo := NewSqlEventRepository(testDB)
 
 	event := &models.Event{
-		Name:        "Original Name",
-		Description: "Original Description",
-		Location:    "Original Location",
-		DateTime:    time.Now().Add(24 * time.Hour),
-		UserId:      1,
+		Name:           "Original Name",
+		Description:    "Original Description",
+		Location:       "Original Location",
+		DateTime:       time.Now().Add(24 * time.Hour),
+		UserId:         1,
+		OrganizationId: 1,
 	}
 
 	id, _ := repo.CreateEvent(event)
@@ -128,7 +131,7 @@ func TestUpdateEvent(t *testing.T) {
 
 	require.NoError(t, err)
 
-	retrievedEvent, _ := repo.GetEventById(id)
+	retrievedEvent, _ := repo.GetEventById(1, id)
 	assert.Equal(t, "Updated Name", retrievedEvent.Name)
 	assert.Equal(t, "Updated Description", retrievedEvent.Description)
 }
@@ -140,18 +143,100 @@ func TestDeleteEvent(t *testing.T) {
 	repo := NewSqlEventRepository(testDB)
 
 	event := &models.Event{
-		Name:        "Event to Delete",
-		Description: "This will be deleted",
-		Location:    "Delete Location",
-		DateTime:    time.Now().Add(24 * time.Hour),
-		UserId:      1,
+		Name:           "Event to Delete",
+		Description:    "This will be deleted",
+		Location:       "Delete Location",
+		DateTime:       time.Now().Add(24 * time.Hour),
+		UserId:         1,
+		OrganizationId: 1,
 	}
 
 	id, _ := repo.CreateEvent(event)
-	err := repo.DeleteEvent(id)
+	deleted, err := repo.DeleteEvent(1, id)
 
 	require.NoError(t, err)
+	assert.True(t, deleted)
 
-	_, err = repo.GetEventById(id)
+	_, err = repo.GetEventById(1, id)
 	assert.Error(t, err, "Event should not exist after deletion")
 }
+
+func TestDeleteEvent_KeepsEventWithPaidRegistrations(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+	orderRepo := NewSqlOrderRepository(testDB)
+
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	eventId := createTestOrgEvent(t, eventRepo, orgId, "Conference")
+	registration := newTestRegistration(orgId, 5, eventId)
+	_, err := registerRepo.RegisterEvent(registration)
+	require.NoError(t, err)
+	order := newTestOrder(registration, "pi_fake_1")
+	order.Status = "paid"
+	require.NoError(t, orderRepo.CreateOrder(order))
+
+	deleted, err := eventRepo.DeleteEvent(orgId, eventId)
+	require.NoError(t, err)
+	assert.False(t, deleted)
+	_, err = eventRepo.GetEventById(orgId, eventId)
+	require.NoError(t, err, "Events with paid registrations are kept")
+
+	cancelled, err := registerRepo.RecordCancellation(&models.Cancellation{
+		RegistrationId: registration.Id, UserId: 5, EventId: eventId, OrganizationId: orgId, OrderId: order.Id,
+		RefundAmount: order.Amount, Currency: "EUR", RefundStatus: "pending", CancelledAt: time.Now(),
+	})
+	require.NoError(t, err)
+	require.True(t, cancelled)
+
+	deleted, err = eventRepo.DeleteEvent(orgId, eventId)
+	require.NoError(t, err)
+	assert.True(t, deleted, "Cancelled registrations do not keep the event")
+}
+
+func TestDeleteEvent_KeepsEventWithFailedRefunds(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+	orderRepo := NewSqlOrderRepository(testDB)
+
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	eventId := createTestOrgEvent(t, eventRepo, orgId, "Conference")
+	registration := newTestRegistration(orgId, 5, eventId)
+	_, err := registerRepo.RegisterEvent(registration)
+	require.NoError(t, err)
+	order := newTestOrder(registration, "pi_fake_1")
+	order.Status = "paid"
+	require.NoError(t, orderRepo.CreateOrder(order))
+	cancellation := models.Cancellation{
+		RegistrationId: registration.Id, UserId: 5, EventId: eventId, OrganizationId: orgId, OrderId: order.Id,
+		RefundAmount: order.Amount, Currency: "EUR", RefundStatus: "pending", CancelledAt: time.Now(),
+	}
+	_, err = registerRepo.RecordCancellation(&cancellation)
+	require.NoError(t, err)
+	cancellation.RefundStatus = "failed"
+	require.NoError(t, registerRepo.SetRefundStatus(cancellation))
+
+	deleted, err := eventRepo.DeleteEvent(orgId, eventId)
+	require.NoError(t, err)
+	assert.False(t, deleted, "Events with failed refunds are kept")
+
+	claimed, err := registerRepo.RetryRefund(orgId, cancellation.Id)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+	claimed, err = registerRepo.RetryRefund(orgId, cancellation.Id)
+	require.NoError(t, err)
+	assert.False(t, claimed, "A failed refund is retried once")
+
+	cancellation.RefundStatus = "refunded"
+	require.NoError(t, registerRepo.SetRefundStatus(cancellation))
+	deleted, err = eventRepo.DeleteEvent(orgId, eventId)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+}
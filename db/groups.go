@@ -0,0 +1,148 @@
+package db
+
+import "event-booking/models"
+
+func (r *SqlEventRegisterRepository) GetOrganizationMemberByEmail(orgId int64, email string) (models.OrganizationMember, error) {
+	return r.eventRepo.GetOrganizationMemberByEmail(orgId, email)
+}
+
+// RegisterGroup inserts the group together with its registrations and their
+// answers, all or none, and counts one use of their promo code for each
+// registration, expiring unpaid registrations of the code like RegisterEvent
+// does: when one of the registrations does not find a free seat or
+// the promo code has not enough uses left, false is returned and nothing is
+// inserted.
+func (r *SqlEventRegisterRepository) RegisterGroup(group *models.RegistrationGroup) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if len(group.Registrations) > 0 && group.Registrations[0].PromoCodeId != 0 {
+		err = expireUnpaidPromoUses(tx, group.OrganizationId, group.Registrations[0].PromoCodeId, group.CreatedAt)
+		if err != nil {
+			return false, err
+		}
+
+		query := `
+			UPDATE promo_codes SET used = used + ?1
+			WHERE id = ?2 AND event_id = ?3 AND organization_id = ?4 AND (max_uses = 0 OR used + ?1 <= max_uses);
+		`
+		result, err := tx.Exec(query, len(group.Registrations), group.Registrations[0].PromoCodeId, group.EventId,
+			group.OrganizationId)
+		if err != nil {
+			return false, err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil || affected == 0 {
+			return false, err
+		}
+	}
+
+	query := `
+		INSERT INTO registration_groups (event_id, organization_id, booker_id, created_at)
+		SELECT e.id, e.organization_id, ?, ? FROM events e WHERE e.id = ? AND e.organization_id = ?;
+	`
+	result, err := tx.Exec(query, group.BookerId, group.CreatedAt, group.EventId, group.OrganizationId)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		return false, err
+	}
+	group.Id, err = result.LastInsertId()
+	if err != nil {
+		return false, err
+	}
+
+	for i := range group.Registrations {
+		group.Registrations[i].GroupId = group.Id
+		inserted, err := insertRegistration(tx, &group.Registrations[i])
+		if err != nil || !inserted {
+			return false, err
+		}
+	}
+
+	return true, tx.Commit()
+}
+
+const selectGroups = `
+	SELECT g.id, g.event_id, g.organization_id, g.booker_id, g.created_at
+	FROM registration_groups g
+`
+
+func scanGroup(row rowScanner) (models.RegistrationGroup, error) {
+	var g models.RegistrationGroup
+	err := row.Scan(&g.Id, &g.EventId, &g.OrganizationId, &g.BookerId, &g.CreatedAt)
+	return g, err
+}
+
+// GetGroup returns the group with its registrations in any status.
+func (r *SqlEventRegisterRepository) GetGroup(orgId, id int64) (models.RegistrationGroup, error) {
+	query := selectGroups + `WHERE g.id = ? AND g.organization_id = ?;`
+	group, err := scanGroup(r.db.QueryRow(query, id, orgId))
+	if err != nil {
+		return models.RegistrationGroup{}, err
+	}
+
+	err = r.addGroupRegistrations(&group)
+	if err != nil {
+		return models.RegistrationGroup{}, err
+	}
+	return group, nil
+}
+
+// GetGroups lists the groups booked by bookerId with their registrations,
+// newest first.
+func (r *SqlEventRegisterRepository) GetGroups(orgId, bookerId int64) ([]models.RegistrationGroup, error) {
+	query := selectGroups + `
+		WHERE g.booker_id = ? AND g.organization_id = ?
+		ORDER BY g.id DESC;
+	`
+	rows, err := r.db.Query(query, bookerId, orgId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := []models.RegistrationGroup{}
+	for rows.Next() {
+		group, err := scanGroup(rows)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	for i := range groups {
+		err = r.addGroupRegistrations(&groups[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return groups, nil
+}
+
+// addGroupRegistrations adds the registrations of the group with their
+// answers.
+func (r *SqlEventRegisterRepository) addGroupRegistrations(group *models.RegistrationGroup) error {
+	query := selectRegistrations + `WHERE r.group_id = ? AND r.organization_id = ? ORDER BY r.id;`
+	registrations, err := r.queryRegistrations(query, group.Id, group.OrganizationId)
+	if err != nil {
+		return err
+	}
+
+	err = r.addAnswers(group.OrganizationId, group.EventId, registrations)
+	if err != nil {
+		return err
+	}
+	group.Registrations = registrations
+	return nil
+}
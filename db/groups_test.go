@@ -0,0 +1,246 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"event-booking/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGroup(orgId, eventId, bookerId int64, registrations ...models.RegisterEvent) *models.RegistrationGroup {
+	return &models.RegistrationGroup{
+		EventId: eventId, OrganizationId: orgId, BookerId: bookerId, CreatedAt: time.Now(), Registrations: registrations,
+	}
+}
+
+func newTestGuestRegistration(orgId, eventId int64, name, email string) models.RegisterEvent {
+	registration := newTestRegistration(orgId, 0, eventId)
+	registration.GuestName = name
+	registration.Email = email
+	return *registration
+}
+
+func TestRegisterGroup_AllOrNothing(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	eventId, err := eventRepo.CreateEvent(&models.Event{
+		Name: "Workshop", Description: "Description", Location: "Location",
+		DateTime: time.Now().Add(24 * time.Hour), UserId: 1, OrganizationId: orgId, Visibility: "public", Capacity: 2,
+	})
+	require.NoError(t, err)
+	_, err = registerRepo.RegisterEvent(newTestRegistration(orgId, 5, eventId))
+	require.NoError(t, err)
+
+	registered, err := registerRepo.RegisterGroup(newTestGroup(orgId, eventId, 6,
+		*newTestRegistration(orgId, 6, eventId),
+		newTestGuestRegistration(orgId, eventId, "Grace", "grace@example.com"),
+	))
+	require.NoError(t, err)
+	assert.False(t, registered)
+
+	registrations, err := registerRepo.GetRegistrations(orgId, eventId, "")
+	require.NoError(t, err)
+	assert.Len(t, registrations, 1, "No seat of the group is taken when not all fit")
+	groups, err := registerRepo.GetGroups(orgId, 6)
+	require.NoError(t, err)
+	assert.Empty(t, groups)
+
+	registered, err = registerRepo.RegisterGroup(newTestGroup(orgId, eventId, 6,
+		newTestGuestRegistration(orgId, eventId, "Grace", "grace@example.com"),
+	))
+	require.NoError(t, err)
+	assert.True(t, registered)
+}
+
+func TestGetGroup_MembersAndGuests(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	userRepo := NewSqlUserRepository(testDB)
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+
+	memberId, err := userRepo.CreateUser(&models.User{Email: "colleague@example.com", Password: "password123"})
+	require.NoError(t, err)
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	eventId := createTestOrgEvent(t, eventRepo, orgId, "Conference")
+
+	group := newTestGroup(orgId, eventId, 6,
+		*newTestRegistration(orgId, memberId, eventId),
+		newTestGuestRegistration(orgId, eventId, "Grace", "grace@example.com"),
+	)
+	registered, err := registerRepo.RegisterGroup(group)
+	require.NoError(t, err)
+	require.True(t, registered)
+
+	saved, err := registerRepo.GetGroup(orgId, group.Id)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), saved.BookerId)
+	require.Len(t, saved.Registrations, 2)
+	assert.Equal(t, memberId, saved.Registrations[0].UserId)
+	assert.Equal(t, "colleague@example.com", saved.Registrations[0].Email)
+	assert.Equal(t, int64(0), saved.Registrations[1].UserId)
+	assert.Equal(t, "Grace", saved.Registrations[1].GuestName)
+	assert.Equal(t, "grace@example.com", saved.Registrations[1].Email)
+	assert.Equal(t, group.Id, saved.Registrations[1].GroupId)
+
+	groups, err := registerRepo.GetGroups(orgId, 6)
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Len(t, groups[0].Registrations, 2)
+
+	_, err = registerRepo.GetGroup(orgId+1, group.Id)
+	assert.Error(t, err, "Groups of other organizations are not found")
+}
+
+func TestCheckIn_GuestHeldByBooker(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	eventId := createTestOrgEvent(t, eventRepo, orgId, "Conference")
+	group := newTestGroup(orgId, eventId, 6, newTestGuestRegistration(orgId, eventId, "Grace", "grace@example.com"))
+	_, err := registerRepo.RegisterGroup(group)
+	require.NoError(t, err)
+	guestId := group.Registrations[0].Id
+
+	checkedIn, err := registerRepo.CheckIn(orgId, eventId, guestId, 7, time.Now())
+	require.NoError(t, err)
+	assert.False(t, checkedIn, "Only the booker holds the guest's ticket")
+
+	checkedIn, err = registerRepo.CheckIn(orgId, eventId, guestId, 6, time.Now())
+	require.NoError(t, err)
+	assert.True(t, checkedIn)
+}
+
+func TestGroupOrder_PaysAndRefundsSeats(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+	orderRepo := NewSqlOrderRepository(testDB)
+
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	eventId := createTestOrgEvent(t, eventRepo, orgId, "Conference")
+	promoCodeId := createTestPromoCode(t, eventRepo, orgId, eventId, "TEAM", 3)
+
+	awaiting := func(userId int64) models.RegisterEvent {
+		registration := newTestRegistration(orgId, userId, eventId)
+		registration.Status = "awaiting_payment"
+		registration.PromoCodeId = promoCodeId
+		registration.Amount = 2000
+		return *registration
+	}
+	group := newTestGroup(orgId, eventId, 5, awaiting(5), awaiting(6))
+	registered, err := registerRepo.RegisterGroup(group)
+	require.NoError(t, err)
+	require.True(t, registered)
+	registered, err = registerRepo.RegisterGroup(newTestGroup(orgId, eventId, 7, awaiting(7), awaiting(8)))
+	require.NoError(t, err)
+	assert.False(t, registered, "The promo code has one use left for two attendees")
+	promoCode, err := eventRepo.GetPromoCode(orgId, eventId, promoCodeId)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), promoCode.Used)
+
+	order := newTestOrder(&group.Registrations[0], "pi_group")
+	order.RegistrationId = 0
+	order.GroupId = group.Id
+	order.Amount = 4000
+	require.NoError(t, orderRepo.CreateOrder(order))
+	open, err := orderRepo.GetOpenGroupOrder(orgId, group.Id)
+	require.NoError(t, err)
+	assert.Equal(t, order.Id, open.Id)
+
+	confirmed, err := orderRepo.ConfirmGroup(orgId, group.Id, "approved")
+	require.NoError(t, err)
+	assert.True(t, confirmed)
+	changed, err := orderRepo.SetOrderStatus(order.Id, "pending", "paid", time.Now())
+	require.NoError(t, err)
+	require.True(t, changed)
+
+	for _, registration := range group.Registrations {
+		paid, err := registerRepo.GetPaidOrder(orgId, registration.Id)
+		require.NoError(t, err)
+		assert.Equal(t, order.Id, paid.Id, "Group registrations are paid by the group order")
+	}
+
+	refund := func(registration models.RegisterEvent) {
+		cancellation := &models.Cancellation{
+			RegistrationId: registration.Id, UserId: registration.UserId, EventId: eventId, OrganizationId: orgId,
+			OrderId: order.Id, RefundAmount: 2000, Currency: "EUR", RefundStatus: "pending", CancelledAt: time.Now(),
+		}
+		cancelled, err := registerRepo.RecordCancellation(cancellation)
+		require.NoError(t, err)
+		require.True(t, cancelled)
+		cancellation.RefundStatus = "refunded"
+		require.NoError(t, registerRepo.SetRefundStatus(*cancellation))
+	}
+
+	refund(group.Registrations[0])
+	partly, err := orderRepo.GetOrder(orgId, order.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "paid", partly.Status, "The other seat is still paid for")
+	assert.Equal(t, int64(2000), partly.RefundedAmount)
+	deleted, err := eventRepo.DeleteEvent(orgId, eventId)
+	require.NoError(t, err)
+	assert.False(t, deleted, "Events with paid group seats are kept")
+
+	refund(group.Registrations[1])
+	refunded, err := orderRepo.GetOrder(orgId, order.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "refunded", refunded.Status)
+	assert.Equal(t, int64(4000), refunded.RefundedAmount)
+}
+
+func TestCancelRegistration_CancelsUnpaidGroupOrder(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+	orderRepo := NewSqlOrderRepository(testDB)
+
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	eventId := createTestOrgEvent(t, eventRepo, orgId, "Conference")
+
+	member := newTestRegistration(orgId, 5, eventId)
+	member.Status = "awaiting_payment"
+	guest := newTestGuestRegistration(orgId, eventId, "Grace", "grace@example.com")
+	guest.Status = "awaiting_payment"
+	group := newTestGroup(orgId, eventId, 5, *member, guest)
+	registered, err := registerRepo.RegisterGroup(group)
+	require.NoError(t, err)
+	require.True(t, registered)
+
+	order := newTestOrder(&group.Registrations[0], "pi_group")
+	order.RegistrationId = 0
+	order.GroupId = group.Id
+	require.NoError(t, orderRepo.CreateOrder(order))
+
+	require.NoError(t, registerRepo.CancelRegistration(orgId, group.Registrations[1].Id))
+
+	cancelled, err := orderRepo.GetOrder(orgId, order.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "cancelled", cancelled.Status, "The order no longer matches the group's total")
+
+	require.NoError(t, orderRepo.CancelGroupRegistrations(orgId, group.Id))
+	_, err = registerRepo.GetRegisteredEventById(orgId, 5, eventId)
+	assert.Error(t, err, "All registrations of the group are cancelled")
+}
@@ -0,0 +1,76 @@
+package db
+
+import (
+	"database/sql"
+	"event-booking/models"
+	"time"
+)
+
+type SqlIdempotencyRepository struct {
+	db *sql.DB
+}
+
+func NewSqlIdempotencyRepository(database *sql.DB) *SqlIdempotencyRepository {
+	return &SqlIdempotencyRepository{
+		db: database,
+	}
+}
+
+// ReserveIdempotencyKey inserts a pending key and reports whether it was
+// created. It returns false without error when the user already holds the key.
+func (r *SqlIdempotencyRepository) ReserveIdempotencyKey(k *models.IdempotencyKey) (bool, error) {
+	query := `
+	INSERT INTO idempotency_keys (user_id, key, request_hash, created_at)
+	VALUES (?, ?, ?, ?)
+	ON CONFLICT(user_id, key) DO NOTHING;
+	`
+	result, err := r.db.Exec(query, k.UserId, k.Key, k.RequestHash, k.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	k.Id, err = result.LastInsertId()
+	return true, err
+}
+
+func (r *SqlIdempotencyRepository) GetIdempotencyKey(userId int64, key string) (models.IdempotencyKey, error) {
+	query := `
+	SELECT id, user_id, key, request_hash, status_code, content_type, response_body, completed, created_at
+	FROM idempotency_keys WHERE user_id = ? AND key = ?;
+	`
+	row := r.db.QueryRow(query, userId, key)
+
+	var k models.IdempotencyKey
+	err := row.Scan(&k.Id, &k.UserId, &k.Key, &k.RequestHash, &k.StatusCode, &k.ContentType, &k.ResponseBody, &k.Completed, &k.CreatedAt)
+	return k, err
+}
+
+func (r *SqlIdempotencyRepository) CompleteIdempotencyKey(k *models.IdempotencyKey) error {
+	query := `
+	UPDATE idempotency_keys
+	SET status_code = ?, content_type = ?, response_body = ?, completed = 1
+	WHERE user_id = ? AND key = ?;
+	`
+	_, err := r.db.Exec(query, k.StatusCode, k.ContentType, k.ResponseBody, k.UserId, k.Key)
+	return err
+}
+
+func (r *SqlIdempotencyRepository) DeleteIdempotencyKey(userId int64, key string) error {
+	query := `DELETE FROM idempotency_keys WHERE user_id = ? AND key = ?;`
+	_, err := r.db.Exec(query, userId, key)
+	return err
+}
+
+func (r *SqlIdempotencyRepository) DeleteExpiredIdempotencyKeys(before time.Time) error {
+	query := `DELETE FROM idempotency_keys WHERE created_at < ?;`
+	_, err := r.db.Exec(query, before)
+	return err
+}
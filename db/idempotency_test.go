@@ -0,0 +1,138 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"event-booking/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReserveIdempotencyKey(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlIdempotencyRepository(testDB)
+
+	key := &models.IdempotencyKey{
+		UserId:      1,
+		Key:         "key-1",
+		RequestHash: "hash",
+		CreatedAt:   time.Now(),
+	}
+
+	created, err := repo.ReserveIdempotencyKey(key)
+
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Greater(t, key.Id, int64(0))
+}
+
+func TestReserveIdempotencyKey_AlreadyReserved(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlIdempotencyRepository(testDB)
+
+	first := &models.IdempotencyKey{UserId: 1, Key: "key-1", RequestHash: "hash", CreatedAt: time.Now()}
+	second := &models.IdempotencyKey{UserId: 1, Key: "key-1", RequestHash: "other", CreatedAt: time.Now()}
+
+	_, err := repo.ReserveIdempotencyKey(first)
+	require.NoError(t, err)
+
+	created, err := repo.ReserveIdempotencyKey(second)
+
+	require.NoError(t, err)
+	assert.False(t, created)
+}
+
+func TestReserveIdempotencyKey_ScopedPerUser(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlIdempotencyRepository(testDB)
+
+	_, err := repo.ReserveIdempotencyKey(&models.IdempotencyKey{UserId: 1, Key: "key-1", RequestHash: "hash", CreatedAt: time.Now()})
+	require.NoError(t, err)
+
+	created, err := repo.ReserveIdempotencyKey(&models.IdempotencyKey{UserId: 2, Key: "key-1", RequestHash: "hash", CreatedAt: time.Now()})
+
+	require.NoError(t, err)
+	assert.True(t, created)
+}
+
+func TestCompleteIdempotencyKey(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlIdempotencyRepository(testDB)
+
+	key := &models.IdempotencyKey{UserId: 1, Key: "key-1", RequestHash: "hash", CreatedAt: time.Now()}
+	_, err := repo.ReserveIdempotencyKey(key)
+	require.NoError(t, err)
+
+	key.StatusCode = 201
+	key.ContentType = "application/json; charset=utf-8"
+	key.ResponseBody = []byte(`{"message":"ok"}`)
+	err = repo.CompleteIdempotencyKey(key)
+	require.NoError(t, err)
+
+	stored, err := repo.GetIdempotencyKey(1, "key-1")
+
+	require.NoError(t, err)
+	assert.True(t, stored.Completed)
+	assert.Equal(t, 201, stored.StatusCode)
+	assert.Equal(t, key.ContentType, stored.ContentType)
+	assert.Equal(t, key.ResponseBody, stored.ResponseBody)
+	assert.Equal(t, "hash", stored.RequestHash)
+}
+
+func TestGetIdempotencyKey_NotFound(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlIdempotencyRepository(testDB)
+
+	_, err := repo.GetIdempotencyKey(1, "missing")
+
+	require.Error(t, err)
+}
+
+func TestDeleteIdempotencyKey(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlIdempotencyRepository(testDB)
+
+	_, err := repo.ReserveIdempotencyKey(&models.IdempotencyKey{UserId: 1, Key: "key-1", RequestHash: "hash", CreatedAt: time.Now()})
+	require.NoError(t, err)
+
+	err = repo.DeleteIdempotencyKey(1, "key-1")
+	require.NoError(t, err)
+
+	_, err = repo.GetIdempotencyKey(1, "key-1")
+	require.Error(t, err)
+}
+
+func TestDeleteExpiredIdempotencyKeys(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlIdempotencyRepository(testDB)
+
+	now := time.Now()
+	_, err := repo.ReserveIdempotencyKey(&models.IdempotencyKey{UserId: 1, Key: "old", RequestHash: "hash", CreatedAt: now.Add(-48 * time.Hour)})
+	require.NoError(t, err)
+	_, err = repo.ReserveIdempotencyKey(&models.IdempotencyKey{UserId: 1, Key: "new", RequestHash: "hash", CreatedAt: now})
+	require.NoError(t, err)
+
+	err = repo.DeleteExpiredIdempotencyKeys(now.Add(-24 * time.Hour))
+	require.NoError(t, err)
+
+	_, err = repo.GetIdempotencyKey(1, "old")
+	assert.Error(t, err)
+	_, err = repo.GetIdempotencyKey(1, "new")
+	assert.NoError(t, err)
+}
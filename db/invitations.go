@@ -0,0 +1,159 @@
+package db
+
+import (
+	"database/sql"
+	"event-booking/models"
+)
+
+type SqlEventInvitationRepository struct {
+	db        *sql.DB
+	eventRepo *SqlEventRepository
+	orgRepo   *SqlOrganizationRepository
+	userRepo  *SqlUserRepository
+}
+
+func NewSqlEventInvitationRepository(database *sql.DB) *SqlEventInvitationRepository {
+	return &SqlEventInvitationRepository{
+		db:        database,
+		eventRepo: NewSqlEventRepository(database),
+		orgRepo:   NewSqlOrganizationRepository(database),
+		userRepo:  NewSqlUserRepository(database),
+	}
+}
+
+const selectEventInvitations = `
+	SELECT id, event_id, organization_id, email, user_id, invited_by, created_at
+	FROM event_invitations
+`
+
+func scanEventInvitation(row rowScanner) (models.EventInvitation, error) {
+	var i models.EventInvitation
+	var userId sql.NullInt64
+	err := row.Scan(&i.Id, &i.EventId, &i.OrganizationId, &i.Email, &userId, &i.InvitedBy, &i.CreatedAt)
+	i.UserId = userId.Int64
+	return i, err
+}
+
+func (r *SqlEventInvitationRepository) GetEventById(orgId, id int64) (models.Event, error) {
+	return r.eventRepo.GetEventById(orgId, id)
+}
+
+func (r *SqlEventInvitationRepository) GetEventMember(orgId, eventId, userId int64) (models.EventMember, error) {
+	return r.eventRepo.GetEventMember(orgId, eventId, userId)
+}
+
+func (r *SqlEventInvitationRepository) GetMembership(orgId, userId int64) (models.Membership, error) {
+	return r.orgRepo.GetMembership(orgId, userId)
+}
+
+func (r *SqlEventInvitationRepository) GetUserById(id int64) (models.User, error) {
+	return r.userRepo.GetUserById(id)
+}
+
+// CreateEventInvitation invites i.Email to the event. Inviting an address
+// again returns the existing invitation.
+func (r *SqlEventInvitationRepository) CreateEventInvitation(i *models.EventInvitation) error {
+	query := selectEventInvitations + `WHERE event_id = ? AND organization_id = ? AND LOWER(email) = LOWER(?);`
+	existing, err := scanEventInvitation(r.db.QueryRow(query, i.EventId, i.OrganizationId, i.Email))
+	if err == nil {
+		*i = existing
+		return nil
+	}
+	if err != sql.ErrNoRows {
+		return err
+	}
+
+	insert := `
+	INSERT INTO event_invitations (event_id, organization_id, email, invited_by, created_at)
+	VALUES (?, ?, ?, ?, ?);
+	`
+	result, err := r.db.Exec(insert, i.EventId, i.OrganizationId, i.Email, i.InvitedBy, i.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	i.Id, err = result.LastInsertId()
+	return err
+}
+
+// AcceptEventInvitation records that i.UserId accepted an invitation. With
+// i.Email, the email invitation for that address is claimed by the user and
+// sql.ErrNoRows is returned when it was revoked. Without, an invite link was
+// used and a new invitation is recorded.
+func (r *SqlEventInvitationRepository) AcceptEventInvitation(i *models.EventInvitation) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM event_invitations WHERE event_id = ? AND organization_id = ? AND user_id = ?);`
+	err = tx.QueryRow(query, i.EventId, i.OrganizationId, i.UserId).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return tx.Commit()
+	}
+
+	claim := `
+	UPDATE event_invitations SET user_id = ?
+	WHERE event_id = ? AND organization_id = ? AND user_id IS NULL
+		AND email != '' AND LOWER(email) = LOWER(?);
+	`
+	result, err := tx.Exec(claim, i.UserId, i.EventId, i.OrganizationId, i.Email)
+	if err != nil {
+		return err
+	}
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if claimed == 0 && i.Email != "" {
+		return sql.ErrNoRows
+	}
+	if claimed == 0 {
+		insert := `
+		INSERT INTO event_invitations (event_id, organization_id, email, user_id, invited_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?);
+		`
+		_, err = tx.Exec(insert, i.EventId, i.OrganizationId, i.Email, i.UserId, i.InvitedBy, i.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *SqlEventInvitationRepository) GetEventInvitations(orgId, eventId int64) ([]models.EventInvitation, error) {
+	rows, err := r.db.Query(selectEventInvitations+`WHERE event_id = ? AND organization_id = ? ORDER BY id;`, eventId, orgId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invitations := []models.EventInvitation{}
+	for rows.Next() {
+		i, err := scanEventInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, i)
+	}
+
+	return invitations, nil
+}
+
+func (r *SqlEventInvitationRepository) DeleteEventInvitation(orgId, eventId, id int64) (bool, error) {
+	query := `DELETE FROM event_invitations WHERE id = ? AND event_id = ? AND organization_id = ?;`
+	result, err := r.db.Exec(query, id, eventId, orgId)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
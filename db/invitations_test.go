@@ -0,0 +1,117 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"event-booking/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestPrivateEvent(t *testing.T, repo *SqlEventRepository, orgId int64, name string) int64 {
+	id, err := repo.CreateEvent(&models.Event{
+		Name: name, Description: "Description", Location: "Location",
+		DateTime: time.Now().Add(24 * time.Hour), UserId: 1, OrganizationId: orgId, Visibility: "private",
+	})
+	require.NoError(t, err)
+	return id
+}
+
+func TestPrivateEvents_OnlyVisibleToInvitees(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	userRepo := NewSqlUserRepository(testDB)
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	repo := NewSqlEventInvitationRepository(testDB)
+
+	ownerId, _ := userRepo.CreateUser(&models.User{Email: "owner@example.com", Password: "password123"})
+	guestId, _ := userRepo.CreateUser(&models.User{Email: "guest@example.com", Password: "password123"})
+	orgId := createTestOrganization(t, orgRepo, "Acme", ownerId)
+	createTestOrgEvent(t, eventRepo, orgId, "Public")
+	privateId := createTestPrivateEvent(t, eventRepo, orgId, "Private")
+
+	events, err := eventRepo.GetEvents(orgId, guestId)
+	require.NoError(t, err)
+	assert.Len(t, events, 1, "Private events are hidden from users without access")
+
+	events, err = eventRepo.GetEvents(orgId, ownerId)
+	require.NoError(t, err)
+	assert.Len(t, events, 2, "The creator sees their private events")
+
+	err = repo.CreateEventInvitation(&models.EventInvitation{
+		EventId: privateId, OrganizationId: orgId, Email: "Guest@Example.com", InvitedBy: ownerId, CreatedAt: time.Now(),
+	})
+	require.NoError(t, err)
+
+	allowed, err := eventRepo.CanAccessEvent(orgId, privateId, guestId)
+	require.NoError(t, err)
+	assert.True(t, allowed, "Email invitations grant access before they are accepted")
+
+	events, err = eventRepo.GetEvents(orgId, guestId)
+	require.NoError(t, err)
+	assert.Len(t, events, 2)
+}
+
+func TestCreateEventInvitation_SameEmailTwice(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	repo := NewSqlEventInvitationRepository(testDB)
+
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	eventId := createTestPrivateEvent(t, eventRepo, orgId, "Private")
+
+	first := &models.EventInvitation{EventId: eventId, OrganizationId: orgId, Email: "guest@example.com", InvitedBy: 1, CreatedAt: time.Now()}
+	require.NoError(t, repo.CreateEventInvitation(first))
+	second := &models.EventInvitation{EventId: eventId, OrganizationId: orgId, Email: "GUEST@example.com", InvitedBy: 1, CreatedAt: time.Now()}
+	require.NoError(t, repo.CreateEventInvitation(second))
+
+	assert.Equal(t, first.Id, second.Id)
+	invitations, err := repo.GetEventInvitations(orgId, eventId)
+	require.NoError(t, err)
+	assert.Len(t, invitations, 1)
+}
+
+func TestAcceptEventInvitation(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	repo := NewSqlEventInvitationRepository(testDB)
+
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	eventId := createTestPrivateEvent(t, eventRepo, orgId, "Private")
+
+	invitation := &models.EventInvitation{EventId: eventId, OrganizationId: orgId, Email: "guest@example.com", InvitedBy: 1, CreatedAt: time.Now()}
+	require.NoError(t, repo.CreateEventInvitation(invitation))
+
+	err := repo.AcceptEventInvitation(&models.EventInvitation{EventId: eventId, OrganizationId: orgId, Email: "guest@example.com", UserId: 5, InvitedBy: 1, CreatedAt: time.Now()})
+	require.NoError(t, err)
+	err = repo.AcceptEventInvitation(&models.EventInvitation{EventId: eventId, OrganizationId: orgId, UserId: 6, InvitedBy: 1, CreatedAt: time.Now()})
+	require.NoError(t, err)
+
+	invitations, err := repo.GetEventInvitations(orgId, eventId)
+	require.NoError(t, err)
+	require.Len(t, invitations, 2)
+	assert.Equal(t, int64(5), invitations[0].UserId, "The email invitation is claimed")
+	assert.Equal(t, int64(6), invitations[1].UserId, "Invite links record a new invitation")
+
+	allowed, err := eventRepo.CanAccessEvent(orgId, eventId, 6)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	deleted, err := repo.DeleteEventInvitation(orgId, eventId, invitation.Id)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	err = repo.AcceptEventInvitation(&models.EventInvitation{EventId: eventId, OrganizationId: orgId, Email: "guest@example.com", UserId: 7, InvitedBy: 1, CreatedAt: time.Now()})
+	assert.ErrorIs(t, err, sql.ErrNoRows, "Revoked email invitations cannot be accepted")
+}
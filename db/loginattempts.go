@@ -0,0 +1,64 @@
+package db
+
+import (
+	"database/sql"
+	"event-booking/models"
+	"time"
+)
+
+type SqlLoginAttemptRepository struct {
+	db *sql.DB
+}
+
+func NewSqlLoginAttemptRepository(database *sql.DB) *SqlLoginAttemptRepository {
+	return &SqlLoginAttemptRepository{
+		db: database,
+	}
+}
+
+// GetLoginAttempt returns the tracked failures for scope and key. Subjects
+// without failures get an empty attempt.
+func (r *SqlLoginAttemptRepository) GetLoginAttempt(scope, key string) (models.LoginAttempt, error) {
+	query := `
+	SELECT scope, key, failures, last_failure_at, locked_until
+	FROM login_attempts WHERE scope = ? AND key = ?;
+	`
+	row := r.db.QueryRow(query, scope, key)
+
+	var a models.LoginAttempt
+	var lockedUntil sql.NullTime
+	err := row.Scan(&a.Scope, &a.Key, &a.Failures, &a.LastFailureAt, &lockedUntil)
+	if err == sql.ErrNoRows {
+		return models.LoginAttempt{Scope: scope, Key: key}, nil
+	}
+	a.LockedUntil = lockedUntil.Time
+	return a, err
+}
+
+// RecordLoginFailure atomically counts a failure and returns the new number
+// of consecutive failures. Failures before resetBefore are forgotten.
+func (r *SqlLoginAttemptRepository) RecordLoginFailure(scope, key string, now, resetBefore time.Time) (int, error) {
+	query := `
+	INSERT INTO login_attempts (scope, key, failures, last_failure_at)
+	VALUES (?, ?, 1, ?)
+	ON CONFLICT(scope, key) DO UPDATE SET
+		failures = CASE WHEN last_failure_at < ? THEN 1 ELSE failures + 1 END,
+		last_failure_at = excluded.last_failure_at
+	RETURNING failures;
+	`
+	var failures int
+	err := r.db.QueryRow(query, scope, key, now, resetBefore).Scan(&failures)
+	return failures, err
+}
+
+func (r *SqlLoginAttemptRepository) LockLoginAttempt(scope, key string, until time.Time) error {
+	query := `UPDATE login_attempts SET locked_until = ? WHERE scope = ? AND key = ?;`
+	_, err := r.db.Exec(query, until, scope, key)
+	return err
+}
+
+func (r *SqlLoginAttemptRepository) DeleteLoginAttempt(scope, key string) error {
+	query := `DELETE FROM login_attempts WHERE scope = ? AND key = ?;`
+	_, err := r.db.Exec(query, scope, key)
+	return err
+}
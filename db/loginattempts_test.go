@@ -0,0 +1,82 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordLoginFailure_CountsConsecutiveFailures(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlLoginAttemptRepository(testDB)
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 1; i <= 3; i++ {
+		failures, err := repo.RecordLoginFailure("account", "test@example.com", now, now.Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, i, failures)
+	}
+
+	failures, err := repo.RecordLoginFailure("ip", "127.0.0.1", now, now.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, failures)
+}
+
+func TestRecordLoginFailure_ResetsAfterWindow(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlLoginAttemptRepository(testDB)
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	_, err := repo.RecordLoginFailure("account", "test@example.com", now, now.Add(-time.Hour))
+	require.NoError(t, err)
+	_, err = repo.RecordLoginFailure("account", "test@example.com", now, now.Add(-time.Hour))
+	require.NoError(t, err)
+
+	later := now.Add(2 * time.Hour)
+	failures, err := repo.RecordLoginFailure("account", "test@example.com", later, later.Add(-time.Hour))
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, failures)
+}
+
+func TestLockLoginAttempt(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlLoginAttemptRepository(testDB)
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	_, err := repo.RecordLoginFailure("account", "test@example.com", now, now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, repo.LockLoginAttempt("account", "test@example.com", now.Add(time.Minute)))
+
+	attempt, err := repo.GetLoginAttempt("account", "test@example.com")
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempt.Failures)
+	assert.True(t, attempt.LockedUntil.Equal(now.Add(time.Minute)))
+}
+
+func TestDeleteLoginAttempt(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlLoginAttemptRepository(testDB)
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	_, err := repo.RecordLoginFailure("account", "test@example.com", now, now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, repo.DeleteLoginAttempt("account", "test@example.com"))
+
+	attempt, err := repo.GetLoginAttempt("account", "test@example.com")
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, attempt.Failures)
+	assert.True(t, attempt.LockedUntil.IsZero())
+}
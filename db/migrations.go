@@ -0,0 +1,262 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// migration upgrades databases created by an earlier version of the schema.
+// createTablesForDB creates missing tables in their current shape; the
+// migrations then add what older tables lack. The version reached is kept
+// in PRAGMA user_version, so every migration runs once. Columns are only
+// added when they are missing, as tables created by createTablesForDB
+// already have them.
+type migration struct {
+	version int
+	up      func(*sql.Tx) error
+}
+
+var migrations = []migration{
+	{version: 1, up: func(tx *sql.Tx) error {
+		added, err := addColumn(tx, "users", "verified BOOLEAN NOT NULL DEFAULT 0")
+		if err != nil || !added {
+			return err
+		}
+		// Accounts made before email verification existed stay usable.
+		_, err = tx.Exec(`UPDATE users SET verified = 1;`)
+		return err
+	}},
+	{version: 2, up: func(tx *sql.Tx) error {
+		_, err := addColumn(tx, "users", "token_version INTEGER NOT NULL DEFAULT 0")
+		return err
+	}},
+	{version: 3, up: func(tx *sql.Tx) error {
+		_, err := addColumn(tx, "users", "deleted_at DATETIME")
+		if err != nil {
+			return err
+		}
+		added, err := addColumn(tx, "email_verifications", "email TEXT NOT NULL DEFAULT ''")
+		if err != nil || !added {
+			return err
+		}
+		_, err = tx.Exec(`
+			UPDATE email_verifications
+			SET email = (SELECT email FROM users WHERE users.id = email_verifications.user_id);`)
+		return err
+	}},
+	{version: 4, up: addDefaultOrganization},
+	{version: 5, up: func(tx *sql.Tx) error {
+		_, err := addColumn(tx, "events", "visibility TEXT NOT NULL DEFAULT 'public'")
+		return err
+	}},
+	{version: 6, up: func(tx *sql.Tx) error {
+		_, err := addColumns(tx, "events",
+			"capacity INTEGER NOT NULL DEFAULT 0",
+			"requires_approval BOOLEAN NOT NULL DEFAULT 0",
+		)
+		if err != nil {
+			return err
+		}
+		_, err = addColumn(tx, "registrations", "status TEXT NOT NULL DEFAULT 'approved'")
+		if err != nil {
+			return err
+		}
+		// Added columns cannot default to CURRENT_TIMESTAMP, so registrations
+		// made before are dated to the upgrade.
+		added, err := addColumn(tx, "registrations", "created_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00'")
+		if err != nil || !added {
+			return err
+		}
+		_, err = tx.Exec(`UPDATE registrations SET created_at = CURRENT_TIMESTAMP;`)
+		return err
+	}},
+	{version: 7, up: func(tx *sql.Tx) error {
+		_, err := addColumn(tx, "registrations", "ticket_type_id INTEGER REFERENCES ticket_types(id)")
+		return err
+	}},
+	{version: 8, up: func(tx *sql.Tx) error {
+		_, err := addColumns(tx, "registrations",
+			"promo_code_id INTEGER REFERENCES promo_codes(id)",
+			"amount INTEGER NOT NULL DEFAULT 0",
+		)
+		return err
+	}},
+	{version: 9, up: func(tx *sql.Tx) error {
+		_, err := addColumn(tx, "orders", "refunded_amount INTEGER NOT NULL DEFAULT 0")
+		return err
+	}},
+	{version: 10, up: func(tx *sql.Tx) error {
+		_, err := addColumn(tx, "registrations", "checked_in_at DATETIME")
+		return err
+	}},
+	{version: 11, up: func(tx *sql.Tx) error {
+		_, err := addColumns(tx, "organizations",
+			"deprioritize_no_shows BOOLEAN NOT NULL DEFAULT 0",
+			"no_show_threshold INTEGER NOT NULL DEFAULT 2",
+		)
+		return err
+	}},
+	{version: 12, up: func(tx *sql.Tx) error {
+		_, err := addColumns(tx, "registrations",
+			"group_id INTEGER REFERENCES registration_groups(id)",
+			"guest_name TEXT NOT NULL DEFAULT ''",
+			"guest_email TEXT NOT NULL DEFAULT ''",
+		)
+		return err
+	}},
+	{version: 13, up: uniqueActiveRegistrations},
+	{version: 14, up: func(tx *sql.Tx) error {
+		_, err := addColumn(tx, "orders", "group_id INTEGER REFERENCES registration_groups(id)")
+		return err
+	}},
+}
+
+// uniqueActiveRegistrations lets a user hold one active registration per
+// event, so concurrent registrations cannot both be inserted. Registrations
+// that concurrent requests already doubled are cancelled, keeping the first.
+func uniqueActiveRegistrations(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		UPDATE registrations SET status = 'cancelled'
+		WHERE status IN ('awaiting_payment', 'pending', 'approved') AND user_id IS NOT NULL AND EXISTS (
+			SELECT 1 FROM registrations r
+			WHERE r.event_id = registrations.event_id AND r.user_id = registrations.user_id
+				AND r.status IN ('awaiting_payment', 'pending', 'approved') AND r.id < registrations.id
+		);`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		CREATE UNIQUE INDEX IF NOT EXISTS registrations_active
+		ON registrations(user_id, event_id) WHERE status IN ('awaiting_payment', 'pending', 'approved');`)
+	return err
+}
+
+// addDefaultOrganization moves events and registrations made before
+// organizations existed into one organization. Its first user owns it and
+// everyone else can manage events as an admin, as they could before.
+func addDefaultOrganization(tx *sql.Tx) error {
+	addedToEvents, err := addColumn(tx, "events", "organization_id INTEGER NOT NULL DEFAULT 0 REFERENCES organizations(id)")
+	if err != nil {
+		return err
+	}
+	addedToRegistrations, err := addColumn(tx, "registrations", "organization_id INTEGER NOT NULL DEFAULT 0 REFERENCES organizations(id)")
+	if err != nil || (!addedToEvents && !addedToRegistrations) {
+		return err
+	}
+
+	var users int
+	err = tx.QueryRow(`SELECT COUNT(*) FROM users;`).Scan(&users)
+	if err != nil || users == 0 {
+		return err
+	}
+
+	result, err := tx.Exec(`INSERT INTO organizations (name, created_at) VALUES ('Default organization', CURRENT_TIMESTAMP);`)
+	if err != nil {
+		return err
+	}
+	orgId, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	statements := []string{
+		`INSERT INTO organization_members (organization_id, user_id, role, created_at)
+		SELECT ?, id, CASE WHEN id = (SELECT MIN(id) FROM users) THEN 'owner' ELSE 'admin' END, CURRENT_TIMESTAMP
+		FROM users;`,
+		`UPDATE events SET organization_id = ?;`,
+		`UPDATE registrations SET organization_id = ?;`,
+	}
+	for _, statement := range statements {
+		_, err = tx.Exec(statement, orgId)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrate runs the migrations newer than the version of database.
+func migrate(database *sql.DB) error {
+	var version int
+	err := database.QueryRow(`PRAGMA user_version;`).Scan(&version)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if m.version <= version {
+			continue
+		}
+
+		err = runMigration(database, m)
+		if err != nil {
+			return fmt.Errorf("migration %d: %w", m.version, err)
+		}
+	}
+	return nil
+}
+
+func runMigration(database *sql.DB, m migration) error {
+	tx, err := database.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = m.up(tx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(fmt.Sprintf(`PRAGMA user_version = %d;`, m.version))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// addColumn adds a column, given as its definition, to table unless the
+// table already has it. It returns whether the column was added.
+func addColumn(tx *sql.Tx, table, definition string) (bool, error) {
+	name, _, _ := strings.Cut(definition, " ")
+
+	rows, err := tx.Query(`SELECT name FROM pragma_table_info(?);`, table)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var column string
+		err = rows.Scan(&column)
+		if err != nil {
+			return false, err
+		}
+		if column == name {
+			return false, nil
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return false, err
+	}
+
+	_, err = tx.Exec(`ALTER TABLE ` + table + ` ADD COLUMN ` + definition + `;`)
+	return err == nil, err
+}
+
+// addColumns adds the columns that table lacks and returns whether any was
+// added.
+func addColumns(tx *sql.Tx, table string, definitions ...string) (bool, error) {
+	var added bool
+	for _, definition := range definitions {
+		columnAdded, err := addColumn(tx, table, definition)
+		if err != nil {
+			return false, err
+		}
+		added = added || columnAdded
+	}
+	return added, nil
+}
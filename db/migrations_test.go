@@ -0,0 +1,114 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"event-booking/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// baselineSchema is the schema of the first release, before migrations.
+const baselineSchema = `
+	CREATE TABLE users (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		email TEXT NOT NULL UNIQUE,
+		password TEXT NOT NULL
+	);
+	CREATE TABLE events (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		description TEXT NOT NULL,
+		location TEXT NOT NULL,
+		datetime DATETIME NOT NULL,
+		user_id INTEGER NOT NULL,
+		FOREIGN KEY(user_id) REFERENCES users(id)
+	);
+	CREATE TABLE registrations (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		event_id INTEGER,
+		user_id INTEGER,
+		FOREIGN KEY(event_id) REFERENCES events(id),
+		FOREIGN KEY(user_id) REFERENCES users(id)
+	);
+`
+
+func setupBaselineDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(baselineSchema)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO users (email, password) VALUES ('ada@example.com', 'hash'), ('grace@example.com', 'hash');`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO events (name, description, location, datetime, user_id) VALUES (?, ?, ?, ?, 2);`,
+		"Conference", "Description", "Location", time.Now().Add(24*time.Hour))
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO registrations (event_id, user_id) VALUES (1, 1), (1, 1);`)
+	require.NoError(t, err)
+	return db
+}
+
+func TestMigrate_UpgradesBaselineDatabase(t *testing.T) {
+	testDB := setupBaselineDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	require.NoError(t, createTablesForDB(testDB))
+
+	var version int
+	require.NoError(t, testDB.QueryRow(`PRAGMA user_version;`).Scan(&version))
+	assert.Equal(t, migrations[len(migrations)-1].version, version)
+
+	userRepo := NewSqlUserRepository(testDB)
+	user, err := userRepo.GetUserByEmail("ada@example.com")
+	require.NoError(t, err)
+	assert.True(t, user.Verified, "Existing accounts stay usable")
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	memberships, err := orgRepo.GetMemberships(1)
+	require.NoError(t, err)
+	require.Len(t, memberships, 1)
+	assert.Equal(t, "owner", memberships[0].Role)
+	orgId := memberships[0].Id
+	membership, err := orgRepo.GetMembership(orgId, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "admin", membership.Role)
+
+	eventRepo := NewSqlEventRepository(testDB)
+	event, err := eventRepo.GetEventById(orgId, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "public", event.Visibility)
+	assert.Equal(t, int64(2), event.UserId)
+
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+	registration, err := registerRepo.GetRegisteredEventById(orgId, 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "approved", registration.Status)
+	assert.False(t, registration.CreatedAt.IsZero())
+	doubled, err := registerRepo.GetRegistration(orgId, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "cancelled", doubled.Status, "Doubled registrations are cancelled")
+
+	registration = *newTestRegistration(orgId, 2, 1)
+	registered, err := registerRepo.RegisterEvent(&registration)
+	require.NoError(t, err)
+	assert.True(t, registered, "Upgraded tables take new registrations")
+
+	_, err = userRepo.CreateUser(&models.User{Email: "new@example.com", Password: "password123"})
+	require.NoError(t, err)
+}
+
+func TestMigrate_RunsOnce(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	require.NoError(t, createTablesForDB(testDB), "Creating the tables again leaves them alone")
+
+	var organizations int
+	require.NoError(t, testDB.QueryRow(`SELECT COUNT(*) FROM organizations;`).Scan(&organizations))
+	assert.Zero(t, organizations, "New databases get no default organization")
+}
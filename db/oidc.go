@@ -0,0 +1,148 @@
+package db
+
+import (
+	"database/sql"
+	"event-booking/models"
+	"time"
+)
+
+type SqlOIDCRepository struct {
+	db       *sql.DB
+	userRepo *SqlUserRepository
+}
+
+func NewSqlOIDCRepository(database *sql.DB) *SqlOIDCRepository {
+	return &SqlOIDCRepository{
+		db:       database,
+		userRepo: NewSqlUserRepository(database),
+	}
+}
+
+func (r *SqlOIDCRepository) GetUserByEmail(email string) (models.User, error) {
+	return r.userRepo.GetUserByEmail(email)
+}
+
+func (r *SqlOIDCRepository) CreateOIDCLoginState(s *models.OIDCLoginState) error {
+	query := `
+	INSERT INTO oidc_login_states (state_hash, provider, nonce, code_verifier, expires_at)
+	VALUES (?, ?, ?, ?, ?);
+	`
+	_, err := r.db.Exec(query, s.StateHash, s.Provider, s.Nonce, s.CodeVerifier, s.ExpiresAt)
+	return err
+}
+
+// ConsumeOIDCLoginState deletes and returns the login state so it can only be
+// used once. Expired states of every login are removed along the way. It
+// reports false when the state is unknown, expired or belongs to another
+// provider.
+func (r *SqlOIDCRepository) ConsumeOIDCLoginState(stateHash, provider string, now time.Time) (models.OIDCLoginState, bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return models.OIDCLoginState{}, false, err
+	}
+	defer tx.Rollback()
+
+	query := `
+	SELECT state_hash, provider, nonce, code_verifier, expires_at
+	FROM oidc_login_states WHERE state_hash = ?;
+	`
+	var s models.OIDCLoginState
+	err = tx.QueryRow(query, stateHash).Scan(&s.StateHash, &s.Provider, &s.Nonce, &s.CodeVerifier, &s.ExpiresAt)
+	if err != nil && err != sql.ErrNoRows {
+		return models.OIDCLoginState{}, false, err
+	}
+	found := err == nil && s.Provider == provider && s.ExpiresAt.After(now)
+
+	_, err = tx.Exec(`DELETE FROM oidc_login_states WHERE state_hash = ? OR expires_at <= ?;`, stateHash, now)
+	if err != nil {
+		return models.OIDCLoginState{}, false, err
+	}
+
+	err = tx.Commit()
+	if err != nil || !found {
+		return models.OIDCLoginState{}, false, err
+	}
+	return s, true, nil
+}
+
+func (r *SqlOIDCRepository) GetUserByExternalIdentity(provider, subject string) (models.User, error) {
+	query := selectUsers + `
+	JOIN external_identities e ON e.user_id = u.id
+	WHERE e.provider = ? AND e.subject = ? AND u.deleted_at IS NULL;
+	`
+	return scanUser(r.db.QueryRow(query, provider, subject))
+}
+
+// LinkExternalIdentity links identity to an existing user and marks the
+// user's email as verified. When the account was never verified, its password
+// may have been set by someone else, so it is removed and existing sessions
+// are revoked.
+func (r *SqlOIDCRepository) LinkExternalIdentity(userId int64, identity models.ExternalIdentity, now time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = insertExternalIdentity(tx, userId, identity, now)
+	if err != nil {
+		return err
+	}
+
+	query := `
+	UPDATE users
+	SET password = CASE WHEN verified = 0 THEN '!' ELSE password END,
+		token_version = CASE WHEN verified = 0 THEN token_version + 1 ELSE token_version END,
+		verified = 1
+	WHERE id = ?;
+	`
+	_, err = tx.Exec(query, userId)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// CreateExternalUser creates a verified user without a password for identity.
+// The password can be set later through the password reset flow.
+func (r *SqlOIDCRepository) CreateExternalUser(identity models.ExternalIdentity, now time.Time) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO users (email, password, verified) SELECT ?1, '!', 1 WHERE NOT EXISTS (SELECT 1 FROM users WHERE ` + emailMatches + `);`
+	result, err := tx.Exec(query, identity.Email)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if affected == 0 {
+		return 0, ErrEmailTaken
+	}
+	userId, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	err = insertExternalIdentity(tx, userId, identity, now)
+	if err != nil {
+		return 0, err
+	}
+
+	return userId, tx.Commit()
+}
+
+func insertExternalIdentity(tx *sql.Tx, userId int64, identity models.ExternalIdentity, now time.Time) error {
+	query := `
+	INSERT INTO external_identities (provider, subject, user_id, email, created_at)
+	VALUES (?, ?, ?, ?, ?);
+	`
+	_, err := tx.Exec(query, identity.Provider, identity.Subject, userId, identity.Email, now)
+	return err
+}
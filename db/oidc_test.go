@@ -0,0 +1,88 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"event-booking/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsumeOIDCLoginState_SingleUse(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlOIDCRepository(testDB)
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	state := models.OIDCLoginState{StateHash: "hash", Provider: "corp", Nonce: "nonce", CodeVerifier: "verifier", ExpiresAt: now.Add(time.Minute)}
+	require.NoError(t, repo.CreateOIDCLoginState(&state))
+
+	consumed, found, err := repo.ConsumeOIDCLoginState("hash", "corp", now)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, "verifier", consumed.CodeVerifier)
+
+	_, found, err = repo.ConsumeOIDCLoginState("hash", "corp", now)
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestConsumeOIDCLoginState_WrongProviderOrExpired(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlOIDCRepository(testDB)
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, repo.CreateOIDCLoginState(&models.OIDCLoginState{StateHash: "a", Provider: "corp", ExpiresAt: now.Add(time.Minute)}))
+	require.NoError(t, repo.CreateOIDCLoginState(&models.OIDCLoginState{StateHash: "b", Provider: "corp", ExpiresAt: now.Add(-time.Minute)}))
+
+	_, found, err := repo.ConsumeOIDCLoginState("a", "other", now)
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	_, found, err = repo.ConsumeOIDCLoginState("b", "corp", now)
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestCreateExternalUser(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlOIDCRepository(testDB)
+	identity := models.ExternalIdentity{Provider: "corp", Subject: "sub-1", Email: "jane@example.com", EmailVerified: true}
+
+	id, err := repo.CreateExternalUser(identity, time.Now())
+	require.NoError(t, err)
+
+	u, err := repo.GetUserByExternalIdentity("corp", "sub-1")
+	require.NoError(t, err)
+	assert.Equal(t, id, u.Id)
+	assert.True(t, u.Verified)
+	assert.Equal(t, "!", u.Password)
+}
+
+func TestLinkExternalIdentity_DropsPasswordOfUnverifiedAccount(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	userRepo := NewSqlUserRepository(testDB)
+	repo := NewSqlOIDCRepository(testDB)
+
+	id, err := userRepo.CreateUser(&models.User{Email: "jane@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	identity := models.ExternalIdentity{Provider: "corp", Subject: "sub-1", Email: "jane@example.com", EmailVerified: true}
+	require.NoError(t, repo.LinkExternalIdentity(id, identity, time.Now()))
+
+	u, err := repo.GetUserByExternalIdentity("corp", "sub-1")
+	require.NoError(t, err)
+	assert.Equal(t, id, u.Id)
+	assert.True(t, u.Verified)
+	assert.Equal(t, "!", u.Password)
+	assert.Equal(t, int64(1), u.TokenVersion)
+}
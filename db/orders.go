@@ -0,0 +1,260 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"event-booking/models"
+	"time"
+)
+
+type SqlOrderRepository struct {
+	db           *sql.DB
+	eventRepo    *SqlEventRepository
+	registerRepo *SqlEventRegisterRepository
+}
+
+func NewSqlOrderRepository(database *sql.DB) *SqlOrderRepository {
+	return &SqlOrderRepository{
+		db:           database,
+		eventRepo:    NewSqlEventRepository(database),
+		registerRepo: NewSqlEventRegisterRepository(database),
+	}
+}
+
+func (r *SqlOrderRepository) GetEventById(orgId, id int64) (models.Event, error) {
+	return r.eventRepo.GetEventById(orgId, id)
+}
+
+func (r *SqlOrderRepository) GetTicketType(orgId, eventId, id int64) (models.TicketType, error) {
+	return r.eventRepo.GetTicketType(orgId, eventId, id)
+}
+
+func (r *SqlOrderRepository) GetRegisteredEventById(orgId, userId, eventId int64) (models.RegisterEvent, error) {
+	return r.registerRepo.GetRegisteredEventById(orgId, userId, eventId)
+}
+
+func (r *SqlOrderRepository) CancelRegistration(orgId, id int64) error {
+	return r.registerRepo.CancelRegistration(orgId, id)
+}
+
+func (r *SqlOrderRepository) GetGroup(orgId, id int64) (models.RegistrationGroup, error) {
+	return r.registerRepo.GetGroup(orgId, id)
+}
+
+const selectOrders = `
+	SELECT id, registration_id, group_id, user_id, event_id, organization_id, amount, currency, refunded_amount,
+		status, payment_intent_id, client_secret, created_at, updated_at
+	FROM orders
+`
+
+func scanOrder(row rowScanner) (models.Order, error) {
+	var o models.Order
+	var groupId sql.NullInt64
+	err := row.Scan(&o.Id, &o.RegistrationId, &groupId, &o.UserId, &o.EventId, &o.OrganizationId, &o.Amount,
+		&o.Currency, &o.RefundedAmount, &o.Status, &o.PaymentIntentId, &o.ClientSecret, &o.CreatedAt, &o.UpdatedAt)
+	o.GroupId = groupId.Int64
+	return o, err
+}
+
+var ErrPaymentIntentTaken = errors.New("Payment intent belongs to another order")
+
+// CreateOrder inserts the order. An order for the same payment intent is
+// only stored once; the existing order is returned in its place when it is
+// for the same registration or group and amount, and ErrPaymentIntentTaken
+// when not.
+func (r *SqlOrderRepository) CreateOrder(o *models.Order) error {
+	var groupId sql.NullInt64
+	if o.GroupId != 0 {
+		groupId = sql.NullInt64{Int64: o.GroupId, Valid: true}
+	}
+	query := `
+	INSERT INTO orders (registration_id, group_id, user_id, event_id, organization_id, amount, currency, status,
+		payment_intent_id, client_secret, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT(payment_intent_id) DO NOTHING;
+	`
+	_, err := r.db.Exec(query, o.RegistrationId, groupId, o.UserId, o.EventId, o.OrganizationId, o.Amount, o.Currency,
+		o.Status, o.PaymentIntentId, o.ClientSecret, o.CreatedAt, o.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	existing, err := r.GetOrderByPaymentIntent(o.PaymentIntentId)
+	if err != nil {
+		return err
+	}
+	if existing.RegistrationId != o.RegistrationId || existing.GroupId != o.GroupId ||
+		existing.OrganizationId != o.OrganizationId || existing.UserId != o.UserId || existing.Amount != o.Amount {
+		return ErrPaymentIntentTaken
+	}
+	*o = existing
+	return nil
+}
+
+func (r *SqlOrderRepository) GetOrder(orgId, id int64) (models.Order, error) {
+	query := selectOrders + `WHERE id = ? AND organization_id = ?;`
+	return scanOrder(r.db.QueryRow(query, id, orgId))
+}
+
+// GetOpenOrder returns the pending or failed order of a registration.
+func (r *SqlOrderRepository) GetOpenOrder(orgId, registrationId int64) (models.Order, error) {
+	query := selectOrders + `WHERE registration_id = ? AND organization_id = ? AND status IN ('pending', 'failed');`
+	return scanOrder(r.db.QueryRow(query, registrationId, orgId))
+}
+
+// GetOpenGroupOrder returns the pending or failed order of a group.
+func (r *SqlOrderRepository) GetOpenGroupOrder(orgId, groupId int64) (models.Order, error) {
+	query := selectOrders + `WHERE group_id = ? AND organization_id = ? AND status IN ('pending', 'failed');`
+	return scanOrder(r.db.QueryRow(query, groupId, orgId))
+}
+
+func (r *SqlOrderRepository) GetOrderByPaymentIntent(intentId string) (models.Order, error) {
+	query := selectOrders + `WHERE payment_intent_id = ?;`
+	return scanOrder(r.db.QueryRow(query, intentId))
+}
+
+// SetOrderStatus moves the order from status from to status to. It returns
+// false when the order no longer has status from. Orders moved to refunded
+// are refunded in full.
+func (r *SqlOrderRepository) SetOrderStatus(id int64, from, to string, updatedAt time.Time) (bool, error) {
+	query := `
+	UPDATE orders SET status = ?, updated_at = ?,
+		refunded_amount = CASE WHEN ? = 'refunded' THEN amount ELSE refunded_amount END
+	WHERE id = ? AND status = ?;
+	`
+	result, err := r.db.Exec(query, to, updatedAt, to, id, from)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// ConfirmRegistration moves a registration awaiting payment to status.
+// Approved registrations need a free seat in the event and in the ticket
+// type; false is returned and nothing changes when there is none or when
+// the registration no longer awaits payment.
+func (r *SqlOrderRepository) ConfirmRegistration(orgId, id int64, status string) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE registrations SET status = ?
+		WHERE id = ? AND organization_id = ? AND status = 'awaiting_payment';
+	`
+	result, err := tx.Exec(query, status, id, orgId)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		return false, err
+	}
+
+	if status == "approved" {
+		var full bool
+		query := `
+			SELECT ` + overbooked + ` FROM events e
+			WHERE e.id = (SELECT event_id FROM registrations WHERE id = ?) AND e.organization_id = ?;
+		`
+		err = tx.QueryRow(query, id, orgId).Scan(&full)
+		if err != nil || full {
+			return false, err
+		}
+	}
+
+	return true, tx.Commit()
+}
+
+// ConfirmGroup moves the registrations of a group that await payment to
+// status, all or none, like ConfirmRegistration does for one registration.
+func (r *SqlOrderRepository) ConfirmGroup(orgId, groupId int64, status string) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE registrations SET status = ?
+		WHERE group_id = ? AND organization_id = ? AND status = 'awaiting_payment';
+	`
+	result, err := tx.Exec(query, status, groupId, orgId)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		return false, err
+	}
+
+	if status == "approved" {
+		var full bool
+		query := `
+			SELECT ` + overbooked + ` FROM events e
+			WHERE e.id = (SELECT event_id FROM registration_groups WHERE id = ?) AND e.organization_id = ?;
+		`
+		err = tx.QueryRow(query, groupId, orgId).Scan(&full)
+		if err != nil || full {
+			return false, err
+		}
+	}
+
+	return true, tx.Commit()
+}
+
+// AwaitsPayment reports whether the registration, or a registration of the
+// group, still awaits payment.
+func (r *SqlOrderRepository) AwaitsPayment(orgId, registrationId, groupId int64) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM registrations
+			WHERE organization_id = ?1 AND status = 'awaiting_payment' AND (id = ?2 OR group_id = ?3)
+		);
+	`
+	var awaiting bool
+	err := r.db.QueryRow(query, orgId, registrationId, groupId).Scan(&awaiting)
+	return awaiting, err
+}
+
+// CancelGroupRegistrations cancels the active registrations of a group, like
+// CancelRegistration does for one registration.
+func (r *SqlOrderRepository) CancelGroupRegistrations(orgId, groupId int64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`SELECT id FROM registrations WHERE group_id = ? AND organization_id = ?;`, groupId, orgId)
+	if err != nil {
+		return err
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		err = rows.Scan(&id)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		_, err = cancelRegistration(tx, orgId, id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
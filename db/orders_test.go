@@ -0,0 +1,170 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"event-booking/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestOrder(registration *models.RegisterEvent, intentId string) *models.Order {
+	now := time.Now()
+	return &models.Order{
+		RegistrationId: registration.Id, UserId: registration.UserId, EventId: registration.EventId,
+		OrganizationId: registration.OrganizationId, Amount: 2500, Currency: "EUR", Status: "pending",
+		PaymentIntentId: intentId, ClientSecret: intentId + "_secret", CreatedAt: now, UpdatedAt: now,
+	}
+}
+
+func TestCreateOrder_OncePerPaymentIntent(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+	repo := NewSqlOrderRepository(testDB)
+
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	eventId := createTestOrgEvent(t, eventRepo, orgId, "Conference")
+	registration := newTestRegistration(orgId, 5, eventId)
+	registration.Status = "awaiting_payment"
+	_, err := registerRepo.RegisterEvent(registration)
+	require.NoError(t, err)
+
+	first := newTestOrder(registration, "pi_fake_1")
+	require.NoError(t, repo.CreateOrder(first))
+	second := newTestOrder(registration, "pi_fake_1")
+	require.NoError(t, repo.CreateOrder(second))
+	assert.Equal(t, first.Id, second.Id)
+
+	open, err := repo.GetOpenOrder(orgId, registration.Id)
+	require.NoError(t, err)
+	assert.Equal(t, first.Id, open.Id)
+	assert.Equal(t, int64(2500), open.Amount)
+
+	_, err = repo.GetOrder(orgId+1, first.Id)
+	assert.Error(t, err, "Orders of other organizations are not visible")
+
+	updated, err := repo.SetOrderStatus(first.Id, "pending", "paid", time.Now())
+	require.NoError(t, err)
+	assert.True(t, updated)
+	updated, err = repo.SetOrderStatus(first.Id, "pending", "failed", time.Now())
+	require.NoError(t, err)
+	assert.False(t, updated, "Only orders in the expected status change")
+
+	_, err = repo.GetOpenOrder(orgId, registration.Id)
+	assert.Error(t, err, "Paid orders are not open")
+}
+
+func TestCreateOrder_PaymentIntentOfOtherRegistration(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+	repo := NewSqlOrderRepository(testDB)
+
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	eventId := createTestOrgEvent(t, eventRepo, orgId, "Conference")
+	first := newTestRegistration(orgId, 5, eventId)
+	first.Status = "awaiting_payment"
+	_, err := registerRepo.RegisterEvent(first)
+	require.NoError(t, err)
+	second := newTestRegistration(orgId, 6, eventId)
+	second.Status = "awaiting_payment"
+	_, err = registerRepo.RegisterEvent(second)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.CreateOrder(newTestOrder(first, "pi_fake_1")))
+
+	other := newTestOrder(second, "pi_fake_1")
+	err = repo.CreateOrder(other)
+	assert.Equal(t, ErrPaymentIntentTaken, err)
+	assert.Zero(t, other.Id, "The order of another user is not handed out")
+
+	changed := newTestOrder(first, "pi_fake_1")
+	changed.Amount = 100
+	assert.Equal(t, ErrPaymentIntentTaken, repo.CreateOrder(changed))
+}
+
+func TestConfirmRegistration_NeedsFreeSeat(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+	repo := NewSqlOrderRepository(testDB)
+
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	eventId := createTestOrgEvent(t, eventRepo, orgId, "Conference")
+	vipId := createTestTicketType(t, eventRepo, orgId, eventId, "VIP", 9900, 1)
+
+	ids := []int64{}
+	for userId := int64(5); userId <= 6; userId++ {
+		registration := newTestRegistration(orgId, userId, eventId)
+		registration.TicketTypeId = vipId
+		registration.Status = "awaiting_payment"
+		registered, err := registerRepo.RegisterEvent(registration)
+		require.NoError(t, err)
+		assert.True(t, registered, "Registrations awaiting payment do not take a seat")
+		ids = append(ids, registration.Id)
+	}
+
+	confirmed, err := repo.ConfirmRegistration(orgId, ids[0], "approved")
+	require.NoError(t, err)
+	assert.True(t, confirmed)
+
+	confirmed, err = repo.ConfirmRegistration(orgId, ids[1], "approved")
+	require.NoError(t, err)
+	assert.False(t, confirmed, "The last ticket was taken by the first payment")
+
+	registration, err := registerRepo.GetRegisteredEventById(orgId, 6, eventId)
+	require.NoError(t, err)
+	assert.Equal(t, "awaiting_payment", registration.Status)
+
+	confirmed, err = repo.ConfirmRegistration(orgId, ids[0], "approved")
+	require.NoError(t, err)
+	assert.False(t, confirmed, "Only registrations awaiting payment are confirmed")
+
+	awaiting, err := repo.AwaitsPayment(orgId, ids[0], 0)
+	require.NoError(t, err)
+	assert.False(t, awaiting)
+	awaiting, err = repo.AwaitsPayment(orgId, ids[1], 0)
+	require.NoError(t, err)
+	assert.True(t, awaiting)
+	awaiting, err = repo.AwaitsPayment(orgId+1, ids[1], 0)
+	require.NoError(t, err)
+	assert.False(t, awaiting, "Registrations of other organizations are not seen")
+}
+
+func TestCancelRegistration_CancelsOpenOrder(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+	repo := NewSqlOrderRepository(testDB)
+
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	eventId := createTestOrgEvent(t, eventRepo, orgId, "Conference")
+	registration := newTestRegistration(orgId, 5, eventId)
+	registration.Status = "awaiting_payment"
+	_, err := registerRepo.RegisterEvent(registration)
+	require.NoError(t, err)
+
+	order := newTestOrder(registration, "pi_fake_1")
+	require.NoError(t, repo.CreateOrder(order))
+
+	require.NoError(t, registerRepo.CancelRegistration(orgId, registration.Id))
+
+	cancelled, err := repo.GetOrderByPaymentIntent("pi_fake_1")
+	require.NoError(t, err)
+	assert.Equal(t, "cancelled", cancelled.Status)
+}
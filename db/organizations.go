@@ -0,0 +1,183 @@
+package db
+
+import (
+	"database/sql"
+	"event-booking/models"
+)
+
+type SqlOrganizationRepository struct {
+	db        *sql.DB
+	userRepo  *SqlUserRepository
+	eventRepo *SqlEventRepository
+}
+
+func NewSqlOrganizationRepository(database *sql.DB) *SqlOrganizationRepository {
+	return &SqlOrganizationRepository{
+		db:        database,
+		userRepo:  NewSqlUserRepository(database),
+		eventRepo: NewSqlEventRepository(database),
+	}
+}
+
+const selectMemberships = `
+	SELECT o.id, o.name, o.deprioritize_no_shows, o.no_show_threshold, o.created_at, m.role
+	FROM organizations o
+	JOIN organization_members m ON m.organization_id = o.id
+`
+
+func scanMembership(row rowScanner) (models.Membership, error) {
+	var m models.Membership
+	err := row.Scan(&m.Id, &m.Name, &m.DeprioritizeNoShows, &m.NoShowThreshold, &m.CreatedAt, &m.Role)
+	return m, err
+}
+
+const selectMembers = `
+	SELECT m.organization_id, m.user_id, u.email, m.role, m.created_at
+	FROM organization_members m
+	JOIN users u ON u.id = m.user_id
+`
+
+func scanMember(row rowScanner) (models.OrganizationMember, error) {
+	var m models.OrganizationMember
+	err := row.Scan(&m.OrganizationId, &m.UserId, &m.Email, &m.Role, &m.CreatedAt)
+	return m, err
+}
+
+func (r *SqlOrganizationRepository) GetUserByEmail(email string) (models.User, error) {
+	return r.userRepo.GetUserByEmail(email)
+}
+
+// CreateOrganization creates org with ownerId as its first owner.
+func (r *SqlOrganizationRepository) CreateOrganization(org *models.Organization, ownerId int64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+	INSERT INTO organizations (name, deprioritize_no_shows, no_show_threshold, created_at)
+	VALUES (?, ?, ?, ?);
+	`
+	result, err := tx.Exec(query, org.Name, org.DeprioritizeNoShows, org.NoShowThreshold, org.CreatedAt)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	query = `
+	INSERT INTO organization_members (organization_id, user_id, role, created_at)
+	VALUES (?, ?, 'owner', ?);
+	`
+	_, err = tx.Exec(query, id, ownerId, org.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+	org.Id = id
+	return nil
+}
+
+func (r *SqlOrganizationRepository) UpdateOrganization(org *models.Organization) error {
+	query := `
+	UPDATE organizations SET name = ?, deprioritize_no_shows = ?, no_show_threshold = ?
+	WHERE id = ?;
+	`
+	_, err := r.db.Exec(query, org.Name, org.DeprioritizeNoShows, org.NoShowThreshold, org.Id)
+	return err
+}
+
+func (r *SqlOrganizationRepository) GetOrganization(id int64) (models.Organization, error) {
+	var org models.Organization
+	query := `SELECT id, name, deprioritize_no_shows, no_show_threshold, created_at FROM organizations WHERE id = ?;`
+	err := r.db.QueryRow(query, id).Scan(&org.Id, &org.Name, &org.DeprioritizeNoShows, &org.NoShowThreshold, &org.CreatedAt)
+	return org, err
+}
+
+// GetMemberships returns the organizations userId belongs to.
+func (r *SqlOrganizationRepository) GetMemberships(userId int64) ([]models.Membership, error) {
+	rows, err := r.db.Query(selectMemberships+`WHERE m.user_id = ? ORDER BY o.id;`, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	memberships := []models.Membership{}
+	for rows.Next() {
+		m, err := scanMembership(rows)
+		if err != nil {
+			return nil, err
+		}
+		memberships = append(memberships, m)
+	}
+
+	return memberships, nil
+}
+
+// GetMembership returns organization orgId, but only when userId is a member.
+func (r *SqlOrganizationRepository) GetMembership(orgId, userId int64) (models.Membership, error) {
+	return scanMembership(r.db.QueryRow(selectMemberships+`WHERE o.id = ? AND m.user_id = ?;`, orgId, userId))
+}
+
+func (r *SqlOrganizationRepository) GetMembers(orgId int64) ([]models.OrganizationMember, error) {
+	rows, err := r.db.Query(selectMembers+`WHERE m.organization_id = ? AND u.deleted_at IS NULL ORDER BY m.created_at, m.user_id;`, orgId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []models.OrganizationMember{}
+	for rows.Next() {
+		m, err := scanMember(rows)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+
+	return members, nil
+}
+
+func (r *SqlOrganizationRepository) GetMember(orgId, userId int64) (models.OrganizationMember, error) {
+	return scanMember(r.db.QueryRow(selectMembers+`WHERE m.organization_id = ? AND m.user_id = ?;`, orgId, userId))
+}
+
+func (r *SqlOrganizationRepository) AddMember(m *models.OrganizationMember) error {
+	query := `
+	INSERT INTO organization_members (organization_id, user_id, role, created_at)
+	VALUES (?, ?, ?, ?);
+	`
+	_, err := r.db.Exec(query, m.OrganizationId, m.UserId, m.Role, m.CreatedAt)
+	return err
+}
+
+func (r *SqlOrganizationRepository) UpdateMemberRole(orgId, userId int64, role string) error {
+	query := `UPDATE organization_members SET role = ? WHERE organization_id = ? AND user_id = ?;`
+	_, err := r.db.Exec(query, role, orgId, userId)
+	return err
+}
+
+func (r *SqlOrganizationRepository) RemoveMember(orgId, userId int64) (bool, error) {
+	query := `DELETE FROM organization_members WHERE organization_id = ? AND user_id = ?;`
+	result, err := r.db.Exec(query, orgId, userId)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func (r *SqlOrganizationRepository) CountOwners(orgId int64) (int, error) {
+	query := `SELECT COUNT(*) FROM organization_members WHERE organization_id = ? AND role = 'owner';`
+	var count int
+	err := r.db.QueryRow(query, orgId).Scan(&count)
+	return count, err
+}
@@ -0,0 +1,200 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"event-booking/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestOrganization(t *testing.T, repo *SqlOrganizationRepository, name string, ownerId int64) int64 {
+	org := &models.Organization{Name: name, CreatedAt: time.Now()}
+	require.NoError(t, repo.CreateOrganization(org, ownerId))
+	return org.Id
+}
+
+func createTestOrgEvent(t *testing.T, repo *SqlEventRepository, orgId int64, name string) int64 {
+	id, err := repo.CreateEvent(&models.Event{
+		Name: name, Description: "Description", Location: "Location",
+		DateTime: time.Now().Add(24 * time.Hour), UserId: 1, OrganizationId: orgId, Visibility: "public",
+	})
+	require.NoError(t, err)
+	return id
+}
+
+func TestCreateOrganization_AddsOwner(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	userRepo := NewSqlUserRepository(testDB)
+	repo := NewSqlOrganizationRepository(testDB)
+
+	userId, err := userRepo.CreateUser(&models.User{Email: "owner@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	orgId := createTestOrganization(t, repo, "Acme", userId)
+
+	membership, err := repo.GetMembership(orgId, userId)
+	require.NoError(t, err)
+	assert.Equal(t, "Acme", membership.Name)
+	assert.Equal(t, "owner", membership.Role)
+
+	members, err := repo.GetMembers(orgId)
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	assert.Equal(t, "owner@example.com", members[0].Email)
+
+	owners, err := repo.CountOwners(orgId)
+	require.NoError(t, err)
+	assert.Equal(t, 1, owners)
+}
+
+func TestGetMembership_NotAMember(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlOrganizationRepository(testDB)
+	orgId := createTestOrganization(t, repo, "Acme", 1)
+
+	_, err := repo.GetMembership(orgId, 2)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestOrganizationMembers(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	userRepo := NewSqlUserRepository(testDB)
+	repo := NewSqlOrganizationRepository(testDB)
+
+	ownerId, _ := userRepo.CreateUser(&models.User{Email: "owner@example.com", Password: "password123"})
+	memberId, _ := userRepo.CreateUser(&models.User{Email: "member@example.com", Password: "password123"})
+	orgId := createTestOrganization(t, repo, "Acme", ownerId)
+
+	err := repo.AddMember(&models.OrganizationMember{OrganizationId: orgId, UserId: memberId, Role: "member", CreatedAt: time.Now()})
+	require.NoError(t, err)
+
+	err = repo.AddMember(&models.OrganizationMember{OrganizationId: orgId, UserId: memberId, Role: "admin", CreatedAt: time.Now()})
+	assert.Error(t, err, "A user can only be a member once")
+
+	require.NoError(t, repo.UpdateMemberRole(orgId, memberId, "admin"))
+	member, err := repo.GetMember(orgId, memberId)
+	require.NoError(t, err)
+	assert.Equal(t, "admin", member.Role)
+	assert.Equal(t, "member@example.com", member.Email)
+
+	memberships, err := repo.GetMemberships(memberId)
+	require.NoError(t, err)
+	require.Len(t, memberships, 1)
+	assert.Equal(t, orgId, memberships[0].Id)
+
+	removed, err := repo.RemoveMember(orgId, memberId)
+	require.NoError(t, err)
+	assert.True(t, removed)
+
+	removed, err = repo.RemoveMember(orgId, memberId)
+	require.NoError(t, err)
+	assert.False(t, removed)
+}
+
+func TestDeleteUser_RemovesMemberships(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	userRepo := NewSqlUserRepository(testDB)
+	repo := NewSqlOrganizationRepository(testDB)
+
+	userId, _ := userRepo.CreateUser(&models.User{Email: "owner@example.com", Password: "password123"})
+	otherId, _ := userRepo.CreateUser(&models.User{Email: "other@example.com", Password: "password123"})
+	orgId := createTestOrganization(t, repo, "Acme", userId)
+	require.NoError(t, repo.AddMember(&models.OrganizationMember{OrganizationId: orgId, UserId: otherId, Role: "admin", CreatedAt: time.Now()}))
+
+	deleted, err := userRepo.DeleteUser(userId, time.Now())
+	require.NoError(t, err)
+	assert.False(t, deleted, "The last owner of an organization is not deleted")
+	_, err = repo.GetMembership(orgId, userId)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.UpdateMemberRole(orgId, otherId, "owner"))
+	deleted, err = userRepo.DeleteUser(userId, time.Now())
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	_, err = repo.GetMembership(orgId, userId)
+	assert.Error(t, err)
+}
+
+func TestTenantIsolation_Events(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	repo := NewSqlEventRepository(testDB)
+
+	orgA := createTestOrganization(t, orgRepo, "Team A", 1)
+	orgB := createTestOrganization(t, orgRepo, "Team B", 2)
+	eventA := createTestOrgEvent(t, repo, orgA, "Event A")
+	eventB := createTestOrgEvent(t, repo, orgB, "Event B")
+
+	events, err := repo.GetEvents(orgA, 1)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, eventA, events[0].Id)
+	assert.Equal(t, orgA, events[0].OrganizationId)
+
+	_, err = repo.GetEventById(orgA, eventB)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "Events of other organizations must not be found")
+
+	err = repo.UpdateEvent(&models.Event{
+		Id: eventB, OrganizationId: orgA, Name: "Hijacked", Description: "Hijacked",
+		Location: "Hijacked", DateTime: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	event, err := repo.GetEventById(orgB, eventB)
+	require.NoError(t, err)
+	assert.Equal(t, "Event B", event.Name, "Events of other organizations must not be updated")
+
+	_, err = repo.DeleteEvent(orgA, eventB)
+	require.NoError(t, err)
+	_, err = repo.GetEventById(orgB, eventB)
+	assert.NoError(t, err, "Events of other organizations must not be deleted")
+}
+
+func TestTenantIsolation_Registrations(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	repo := NewSqlEventRegisterRepository(testDB)
+
+	orgA := createTestOrganization(t, orgRepo, "Team A", 1)
+	orgB := createTestOrganization(t, orgRepo, "Team B", 2)
+	eventB := createTestOrgEvent(t, eventRepo, orgB, "Event B")
+	userId := int64(5)
+
+	registered, err := repo.RegisterEvent(newTestRegistration(orgA, userId, eventB))
+	require.NoError(t, err)
+	assert.False(t, registered, "Events of other organizations must not be registered for")
+
+	_, err = repo.GetEventById(orgA, eventB)
+	assert.Error(t, err)
+
+	_, err = repo.RegisterEvent(newTestRegistration(orgB, userId, eventB))
+	require.NoError(t, err)
+
+	_, err = repo.GetRegisteredEventById(orgA, userId, eventB)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "Registrations of other organizations must not be found")
+
+	registration, err := repo.GetRegisteredEventById(orgB, userId, eventB)
+	require.NoError(t, err)
+	assert.Equal(t, orgB, registration.OrganizationId)
+
+	require.NoError(t, repo.CancelRegistration(orgA, registration.Id))
+	_, err = repo.GetRegisteredEventById(orgB, userId, eventB)
+	assert.NoError(t, err, "Registrations of other organizations must not be cancelled")
+}
@@ -0,0 +1,120 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"event-booking/models"
+)
+
+const selectPromoCodes = `
+	SELECT id, event_id, organization_id, code, discount_type, discount_value, max_uses, used,
+		valid_from, valid_until, ticket_type_ids
+	FROM promo_codes
+`
+
+func scanPromoCode(row rowScanner) (models.PromoCode, error) {
+	var p models.PromoCode
+	var validFrom, validUntil sql.NullTime
+	var ticketTypeIds string
+	err := row.Scan(&p.Id, &p.EventId, &p.OrganizationId, &p.Code, &p.DiscountType, &p.DiscountValue, &p.MaxUses,
+		&p.Used, &validFrom, &validUntil, &ticketTypeIds)
+	if err != nil {
+		return p, err
+	}
+
+	if validFrom.Valid {
+		p.ValidFrom = &validFrom.Time
+	}
+	if validUntil.Valid {
+		p.ValidUntil = &validUntil.Time
+	}
+	err = json.Unmarshal([]byte(ticketTypeIds), &p.TicketTypeIds)
+	return p, err
+}
+
+func (r *SqlEventRepository) GetPromoCodes(orgId, eventId int64) ([]models.PromoCode, error) {
+	query := selectPromoCodes + `WHERE event_id = ? AND organization_id = ? ORDER BY code;`
+	rows, err := r.db.Query(query, eventId, orgId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	promoCodes := []models.PromoCode{}
+	for rows.Next() {
+		p, err := scanPromoCode(rows)
+		if err != nil {
+			return nil, err
+		}
+		promoCodes = append(promoCodes, p)
+	}
+
+	return promoCodes, nil
+}
+
+func (r *SqlEventRepository) GetPromoCode(orgId, eventId, id int64) (models.PromoCode, error) {
+	query := selectPromoCodes + `WHERE id = ? AND event_id = ? AND organization_id = ?;`
+	return scanPromoCode(r.db.QueryRow(query, id, eventId, orgId))
+}
+
+// GetPromoCodeByCode looks up a promo code of the event. Codes are stored in
+// upper case.
+func (r *SqlEventRepository) GetPromoCodeByCode(orgId, eventId int64, code string) (models.PromoCode, error) {
+	query := selectPromoCodes + `WHERE code = ? AND event_id = ? AND organization_id = ?;`
+	return scanPromoCode(r.db.QueryRow(query, code, eventId, orgId))
+}
+
+func (r *SqlEventRepository) CreatePromoCode(p *models.PromoCode) error {
+	ticketTypeIds, err := json.Marshal(p.TicketTypeIds)
+	if err != nil {
+		return err
+	}
+
+	query := `
+	INSERT INTO promo_codes (event_id, organization_id, code, discount_type, discount_value, max_uses,
+		valid_from, valid_until, ticket_type_ids)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
+	`
+	result, err := r.db.Exec(query, p.EventId, p.OrganizationId, p.Code, p.DiscountType, p.DiscountValue, p.MaxUses,
+		p.ValidFrom, p.ValidUntil, string(ticketTypeIds))
+	if err != nil {
+		return err
+	}
+
+	p.Id, err = result.LastInsertId()
+	return err
+}
+
+// UpdatePromoCode changes the promo code but not how often it was used.
+func (r *SqlEventRepository) UpdatePromoCode(p *models.PromoCode) error {
+	ticketTypeIds, err := json.Marshal(p.TicketTypeIds)
+	if err != nil {
+		return err
+	}
+
+	query := `
+	UPDATE promo_codes
+	SET code = ?, discount_type = ?, discount_value = ?, max_uses = ?, valid_from = ?, valid_until = ?,
+		ticket_type_ids = ?
+	WHERE id = ? AND event_id = ? AND organization_id = ?;
+	`
+	_, err = r.db.Exec(query, p.Code, p.DiscountType, p.DiscountValue, p.MaxUses, p.ValidFrom, p.ValidUntil,
+		string(ticketTypeIds), p.Id, p.EventId, p.OrganizationId)
+	return err
+}
+
+// DeletePromoCode deletes the promo code unless a registration used it, even
+// one that was cancelled since, in which case false is returned.
+func (r *SqlEventRepository) DeletePromoCode(orgId, eventId, id int64) (bool, error) {
+	query := `
+		DELETE FROM promo_codes WHERE id = ? AND event_id = ? AND organization_id = ?
+		AND NOT EXISTS (SELECT 1 FROM registrations WHERE promo_code_id = promo_codes.id);
+	`
+	result, err := r.db.Exec(query, id, eventId, orgId)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
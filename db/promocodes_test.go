@@ -0,0 +1,245 @@
+package db
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"event-booking/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestPromoCode(t *testing.T, repo *SqlEventRepository, orgId, eventId int64, code string, maxUses int64) int64 {
+	promoCode := &models.PromoCode{
+		EventId: eventId, OrganizationId: orgId, Code: code, DiscountType: "fixed", DiscountValue: 500,
+		MaxUses: maxUses, TicketTypeIds: []int64{},
+	}
+	require.NoError(t, repo.CreatePromoCode(promoCode))
+	return promoCode.Id
+}
+
+func TestPromoCodes_CreateAndUpdate(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	otherOrgId := createTestOrganization(t, orgRepo, "Globex", 1)
+	eventId := createTestOrgEvent(t, eventRepo, orgId, "Conference")
+	regularId := createTestTicketType(t, eventRepo, orgId, eventId, "Regular", 2500, 100)
+
+	validUntil := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	spring := &models.PromoCode{
+		EventId: eventId, OrganizationId: orgId, Code: "SPRING", DiscountType: "percentage", DiscountValue: 10,
+		ValidUntil: &validUntil, TicketTypeIds: []int64{regularId},
+	}
+	require.NoError(t, eventRepo.CreatePromoCode(spring))
+	createTestPromoCode(t, eventRepo, orgId, eventId, "EARLY", 0)
+
+	promoCodes, err := eventRepo.GetPromoCodes(orgId, eventId)
+	require.NoError(t, err)
+	require.Len(t, promoCodes, 2)
+	assert.Equal(t, "EARLY", promoCodes[0].Code, "Promo codes are ordered by code")
+	assert.Equal(t, []int64{}, promoCodes[0].TicketTypeIds)
+	assert.Equal(t, []int64{regularId}, promoCodes[1].TicketTypeIds)
+	require.NotNil(t, promoCodes[1].ValidUntil)
+	assert.True(t, validUntil.Equal(*promoCodes[1].ValidUntil))
+
+	spring.MaxUses = 20
+	spring.Used = 7
+	require.NoError(t, eventRepo.UpdatePromoCode(spring))
+
+	updated, err := eventRepo.GetPromoCodeByCode(orgId, eventId, "SPRING")
+	require.NoError(t, err)
+	assert.Equal(t, int64(20), updated.MaxUses)
+	assert.Equal(t, int64(0), updated.Used, "Updates do not change the usage count")
+
+	_, err = eventRepo.GetPromoCode(otherOrgId, eventId, spring.Id)
+	assert.Error(t, err, "Promo codes of other organizations are not visible")
+
+	err = eventRepo.CreatePromoCode(&models.PromoCode{
+		EventId: eventId, OrganizationId: orgId, Code: "SPRING", DiscountType: "fixed", DiscountValue: 100, TicketTypeIds: []int64{},
+	})
+	assert.Error(t, err, "Codes are unique within an event")
+}
+
+func TestRegisterEvent_PromoCodeUsage(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	eventId := createTestOrgEvent(t, eventRepo, orgId, "Conference")
+	promoCodeId := createTestPromoCode(t, eventRepo, orgId, eventId, "ONCE", 1)
+
+	registration := newTestRegistration(orgId, 5, eventId)
+	registration.PromoCodeId = promoCodeId
+	registration.Amount = 2000
+	registered, err := registerRepo.RegisterEvent(registration)
+	require.NoError(t, err)
+	assert.True(t, registered)
+
+	registration = newTestRegistration(orgId, 6, eventId)
+	registration.PromoCodeId = promoCodeId
+	registered, err = registerRepo.RegisterEvent(registration)
+	require.NoError(t, err)
+	assert.False(t, registered, "Used up promo codes take no more registrations")
+
+	saved, err := registerRepo.GetRegisteredEventById(orgId, 5, eventId)
+	require.NoError(t, err)
+	assert.Equal(t, promoCodeId, saved.PromoCodeId)
+	assert.Equal(t, int64(2000), saved.Amount)
+
+	deleted, err := eventRepo.DeletePromoCode(orgId, eventId, promoCodeId)
+	require.NoError(t, err)
+	assert.False(t, deleted, "Used promo codes are kept")
+}
+
+func TestRegisterEvent_PromoCodeReleased(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	eventId := createTestOrgEvent(t, eventRepo, orgId, "Conference")
+	promoCodeId := createTestPromoCode(t, eventRepo, orgId, eventId, "ONCE", 1)
+
+	register := func(userId int64, status string) *models.RegisterEvent {
+		registration := newTestRegistration(orgId, userId, eventId)
+		registration.PromoCodeId = promoCodeId
+		registration.Status = status
+		registered, err := registerRepo.RegisterEvent(registration)
+		require.NoError(t, err)
+		require.True(t, registered)
+		return registration
+	}
+	used := func() int64 {
+		promoCode, err := eventRepo.GetPromoCode(orgId, eventId, promoCodeId)
+		require.NoError(t, err)
+		return promoCode.Used
+	}
+
+	cancelled := register(5, "approved")
+	require.NoError(t, registerRepo.CancelRegistration(orgId, cancelled.Id))
+	assert.Zero(t, used(), "Cancelling gives the use back")
+	require.NoError(t, registerRepo.CancelRegistration(orgId, cancelled.Id))
+	assert.Zero(t, used(), "Cancelling again gives nothing back")
+
+	rejected := register(6, "pending")
+	changed, err := registerRepo.SetRegistrationStatus(orgId, eventId, []int64{rejected.Id}, "rejected")
+	require.NoError(t, err)
+	require.True(t, changed)
+	assert.Zero(t, used(), "Rejecting gives the use back")
+
+	register(7, "approved")
+	assert.Equal(t, int64(1), used())
+
+	deleted, err := eventRepo.DeletePromoCode(orgId, eventId, promoCodeId)
+	require.NoError(t, err)
+	assert.False(t, deleted, "Promo codes of cancelled registrations are kept")
+}
+
+func TestRegisterEvent_ExpiresUnpaidPromoUses(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+	orderRepo := NewSqlOrderRepository(testDB)
+
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	eventId := createTestOrgEvent(t, eventRepo, orgId, "Conference")
+	promoCodeId := createTestPromoCode(t, eventRepo, orgId, eventId, "THRICE", 3)
+
+	register := func(userId int64, status string, age time.Duration) (*models.RegisterEvent, bool) {
+		registration := newTestRegistration(orgId, userId, eventId)
+		registration.PromoCodeId = promoCodeId
+		registration.Status = status
+		registration.CreatedAt = time.Now().Add(-age)
+		registered, err := registerRepo.RegisterEvent(registration)
+		require.NoError(t, err)
+		return registration, registered
+	}
+
+	stale, _ := register(5, "awaiting_payment", 2*time.Hour)
+	staleOrder := newTestOrder(stale, "pi_fake_1")
+	require.NoError(t, orderRepo.CreateOrder(staleOrder))
+	paid, _ := register(6, "awaiting_payment", 2*time.Hour)
+	paidOrder := newTestOrder(paid, "pi_fake_2")
+	require.NoError(t, orderRepo.CreateOrder(paidOrder))
+	_, err := orderRepo.SetOrderStatus(paidOrder.Id, "pending", "paid", time.Now())
+	require.NoError(t, err)
+	register(9, "awaiting_payment", time.Minute)
+
+	_, registered := register(7, "approved", 0)
+	assert.True(t, registered, "The use of the stale unpaid registration is given back")
+	_, registered = register(8, "approved", 0)
+	assert.False(t, registered, "Recent and paid registrations keep their uses")
+
+	var status string
+	require.NoError(t, testDB.QueryRow(`SELECT status FROM registrations WHERE id = ?;`, stale.Id).Scan(&status))
+	assert.Equal(t, "cancelled", status)
+	order, err := orderRepo.GetOrder(orgId, staleOrder.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "cancelled", order.Status)
+
+	saved, err := registerRepo.GetRegisteredEventById(orgId, 6, eventId)
+	require.NoError(t, err)
+	assert.Equal(t, "awaiting_payment", saved.Status)
+
+	promoCode, err := eventRepo.GetPromoCode(orgId, eventId, promoCodeId)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), promoCode.Used)
+}
+
+func TestRegisterEvent_PromoCodeLimitUnderConcurrency(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	eventId := createTestOrgEvent(t, eventRepo, orgId, "Conference")
+	promoCodeId := createTestPromoCode(t, eventRepo, orgId, eventId, "FIRST3", 3)
+
+	var wg sync.WaitGroup
+	results := make(chan bool, 10)
+	for userId := int64(10); userId < 20; userId++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			registration := newTestRegistration(orgId, userId, eventId)
+			registration.PromoCodeId = promoCodeId
+			registered, err := registerRepo.RegisterEvent(registration)
+			assert.NoError(t, err)
+			results <- registered
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	registrations := 0
+	for registered := range results {
+		if registered {
+			registrations++
+		}
+	}
+	assert.Equal(t, 3, registrations)
+
+	promoCode, err := eventRepo.GetPromoCode(orgId, eventId, promoCodeId)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), promoCode.Used)
+}
@@ -0,0 +1,110 @@
+package db
+
+import (
+	"encoding/json"
+	"event-booking/models"
+)
+
+const selectRegistrationQuestions = `
+	SELECT id, event_id, organization_id, label, type, options, required
+	FROM registration_questions
+`
+
+func scanRegistrationQuestion(row rowScanner) (models.RegistrationQuestion, error) {
+	var q models.RegistrationQuestion
+	var options string
+	err := row.Scan(&q.Id, &q.EventId, &q.OrganizationId, &q.Label, &q.Type, &options, &q.Required)
+	if err != nil {
+		return q, err
+	}
+	err = json.Unmarshal([]byte(options), &q.Options)
+	return q, err
+}
+
+// GetRegistrationQuestions returns the questions of the event in the order
+// they are asked.
+func (r *SqlEventRepository) GetRegistrationQuestions(orgId, eventId int64) ([]models.RegistrationQuestion, error) {
+	query := selectRegistrationQuestions + `WHERE event_id = ? AND organization_id = ? ORDER BY position, id;`
+	rows, err := r.db.Query(query, eventId, orgId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	questions := []models.RegistrationQuestion{}
+	for rows.Next() {
+		q, err := scanRegistrationQuestion(rows)
+		if err != nil {
+			return nil, err
+		}
+		questions = append(questions, q)
+	}
+
+	return questions, nil
+}
+
+// ReplaceRegistrationQuestions makes questions the questions of the event.
+// Questions with an id are updated and keep their answers, the others are
+// added. Questions that are left out are deleted together with their
+// answers.
+func (r *SqlEventRepository) ReplaceRegistrationQuestions(orgId, eventId int64, questions []models.RegistrationQuestion) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	kept := []any{eventId, orgId}
+	placeholders := "0"
+	for _, q := range questions {
+		if q.Id != 0 {
+			kept = append(kept, q.Id)
+			placeholders += ", ?"
+		}
+	}
+	removed := `SELECT id FROM registration_questions WHERE event_id = ? AND organization_id = ? AND id NOT IN (` + placeholders + `)`
+
+	_, err = tx.Exec(`DELETE FROM registration_answers WHERE question_id IN (`+removed+`);`, kept...)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`DELETE FROM registration_questions WHERE id IN (`+removed+`);`, kept...)
+	if err != nil {
+		return err
+	}
+
+	for position := range questions {
+		q := &questions[position]
+		options, err := json.Marshal(q.Options)
+		if err != nil {
+			return err
+		}
+
+		if q.Id != 0 {
+			query := `
+			UPDATE registration_questions SET position = ?, label = ?, type = ?, options = ?, required = ?
+			WHERE id = ? AND event_id = ? AND organization_id = ?;
+			`
+			_, err = tx.Exec(query, position, q.Label, q.Type, string(options), q.Required, q.Id, eventId, orgId)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		query := `
+		INSERT INTO registration_questions (event_id, organization_id, position, label, type, options, required)
+		VALUES (?, ?, ?, ?, ?, ?, ?);
+		`
+		result, err := tx.Exec(query, eventId, orgId, position, q.Label, q.Type, string(options), q.Required)
+		if err != nil {
+			return err
+		}
+		q.Id, err = result.LastInsertId()
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
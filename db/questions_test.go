@@ -0,0 +1,125 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"event-booking/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplaceRegistrationQuestions(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	repo := NewSqlEventRepository(testDB)
+
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	eventId := createTestOrgEvent(t, repo, orgId, "Workshop")
+
+	questions := []models.RegistrationQuestion{
+		{Label: "Dietary requirements", Type: "text"},
+		{Label: "T-shirt size", Type: "single_choice", Options: []string{"S", "M", "L"}, Required: true},
+	}
+	require.NoError(t, repo.ReplaceRegistrationQuestions(orgId, eventId, questions))
+	assert.NotZero(t, questions[0].Id)
+
+	saved, err := repo.GetRegistrationQuestions(orgId, eventId)
+	require.NoError(t, err)
+	require.Len(t, saved, 2)
+	assert.Equal(t, []string{"S", "M", "L"}, saved[1].Options)
+	assert.True(t, saved[1].Required)
+
+	kept := saved[1]
+	kept.Label = "Shirt size"
+	require.NoError(t, repo.ReplaceRegistrationQuestions(orgId, eventId, []models.RegistrationQuestion{
+		{Label: "Sessions", Type: "multi_choice", Options: []string{"Morning", "Afternoon"}},
+		kept,
+	}))
+
+	saved, err = repo.GetRegistrationQuestions(orgId, eventId)
+	require.NoError(t, err)
+	require.Len(t, saved, 2, "Questions left out are deleted")
+	assert.Equal(t, "Sessions", saved[0].Label, "Questions are kept in the given order")
+	assert.Equal(t, kept.Id, saved[1].Id)
+	assert.Equal(t, "Shirt size", saved[1].Label)
+
+	others, err := repo.GetRegistrationQuestions(orgId+1, eventId)
+	require.NoError(t, err)
+	assert.Empty(t, others, "Questions of other organizations must not be found")
+}
+
+func TestRegisterEvent_Answers(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	userRepo := NewSqlUserRepository(testDB)
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+
+	userId, _ := userRepo.CreateUser(&models.User{Email: "ada@example.com", Password: "password123"})
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	eventId := createTestOrgEvent(t, eventRepo, orgId, "Workshop")
+
+	questions := []models.RegistrationQuestion{
+		{Label: "Dietary requirements", Type: "text"},
+		{Label: "Sessions", Type: "multi_choice", Options: []string{"Morning", "Afternoon"}},
+	}
+	require.NoError(t, eventRepo.ReplaceRegistrationQuestions(orgId, eventId, questions))
+
+	registration := newTestRegistration(orgId, userId, eventId)
+	registration.Answers = []models.RegistrationAnswer{
+		{QuestionId: questions[0].Id, Value: "Vegan"},
+		{QuestionId: questions[1].Id, Values: []string{"Morning", "Afternoon"}},
+	}
+	_, err := registerRepo.RegisterEvent(registration)
+	require.NoError(t, err)
+
+	registrations, err := registerRepo.GetRegistrations(orgId, eventId, "")
+	require.NoError(t, err)
+	require.Len(t, registrations, 1)
+	assert.Equal(t, "ada@example.com", registrations[0].Email)
+	assert.Equal(t, registration.Answers, registrations[0].Answers)
+
+	require.NoError(t, eventRepo.ReplaceRegistrationQuestions(orgId, eventId, questions[1:]))
+	registrations, err = registerRepo.GetRegistrations(orgId, eventId, "")
+	require.NoError(t, err)
+	assert.Equal(t, registration.Answers[1:], registrations[0].Answers, "Answers to deleted questions are deleted")
+
+	deleted, err := eventRepo.DeleteEvent(orgId, eventId)
+	require.NoError(t, err)
+	require.True(t, deleted)
+	saved, err := registerRepo.GetRegistrationQuestions(orgId, eventId)
+	require.NoError(t, err)
+	assert.Empty(t, saved, "Questions are deleted with their event")
+}
+
+func TestDeleteUser_RemovesAnswers(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	userRepo := NewSqlUserRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+
+	userId, _ := userRepo.CreateUser(&models.User{Email: "ada@example.com", Password: "password123"})
+	eventId := createTestOrgEvent(t, eventRepo, 1, "Workshop")
+	questions := []models.RegistrationQuestion{{Label: "Notes", Type: "text"}}
+	require.NoError(t, eventRepo.ReplaceRegistrationQuestions(1, eventId, questions))
+
+	registration := newTestRegistration(1, userId, eventId)
+	registration.Answers = []models.RegistrationAnswer{{QuestionId: questions[0].Id, Value: "Private note"}}
+	_, err := registerRepo.RegisterEvent(registration)
+	require.NoError(t, err)
+
+	_, err = userRepo.DeleteUser(userId, time.Now())
+	require.NoError(t, err)
+
+	var answers int
+	require.NoError(t, testDB.QueryRow(`SELECT COUNT(*) FROM registration_answers;`).Scan(&answers))
+	assert.Zero(t, answers)
+}
@@ -2,44 +2,494 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
 	"event-booking/models"
+	"time"
 )
 
 type SqlEventRegisterRepository struct {
 	db        *sql.DB
 	eventRepo *SqlEventRepository
+	userRepo  *SqlUserRepository
+	orgRepo   *SqlOrganizationRepository
 }
 
 func NewSqlEventRegisterRepository(database *sql.DB) *SqlEventRegisterRepository {
 	return &SqlEventRegisterRepository{
 		db:        database,
 		eventRepo: NewSqlEventRepository(database),
+		userRepo:  NewSqlUserRepository(database),
+		orgRepo:   NewSqlOrganizationRepository(database),
 	}
 }
 
-func (r *SqlEventRegisterRepository) GetEventById(id int64) (models.Event, error) {
-	return r.eventRepo.GetEventById(id)
+func (r *SqlEventRegisterRepository) GetEventById(orgId, id int64) (models.Event, error) {
+	return r.eventRepo.GetEventById(orgId, id)
 }
 
-func (r *SqlEventRegisterRepository) RegisterEvent(userId, eventId int64) error {
+func (r *SqlEventRegisterRepository) CanAccessEvent(orgId, eventId, userId int64) (bool, error) {
+	return r.eventRepo.CanAccessEvent(orgId, eventId, userId)
+}
+
+func (r *SqlEventRegisterRepository) GetTicketTypes(orgId, eventId int64) ([]models.TicketType, error) {
+	return r.eventRepo.GetTicketTypes(orgId, eventId)
+}
+
+func (r *SqlEventRegisterRepository) GetPromoCode(orgId, eventId, id int64) (models.PromoCode, error) {
+	return r.eventRepo.GetPromoCode(orgId, eventId, id)
+}
+
+func (r *SqlEventRegisterRepository) GetPromoCodeByCode(orgId, eventId int64, code string) (models.PromoCode, error) {
+	return r.eventRepo.GetPromoCodeByCode(orgId, eventId, code)
+}
+
+func (r *SqlEventRegisterRepository) GetRegistrationQuestions(orgId, eventId int64) ([]models.RegistrationQuestion, error) {
+	return r.eventRepo.GetRegistrationQuestions(orgId, eventId)
+}
+
+func (r *SqlEventRegisterRepository) GetEventMember(orgId, eventId, userId int64) (models.EventMember, error) {
+	return r.eventRepo.GetEventMember(orgId, eventId, userId)
+}
+
+func (r *SqlEventRegisterRepository) GetUserById(id int64) (models.User, error) {
+	return r.userRepo.GetUserById(id)
+}
+
+// selectRegistrations selects registrations with the email address of their
+// user, or of the guest for guest registrations.
+const selectRegistrations = `
+	SELECT r.id, r.event_id, r.user_id, r.organization_id, r.ticket_type_id, r.promo_code_id, r.amount, r.status,
+		r.checked_in_at, r.group_id, r.guest_name, COALESCE(u.email, r.guest_email), r.created_at
+	FROM registrations r
+	LEFT JOIN users u ON u.id = r.user_id
+`
+
+// activeRegistration matches registrations that hold or wait for a seat.
+const activeRegistration = `r.status IN ('awaiting_payment', 'pending', 'approved')`
+
+func scanRegistration(row rowScanner) (models.RegisterEvent, error) {
+	var registration models.RegisterEvent
+	var userId, ticketTypeId, promoCodeId, groupId sql.NullInt64
+	var checkedInAt sql.NullTime
+	err := row.Scan(&registration.Id, &registration.EventId, &userId, &registration.OrganizationId,
+		&ticketTypeId, &promoCodeId, &registration.Amount, &registration.Status, &checkedInAt, &groupId,
+		&registration.GuestName, &registration.Email, &registration.CreatedAt)
+	registration.UserId = userId.Int64
+	registration.GroupId = groupId.Int64
+	registration.TicketTypeId = ticketTypeId.Int64
+	registration.PromoCodeId = promoCodeId.Int64
+	if checkedInAt.Valid {
+		registration.CheckedInAt = &checkedInAt.Time
+	}
+	return registration, err
+}
+
+// seatsAvailable matches events e that have a free seat, both in the event
+// and in ticket type ?, which is NULL for registrations without a ticket.
+const seatsAvailable = `(e.capacity = 0 OR e.capacity > (
+		SELECT COUNT(*) FROM registrations WHERE event_id = e.id AND status = 'approved'
+	)) AND (? IS NULL OR EXISTS (
+		SELECT 1 FROM ticket_types t WHERE t.id = ? AND t.event_id = e.id AND t.quantity > (
+			SELECT COUNT(*) FROM registrations WHERE ticket_type_id = t.id AND status = 'approved'
+		)
+	))`
+
+// overbooked matches events e with more approved registrations than seats,
+// in the event or in one of its ticket types.
+const overbooked = `((e.capacity > 0 AND e.capacity < (
+		SELECT COUNT(*) FROM registrations WHERE event_id = e.id AND status = 'approved'
+	)) OR EXISTS (
+		SELECT 1 FROM ticket_types t WHERE t.event_id = e.id AND t.quantity < (
+			SELECT COUNT(*) FROM registrations WHERE ticket_type_id = t.id AND status = 'approved'
+		)
+	))`
+
+// RegisterEvent inserts the registration together with its answers and
+// counts the use of its promo code, after expiring unpaid registrations that
+// held a use of the code for too long. Registrations that are not pending are
+// only inserted while the event and the ticket type have a free seat, and
+// promo codes are only used while they have uses left; false is returned
+// when either is not the case. Nothing is inserted when the event does not
+// belong to the registration's organization.
+func (r *SqlEventRegisterRepository) RegisterEvent(registration *models.RegisterEvent) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if registration.PromoCodeId != 0 {
+		err = expireUnpaidPromoUses(tx, registration.OrganizationId, registration.PromoCodeId, registration.CreatedAt)
+		if err != nil {
+			return false, err
+		}
+
+		query := `
+			UPDATE promo_codes SET used = used + 1
+			WHERE id = ? AND event_id = ? AND organization_id = ? AND (max_uses = 0 OR used < max_uses);
+		`
+		result, err := tx.Exec(query, registration.PromoCodeId, registration.EventId, registration.OrganizationId)
+		if err != nil {
+			return false, err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil || affected == 0 {
+			return false, err
+		}
+	}
+
+	inserted, err := insertRegistration(tx, registration)
+	if err != nil || !inserted {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// insertRegistration inserts the registration and its answers within tx.
+// Registrations that are not pending are only inserted while the event and
+// the ticket type have a free seat, and no registration is inserted while
+// the user holds another active one for the event; false is returned
+// otherwise. Guest registrations have no user.
+func insertRegistration(tx *sql.Tx, registration *models.RegisterEvent) (bool, error) {
+	var userId, ticketTypeId, promoCodeId, groupId sql.NullInt64
+	if registration.UserId != 0 {
+		userId = sql.NullInt64{Int64: registration.UserId, Valid: true}
+	}
+	if registration.TicketTypeId != 0 {
+		ticketTypeId = sql.NullInt64{Int64: registration.TicketTypeId, Valid: true}
+	}
+	if registration.PromoCodeId != 0 {
+		promoCodeId = sql.NullInt64{Int64: registration.PromoCodeId, Valid: true}
+	}
+	if registration.GroupId != 0 {
+		groupId = sql.NullInt64{Int64: registration.GroupId, Valid: true}
+	}
+	guestEmail := ""
+	if registration.UserId == 0 {
+		guestEmail = registration.Email
+	}
+
 	query := `
-		INSERT INTO registrations (user_id, event_id)
-		VALUES (?, ?);
+		INSERT INTO registrations (user_id, event_id, organization_id, ticket_type_id, promo_code_id, amount, status,
+			group_id, guest_name, guest_email, created_at)
+		SELECT ?, e.id, e.organization_id, ?, ?, ?, ?, ?, ?, ?, ? FROM events e
+		WHERE e.id = ? AND e.organization_id = ? AND (? = 'pending' OR ` + seatsAvailable + `)
+		ON CONFLICT DO NOTHING;
 	`
-	_, err := r.db.Exec(query, userId, eventId)
-	return err
+	result, err := tx.Exec(query, userId, ticketTypeId, promoCodeId, registration.Amount, registration.Status,
+		groupId, registration.GuestName, guestEmail, registration.CreatedAt, registration.EventId,
+		registration.OrganizationId, registration.Status, ticketTypeId, ticketTypeId)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		return false, err
+	}
+
+	registration.Id, err = result.LastInsertId()
+	if err != nil {
+		return false, err
+	}
+
+	for _, answer := range registration.Answers {
+		value := answer.Value
+		if answer.Values != nil {
+			encoded, err := json.Marshal(answer.Values)
+			if err != nil {
+				return false, err
+			}
+			value = string(encoded)
+		}
+
+		query := `INSERT INTO registration_answers (registration_id, question_id, value) VALUES (?, ?, ?);`
+		_, err = tx.Exec(query, registration.Id, answer.QuestionId, value)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// GetRegisteredEventById returns the pending or approved registration of
+// userId for the event.
+func (r *SqlEventRegisterRepository) GetRegisteredEventById(orgId, userId, eventId int64) (models.RegisterEvent, error) {
+	query := selectRegistrations + `WHERE r.user_id = ? AND r.event_id = ? AND r.organization_id = ? AND ` + activeRegistration + `;`
+	return scanRegistration(r.db.QueryRow(query, userId, eventId, orgId))
+}
+
+// GetRegistration returns a registration of the organization in any status.
+func (r *SqlEventRegisterRepository) GetRegistration(orgId, id int64) (models.RegisterEvent, error) {
+	query := selectRegistrations + `WHERE r.id = ? AND r.organization_id = ?;`
+	return scanRegistration(r.db.QueryRow(query, id, orgId))
 }
 
-func (r *SqlEventRegisterRepository) GetRegisteredEventById(userId, eventId int64) (models.RegisterEvent, error) {
-	query := `SELECT * FROM registrations WHERE user_id = ? AND event_id = ?;`
-	row := r.db.QueryRow(query, userId, eventId)
-	var registeredEvent models.RegisterEvent
-	err := row.Scan(&registeredEvent.Id, &registeredEvent.EventId, &registeredEvent.UserId)
-	return registeredEvent, err
+// CheckIn marks the approved registration of userId as checked in at the
+// event. Guest registrations are held by the booker of their group. It
+// returns false when the registration is not approved, belongs to someone
+// else or was already checked in, so every ticket is only let in once.
+func (r *SqlEventRegisterRepository) CheckIn(orgId, eventId, id, userId int64, at time.Time) (bool, error) {
+	query := `
+		UPDATE registrations SET checked_in_at = ?
+		WHERE id = ? AND event_id = ? AND organization_id = ? AND status = 'approved'
+			AND COALESCE(user_id, (SELECT booker_id FROM registration_groups WHERE id = registrations.group_id)) = ?
+			AND checked_in_at IS NULL;
+	`
+	result, err := r.db.Exec(query, at, id, eventId, orgId, userId)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// GetRegistrations returns the registrations of the event with the email
+// addresses of their users or guests, filtered by status unless it is empty.
+func (r *SqlEventRegisterRepository) GetRegistrations(orgId, eventId int64, status string) ([]models.RegisterEvent, error) {
+	query := selectRegistrations + `
+		WHERE r.event_id = ? AND r.organization_id = ? AND (? = '' OR r.status = ?)
+		ORDER BY r.id;
+	`
+	registrations, err := r.queryRegistrations(query, eventId, orgId, status, status)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.addAnswers(orgId, eventId, registrations)
+	if err != nil {
+		return nil, err
+	}
+	return registrations, nil
 }
 
-func (r *SqlEventRegisterRepository) DeleteRegisteredEvent(id int64) error {
-	query := `DELETE FROM registrations WHERE id = ?;`
-	_, err := r.db.Exec(query, id)
+func (r *SqlEventRegisterRepository) queryRegistrations(query string, args ...any) ([]models.RegisterEvent, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	registrations := []models.RegisterEvent{}
+	for rows.Next() {
+		registration, err := scanRegistration(rows)
+		if err != nil {
+			return nil, err
+		}
+		registrations = append(registrations, registration)
+	}
+	return registrations, rows.Err()
+}
+
+// addAnswers adds the answers to the registration questions of the event
+// to registrations.
+func (r *SqlEventRegisterRepository) addAnswers(orgId, eventId int64, registrations []models.RegisterEvent) error {
+	query := `
+		SELECT a.registration_id, a.question_id, a.value, q.type
+		FROM registration_answers a
+		JOIN registration_questions q ON q.id = a.question_id
+		WHERE q.event_id = ? AND q.organization_id = ?
+		ORDER BY q.position, q.id;
+	`
+	rows, err := r.db.Query(query, eventId, orgId)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	index := map[int64]int{}
+	for i, registration := range registrations {
+		index[registration.Id] = i
+	}
+
+	for rows.Next() {
+		var registrationId int64
+		var answer models.RegistrationAnswer
+		var value, questionType string
+		err := rows.Scan(&registrationId, &answer.QuestionId, &value, &questionType)
+		if err != nil {
+			return err
+		}
+
+		i, ok := index[registrationId]
+		if !ok {
+			continue
+		}
+
+		if questionType == "multi_choice" {
+			err = json.Unmarshal([]byte(value), &answer.Values)
+			if err != nil {
+				return err
+			}
+		} else {
+			answer.Value = value
+		}
+		registrations[i].Answers = append(registrations[i].Answers, answer)
+	}
+
+	return rows.Err()
+}
+
+// SetRegistrationStatus moves the pending registrations ids of the event to
+// status, all or none: false is returned and nothing changes when one of
+// them is not pending anymore, or when approving them would exceed the
+// capacity of the event or of a ticket type. Rejected registrations give
+// back the use of their promo code.
+func (r *SqlEventRegisterRepository) SetRegistrationStatus(orgId, eventId int64, ids []int64, status string) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE registrations SET status = ?
+		WHERE id = ? AND event_id = ? AND organization_id = ? AND status = 'pending';
+	`
+	for _, id := range ids {
+		result, err := tx.Exec(query, status, id, eventId, orgId)
+		if err != nil {
+			return false, err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil || affected == 0 {
+			return false, err
+		}
+
+		if status == "rejected" {
+			err = releasePromoCode(tx, orgId, id)
+			if err != nil {
+				return false, err
+			}
+		}
+	}
+
+	if status == "approved" {
+		var full bool
+		query := `SELECT ` + overbooked + ` FROM events e WHERE e.id = ? AND e.organization_id = ?;`
+		err = tx.QueryRow(query, eventId, orgId).Scan(&full)
+		if err != nil || full {
+			return false, err
+		}
+	}
+
+	return true, tx.Commit()
+}
+
+// CancelRegistration marks the pending, approved or awaiting payment
+// registration as cancelled, which frees its seat, and cancels its unpaid
+// orders and pending transfer.
+func (r *SqlEventRegisterRepository) CancelRegistration(orgId, id int64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = cancelRegistration(tx, orgId, id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// cancelRegistration cancels the registration, its unpaid orders or those of
+// its group and its pending transfer within tx, and gives back the use of
+// its promo code. It returns false when the registration was not active.
+func cancelRegistration(tx *sql.Tx, orgId, id int64) (bool, error) {
+	query := `
+		UPDATE registrations SET status = 'cancelled'
+		WHERE id = ? AND organization_id = ? AND status IN ('awaiting_payment', 'pending', 'approved');
+	`
+	result, err := tx.Exec(query, id, orgId)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		return false, err
+	}
+
+	err = releasePromoCode(tx, orgId, id)
+	if err != nil {
+		return false, err
+	}
+
+	// An unpaid group order no longer matches the group's total.
+	query = `
+		UPDATE orders SET status = 'cancelled', updated_at = CURRENT_TIMESTAMP
+		WHERE organization_id = ? AND status IN ('pending', 'failed')
+			AND (registration_id = ?2 OR group_id = (SELECT group_id FROM registrations WHERE id = ?2));
+	`
+	_, err = tx.Exec(query, orgId, id)
+	if err != nil {
+		return false, err
+	}
+
+	query = `
+		UPDATE registration_transfers SET status = 'cancelled', decided_at = CURRENT_TIMESTAMP
+		WHERE registration_id = ? AND organization_id = ? AND status = 'pending';
+	`
+	_, err = tx.Exec(query, id, orgId)
+	return err == nil, err
+}
+
+// releasePromoCode gives back the use of the registration's promo code
+// within tx, so codes with limited uses can be used again.
+func releasePromoCode(tx *sql.Tx, orgId, registrationId int64) error {
+	query := `
+		UPDATE promo_codes SET used = used - 1
+		WHERE used > 0 AND id = (SELECT promo_code_id FROM registrations WHERE id = ? AND organization_id = ?);
+	`
+	_, err := tx.Exec(query, registrationId, orgId)
 	return err
 }
+
+// unpaidPromoHold is how long a registration awaiting payment holds the use
+// of its promo code when another registration needs it.
+const unpaidPromoHold = time.Hour
+
+// expireUnpaidPromoUses cancels the registrations of the promo code that
+// still await payment unpaidPromoHold after they were made, which gives back
+// their uses and cancels their unpaid orders. Registrations whose order was
+// paid are kept, since the payment still confirms them.
+func expireUnpaidPromoUses(tx *sql.Tx, orgId, promoCodeId int64, now time.Time) error {
+	query := `
+		SELECT r.id FROM registrations r
+		WHERE r.promo_code_id = ? AND r.organization_id = ? AND r.status = 'awaiting_payment' AND r.created_at < ?
+			AND NOT EXISTS (
+				SELECT 1 FROM orders o
+				WHERE o.status = 'paid' AND (o.registration_id = r.id OR o.group_id = r.group_id)
+			);
+	`
+	rows, err := tx.Query(query, promoCodeId, orgId, now.Add(-unpaidPromoHold))
+	if err != nil {
+		return err
+	}
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		err = rows.Scan(&id)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		_, err = cancelRegistration(tx, orgId, id)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"sync"
 	"testing"
 	"time"
 
@@ -10,6 +11,12 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+func newTestRegistration(orgId, userId, eventId int64) *models.RegisterEvent {
+	return &models.RegisterEvent{
+		UserId: userId, EventId: eventId, OrganizationId: orgId, Status: "approved", CreatedAt: time.Now(),
+	}
+}
+
 func TestRegisterEvent(t *testing.T) {
 	testDB := SetupTestDB(t)
 	defer TeardownTestDB(t, testDB)
@@ -18,18 +25,20 @@ func TestRegisterEvent(t *testing.T) {
 	registerRepo := NewSqlEventRegisterRepository(testDB)
 
 	event := &models.Event{
-		Name:        "Test Event",
-		Description: "Test Description",
-		Location:    "Test Location",
-		DateTime:    time.Now().Add(24 * time.Hour),
-		UserId:      1,
+		Name:           "Test Event",
+		Description:    "Test Description",
+		Location:       "Test Location",
+		DateTime:       time.Now().Add(24 * time.Hour),
+		UserId:         1,
+		OrganizationId: 1,
 	}
 	eventId, _ := eventRepo.CreateEvent(event)
 
 	userId := int64(5)
-	err := registerRepo.RegisterEvent(userId, eventId)
+	registered, err := registerRepo.RegisterEvent(newTestRegistration(1, userId, eventId))
 
 	require.NoError(t, err)
+	assert.True(t, registered)
 }
 
 func TestRegisterEvent_DuplicateRegistration(t *testing.T) {
@@ -40,23 +49,66 @@ func TestRegisterEvent_DuplicateRegistration(t *testing.T) {
 	registerRepo := NewSqlEventRegisterRepository(testDB)
 
 	event := &models.Event{
-		Name:        "Test Event",
-		Description: "Test Description",
-		Location:    "Test Location",
-		DateTime:    time.Now().Add(24 * time.Hour),
-		UserId:      1,
+		Name:           "Test Event",
+		Description:    "Test Description",
+		Location:       "Test Location",
+		DateTime:       time.Now().Add(24 * time.Hour),
+		UserId:         1,
+		OrganizationId: 1,
 	}
 	eventId, _ := eventRepo.CreateEvent(event)
 
 	userId := int64(5)
-	err1 := registerRepo.RegisterEvent(userId, eventId)
-	require.NoError(t, err1)
-
-	// Try to register again - SQLite allows it since we don't have UNIQUE constraint
-	// Just verify we can register successfully
-	err2 := registerRepo.RegisterEvent(userId, eventId)
-	// Accept either success or error depending on schema
-	_ = err2
+	first := newTestRegistration(1, userId, eventId)
+	registered, err := registerRepo.RegisterEvent(first)
+	require.NoError(t, err)
+	assert.True(t, registered)
+
+	pending := newTestRegistration(1, userId, eventId)
+	pending.Status = "pending"
+	registered, err = registerRepo.RegisterEvent(pending)
+	require.NoError(t, err)
+	assert.False(t, registered, "Users hold one active registration per event")
+
+	require.NoError(t, registerRepo.CancelRegistration(1, first.Id))
+	registered, err = registerRepo.RegisterEvent(newTestRegistration(1, userId, eventId))
+	require.NoError(t, err)
+	assert.True(t, registered, "Cancelled registrations do not count")
+}
+
+func TestRegisterEvent_DuplicateRegistrationUnderConcurrency(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	eventRepo := NewSqlEventRepository(testDB)
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+
+	eventId, _ := eventRepo.CreateEvent(&models.Event{
+		Name: "Workshop", Description: "Popular workshop", Location: "Room 1",
+		DateTime: time.Now().Add(24 * time.Hour), UserId: 1, OrganizationId: 1,
+	})
+
+	var wg sync.WaitGroup
+	results := make(chan bool, 10)
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			registered, err := registerRepo.RegisterEvent(newTestRegistration(1, 5, eventId))
+			assert.NoError(t, err)
+			results <- registered
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	registrations := 0
+	for registered := range results {
+		if registered {
+			registrations++
+		}
+	}
+	assert.Equal(t, 1, registrations)
 }
 
 func TestGetRegisteredEventById(t *testing.T) {
@@ -67,18 +119,19 @@ func TestGetRegisteredEventById(t *testing.T) {
 	registerRepo := NewSqlEventRegisterRepository(testDB)
 
 	event := &models.Event{
-		Name:        "Test Event",
-		Description: "Test Description",
-		Location:    "Test Location",
-		DateTime:    time.Now().Add(24 * time.Hour),
-		UserId:      1,
+		Name:           "Test Event",
+		Description:    "Test Description",
+		Location:       "Test Location",
+		DateTime:       time.Now().Add(24 * time.Hour),
+		UserId:         1,
+		OrganizationId: 1,
 	}
 	eventId, _ := eventRepo.CreateEvent(event)
 
 	userId := int64(5)
-	registerRepo.RegisterEvent(userId, eventId)
+	registerRepo.RegisterEvent(newTestRegistration(1, userId, eventId))
 
-	registeredEvent, err := registerRepo.GetRegisteredEventById(userId, eventId)
+	registeredEvent, err := registerRepo.GetRegisteredEventById(1, userId, eventId)
 
 	require.NoError(t, err)
 	assert.Greater(t, registeredEvent.Id, int64(0))
@@ -92,12 +145,12 @@ func TestGetRegisteredEventById_NotFound(t *testing.T) {
 
 	registerRepo := NewSqlEventRegisterRepository(testDB)
 
-	_, err := registerRepo.GetRegisteredEventById(999, 888)
+	_, err := registerRepo.GetRegisteredEventById(1, 999, 888)
 
 	require.Error(t, err)
 }
 
-func TestDeleteRegisteredEvent(t *testing.T) {
+func TestCancelRegistration(t *testing.T) {
 	testDB := SetupTestDB(t)
 	defer TeardownTestDB(t, testDB)
 
@@ -105,23 +158,28 @@ func TestDeleteRegisteredEvent(t *testing.T) {
 	registerRepo := NewSqlEventRegisterRepository(testDB)
 
 	event := &models.Event{
-		Name:        "Test Event",
-		Description: "Test Description",
-		Location:    "Test Location",
-		DateTime:    time.Now().Add(24 * time.Hour),
-		UserId:      1,
+		Name:           "Test Event",
+		Description:    "Test Description",
+		Location:       "Test Location",
+		DateTime:       time.Now().Add(24 * time.Hour),
+		UserId:         1,
+		OrganizationId: 1,
 	}
 	eventId, _ := eventRepo.CreateEvent(event)
 
 	userId := int64(5)
-	registerRepo.RegisterEvent(userId, eventId)
-	registeredEvent, _ := registerRepo.GetRegisteredEventById(userId, eventId)
-	err := registerRepo.DeleteRegisteredEvent(registeredEvent.Id)
+	registerRepo.RegisterEvent(newTestRegistration(1, userId, eventId))
+	registeredEvent, _ := registerRepo.GetRegisteredEventById(1, userId, eventId)
+	err := registerRepo.CancelRegistration(1, registeredEvent.Id)
 
 	require.NoError(t, err)
 
-	_, err = registerRepo.GetRegisteredEventById(userId, eventId)
-	assert.Error(t, err, "Registration should not exist after deletion")
+	_, err = registerRepo.GetRegisteredEventById(1, userId, eventId)
+	assert.Error(t, err, "Cancelled registrations should not be returned")
+
+	registrations, err := registerRepo.GetRegistrations(1, eventId, "cancelled")
+	require.NoError(t, err)
+	assert.Len(t, registrations, 1, "Cancelled registrations are kept")
 }
 
 func TestGetEventById_ThroughRegisterRepo(t *testing.T) {
@@ -132,17 +190,93 @@ func TestGetEventById_ThroughRegisterRepo(t *testing.T) {
 	registerRepo := NewSqlEventRegisterRepository(testDB)
 
 	event := &models.Event{
-		Name:        "Test Event",
-		Description: "Test Description",
-		Location:    "Test Location",
-		DateTime:    time.Now().Add(24 * time.Hour),
-		UserId:      1,
+		Name:           "Test Event",
+		Description:    "Test Description",
+		Location:       "Test Location",
+		DateTime:       time.Now().Add(24 * time.Hour),
+		UserId:         1,
+		OrganizationId: 1,
 	}
 	eventId, _ := eventRepo.CreateEvent(event)
 
-	retrievedEvent, err := registerRepo.GetEventById(eventId)
+	retrievedEvent, err := registerRepo.GetEventById(1, eventId)
 
 	require.NoError(t, err)
 	assert.Equal(t, event.Name, retrievedEvent.Name)
 	assert.Equal(t, event.Location, retrievedEvent.Location)
 }
+
+func TestRegisterEvent_Capacity(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	eventRepo := NewSqlEventRepository(testDB)
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+
+	eventId, _ := eventRepo.CreateEvent(&models.Event{
+		Name: "Workshop", Description: "Small workshop", Location: "Room 1",
+		DateTime: time.Now().Add(24 * time.Hour), UserId: 1, OrganizationId: 1, Capacity: 1,
+	})
+
+	registered, err := registerRepo.RegisterEvent(newTestRegistration(1, 5, eventId))
+	require.NoError(t, err)
+	assert.True(t, registered)
+
+	registered, err = registerRepo.RegisterEvent(newTestRegistration(1, 6, eventId))
+	require.NoError(t, err)
+	assert.False(t, registered, "Approved registrations must not exceed the capacity")
+
+	pending := newTestRegistration(1, 6, eventId)
+	pending.Status = "pending"
+	registered, err = registerRepo.RegisterEvent(pending)
+	require.NoError(t, err)
+	assert.True(t, registered, "Pending registrations do not take a seat")
+}
+
+func TestSetRegistrationStatus(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	eventRepo := NewSqlEventRepository(testDB)
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+
+	eventId, _ := eventRepo.CreateEvent(&models.Event{
+		Name: "Workshop", Description: "Small workshop", Location: "Room 1",
+		DateTime: time.Now().Add(24 * time.Hour), UserId: 1, OrganizationId: 1, Capacity: 2, RequiresApproval: true,
+	})
+
+	ids := []int64{}
+	for userId := int64(5); userId <= 7; userId++ {
+		registration := newTestRegistration(1, userId, eventId)
+		registration.Status = "pending"
+		_, err := registerRepo.RegisterEvent(registration)
+		require.NoError(t, err)
+		ids = append(ids, registration.Id)
+	}
+
+	updated, err := registerRepo.SetRegistrationStatus(1, eventId, ids, "approved")
+	require.NoError(t, err)
+	assert.False(t, updated, "Approving more registrations than seats must fail")
+
+	pending, err := registerRepo.GetRegistrations(1, eventId, "pending")
+	require.NoError(t, err)
+	assert.Len(t, pending, 3, "A failed approval must not approve anyone")
+
+	updated, err = registerRepo.SetRegistrationStatus(1, eventId, ids[:2], "approved")
+	require.NoError(t, err)
+	assert.True(t, updated)
+
+	updated, err = registerRepo.SetRegistrationStatus(1, eventId, ids[1:], "rejected")
+	require.NoError(t, err)
+	assert.False(t, updated, "Only pending registrations can be decided")
+
+	updated, err = registerRepo.SetRegistrationStatus(1, eventId, ids[2:], "rejected")
+	require.NoError(t, err)
+	assert.True(t, updated)
+
+	registrations, err := registerRepo.GetRegistrations(1, eventId, "")
+	require.NoError(t, err)
+	require.Len(t, registrations, 3)
+	assert.Equal(t, "approved", registrations[1].Status)
+	assert.Equal(t, "rejected", registrations[2].Status)
+}
@@ -2,13 +2,17 @@ package db
 
 import "database/sql"
 
-// createTablesForDB creates tables in the provided database
+// createTablesForDB creates tables in the provided database and migrates
+// tables of earlier versions, see migration.
 func createTablesForDB(database *sql.DB) error {
 	createUsersTable := `
 	CREATE TABLE IF NOT EXISTS users (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		email TEXT NOT NULL UNIQUE,
-		password TEXT NOT NULL
+		password TEXT NOT NULL,
+		verified BOOLEAN NOT NULL DEFAULT 0,
+		token_version INTEGER NOT NULL DEFAULT 0,
+		deleted_at DATETIME
 	);
 	`
 	_, err := database.Exec(createUsersTable)
@@ -16,6 +20,112 @@ func createTablesForDB(database *sql.DB) error {
 		return err
 	}
 
+	createProfilesTable := `
+	CREATE TABLE IF NOT EXISTS profiles (
+		user_id INTEGER PRIMARY KEY,
+		display_name TEXT NOT NULL DEFAULT '',
+		avatar_url TEXT NOT NULL DEFAULT '',
+		bio TEXT NOT NULL DEFAULT '',
+		time_zone TEXT NOT NULL DEFAULT '',
+		locale TEXT NOT NULL DEFAULT '',
+		FOREIGN KEY(user_id) REFERENCES users(id)
+	);
+	`
+	_, err = database.Exec(createProfilesTable)
+	if err != nil {
+		return err
+	}
+
+	createTwoFactorTable := `
+	CREATE TABLE IF NOT EXISTS two_factor (
+		user_id INTEGER PRIMARY KEY,
+		secret TEXT NOT NULL,
+		enabled BOOLEAN NOT NULL DEFAULT 0,
+		last_used_step INTEGER NOT NULL DEFAULT 0,
+		FOREIGN KEY(user_id) REFERENCES users(id)
+	);
+	`
+	_, err = database.Exec(createTwoFactorTable)
+	if err != nil {
+		return err
+	}
+
+	createRecoveryCodesTable := `
+	CREATE TABLE IF NOT EXISTS recovery_codes (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		code_hash TEXT NOT NULL,
+		used_at DATETIME,
+		FOREIGN KEY(user_id) REFERENCES users(id)
+	);
+	`
+	_, err = database.Exec(createRecoveryCodesTable)
+	if err != nil {
+		return err
+	}
+
+	createEmailVerificationsTable := `
+	CREATE TABLE IF NOT EXISTS email_verifications (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		token_id TEXT NOT NULL UNIQUE,
+		user_id INTEGER NOT NULL,
+		email TEXT NOT NULL,
+		expires_at DATETIME NOT NULL,
+		used_at DATETIME,
+		FOREIGN KEY(user_id) REFERENCES users(id)
+	);
+	`
+	_, err = database.Exec(createEmailVerificationsTable)
+	if err != nil {
+		return err
+	}
+
+	createPasswordResetsTable := `
+	CREATE TABLE IF NOT EXISTS password_resets (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		token_hash TEXT NOT NULL UNIQUE,
+		user_id INTEGER NOT NULL,
+		expires_at DATETIME NOT NULL,
+		used_at DATETIME,
+		created_at DATETIME NOT NULL,
+		FOREIGN KEY(user_id) REFERENCES users(id)
+	);
+	`
+	_, err = database.Exec(createPasswordResetsTable)
+	if err != nil {
+		return err
+	}
+
+	createOrganizationsTable := `
+	CREATE TABLE IF NOT EXISTS organizations (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		deprioritize_no_shows BOOLEAN NOT NULL DEFAULT 0,
+		no_show_threshold INTEGER NOT NULL DEFAULT 2,
+		created_at DATETIME NOT NULL
+	);
+	`
+	_, err = database.Exec(createOrganizationsTable)
+	if err != nil {
+		return err
+	}
+
+	createOrganizationMembersTable := `
+	CREATE TABLE IF NOT EXISTS organization_members (
+		organization_id INTEGER NOT NULL,
+		user_id INTEGER NOT NULL,
+		role TEXT NOT NULL,
+		created_at DATETIME NOT NULL,
+		PRIMARY KEY(organization_id, user_id),
+		FOREIGN KEY(organization_id) REFERENCES organizations(id),
+		FOREIGN KEY(user_id) REFERENCES users(id)
+	);
+	`
+	_, err = database.Exec(createOrganizationMembersTable)
+	if err != nil {
+		return err
+	}
+
 	createEventsTable := `
 	CREATE TABLE IF NOT EXISTS events (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -24,7 +134,12 @@ func createTablesForDB(database *sql.DB) error {
 		location TEXT NOT NULL,
 		datetime DATETIME NOT NULL,
 		user_id INTEGER NOT NULL,
-		FOREIGN KEY(user_id) REFERENCES users(id)
+		organization_id INTEGER NOT NULL,
+		visibility TEXT NOT NULL DEFAULT 'public',
+		capacity INTEGER NOT NULL DEFAULT 0,
+		requires_approval BOOLEAN NOT NULL DEFAULT 0,
+		FOREIGN KEY(user_id) REFERENCES users(id),
+		FOREIGN KEY(organization_id) REFERENCES organizations(id)
 	);
 	`
 
@@ -33,18 +148,359 @@ func createTablesForDB(database *sql.DB) error {
 		return err
 	}
 
+	createEventMembersTable := `
+	CREATE TABLE IF NOT EXISTS event_members (
+		event_id INTEGER NOT NULL,
+		user_id INTEGER NOT NULL,
+		organization_id INTEGER NOT NULL,
+		role TEXT NOT NULL,
+		created_at DATETIME NOT NULL,
+		PRIMARY KEY(event_id, user_id),
+		FOREIGN KEY(event_id) REFERENCES events(id),
+		FOREIGN KEY(user_id) REFERENCES users(id),
+		FOREIGN KEY(organization_id) REFERENCES organizations(id)
+	);
+	`
+	_, err = database.Exec(createEventMembersTable)
+	if err != nil {
+		return err
+	}
+
+	createEventInvitationsTable := `
+	CREATE TABLE IF NOT EXISTS event_invitations (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		event_id INTEGER NOT NULL,
+		organization_id INTEGER NOT NULL,
+		email TEXT NOT NULL DEFAULT '',
+		user_id INTEGER,
+		invited_by INTEGER NOT NULL,
+		created_at DATETIME NOT NULL,
+		FOREIGN KEY(event_id) REFERENCES events(id),
+		FOREIGN KEY(user_id) REFERENCES users(id),
+		FOREIGN KEY(invited_by) REFERENCES users(id),
+		FOREIGN KEY(organization_id) REFERENCES organizations(id)
+	);
+	`
+	_, err = database.Exec(createEventInvitationsTable)
+	if err != nil {
+		return err
+	}
+
+	createTicketTypesTable := `
+	CREATE TABLE IF NOT EXISTS ticket_types (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		event_id INTEGER NOT NULL,
+		organization_id INTEGER NOT NULL,
+		name TEXT NOT NULL,
+		price INTEGER NOT NULL,
+		currency TEXT NOT NULL,
+		quantity INTEGER NOT NULL,
+		sales_start DATETIME,
+		sales_end DATETIME,
+		FOREIGN KEY(event_id) REFERENCES events(id),
+		FOREIGN KEY(organization_id) REFERENCES organizations(id)
+	);
+	`
+	_, err = database.Exec(createTicketTypesTable)
+	if err != nil {
+		return err
+	}
+
+	createPromoCodesTable := `
+	CREATE TABLE IF NOT EXISTS promo_codes (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		event_id INTEGER NOT NULL,
+		organization_id INTEGER NOT NULL,
+		code TEXT NOT NULL,
+		discount_type TEXT NOT NULL,
+		discount_value INTEGER NOT NULL,
+		max_uses INTEGER NOT NULL DEFAULT 0,
+		used INTEGER NOT NULL DEFAULT 0,
+		valid_from DATETIME,
+		valid_until DATETIME,
+		ticket_type_ids TEXT NOT NULL DEFAULT '[]',
+		UNIQUE(event_id, code),
+		FOREIGN KEY(event_id) REFERENCES events(id),
+		FOREIGN KEY(organization_id) REFERENCES organizations(id)
+	);
+	`
+	_, err = database.Exec(createPromoCodesTable)
+	if err != nil {
+		return err
+	}
+
+	createRegistrationQuestionsTable := `
+	CREATE TABLE IF NOT EXISTS registration_questions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		event_id INTEGER NOT NULL,
+		organization_id INTEGER NOT NULL,
+		position INTEGER NOT NULL,
+		label TEXT NOT NULL,
+		type TEXT NOT NULL,
+		options TEXT NOT NULL DEFAULT '[]',
+		required BOOLEAN NOT NULL DEFAULT 0,
+		FOREIGN KEY(event_id) REFERENCES events(id),
+		FOREIGN KEY(organization_id) REFERENCES organizations(id)
+	);
+	`
+	_, err = database.Exec(createRegistrationQuestionsTable)
+	if err != nil {
+		return err
+	}
+
 	createRegistrationsTable := `
 	CREATE TABLE IF NOT EXISTS registrations (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		event_id INTEGER,
 		user_id INTEGER,
+		organization_id INTEGER NOT NULL,
+		ticket_type_id INTEGER,
+		promo_code_id INTEGER,
+		amount INTEGER NOT NULL DEFAULT 0,
+		status TEXT NOT NULL DEFAULT 'approved',
+		checked_in_at DATETIME,
+		group_id INTEGER,
+		guest_name TEXT NOT NULL DEFAULT '',
+		guest_email TEXT NOT NULL DEFAULT '',
+		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
 		FOREIGN KEY(event_id) REFERENCES events(id),
-		FOREIGN KEY(user_id) REFERENCES users(id)
+		FOREIGN KEY(group_id) REFERENCES registration_groups(id),
+		FOREIGN KEY(ticket_type_id) REFERENCES ticket_types(id),
+		FOREIGN KEY(promo_code_id) REFERENCES promo_codes(id),
+		FOREIGN KEY(user_id) REFERENCES users(id),
+		FOREIGN KEY(organization_id) REFERENCES organizations(id)
 	);
 	`
 
 	_, err = database.Exec(createRegistrationsTable)
-	return err
+	if err != nil {
+		return err
+	}
+
+	createRegistrationGroupsTable := `
+	CREATE TABLE IF NOT EXISTS registration_groups (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		event_id INTEGER NOT NULL,
+		organization_id INTEGER NOT NULL,
+		booker_id INTEGER NOT NULL,
+		created_at DATETIME NOT NULL,
+		FOREIGN KEY(event_id) REFERENCES events(id),
+		FOREIGN KEY(booker_id) REFERENCES users(id),
+		FOREIGN KEY(organization_id) REFERENCES organizations(id)
+	);
+	`
+	_, err = database.Exec(createRegistrationGroupsTable)
+	if err != nil {
+		return err
+	}
+
+	createOrdersTable := `
+	CREATE TABLE IF NOT EXISTS orders (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		registration_id INTEGER NOT NULL,
+		user_id INTEGER NOT NULL,
+		event_id INTEGER NOT NULL,
+		organization_id INTEGER NOT NULL,
+		amount INTEGER NOT NULL,
+		currency TEXT NOT NULL,
+		refunded_amount INTEGER NOT NULL DEFAULT 0,
+		status TEXT NOT NULL,
+		payment_intent_id TEXT NOT NULL UNIQUE,
+		client_secret TEXT NOT NULL,
+		group_id INTEGER,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL,
+		FOREIGN KEY(user_id) REFERENCES users(id),
+		FOREIGN KEY(group_id) REFERENCES registration_groups(id),
+		FOREIGN KEY(organization_id) REFERENCES organizations(id)
+	);
+	`
+	_, err = database.Exec(createOrdersTable)
+	if err != nil {
+		return err
+	}
+
+	createCancellationPoliciesTable := `
+	CREATE TABLE IF NOT EXISTS cancellation_policies (
+		event_id INTEGER PRIMARY KEY,
+		organization_id INTEGER NOT NULL,
+		full_refund_days INTEGER NOT NULL DEFAULT 0,
+		partial_refund_percent INTEGER NOT NULL DEFAULT 0,
+		FOREIGN KEY(event_id) REFERENCES events(id),
+		FOREIGN KEY(organization_id) REFERENCES organizations(id)
+	);
+	`
+	_, err = database.Exec(createCancellationPoliciesTable)
+	if err != nil {
+		return err
+	}
+
+	createCancellationsTable := `
+	CREATE TABLE IF NOT EXISTS cancellations (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		registration_id INTEGER NOT NULL UNIQUE,
+		user_id INTEGER NOT NULL,
+		event_id INTEGER NOT NULL,
+		organization_id INTEGER NOT NULL,
+		order_id INTEGER,
+		refund_amount INTEGER NOT NULL DEFAULT 0,
+		currency TEXT NOT NULL DEFAULT '',
+		refund_status TEXT NOT NULL,
+		cancelled_at DATETIME NOT NULL,
+		FOREIGN KEY(order_id) REFERENCES orders(id),
+		FOREIGN KEY(user_id) REFERENCES users(id),
+		FOREIGN KEY(organization_id) REFERENCES organizations(id)
+	);
+	`
+	_, err = database.Exec(createCancellationsTable)
+	if err != nil {
+		return err
+	}
+
+	// A registration has at most one pending transfer at a time.
+	createRegistrationTransfersTable := `
+	CREATE TABLE IF NOT EXISTS registration_transfers (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		registration_id INTEGER NOT NULL,
+		event_id INTEGER NOT NULL,
+		organization_id INTEGER NOT NULL,
+		from_user_id INTEGER NOT NULL,
+		to_user_id INTEGER NOT NULL,
+		status TEXT NOT NULL,
+		created_at DATETIME NOT NULL,
+		decided_at DATETIME,
+		FOREIGN KEY(registration_id) REFERENCES registrations(id),
+		FOREIGN KEY(from_user_id) REFERENCES users(id),
+		FOREIGN KEY(to_user_id) REFERENCES users(id),
+		FOREIGN KEY(organization_id) REFERENCES organizations(id)
+	);
+	CREATE UNIQUE INDEX IF NOT EXISTS registration_transfers_pending
+		ON registration_transfers(registration_id) WHERE status = 'pending';
+	`
+	_, err = database.Exec(createRegistrationTransfersTable)
+	if err != nil {
+		return err
+	}
+
+	createRegistrationAnswersTable := `
+	CREATE TABLE IF NOT EXISTS registration_answers (
+		registration_id INTEGER NOT NULL,
+		question_id INTEGER NOT NULL,
+		value TEXT NOT NULL,
+		PRIMARY KEY(registration_id, question_id),
+		FOREIGN KEY(registration_id) REFERENCES registrations(id),
+		FOREIGN KEY(question_id) REFERENCES registration_questions(id)
+	);
+	`
+	_, err = database.Exec(createRegistrationAnswersTable)
+	if err != nil {
+		return err
+	}
+
+	createIdempotencyKeysTable := `
+	CREATE TABLE IF NOT EXISTS idempotency_keys (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		key TEXT NOT NULL,
+		request_hash TEXT NOT NULL,
+		status_code INTEGER NOT NULL DEFAULT 0,
+		content_type TEXT NOT NULL DEFAULT '',
+		response_body BLOB,
+		completed BOOLEAN NOT NULL DEFAULT 0,
+		created_at DATETIME NOT NULL,
+		UNIQUE(user_id, key),
+		FOREIGN KEY(user_id) REFERENCES users(id)
+	);
+	`
+
+	_, err = database.Exec(createIdempotencyKeysTable)
+	if err != nil {
+		return err
+	}
+
+	createAuditEventsTable := `
+	CREATE TABLE IF NOT EXISTS audit_events (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		action TEXT NOT NULL,
+		user_id INTEGER,
+		subject TEXT NOT NULL DEFAULT '',
+		ip TEXT NOT NULL DEFAULT '',
+		details TEXT NOT NULL DEFAULT '',
+		created_at DATETIME NOT NULL
+	);
+	`
+
+	_, err = database.Exec(createAuditEventsTable)
+	if err != nil {
+		return err
+	}
+
+	createLoginAttemptsTable := `
+	CREATE TABLE IF NOT EXISTS login_attempts (
+		scope TEXT NOT NULL,
+		key TEXT NOT NULL,
+		failures INTEGER NOT NULL DEFAULT 0,
+		last_failure_at DATETIME NOT NULL,
+		locked_until DATETIME,
+		PRIMARY KEY(scope, key)
+	);
+	`
+
+	_, err = database.Exec(createLoginAttemptsTable)
+	if err != nil {
+		return err
+	}
+
+	createAPIKeysTable := `
+	CREATE TABLE IF NOT EXISTS api_keys (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		name TEXT NOT NULL,
+		prefix TEXT NOT NULL UNIQUE,
+		secret_hash TEXT NOT NULL,
+		scopes TEXT NOT NULL,
+		last_used_at DATETIME,
+		expires_at DATETIME,
+		created_at DATETIME NOT NULL,
+		FOREIGN KEY(user_id) REFERENCES users(id)
+	);
+	`
+	_, err = database.Exec(createAPIKeysTable)
+	if err != nil {
+		return err
+	}
+
+	createExternalIdentitiesTable := `
+	CREATE TABLE IF NOT EXISTS external_identities (
+		provider TEXT NOT NULL,
+		subject TEXT NOT NULL,
+		user_id INTEGER NOT NULL,
+		email TEXT NOT NULL,
+		created_at DATETIME NOT NULL,
+		PRIMARY KEY(provider, subject),
+		FOREIGN KEY(user_id) REFERENCES users(id)
+	);
+	`
+	_, err = database.Exec(createExternalIdentitiesTable)
+	if err != nil {
+		return err
+	}
+
+	createOIDCLoginStatesTable := `
+	CREATE TABLE IF NOT EXISTS oidc_login_states (
+		state_hash TEXT PRIMARY KEY,
+		provider TEXT NOT NULL,
+		nonce TEXT NOT NULL,
+		code_verifier TEXT NOT NULL,
+		expires_at DATETIME NOT NULL
+	);
+	`
+	_, err = database.Exec(createOIDCLoginStatesTable)
+	if err != nil {
+		return err
+	}
+
+	return migrate(database)
 }
 
 // createTables creates tables in the global DB
@@ -0,0 +1,100 @@
+package db
+
+import (
+	"database/sql"
+	"event-booking/models"
+)
+
+const selectTicketTypes = `
+	SELECT t.id, t.event_id, t.organization_id, t.name, t.price, t.currency, t.quantity, t.sales_start, t.sales_end,
+		(SELECT COUNT(*) FROM registrations r WHERE r.ticket_type_id = t.id AND r.status = 'approved')
+	FROM ticket_types t
+`
+
+func scanTicketType(row rowScanner) (models.TicketType, error) {
+	var t models.TicketType
+	var salesStart, salesEnd sql.NullTime
+	err := row.Scan(&t.Id, &t.EventId, &t.OrganizationId, &t.Name, &t.Price, &t.Currency, &t.Quantity,
+		&salesStart, &salesEnd, &t.Sold)
+	if err != nil {
+		return t, err
+	}
+
+	if salesStart.Valid {
+		t.SalesStart = &salesStart.Time
+	}
+	if salesEnd.Valid {
+		t.SalesEnd = &salesEnd.Time
+	}
+	return t, nil
+}
+
+// GetTicketTypes returns the ticket types of the event with the number of
+// tickets sold.
+func (r *SqlEventRepository) GetTicketTypes(orgId, eventId int64) ([]models.TicketType, error) {
+	query := selectTicketTypes + `WHERE t.event_id = ? AND t.organization_id = ? ORDER BY t.price, t.id;`
+	rows, err := r.db.Query(query, eventId, orgId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ticketTypes := []models.TicketType{}
+	for rows.Next() {
+		t, err := scanTicketType(rows)
+		if err != nil {
+			return nil, err
+		}
+		ticketTypes = append(ticketTypes, t)
+	}
+
+	return ticketTypes, nil
+}
+
+func (r *SqlEventRepository) GetTicketType(orgId, eventId, id int64) (models.TicketType, error) {
+	query := selectTicketTypes + `WHERE t.id = ? AND t.event_id = ? AND t.organization_id = ?;`
+	return scanTicketType(r.db.QueryRow(query, id, eventId, orgId))
+}
+
+func (r *SqlEventRepository) CreateTicketType(t *models.TicketType) error {
+	query := `
+	INSERT INTO ticket_types (event_id, organization_id, name, price, currency, quantity, sales_start, sales_end)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+	`
+	result, err := r.db.Exec(query, t.EventId, t.OrganizationId, t.Name, t.Price, t.Currency, t.Quantity, t.SalesStart, t.SalesEnd)
+	if err != nil {
+		return err
+	}
+
+	t.Id, err = result.LastInsertId()
+	return err
+}
+
+func (r *SqlEventRepository) UpdateTicketType(t *models.TicketType) error {
+	query := `
+	UPDATE ticket_types
+	SET name = ?, price = ?, currency = ?, quantity = ?, sales_start = ?, sales_end = ?
+	WHERE id = ? AND event_id = ? AND organization_id = ?;
+	`
+	_, err := r.db.Exec(query, t.Name, t.Price, t.Currency, t.Quantity, t.SalesStart, t.SalesEnd, t.Id, t.EventId, t.OrganizationId)
+	return err
+}
+
+// DeleteTicketType deletes the ticket type unless active registrations use
+// it, in which case false is returned.
+func (r *SqlEventRepository) DeleteTicketType(orgId, eventId, id int64) (bool, error) {
+	query := `
+	DELETE FROM ticket_types
+	WHERE id = ? AND event_id = ? AND organization_id = ?
+		AND NOT EXISTS (
+			SELECT 1 FROM registrations WHERE ticket_type_id = ? AND status IN ('awaiting_payment', 'pending', 'approved')
+		);
+	`
+	result, err := r.db.Exec(query, id, eventId, orgId, id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
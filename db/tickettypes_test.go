@@ -0,0 +1,138 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"event-booking/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestTicketType(t *testing.T, repo *SqlEventRepository, orgId, eventId int64, name string, price, quantity int64) int64 {
+	ticketType := &models.TicketType{
+		EventId: eventId, OrganizationId: orgId, Name: name, Price: price, Currency: "EUR", Quantity: quantity,
+	}
+	require.NoError(t, repo.CreateTicketType(ticketType))
+	return ticketType.Id
+}
+
+func TestTicketTypes_CreateAndUpdate(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	otherOrgId := createTestOrganization(t, orgRepo, "Globex", 1)
+	eventId := createTestOrgEvent(t, eventRepo, orgId, "Conference")
+
+	salesEnd := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	regularId := createTestTicketType(t, eventRepo, orgId, eventId, "Regular", 2500, 100)
+	earlyBird := &models.TicketType{
+		EventId: eventId, OrganizationId: orgId, Name: "Early bird", Price: 1500, Currency: "EUR", Quantity: 10, SalesEnd: &salesEnd,
+	}
+	require.NoError(t, eventRepo.CreateTicketType(earlyBird))
+
+	ticketTypes, err := eventRepo.GetTicketTypes(orgId, eventId)
+	require.NoError(t, err)
+	require.Len(t, ticketTypes, 2)
+	assert.Equal(t, "Early bird", ticketTypes[0].Name, "Ticket types are ordered by price")
+	require.NotNil(t, ticketTypes[0].SalesEnd)
+	assert.True(t, salesEnd.Equal(*ticketTypes[0].SalesEnd))
+	assert.Nil(t, ticketTypes[1].SalesStart)
+
+	regular := ticketTypes[1]
+	regular.Quantity = 50
+	require.NoError(t, eventRepo.UpdateTicketType(&regular))
+
+	updated, err := eventRepo.GetTicketType(orgId, eventId, regularId)
+	require.NoError(t, err)
+	assert.Equal(t, int64(50), updated.Quantity)
+
+	_, err = eventRepo.GetTicketType(otherOrgId, eventId, regularId)
+	assert.Error(t, err, "Ticket types of other organizations are not visible")
+}
+
+func TestRegisterEvent_TicketTypeInventory(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	eventId := createTestOrgEvent(t, eventRepo, orgId, "Conference")
+	vipId := createTestTicketType(t, eventRepo, orgId, eventId, "VIP", 9900, 1)
+	regularId := createTestTicketType(t, eventRepo, orgId, eventId, "Regular", 2500, 10)
+
+	registration := newTestRegistration(orgId, 5, eventId)
+	registration.TicketTypeId = vipId
+	registered, err := registerRepo.RegisterEvent(registration)
+	require.NoError(t, err)
+	assert.True(t, registered)
+
+	registration = newTestRegistration(orgId, 6, eventId)
+	registration.TicketTypeId = vipId
+	registered, err = registerRepo.RegisterEvent(registration)
+	require.NoError(t, err)
+	assert.False(t, registered, "Sold out ticket types take no more registrations")
+
+	registration.TicketTypeId = regularId
+	registered, err = registerRepo.RegisterEvent(registration)
+	require.NoError(t, err)
+	assert.True(t, registered, "Other ticket types keep their own inventory")
+
+	ticketTypes, err := registerRepo.GetTicketTypes(orgId, eventId)
+	require.NoError(t, err)
+	require.Len(t, ticketTypes, 2)
+	assert.Equal(t, int64(1), ticketTypes[0].Sold)
+	assert.Equal(t, int64(1), ticketTypes[1].Sold)
+
+	saved, err := registerRepo.GetRegisteredEventById(orgId, 6, eventId)
+	require.NoError(t, err)
+	assert.Equal(t, regularId, saved.TicketTypeId)
+
+	deleted, err := eventRepo.DeleteTicketType(orgId, eventId, vipId)
+	require.NoError(t, err)
+	assert.False(t, deleted, "Ticket types with registrations cannot be deleted")
+
+	require.NoError(t, registerRepo.CancelRegistration(orgId, saved.Id))
+	deleted, err = eventRepo.DeleteTicketType(orgId, eventId, regularId)
+	require.NoError(t, err)
+	assert.True(t, deleted, "Cancelled registrations do not keep a ticket type")
+}
+
+func TestSetRegistrationStatus_TicketTypeOverbooked(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	eventId := createTestOrgEvent(t, eventRepo, orgId, "Conference")
+	vipId := createTestTicketType(t, eventRepo, orgId, eventId, "VIP", 9900, 1)
+
+	ids := []int64{}
+	for userId := int64(5); userId <= 6; userId++ {
+		registration := newTestRegistration(orgId, userId, eventId)
+		registration.TicketTypeId = vipId
+		registration.Status = "pending"
+		_, err := registerRepo.RegisterEvent(registration)
+		require.NoError(t, err)
+		ids = append(ids, registration.Id)
+	}
+
+	updated, err := registerRepo.SetRegistrationStatus(orgId, eventId, ids, "approved")
+	require.NoError(t, err)
+	assert.False(t, updated, "Approving more registrations than tickets must fail")
+
+	updated, err = registerRepo.SetRegistrationStatus(orgId, eventId, ids[:1], "approved")
+	require.NoError(t, err)
+	assert.True(t, updated)
+}
@@ -0,0 +1,191 @@
+package db
+
+import (
+	"database/sql"
+	"event-booking/models"
+	"time"
+)
+
+type SqlRegistrationTransferRepository struct {
+	db           *sql.DB
+	registerRepo *SqlEventRegisterRepository
+	orgRepo      *SqlOrganizationRepository
+}
+
+func NewSqlRegistrationTransferRepository(database *sql.DB) *SqlRegistrationTransferRepository {
+	return &SqlRegistrationTransferRepository{
+		db:           database,
+		registerRepo: NewSqlEventRegisterRepository(database),
+		orgRepo:      NewSqlOrganizationRepository(database),
+	}
+}
+
+func (r *SqlRegistrationTransferRepository) GetEventById(orgId, id int64) (models.Event, error) {
+	return r.registerRepo.GetEventById(orgId, id)
+}
+
+func (r *SqlRegistrationTransferRepository) CanAccessEvent(orgId, eventId, userId int64) (bool, error) {
+	return r.registerRepo.CanAccessEvent(orgId, eventId, userId)
+}
+
+func (r *SqlRegistrationTransferRepository) GetRegistration(orgId, id int64) (models.RegisterEvent, error) {
+	return r.registerRepo.GetRegistration(orgId, id)
+}
+
+func (r *SqlRegistrationTransferRepository) GetRegisteredEventById(orgId, userId, eventId int64) (models.RegisterEvent, error) {
+	return r.registerRepo.GetRegisteredEventById(orgId, userId, eventId)
+}
+
+func (r *SqlRegistrationTransferRepository) GetUserById(id int64) (models.User, error) {
+	return r.registerRepo.GetUserById(id)
+}
+
+func (r *SqlRegistrationTransferRepository) GetUserByEmail(email string) (models.User, error) {
+	return r.orgRepo.GetUserByEmail(email)
+}
+
+func (r *SqlRegistrationTransferRepository) GetMembership(orgId, userId int64) (models.Membership, error) {
+	return r.orgRepo.GetMembership(orgId, userId)
+}
+
+const selectTransfers = `
+	SELECT t.id, t.registration_id, t.event_id, t.organization_id, t.from_user_id, COALESCE(f.email, ''),
+		t.to_user_id, COALESCE(u.email, ''), t.status, t.created_at, t.decided_at
+	FROM registration_transfers t
+	LEFT JOIN users f ON f.id = t.from_user_id
+	LEFT JOIN users u ON u.id = t.to_user_id
+`
+
+func scanTransfer(row rowScanner) (models.RegistrationTransfer, error) {
+	var t models.RegistrationTransfer
+	var decidedAt sql.NullTime
+	err := row.Scan(&t.Id, &t.RegistrationId, &t.EventId, &t.OrganizationId, &t.FromUserId, &t.FromEmail,
+		&t.ToUserId, &t.ToEmail, &t.Status, &t.CreatedAt, &decidedAt)
+	if decidedAt.Valid {
+		t.DecidedAt = &decidedAt.Time
+	}
+	return t, err
+}
+
+// CreateTransfer inserts the pending transfer. It returns false when the
+// registration already has a pending transfer.
+func (r *SqlRegistrationTransferRepository) CreateTransfer(t *models.RegistrationTransfer) (bool, error) {
+	query := `
+	INSERT INTO registration_transfers (registration_id, event_id, organization_id, from_user_id, to_user_id, status,
+		created_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT DO NOTHING;
+	`
+	result, err := r.db.Exec(query, t.RegistrationId, t.EventId, t.OrganizationId, t.FromUserId, t.ToUserId, t.Status,
+		t.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		return false, err
+	}
+
+	t.Id, err = result.LastInsertId()
+	return err == nil, err
+}
+
+func (r *SqlRegistrationTransferRepository) GetTransfer(orgId, id int64) (models.RegistrationTransfer, error) {
+	query := selectTransfers + `WHERE t.id = ? AND t.organization_id = ?;`
+	return scanTransfer(r.db.QueryRow(query, id, orgId))
+}
+
+func (r *SqlRegistrationTransferRepository) GetPendingTransfer(orgId, registrationId int64) (models.RegistrationTransfer, error) {
+	query := selectTransfers + `WHERE t.registration_id = ? AND t.organization_id = ? AND t.status = 'pending';`
+	return scanTransfer(r.db.QueryRow(query, registrationId, orgId))
+}
+
+// GetIncomingTransfers lists the pending transfers offered to userId,
+// oldest first.
+func (r *SqlRegistrationTransferRepository) GetIncomingTransfers(orgId, userId int64) ([]models.RegistrationTransfer, error) {
+	query := selectTransfers + `
+	WHERE t.to_user_id = ? AND t.organization_id = ? AND t.status = 'pending'
+	ORDER BY t.id;
+	`
+	rows, err := r.db.Query(query, userId, orgId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transfers := []models.RegistrationTransfer{}
+	for rows.Next() {
+		t, err := scanTransfer(rows)
+		if err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, t)
+	}
+	return transfers, nil
+}
+
+// SetTransferStatus ends the pending transfer with status. It returns false
+// when the transfer is no longer pending.
+func (r *SqlRegistrationTransferRepository) SetTransferStatus(orgId, id int64, status string, decidedAt time.Time) (bool, error) {
+	query := `
+	UPDATE registration_transfers SET status = ?, decided_at = ?
+	WHERE id = ? AND organization_id = ? AND status = 'pending';
+	`
+	result, err := r.db.Exec(query, status, decidedAt, id, orgId)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// AcceptTransfer hands the registration to the recipient in one
+// transaction and removes the sender's answers to the registration
+// questions. The registration stays approved throughout, so its seat is
+// never free for others to take. Nothing changes and false is returned when
+// the transfer is no longer pending, the registration is no longer approved
+// for the sender or was already checked in, or the recipient holds an
+// active registration for the event.
+func (r *SqlRegistrationTransferRepository) AcceptTransfer(t models.RegistrationTransfer, decidedAt time.Time) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `
+	UPDATE registration_transfers SET status = 'accepted', decided_at = ?
+	WHERE id = ? AND organization_id = ? AND status = 'pending';
+	`
+	result, err := tx.Exec(query, decidedAt, t.Id, t.OrganizationId)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		return false, err
+	}
+
+	query = `
+	UPDATE registrations SET user_id = ?
+	WHERE id = ? AND organization_id = ? AND user_id = ? AND status = 'approved' AND checked_in_at IS NULL
+		AND NOT EXISTS (
+			SELECT 1 FROM registrations r WHERE r.event_id = registrations.event_id AND r.user_id = ? AND ` + activeRegistration + `
+		);
+	`
+	result, err = tx.Exec(query, t.ToUserId, t.RegistrationId, t.OrganizationId, t.FromUserId, t.ToUserId)
+	if err != nil {
+		return false, err
+	}
+	affected, err = result.RowsAffected()
+	if err != nil || affected == 0 {
+		return false, err
+	}
+
+	_, err = tx.Exec(`DELETE FROM registration_answers WHERE registration_id = ?;`, t.RegistrationId)
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
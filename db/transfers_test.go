@@ -0,0 +1,176 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"event-booking/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTransfer(registration *models.RegisterEvent, toUserId int64) *models.RegistrationTransfer {
+	return &models.RegistrationTransfer{
+		RegistrationId: registration.Id,
+		EventId:        registration.EventId,
+		OrganizationId: registration.OrganizationId,
+		FromUserId:     registration.UserId,
+		ToUserId:       toUserId,
+		Status:         "pending",
+		CreatedAt:      time.Now(),
+	}
+}
+
+func TestAcceptTransfer_KeepsSeat(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+	repo := NewSqlRegistrationTransferRepository(testDB)
+
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	eventId, err := eventRepo.CreateEvent(&models.Event{
+		Name: "Workshop", Description: "Description", Location: "Location",
+		DateTime: time.Now().Add(24 * time.Hour), UserId: 1, OrganizationId: orgId, Visibility: "public", Capacity: 1,
+	})
+	require.NoError(t, err)
+	registration := newTestRegistration(orgId, 5, eventId)
+	_, err = registerRepo.RegisterEvent(registration)
+	require.NoError(t, err)
+
+	transfer := newTestTransfer(registration, 6)
+	created, err := repo.CreateTransfer(transfer)
+	require.NoError(t, err)
+	require.True(t, created)
+
+	registered, err := registerRepo.RegisterEvent(newTestRegistration(orgId, 7, eventId))
+	require.NoError(t, err)
+	assert.False(t, registered, "The seat is not released while the transfer is pending")
+
+	accepted, err := repo.AcceptTransfer(*transfer, time.Now())
+	require.NoError(t, err)
+	require.True(t, accepted)
+
+	saved, err := registerRepo.GetRegistration(orgId, registration.Id)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), saved.UserId)
+	assert.Equal(t, "approved", saved.Status)
+
+	saved2, err := repo.GetTransfer(orgId, transfer.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "accepted", saved2.Status)
+	assert.NotNil(t, saved2.DecidedAt)
+
+	checkedIn, err := registerRepo.CheckIn(orgId, eventId, registration.Id, 5, time.Now())
+	require.NoError(t, err)
+	assert.False(t, checkedIn, "The sender's ticket no longer works")
+	checkedIn, err = registerRepo.CheckIn(orgId, eventId, registration.Id, 6, time.Now())
+	require.NoError(t, err)
+	assert.True(t, checkedIn)
+
+	accepted, err = repo.AcceptTransfer(*transfer, time.Now())
+	require.NoError(t, err)
+	assert.False(t, accepted, "Transfers are accepted once")
+}
+
+func TestCreateTransfer_OnePendingPerRegistration(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+	repo := NewSqlRegistrationTransferRepository(testDB)
+
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	eventId := createTestOrgEvent(t, eventRepo, orgId, "Conference")
+	registration := newTestRegistration(orgId, 5, eventId)
+	_, err := registerRepo.RegisterEvent(registration)
+	require.NoError(t, err)
+
+	first := newTestTransfer(registration, 6)
+	created, err := repo.CreateTransfer(first)
+	require.NoError(t, err)
+	require.True(t, created)
+
+	created, err = repo.CreateTransfer(newTestTransfer(registration, 7))
+	require.NoError(t, err)
+	assert.False(t, created)
+
+	declined, err := repo.SetTransferStatus(orgId, first.Id, "declined", time.Now())
+	require.NoError(t, err)
+	require.True(t, declined)
+
+	created, err = repo.CreateTransfer(newTestTransfer(registration, 7))
+	require.NoError(t, err)
+	assert.True(t, created, "A new transfer can follow a declined one")
+
+	incoming, err := repo.GetIncomingTransfers(orgId, 7)
+	require.NoError(t, err)
+	require.Len(t, incoming, 1)
+	assert.Equal(t, int64(5), incoming[0].FromUserId)
+
+	incoming, err = repo.GetIncomingTransfers(orgId+1, 7)
+	require.NoError(t, err)
+	assert.Empty(t, incoming, "Transfers of other organizations are not visible")
+}
+
+func TestAcceptTransfer_RecipientAlreadyRegistered(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+	repo := NewSqlRegistrationTransferRepository(testDB)
+
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	eventId := createTestOrgEvent(t, eventRepo, orgId, "Conference")
+	registration := newTestRegistration(orgId, 5, eventId)
+	_, err := registerRepo.RegisterEvent(registration)
+	require.NoError(t, err)
+	transfer := newTestTransfer(registration, 6)
+	_, err = repo.CreateTransfer(transfer)
+	require.NoError(t, err)
+	_, err = registerRepo.RegisterEvent(newTestRegistration(orgId, 6, eventId))
+	require.NoError(t, err)
+
+	accepted, err := repo.AcceptTransfer(*transfer, time.Now())
+
+	require.NoError(t, err)
+	assert.False(t, accepted)
+	saved, err := repo.GetTransfer(orgId, transfer.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "pending", saved.Status, "Nothing changes when the transfer fails")
+	kept, err := registerRepo.GetRegistration(orgId, registration.Id)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), kept.UserId)
+}
+
+func TestCancelRegistration_CancelsPendingTransfer(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+	repo := NewSqlRegistrationTransferRepository(testDB)
+
+	orgId := createTestOrganization(t, orgRepo, "Acme", 1)
+	eventId := createTestOrgEvent(t, eventRepo, orgId, "Conference")
+	registration := newTestRegistration(orgId, 5, eventId)
+	_, err := registerRepo.RegisterEvent(registration)
+	require.NoError(t, err)
+	transfer := newTestTransfer(registration, 6)
+	_, err = repo.CreateTransfer(transfer)
+	require.NoError(t, err)
+
+	require.NoError(t, registerRepo.CancelRegistration(orgId, registration.Id))
+
+	saved, err := repo.GetTransfer(orgId, transfer.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "cancelled", saved.Status)
+}
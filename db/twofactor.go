@@ -0,0 +1,128 @@
+package db
+
+import (
+	"database/sql"
+	"event-booking/models"
+	"time"
+)
+
+type SqlTwoFactorRepository struct {
+	db       *sql.DB
+	userRepo *SqlUserRepository
+}
+
+func NewSqlTwoFactorRepository(database *sql.DB) *SqlTwoFactorRepository {
+	return &SqlTwoFactorRepository{
+		db:       database,
+		userRepo: NewSqlUserRepository(database),
+	}
+}
+
+func (r *SqlTwoFactorRepository) GetUserById(id int64) (models.User, error) {
+	return r.userRepo.GetUserById(id)
+}
+
+func (r *SqlTwoFactorRepository) GetTwoFactor(userId int64) (models.TwoFactor, error) {
+	query := `SELECT user_id, secret, enabled, last_used_step FROM two_factor WHERE user_id = ?;`
+	row := r.db.QueryRow(query, userId)
+
+	var t models.TwoFactor
+	err := row.Scan(&t.UserId, &t.Secret, &t.Enabled, &t.LastUsedStep)
+	return t, err
+}
+
+// SaveTwoFactorSecret stores a new secret for enrollment. Enrollment only
+// replaces secrets that have not been enabled yet.
+func (r *SqlTwoFactorRepository) SaveTwoFactorSecret(userId int64, secret string) error {
+	query := `
+	INSERT INTO two_factor (user_id, secret, enabled, last_used_step)
+	VALUES (?, ?, 0, 0)
+	ON CONFLICT(user_id) DO UPDATE SET secret = excluded.secret, last_used_step = 0
+	WHERE two_factor.enabled = 0;
+	`
+	_, err := r.db.Exec(query, userId, secret)
+	return err
+}
+
+// EnableTwoFactor turns on two-factor authentication and replaces the user's
+// recovery codes in a single transaction.
+func (r *SqlTwoFactorRepository) EnableTwoFactor(userId int64, step int64, recoveryCodeHashes []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`UPDATE two_factor SET enabled = 1, last_used_step = ? WHERE user_id = ?;`, step, userId)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM recovery_codes WHERE user_id = ?;`, userId)
+	if err != nil {
+		return err
+	}
+
+	for _, hash := range recoveryCodeHashes {
+		_, err = tx.Exec(`INSERT INTO recovery_codes (user_id, code_hash) VALUES (?, ?);`, userId, hash)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *SqlTwoFactorRepository) DisableTwoFactor(userId int64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`DELETE FROM two_factor WHERE user_id = ?;`, userId)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM recovery_codes WHERE user_id = ?;`, userId)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// MarkTOTPStepUsed records step as the last accepted code. It reports false
+// when a code from this or a later step was already used, which rejects
+// replays.
+func (r *SqlTwoFactorRepository) MarkTOTPStepUsed(userId int64, step int64) (bool, error) {
+	query := `UPDATE two_factor SET last_used_step = ? WHERE user_id = ? AND last_used_step < ?;`
+	result, err := r.db.Exec(query, step, userId, step)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+// UseRecoveryCode consumes a recovery code. It reports false when the code is
+// unknown or was already used.
+func (r *SqlTwoFactorRepository) UseRecoveryCode(userId int64, codeHash string, now time.Time) (bool, error) {
+	query := `
+	UPDATE recovery_codes SET used_at = ?
+	WHERE id = (
+		SELECT id FROM recovery_codes
+		WHERE user_id = ? AND code_hash = ? AND used_at IS NULL
+		LIMIT 1
+	);
+	`
+	result, err := r.db.Exec(query, now, userId, codeHash)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
@@ -0,0 +1,129 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"event-booking/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveTwoFactorSecret(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlTwoFactorRepository(testDB)
+
+	require.NoError(t, repo.SaveTwoFactorSecret(1, "SECRET1"))
+	require.NoError(t, repo.SaveTwoFactorSecret(1, "SECRET2"))
+
+	twoFactor, err := repo.GetTwoFactor(1)
+
+	require.NoError(t, err)
+	assert.Equal(t, models.TwoFactor{UserId: 1, Secret: "SECRET2"}, twoFactor)
+}
+
+func TestSaveTwoFactorSecret_DoesNotReplaceEnabledSecret(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlTwoFactorRepository(testDB)
+
+	require.NoError(t, repo.SaveTwoFactorSecret(1, "SECRET1"))
+	require.NoError(t, repo.EnableTwoFactor(1, 100, []string{"hash"}))
+	require.NoError(t, repo.SaveTwoFactorSecret(1, "SECRET2"))
+
+	twoFactor, err := repo.GetTwoFactor(1)
+
+	require.NoError(t, err)
+	assert.Equal(t, "SECRET1", twoFactor.Secret)
+	assert.True(t, twoFactor.Enabled)
+}
+
+func TestEnableTwoFactor_ShowsInUser(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	userRepo := NewSqlUserRepository(testDB)
+	repo := NewSqlTwoFactorRepository(testDB)
+
+	id, err := userRepo.CreateUser(&models.User{Email: "test@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	require.NoError(t, repo.SaveTwoFactorSecret(id, "SECRET"))
+	require.NoError(t, repo.EnableTwoFactor(id, 100, []string{"hash-1", "hash-2"}))
+
+	user, err := repo.GetUserById(id)
+	require.NoError(t, err)
+	assert.True(t, user.TwoFactorEnabled)
+
+	loginUser := &models.User{Email: "test@example.com", Password: "password123"}
+	_, err = userRepo.ValidateCredentials(loginUser)
+	require.NoError(t, err)
+	assert.True(t, loginUser.TwoFactorEnabled)
+}
+
+func TestMarkTOTPStepUsed_RejectsReplay(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlTwoFactorRepository(testDB)
+
+	require.NoError(t, repo.SaveTwoFactorSecret(1, "SECRET"))
+	require.NoError(t, repo.EnableTwoFactor(1, 100, nil))
+
+	ok, err := repo.MarkTOTPStepUsed(1, 100)
+	require.NoError(t, err)
+	assert.False(t, ok, "The enrollment code should not be reusable")
+
+	ok, err = repo.MarkTOTPStepUsed(1, 101)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = repo.MarkTOTPStepUsed(1, 101)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestUseRecoveryCode(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlTwoFactorRepository(testDB)
+
+	require.NoError(t, repo.SaveTwoFactorSecret(1, "SECRET"))
+	require.NoError(t, repo.EnableTwoFactor(1, 100, []string{"hash-1", "hash-2"}))
+
+	used, err := repo.UseRecoveryCode(1, "hash-1", time.Now())
+	require.NoError(t, err)
+	assert.True(t, used)
+
+	used, err = repo.UseRecoveryCode(1, "hash-1", time.Now())
+	require.NoError(t, err)
+	assert.False(t, used, "Recovery codes should be single-use")
+
+	used, err = repo.UseRecoveryCode(2, "hash-2", time.Now())
+	require.NoError(t, err)
+	assert.False(t, used, "Recovery codes belong to one user")
+}
+
+func TestDisableTwoFactor(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlTwoFactorRepository(testDB)
+
+	require.NoError(t, repo.SaveTwoFactorSecret(1, "SECRET"))
+	require.NoError(t, repo.EnableTwoFactor(1, 100, []string{"hash-1"}))
+
+	require.NoError(t, repo.DisableTwoFactor(1))
+
+	_, err := repo.GetTwoFactor(1)
+	assert.Error(t, err)
+
+	used, err := repo.UseRecoveryCode(1, "hash-1", time.Now())
+	require.NoError(t, err)
+	assert.False(t, used)
+}
@@ -2,10 +2,29 @@ package db
 
 import (
 	"database/sql"
+	"errors"
 	"event-booking/models"
 	"event-booking/utils"
+	"time"
 )
 
+// selectUsers reads the columns scanned by scanUser.
+const selectUsers = `
+	SELECT u.id, u.email, u.password, u.verified, COALESCE(t.enabled, 0), u.token_version
+	FROM users u
+	LEFT JOIN two_factor t ON t.user_id = u.id
+`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanUser(row rowScanner) (models.User, error) {
+	var u models.User
+	err := row.Scan(&u.Id, &u.Email, &u.Password, &u.Verified, &u.TwoFactorEnabled, &u.TokenVersion)
+	return u, err
+}
+
 type SqlUserRepository struct {
 	db *sql.DB
 }
@@ -16,10 +35,18 @@ func NewSqlUserRepository(database *sql.DB) *SqlUserRepository {
 	}
 }
 
+var ErrEmailTaken = errors.New("Email address is already in use")
+
+// Email addresses are stored as they were typed, but compared without regard
+// to case: emailMatches compares the column email with ?1.
+const emailMatches = `email = ?1 COLLATE NOCASE`
+
+// CreateUser inserts the user unless another user has the email address in
+// any case, when ErrEmailTaken is returned.
 func (r *SqlUserRepository) CreateUser(u *models.User) (int64, error) {
 	query := `
 	INSERT INTO users (email, password)
-	VALUES (?, ?);
+	SELECT ?1, ?2 WHERE NOT EXISTS (SELECT 1 FROM users WHERE ` + emailMatches + `);
 	`
 	hashedPassword, err := utils.HashPassword(u.Password)
 	if err != nil {
@@ -29,6 +56,13 @@ func (r *SqlUserRepository) CreateUser(u *models.User) (int64, error) {
 	if err != nil {
 		return 0, err
 	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if affected == 0 {
+		return 0, ErrEmailTaken
+	}
 
 	id, err := result.LastInsertId()
 	return int64(id), err
@@ -36,12 +70,15 @@ func (r *SqlUserRepository) CreateUser(u *models.User) (int64, error) {
 
 func (r *SqlUserRepository) ValidateCredentials(u *models.User) (bool, error) {
 	query := `
-	SELECT id, password FROM users WHERE email = ?
+	SELECT u.id, u.password, u.verified, u.token_version, COALESCE(t.enabled, 0)
+	FROM users u
+	LEFT JOIN two_factor t ON t.user_id = u.id
+	WHERE u.` + emailMatches + `
 	`
 	row := r.db.QueryRow(query, u.Email)
 
 	var retrievedPassword string
-	err := row.Scan(&u.Id, &retrievedPassword)
+	err := row.Scan(&u.Id, &retrievedPassword, &u.Verified, &u.TokenVersion, &u.TwoFactorEnabled)
 	if err != nil {
 		return false, err
 	}
@@ -50,18 +87,18 @@ func (r *SqlUserRepository) ValidateCredentials(u *models.User) (bool, error) {
 
 }
 
-func (r *SqlUserRepository) GetUsers() ([]models.User, error) {
-	query := `SELECT * FROM users;`
-	rows, err := r.db.Query(query)
+// GetUsers lists the active members of organization orgId.
+func (r *SqlUserRepository) GetUsers(orgId int64) ([]models.OrganizationMember, error) {
+	query := selectMembers + `WHERE m.organization_id = ? AND u.deleted_at IS NULL ORDER BY u.id;`
+	rows, err := r.db.Query(query, orgId)
 	if err != nil {
 		return nil, err
 	}
 
 	defer rows.Close()
-	users := []models.User{}
+	users := []models.OrganizationMember{}
 	for rows.Next() {
-		var u models.User
-		err := rows.Scan(&u.Id, &u.Email, &u.Password)
+		u, err := scanMember(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -70,3 +107,396 @@ func (r *SqlUserRepository) GetUsers() ([]models.User, error) {
 
 	return users, nil
 }
+
+func (r *SqlUserRepository) GetUserById(id int64) (models.User, error) {
+	query := selectUsers + `WHERE u.id = ?;`
+	return scanUser(r.db.QueryRow(query, id))
+}
+
+func (r *SqlUserRepository) GetUserByEmail(email string) (models.User, error) {
+	query := selectUsers + `WHERE u.` + emailMatches + `;`
+	return scanUser(r.db.QueryRow(query, email))
+}
+
+// CreateEmailVerification records a verification token for email, which is
+// either the user's current address or the address they are changing to.
+func (r *SqlUserRepository) CreateEmailVerification(userId int64, email, tokenId string, expiresAt time.Time) error {
+	query := `
+	INSERT INTO email_verifications (token_id, user_id, email, expires_at)
+	VALUES (?, ?, ?, ?);
+	`
+	_, err := r.db.Exec(query, tokenId, userId, email, expiresAt)
+	return err
+}
+
+const selectEmailVerification = `
+	SELECT email FROM email_verifications
+	WHERE token_id = ? AND user_id = ? AND used_at IS NULL AND expires_at > ?;
+`
+
+// VerifyEmail consumes the verification token and makes the verified address
+// the user's email in a single transaction. Using one token invalidates the
+// user's other verification links. It reports false when the token is
+// unknown, expired or has already been used, and when another user has the
+// address by now, in which case the token is left unused.
+func (r *SqlUserRepository) VerifyEmail(userId int64, tokenId string, now time.Time) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var email string
+	err = tx.QueryRow(selectEmailVerification, tokenId, userId, now).Scan(&email)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	query := `
+	UPDATE users SET email = ?1, verified = 1
+	WHERE id = ?2 AND deleted_at IS NULL AND NOT EXISTS (SELECT 1 FROM users WHERE ` + emailMatches + ` AND id != ?2);
+	`
+	result, err := tx.Exec(query, email, userId)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		return false, err
+	}
+
+	_, err = tx.Exec(`UPDATE email_verifications SET used_at = ? WHERE user_id = ? AND used_at IS NULL;`, now, userId)
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// HasEmailVerification reports whether the verification token can still be
+// used, see VerifyEmail.
+func (r *SqlUserRepository) HasEmailVerification(userId int64, tokenId string, now time.Time) (bool, error) {
+	var email string
+	err := r.db.QueryRow(selectEmailVerification, tokenId, userId, now).Scan(&email)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *SqlUserRepository) CreatePasswordReset(userId int64, tokenHash string, expiresAt, createdAt time.Time) error {
+	query := `
+	INSERT INTO password_resets (token_hash, user_id, expires_at, created_at)
+	VALUES (?, ?, ?, ?);
+	`
+	_, err := r.db.Exec(query, tokenHash, userId, expiresAt, createdAt)
+	return err
+}
+
+func (r *SqlUserRepository) CountPasswordResetsSince(userId int64, since time.Time) (int, error) {
+	query := `SELECT COUNT(*) FROM password_resets WHERE user_id = ? AND created_at >= ?;`
+	row := r.db.QueryRow(query, userId, since)
+
+	var count int
+	err := row.Scan(&count)
+	return count, err
+}
+
+// HasPasswordReset reports whether the reset token can still be used.
+func (r *SqlUserRepository) HasPasswordReset(tokenHash string, now time.Time) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM password_resets WHERE token_hash = ? AND used_at IS NULL AND expires_at > ?);`
+	var exists bool
+	err := r.db.QueryRow(query, tokenHash, now).Scan(&exists)
+	return exists, err
+}
+
+// ResetPassword consumes the reset token, stores the new password, bumps the
+// user's token version so existing sessions stop working and deletes the
+// user's API keys. Completing a
+// reset also proves ownership of the email address. It reports false when the
+// token is unknown, expired or has already been used.
+func (r *SqlUserRepository) ResetPassword(tokenHash, newPassword string, now time.Time) (bool, error) {
+	hashedPassword, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return false, err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `
+	SELECT user_id FROM password_resets
+	WHERE token_hash = ? AND used_at IS NULL AND expires_at > ?;
+	`
+	var userId int64
+	err = tx.QueryRow(query, tokenHash, now).Scan(&userId)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// Using one token invalidates every other outstanding reset link.
+	_, err = tx.Exec(`UPDATE password_resets SET used_at = ? WHERE user_id = ? AND used_at IS NULL;`, now, userId)
+	if err != nil {
+		return false, err
+	}
+
+	query = `
+	UPDATE users
+	SET password = ?, verified = 1, token_version = token_version + 1
+	WHERE id = ?;
+	`
+	_, err = tx.Exec(query, hashedPassword, userId)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = tx.Exec(`DELETE FROM api_keys WHERE user_id = ?;`, userId)
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// UpdatePassword stores a new password, bumps the user's token version so
+// existing sessions stop working and deletes the user's API keys.
+func (r *SqlUserRepository) UpdatePassword(id int64, newPassword string) error {
+	hashedPassword, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+	UPDATE users
+	SET password = ?, token_version = token_version + 1
+	WHERE id = ?;
+	`
+	_, err = tx.Exec(query, hashedPassword, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM api_keys WHERE user_id = ?;`, id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// heldRegistration matches registrations r the user ?1 holds, which are their
+// own, those of guests they booked and those for the events they own.
+const heldRegistration = `(COALESCE(r.user_id, (SELECT booker_id FROM registration_groups WHERE id = r.group_id)) = ?1
+	OR r.event_id IN (SELECT id FROM events WHERE user_id = ?1))`
+
+// HasPaidRegistrations reports whether the user holds active registrations
+// that were paid for, or owns events with cancellations whose refund failed,
+// see DeleteUser.
+func (r *SqlUserRepository) HasPaidRegistrations(id int64) (bool, error) {
+	return hasPaidRegistrations(r.db.QueryRow(paidRegistrationsHeld, id))
+}
+
+const paidRegistrationsHeld = `
+	SELECT EXISTS (
+		SELECT 1 FROM registrations r
+		JOIN orders o ON (o.registration_id = r.id OR o.group_id = r.group_id) AND o.status = 'paid'
+		WHERE ` + heldRegistration + ` AND ` + activeRegistration + `
+	) OR EXISTS (
+		SELECT 1 FROM cancellations
+		WHERE refund_status = 'failed' AND event_id IN (SELECT id FROM events WHERE user_id = ?1)
+	);
+`
+
+func hasPaidRegistrations(row rowScanner) (bool, error) {
+	var paid bool
+	err := row.Scan(&paid)
+	return paid, err
+}
+
+// DeleteUser anonymises the user, revokes their sessions and deletes the
+// events they own. The registrations they hold are cancelled and kept with
+// a cancellation record, like orders are kept as a record of payments and
+// refunds, and pending transfers from or to the user are cancelled. The last
+// owner of an organization is not deleted and false is returned, as is the
+// case when the user holds paid registrations, which have to be cancelled
+// and refunded first.
+func (r *SqlUserRepository) DeleteUser(id int64, now time.Time) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var lastOwner bool
+	query := `
+	SELECT EXISTS (
+		SELECT 1 FROM organization_members m
+		WHERE m.user_id = ? AND m.role = 'owner' AND NOT EXISTS (
+			SELECT 1 FROM organization_members o
+			WHERE o.organization_id = m.organization_id AND o.role = 'owner' AND o.user_id != m.user_id
+		)
+	);
+	`
+	err = tx.QueryRow(query, id).Scan(&lastOwner)
+	if err != nil || lastOwner {
+		return false, err
+	}
+
+	paid, err := hasPaidRegistrations(tx.QueryRow(paidRegistrationsHeld, id))
+	if err != nil || paid {
+		return false, err
+	}
+
+	err = cancelHeldRegistrations(tx, id, now)
+	if err != nil {
+		return false, err
+	}
+
+	queries := []string{
+		`DELETE FROM registration_answers WHERE registration_id IN (SELECT id FROM registrations WHERE user_id = ?);`,
+		`DELETE FROM registration_answers WHERE registration_id IN (
+			SELECT r.id FROM registrations r JOIN events e ON e.id = r.event_id WHERE e.user_id = ?
+		);`,
+		`DELETE FROM event_members WHERE user_id = ?;`,
+		`DELETE FROM event_members WHERE event_id IN (SELECT id FROM events WHERE user_id = ?);`,
+		`DELETE FROM event_invitations WHERE user_id = ?;`,
+		`DELETE FROM event_invitations WHERE event_id IN (SELECT id FROM events WHERE user_id = ?);`,
+		`DELETE FROM registration_questions WHERE event_id IN (SELECT id FROM events WHERE user_id = ?);`,
+		`DELETE FROM ticket_types WHERE event_id IN (SELECT id FROM events WHERE user_id = ?);`,
+		`DELETE FROM promo_codes WHERE event_id IN (SELECT id FROM events WHERE user_id = ?);`,
+		`DELETE FROM cancellation_policies WHERE event_id IN (SELECT id FROM events WHERE user_id = ?);`,
+		`DELETE FROM events WHERE user_id = ?;`,
+		`DELETE FROM email_verifications WHERE user_id = ?;`,
+		`DELETE FROM password_resets WHERE user_id = ?;`,
+		`DELETE FROM profiles WHERE user_id = ?;`,
+		`DELETE FROM two_factor WHERE user_id = ?;`,
+		`DELETE FROM recovery_codes WHERE user_id = ?;`,
+		`DELETE FROM api_keys WHERE user_id = ?;`,
+		`DELETE FROM external_identities WHERE user_id = ?;`,
+		`DELETE FROM organization_members WHERE user_id = ?;`,
+		`UPDATE registration_transfers SET status = 'cancelled', decided_at = CURRENT_TIMESTAMP
+			WHERE status = 'pending' AND (from_user_id = ?1 OR to_user_id = ?1);`,
+	}
+	for _, query := range queries {
+		_, err = tx.Exec(query, id)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	// The row is kept so foreign keys stay intact. The password is replaced
+	// with a value that is not a bcrypt hash, so it can never match.
+	query = `
+	UPDATE users
+	SET email = 'deleted-' || id || '@deleted.invalid', password = '!', verified = 0,
+		token_version = token_version + 1, deleted_at = ?
+	WHERE id = ?;
+	`
+	_, err = tx.Exec(query, now, id)
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// cancelHeldRegistrations cancels the active registrations the user holds
+// within tx and records their cancellations, which have nothing to refund.
+func cancelHeldRegistrations(tx *sql.Tx, userId int64, now time.Time) error {
+	query := `
+		SELECT r.id, COALESCE(r.user_id, 0), r.event_id, r.organization_id FROM registrations r
+		WHERE ` + heldRegistration + ` AND ` + activeRegistration + `;
+	`
+	rows, err := tx.Query(query, userId)
+	if err != nil {
+		return err
+	}
+
+	cancellations := []models.Cancellation{}
+	for rows.Next() {
+		c := models.Cancellation{RefundStatus: "none", CancelledAt: now}
+		err = rows.Scan(&c.RegistrationId, &c.UserId, &c.EventId, &c.OrganizationId)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		cancellations = append(cancellations, c)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, c := range cancellations {
+		cancelled, err := cancelRegistration(tx, c.OrganizationId, c.RegistrationId)
+		if err != nil {
+			return err
+		}
+		if !cancelled {
+			continue
+		}
+		err = insertCancellation(tx, &c)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetProfile returns the profile of an active user. Users who never saved a
+// profile get an empty one.
+func (r *SqlUserRepository) GetProfile(userId int64) (models.Profile, error) {
+	query := `
+	SELECT u.id, COALESCE(p.display_name, ''), COALESCE(p.avatar_url, ''), COALESCE(p.bio, ''),
+		COALESCE(p.time_zone, ''), COALESCE(p.locale, '')
+	FROM users u
+	LEFT JOIN profiles p ON p.user_id = u.id
+	WHERE u.id = ? AND u.deleted_at IS NULL;
+	`
+	row := r.db.QueryRow(query, userId)
+
+	var p models.Profile
+	err := row.Scan(&p.UserId, &p.DisplayName, &p.AvatarURL, &p.Bio, &p.TimeZone, &p.Locale)
+	return p, err
+}
+
+// IsEventOrganizer reports whether the user owns or co-organizes an event.
+func (r *SqlUserRepository) IsEventOrganizer(userId int64) (bool, error) {
+	query := `
+	SELECT EXISTS (SELECT 1 FROM events WHERE user_id = ?1)
+		OR EXISTS (SELECT 1 FROM event_members WHERE user_id = ?1 AND role = 'co_organizer');
+	`
+	var organizer bool
+	err := r.db.QueryRow(query, userId).Scan(&organizer)
+	return organizer, err
+}
+
+func (r *SqlUserRepository) SaveProfile(p *models.Profile) error {
+	query := `
+	INSERT INTO profiles (user_id, display_name, avatar_url, bio, time_zone, locale)
+	VALUES (?, ?, ?, ?, ?, ?)
+	ON CONFLICT(user_id) DO UPDATE SET
+		display_name = excluded.display_name,
+		avatar_url = excluded.avatar_url,
+		bio = excluded.bio,
+		time_zone = excluded.time_zone,
+		locale = excluded.locale;
+	`
+	_, err := r.db.Exec(query, p.UserId, p.DisplayName, p.AvatarURL, p.Bio, p.TimeZone, p.Locale)
+	return err
+}
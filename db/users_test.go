@@ -2,8 +2,10 @@ package db
 
 import (
 	"testing"
+	"time"
 
 	"event-booking/models"
+	"event-booking/utils"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -25,9 +27,9 @@ func TestCreateUser(t *testing.T) {
 	require.NoError(t, err)
 	assert.Greater(t, id, int64(0))
 
-	users, _ := repo.GetUsers()
-	require.Len(t, users, 1)
-	assert.NotEqual(t, "password123", users[0].Password, "Password should be hashed in database")
+	saved, err := repo.GetUserByEmail("test@example.com")
+	require.NoError(t, err)
+	assert.NotEqual(t, "password123", saved.Password, "Password should be hashed in database")
 }
 
 func TestCreateUser_DuplicateEmail(t *testing.T) {
@@ -49,8 +51,31 @@ func TestCreateUser_DuplicateEmail(t *testing.T) {
 	require.NoError(t, err1)
 
 	_, err2 := repo.CreateUser(user2)
-	require.Error(t, err2, "Should fail due to UNIQUE constraint")
-	assert.Contains(t, err2.Error(), "UNIQUE")
+	require.Error(t, err2)
+	assert.Equal(t, ErrEmailTaken, err2)
+
+	_, err3 := repo.CreateUser(&models.User{Email: "Duplicate@Example.com", Password: "password789"})
+	assert.Equal(t, ErrEmailTaken, err3, "Email addresses are compared without regard to case")
+}
+
+func TestGetUserByEmail_IgnoresCase(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlUserRepository(testDB)
+
+	id, err := repo.CreateUser(&models.User{Email: "Alice@Corp.com", Password: "password123"})
+	require.NoError(t, err)
+
+	user, err := repo.GetUserByEmail("alice@corp.com")
+	require.NoError(t, err)
+	assert.Equal(t, id, user.Id)
+	assert.Equal(t, "Alice@Corp.com", user.Email, "Addresses are stored as typed")
+
+	login := &models.User{Email: "ALICE@corp.com", Password: "password123"}
+	valid, err := repo.ValidateCredentials(login)
+	require.NoError(t, err)
+	assert.True(t, valid)
 }
 
 func TestCreateUser_PasswordTooShort(t *testing.T) {
@@ -141,21 +166,23 @@ func TestGetUsers(t *testing.T) {
 	defer TeardownTestDB(t, testDB)
 
 	repo := NewSqlUserRepository(testDB)
+	orgRepo := NewSqlOrganizationRepository(testDB)
 
-	user1 := &models.User{Email: "user1@example.com", Password: "password123"}
-	user2 := &models.User{Email: "user2@example.com", Password: "password456"}
-
-	repo.CreateUser(user1)
-	repo.CreateUser(user2)
+	user1, _ := repo.CreateUser(&models.User{Email: "user1@example.com", Password: "password123"})
+	user2, _ := repo.CreateUser(&models.User{Email: "user2@example.com", Password: "password456"})
+	outsider, _ := repo.CreateUser(&models.User{Email: "outsider@example.com", Password: "password789"})
+	orgId := createTestOrganization(t, orgRepo, "Acme", user1)
+	require.NoError(t, orgRepo.AddMember(&models.OrganizationMember{OrganizationId: orgId, UserId: user2, Role: "member", CreatedAt: time.Now()}))
+	createTestOrganization(t, orgRepo, "Other", outsider)
 
-	users, err := repo.GetUsers()
+	users, err := repo.GetUsers(orgId)
 
 	require.NoError(t, err)
-	assert.Len(t, users, 2)
+	require.Len(t, users, 2, "Users of other organizations are not listed")
 	assert.Equal(t, "user1@example.com", users[0].Email)
+	assert.Equal(t, "owner", users[0].Role)
 	assert.Equal(t, "user2@example.com", users[1].Email)
-	assert.NotEqual(t, "password123", users[0].Password)
-	assert.NotEqual(t, "password456", users[1].Password)
+	assert.Equal(t, "member", users[1].Role)
 }
 
 func TestGetUsers_EmptyTable(t *testing.T) {
@@ -164,8 +191,452 @@ func TestGetUsers_EmptyTable(t *testing.T) {
 
 	repo := NewSqlUserRepository(testDB)
 
-	users, err := repo.GetUsers()
+	users, err := repo.GetUsers(1)
+
+	require.NoError(t, err)
+	assert.Empty(t, users)
+}
+
+func TestGetUserById(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlUserRepository(testDB)
+
+	id, err := repo.CreateUser(&models.User{Email: "test@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	user, err := repo.GetUserById(id)
+
+	require.NoError(t, err)
+	assert.Equal(t, id, user.Id)
+	assert.Equal(t, "test@example.com", user.Email)
+	assert.False(t, user.Verified, "New users should not be verified")
+
+	byEmail, err := repo.GetUserByEmail("test@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, id, byEmail.Id)
+}
+
+func TestGetUserByEmail_NotFound(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlUserRepository(testDB)
+
+	_, err := repo.GetUserByEmail("missing@example.com")
+
+	require.Error(t, err)
+}
+
+func TestVerifyEmail(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlUserRepository(testDB)
+
+	id, err := repo.CreateUser(&models.User{Email: "test@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	now := time.Now()
+	err = repo.CreateEmailVerification(id, "test@example.com", "token-1", now.Add(time.Hour))
+	require.NoError(t, err)
+
+	verified, err := repo.VerifyEmail(id, "token-1", now)
+	require.NoError(t, err)
+	assert.True(t, verified)
+
+	loginUser := &models.User{Email: "test@example.com", Password: "password123"}
+	valid, err := repo.ValidateCredentials(loginUser)
+	require.NoError(t, err)
+	assert.True(t, valid)
+	assert.True(t, loginUser.Verified)
+
+	reused, err := repo.VerifyEmail(id, "token-1", now)
+	require.NoError(t, err)
+	assert.False(t, reused, "Verification tokens should be single-use")
+}
+
+func TestVerifyEmail_ExpiredOrWrongUser(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlUserRepository(testDB)
+
+	now := time.Now()
+	require.NoError(t, repo.CreateEmailVerification(1, "test@example.com", "expired", now.Add(-time.Minute)))
+	require.NoError(t, repo.CreateEmailVerification(1, "test@example.com", "other-user", now.Add(time.Hour)))
+
+	verified, err := repo.VerifyEmail(1, "expired", now)
+	require.NoError(t, err)
+	assert.False(t, verified)
+
+	verified, err = repo.VerifyEmail(2, "other-user", now)
+	require.NoError(t, err)
+	assert.False(t, verified)
+}
+
+func TestCountPasswordResetsSince(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlUserRepository(testDB)
+
+	now := time.Now()
+	require.NoError(t, repo.CreatePasswordReset(1, "hash-old", now.Add(time.Hour), now.Add(-2*time.Hour)))
+	require.NoError(t, repo.CreatePasswordReset(1, "hash-1", now.Add(time.Hour), now.Add(-10*time.Minute)))
+	require.NoError(t, repo.CreatePasswordReset(1, "hash-2", now.Add(time.Hour), now))
+	require.NoError(t, repo.CreatePasswordReset(2, "hash-3", now.Add(time.Hour), now))
+
+	count, err := repo.CountPasswordResetsSince(1, now.Add(-time.Hour))
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestResetPassword(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlUserRepository(testDB)
+
+	id, err := repo.CreateUser(&models.User{Email: "test@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	now := time.Now()
+	require.NoError(t, repo.CreatePasswordReset(id, "hash-1", now.Add(time.Hour), now))
+	require.NoError(t, repo.CreatePasswordReset(id, "hash-2", now.Add(time.Hour), now))
+	keyRepo := NewSqlAPIKeyRepository(testDB)
+	require.NoError(t, keyRepo.CreateAPIKey(&models.APIKey{UserId: id, Name: "ci", Prefix: "0123456789ab", SecretHash: "hash", Scopes: []string{"events:read"}, CreatedAt: now}))
+
+	usable, err := repo.HasPasswordReset("hash-1", now)
+	require.NoError(t, err)
+	assert.True(t, usable)
+
+	ok, err := repo.ResetPassword("hash-1", "newpassword123", now)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	usable, err = repo.HasPasswordReset("hash-1", now)
+	require.NoError(t, err)
+	assert.False(t, usable, "Used reset tokens cannot be used again")
+
+	oldLogin := &models.User{Email: "test@example.com", Password: "password123"}
+	valid, err := repo.ValidateCredentials(oldLogin)
+	require.NoError(t, err)
+	assert.False(t, valid, "Old password should no longer work")
+
+	newLogin := &models.User{Email: "test@example.com", Password: "newpassword123"}
+	valid, err = repo.ValidateCredentials(newLogin)
+	require.NoError(t, err)
+	assert.True(t, valid)
+	assert.Equal(t, int64(1), newLogin.TokenVersion, "Token version should be bumped to revoke sessions")
+	assert.True(t, newLogin.Verified)
+	keys, err := keyRepo.GetAPIKeys(id)
+	require.NoError(t, err)
+	assert.Empty(t, keys, "API keys should be revoked")
+
+	reused, err := repo.ResetPassword("hash-1", "anotherpass123", now)
+	require.NoError(t, err)
+	assert.False(t, reused, "Reset tokens should be single-use")
+
+	other, err := repo.ResetPassword("hash-2", "anotherpass123", now)
+	require.NoError(t, err)
+	assert.False(t, other, "Outstanding reset tokens should be invalidated")
+}
+
+func TestResetPassword_ExpiredToken(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlUserRepository(testDB)
+
+	now := time.Now()
+	require.NoError(t, repo.CreatePasswordReset(1, "hash-1", now.Add(-time.Minute), now.Add(-time.Hour)))
+
+	ok, err := repo.ResetPassword("hash-1", "newpassword123", now)
+
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestVerifyEmail_ChangesToPendingAddress(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlUserRepository(testDB)
+
+	id, err := repo.CreateUser(&models.User{Email: "old@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	now := time.Now()
+	require.NoError(t, repo.CreateEmailVerification(id, "new@example.com", "token-1", now.Add(time.Hour)))
+
+	verified, err := repo.VerifyEmail(id, "token-1", now)
+	require.NoError(t, err)
+	assert.True(t, verified)
+
+	user, err := repo.GetUserById(id)
+	require.NoError(t, err)
+	assert.Equal(t, "new@example.com", user.Email)
+	assert.True(t, user.Verified)
+}
+
+func TestVerifyEmail_AddressTakenMeanwhile(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlUserRepository(testDB)
+
+	id, err := repo.CreateUser(&models.User{Email: "old@example.com", Password: "password123"})
+	require.NoError(t, err)
+	now := time.Now()
+	require.NoError(t, repo.CreateEmailVerification(id, "new@example.com", "token-1", now.Add(time.Hour)))
+	_, err = repo.CreateUser(&models.User{Email: "New@Example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	verified, err := repo.VerifyEmail(id, "token-1", now)
+	require.NoError(t, err)
+	assert.False(t, verified)
+	pending, err := repo.HasEmailVerification(id, "token-1", now)
+	require.NoError(t, err)
+	assert.True(t, pending, "The token is left unused")
+
+	user, err := repo.GetUserById(id)
+	require.NoError(t, err)
+	assert.Equal(t, "old@example.com", user.Email)
+}
+
+func TestVerifyEmail_InvalidatesOtherLinks(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlUserRepository(testDB)
+
+	id, err := repo.CreateUser(&models.User{Email: "old@example.com", Password: "password123"})
+	require.NoError(t, err)
+	now := time.Now()
+	require.NoError(t, repo.CreateEmailVerification(id, "first@example.com", "token-1", now.Add(time.Hour)))
+	require.NoError(t, repo.CreateEmailVerification(id, "second@example.com", "token-2", now.Add(time.Hour)))
+
+	verified, err := repo.VerifyEmail(id, "token-2", now)
+	require.NoError(t, err)
+	assert.True(t, verified)
+
+	verified, err = repo.VerifyEmail(id, "token-1", now)
+	require.NoError(t, err)
+	assert.False(t, verified, "Older links cannot switch the address back")
+
+	user, err := repo.GetUserById(id)
+	require.NoError(t, err)
+	assert.Equal(t, "second@example.com", user.Email)
+}
+
+func TestUpdatePassword(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlUserRepository(testDB)
+
+	id, err := repo.CreateUser(&models.User{Email: "test@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	keyRepo := NewSqlAPIKeyRepository(testDB)
+	require.NoError(t, keyRepo.CreateAPIKey(&models.APIKey{UserId: id, Name: "ci", Prefix: "0123456789ab", SecretHash: "hash", Scopes: []string{"events:read"}, CreatedAt: time.Now()}))
+
+	err = repo.UpdatePassword(id, "newpassword123")
+	require.NoError(t, err)
+
+	user, err := repo.GetUserById(id)
+	require.NoError(t, err)
+	assert.True(t, utils.CheckPasswordHash("newpassword123", user.Password))
+	assert.Equal(t, int64(1), user.TokenVersion)
+	keys, err := keyRepo.GetAPIKeys(id)
+	require.NoError(t, err)
+	assert.Empty(t, keys, "API keys should be revoked")
+}
+
+func TestDeleteUser(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlUserRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+
+	id, err := repo.CreateUser(&models.User{Email: "test@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	ownEventId, _ := eventRepo.CreateEvent(&models.Event{Name: "Own", Description: "Own event", Location: "Here", DateTime: time.Now().Add(time.Hour), UserId: id, OrganizationId: 1})
+	otherEventId, _ := eventRepo.CreateEvent(&models.Event{Name: "Other", Description: "Other event", Location: "There", DateTime: time.Now().Add(time.Hour), UserId: id + 1, OrganizationId: 1})
+	own := newTestRegistration(1, id, otherEventId)
+	_, err = registerRepo.RegisterEvent(own)
+	require.NoError(t, err)
+	attendee := newTestRegistration(1, id+1, ownEventId)
+	_, err = registerRepo.RegisterEvent(attendee)
+	require.NoError(t, err)
+
+	deleted, err := repo.DeleteUser(id, time.Now())
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	user, err := repo.GetUserById(id)
+	require.NoError(t, err)
+	assert.NotEqual(t, "test@example.com", user.Email, "Email should be anonymised")
+	assert.Equal(t, int64(1), user.TokenVersion)
+
+	_, err = repo.GetUserByEmail("test@example.com")
+	assert.Error(t, err)
 
+	users, err := repo.GetUsers(1)
 	require.NoError(t, err)
 	assert.Empty(t, users)
+
+	_, err = eventRepo.GetEventById(1, ownEventId)
+	assert.Error(t, err, "Owned events should be removed")
+	_, err = eventRepo.GetEventById(1, otherEventId)
+	assert.NoError(t, err)
+
+	for _, registration := range []*models.RegisterEvent{own, attendee} {
+		stored, err := registerRepo.GetRegistration(1, registration.Id)
+		require.NoError(t, err)
+		assert.Equal(t, "cancelled", stored.Status, "Registrations should be cancelled rather than deleted")
+	}
+	cancellations, err := eventRepo.GetCancellations(1, otherEventId)
+	require.NoError(t, err)
+	require.Len(t, cancellations, 1)
+	assert.Equal(t, own.Id, cancellations[0].RegistrationId)
+	assert.Equal(t, "none", cancellations[0].RefundStatus)
+	cancellations, err = eventRepo.GetCancellations(1, ownEventId)
+	require.NoError(t, err)
+	assert.Len(t, cancellations, 1, "Registrations for owned events should be recorded as cancelled")
+
+	loginUser := &models.User{Email: user.Email, Password: "password123"}
+	valid, _ := repo.ValidateCredentials(loginUser)
+	assert.False(t, valid)
+}
+
+func TestDeleteUser_PaidRegistrations(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlUserRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+	registerRepo := NewSqlEventRegisterRepository(testDB)
+	orderRepo := NewSqlOrderRepository(testDB)
+
+	id, err := repo.CreateUser(&models.User{Email: "test@example.com", Password: "password123"})
+	require.NoError(t, err)
+	ownEventId, _ := eventRepo.CreateEvent(&models.Event{Name: "Own", Description: "Own event", Location: "Here", DateTime: time.Now().Add(time.Hour), UserId: id, OrganizationId: 1})
+	attendee := newTestRegistration(1, id+1, ownEventId)
+	_, err = registerRepo.RegisterEvent(attendee)
+	require.NoError(t, err)
+	order := newTestOrder(attendee, "pi_fake_1")
+	order.Status = "paid"
+	require.NoError(t, orderRepo.CreateOrder(order))
+
+	paid, err := repo.HasPaidRegistrations(id)
+	require.NoError(t, err)
+	assert.True(t, paid)
+
+	deleted, err := repo.DeleteUser(id, time.Now())
+	require.NoError(t, err)
+	assert.False(t, deleted, "Users holding paid registrations are kept")
+	_, err = eventRepo.GetEventById(1, ownEventId)
+	require.NoError(t, err)
+	stored, err := registerRepo.GetRegistration(1, attendee.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "approved", stored.Status)
+
+	paid, err = repo.HasPaidRegistrations(id + 1)
+	require.NoError(t, err)
+	assert.True(t, paid, "Attendees hold their own paid registrations")
+}
+
+func TestGetProfile_DefaultsToEmpty(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlUserRepository(testDB)
+
+	id, err := repo.CreateUser(&models.User{Email: "test@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	profile, err := repo.GetProfile(id)
+
+	require.NoError(t, err)
+	assert.Equal(t, models.Profile{UserId: id}, profile)
+}
+
+func TestSaveProfile(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlUserRepository(testDB)
+
+	id, err := repo.CreateUser(&models.User{Email: "test@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	profile := &models.Profile{
+		UserId:      id,
+		DisplayName: "Test User",
+		AvatarURL:   "https://example.com/avatar.png",
+		Bio:         "Organizer of things",
+		TimeZone:    "Europe/Berlin",
+		Locale:      "de-DE",
+	}
+	require.NoError(t, repo.SaveProfile(profile))
+
+	profile.DisplayName = "Renamed User"
+	require.NoError(t, repo.SaveProfile(profile))
+
+	stored, err := repo.GetProfile(id)
+
+	require.NoError(t, err)
+	assert.Equal(t, *profile, stored)
+}
+
+func TestGetProfile_DeletedOrMissingUser(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlUserRepository(testDB)
+
+	id, err := repo.CreateUser(&models.User{Email: "test@example.com", Password: "password123"})
+	require.NoError(t, err)
+	require.NoError(t, repo.SaveProfile(&models.Profile{UserId: id, DisplayName: "Test User"}))
+	_, err = repo.DeleteUser(id, time.Now())
+	require.NoError(t, err)
+
+	_, err = repo.GetProfile(id)
+	assert.Error(t, err)
+
+	_, err = repo.GetProfile(999)
+	assert.Error(t, err)
+}
+
+func TestIsEventOrganizer(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer TeardownTestDB(t, testDB)
+
+	repo := NewSqlUserRepository(testDB)
+	orgRepo := NewSqlOrganizationRepository(testDB)
+	eventRepo := NewSqlEventRepository(testDB)
+
+	ids := []int64{}
+	for _, email := range []string{"owner@example.com", "co@example.com", "staff@example.com", "attendee@example.com"} {
+		id, err := repo.CreateUser(&models.User{Email: email, Password: "password123"})
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+	orgId := createTestOrganization(t, orgRepo, "Acme", ids[0])
+	eventId := createTestOrgEvent(t, eventRepo, orgId, "Conference")
+	require.NoError(t, eventRepo.AddEventMember(&models.EventMember{EventId: eventId, UserId: ids[1], OrganizationId: orgId, Role: "co_organizer", CreatedAt: time.Now()}))
+	require.NoError(t, eventRepo.AddEventMember(&models.EventMember{EventId: eventId, UserId: ids[2], OrganizationId: orgId, Role: "checkin_staff", CreatedAt: time.Now()}))
+
+	for i, expected := range []bool{true, true, false, false} {
+		organizer, err := repo.IsEventOrganizer(ids[i])
+		require.NoError(t, err)
+		assert.Equal(t, expected, organizer, "user %d", ids[i])
+	}
 }
@@ -0,0 +1,68 @@
+package mailer
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogMailer writes outgoing emails to a logger instead of delivering them.
+// It is meant for local development.
+type LogMailer struct {
+	logger *log.Logger
+}
+
+func NewLogMailer(logger *log.Logger) *LogMailer {
+	return &LogMailer{
+		logger: logger,
+	}
+}
+
+func (m *LogMailer) Send(to, subject, body string) error {
+	m.logger.Printf("mail to=%s subject=%q\n%s", to, subject, body)
+	return nil
+}
+
+// FileMailer stores each outgoing email as a .eml file in dir so it can be
+// inspected by developers and tests.
+type FileMailer struct {
+	dir string
+	mu  sync.Mutex
+	seq int
+}
+
+func NewFileMailer(dir string) *FileMailer {
+	return &FileMailer{
+		dir: dir,
+	}
+}
+
+func (m *FileMailer) Send(to, subject, body string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	err := os.MkdirAll(m.dir, 0o755)
+	if err != nil {
+		return err
+	}
+
+	m.seq++
+	name := fmt.Sprintf("%s-%04d-%s.eml", time.Now().UTC().Format("20060102T150405"), m.seq, sanitize(to))
+	content := fmt.Sprintf("To: %s\nSubject: %s\n\n%s\n", to, subject, body)
+	return os.WriteFile(filepath.Join(m.dir, name), []byte(content), 0o644)
+}
+
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_', r == '@':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
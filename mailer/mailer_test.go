@@ -0,0 +1,63 @@
+package mailer
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogMailer_WritesMessage(t *testing.T) {
+	var buf bytes.Buffer
+	m := NewLogMailer(log.New(&buf, "", 0))
+
+	err := m.Send("user@example.com", "Hello", "Body text")
+
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "user@example.com")
+	assert.Contains(t, buf.String(), "Hello")
+	assert.Contains(t, buf.String(), "Body text")
+}
+
+func TestFileMailer_WritesOneFilePerMessage(t *testing.T) {
+	dir := t.TempDir()
+	m := NewFileMailer(dir)
+
+	require.NoError(t, m.Send("user@example.com", "First", "one"))
+	require.NoError(t, m.Send("user@example.com", "Second", "two"))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	content, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "To: user@example.com")
+	assert.Contains(t, string(content), "Subject: First")
+	assert.Contains(t, string(content), "one")
+}
+
+func TestFileMailer_CreatesDirectory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested", "mail")
+	m := NewFileMailer(dir)
+
+	err := m.Send("user@example.com", "Hello", "Body")
+
+	require.NoError(t, err)
+	assert.DirExists(t, dir)
+}
+
+func TestFileMailer_SanitizesRecipientInFileName(t *testing.T) {
+	dir := t.TempDir()
+	m := NewFileMailer(dir)
+
+	err := m.Send("../evil/user@example.com", "Hello", "Body")
+
+	require.NoError(t, err)
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+	assert.Len(t, files, 1)
+}
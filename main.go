@@ -2,7 +2,10 @@ package main
 
 import (
 	"log"
+	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
@@ -10,6 +13,10 @@ import (
 	"github.com/joho/godotenv"
 
 	"event-booking/db"
+	"event-booking/mailer"
+	"event-booking/oidc"
+	"event-booking/payment"
+	"event-booking/ratelimit"
 	"event-booking/routes"
 	"event-booking/services"
 	"event-booking/utils"
@@ -21,26 +28,171 @@ func main() {
 		log.Fatal("Error loading .env file")
 	}
 
+	keyRing, err := utils.LoadKeyRingFromEnv()
+	if err != nil {
+		log.Fatalf("Error loading JWT keys: %v", err)
+	}
+	utils.SetKeyRing(keyRing)
+
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
 		v.RegisterValidation("futuredate", utils.ValidateFutureDate)
 	}
 
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8000"
+	}
+
+	appURL := os.Getenv("APP_URL")
+	if appURL == "" {
+		appURL = "http://localhost:" + port
+	}
+
+	var mail services.Mailer = mailer.NewLogMailer(log.Default())
+	if dir := os.Getenv("MAIL_DIR"); dir != "" {
+		mail = mailer.NewFileMailer(dir)
+	}
+
+	twoFactorIssuer := os.Getenv("TWO_FACTOR_ISSUER")
+	if twoFactorIssuer == "" {
+		twoFactorIssuer = "Event Booking"
+	}
+	requireOrganizerTwoFactor := os.Getenv("REQUIRE_ORGANIZER_2FA") == "true"
+
+	payments := paymentProviderFromEnv()
+
+	rateLimits := routes.RateLimits{
+		Public:        rateLimitFromEnv("RATE_LIMIT_PUBLIC", "20/1m"),
+		Authenticated: rateLimitFromEnv("RATE_LIMIT_AUTHENTICATED", "120/1m"),
+	}
+
 	db.InitDB()
 	eventRepo := db.NewSqlEventRepository(db.DB)
 	eventRegisterRepo := db.NewSqlEventRegisterRepository(db.DB)
 	userRepo := db.NewSqlUserRepository(db.DB)
+	idempotencyRepo := db.NewSqlIdempotencyRepository(db.DB)
+	twoFactorRepo := db.NewSqlTwoFactorRepository(db.DB)
+	auditRepo := db.NewSqlAuditRepository(db.DB)
+	loginAttemptRepo := db.NewSqlLoginAttemptRepository(db.DB)
+	apiKeyRepo := db.NewSqlAPIKeyRepository(db.DB)
+	oidcRepo := db.NewSqlOIDCRepository(db.DB)
+	organizationRepo := db.NewSqlOrganizationRepository(db.DB)
+	invitationRepo := db.NewSqlEventInvitationRepository(db.DB)
+	orderRepo := db.NewSqlOrderRepository(db.DB)
+	transferRepo := db.NewSqlRegistrationTransferRepository(db.DB)
 
 	eventService := services.NewEventService(eventRepo)
-	eventRegisterService := services.NewEventRegisterService(eventRegisterRepo)
-	userService := services.NewUserService(userRepo)
+	eventRegisterService := services.NewEventRegisterService(eventRegisterRepo, payments)
+	userService := services.NewUserService(userRepo, mail, appURL)
+	idempotencyService := services.NewIdempotencyService(idempotencyRepo, services.DefaultIdempotencyTTL)
+	twoFactorService := services.NewTwoFactorService(twoFactorRepo, twoFactorIssuer, requireOrganizerTwoFactor)
+	auditService := services.NewAuditService(auditRepo)
+	loginThrottleService := services.NewLoginThrottleService(loginAttemptRepo, auditService)
+	apiKeyService := services.NewAPIKeyService(apiKeyRepo)
+	oidcService := services.NewOIDCService(oidcRepo, oidcProvidersFromEnv(appURL)...)
+	organizationService := services.NewOrganizationService(organizationRepo)
+	invitationService := services.NewEventInvitationService(invitationRepo, mail, appURL)
+	orderService := services.NewOrderService(orderRepo, payments)
+	transferService := services.NewRegistrationTransferService(transferRepo, auditService, mail, appURL)
+	rateLimitService := services.NewRateLimitService(ratelimit.NewMemoryStore())
 
 	server := gin.Default()
-	routes.RegisterRoutes(server, userService, eventService, eventRegisterService)
-
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8000"
+	err = server.SetTrustedProxies(trustedProxiesFromEnv())
+	if err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
 	}
+	routes.RegisterRoutes(server, userService, eventService, eventRegisterService, idempotencyService, twoFactorService,
+		loginThrottleService, apiKeyService, oidcService, organizationService, invitationService, orderService,
+		transferService, rateLimitService, rateLimits)
 
 	server.Run(":" + port)
 }
+
+// paymentProviderFromEnv configures the provider named by PAYMENT_PROVIDER.
+// "stripe" needs STRIPE_SECRET_KEY and PAYMENT_WEBHOOK_SECRET. "fake" marks
+// orders paid without charging anyone and is only meant for development
+// and tests, so it has to be chosen explicitly; the server does not start
+// without a provider.
+func paymentProviderFromEnv() services.PaymentProvider {
+	webhookSecret := os.Getenv("PAYMENT_WEBHOOK_SECRET")
+	if webhookSecret == "" {
+		log.Fatal("PAYMENT_WEBHOOK_SECRET is not set")
+	}
+
+	switch os.Getenv("PAYMENT_PROVIDER") {
+	case "stripe":
+		provider, err := payment.NewStripeProvider(os.Getenv("STRIPE_SECRET_KEY"), webhookSecret,
+			os.Getenv("STRIPE_API_URL"), &http.Client{Timeout: 30 * time.Second})
+		if err != nil {
+			log.Fatalf("Invalid Stripe configuration: %v", err)
+		}
+		return provider
+	case "fake":
+		log.Print("Using the fake payment provider: payments are accepted without charging anyone")
+		return payment.NewFakeProvider(webhookSecret)
+	default:
+		log.Fatal("PAYMENT_PROVIDER must be stripe, or fake for development and tests")
+		return nil
+	}
+}
+
+// rateLimitFromEnv reads a limit such as "100/1m" from the environment.
+// "off" disables the limit.
+func rateLimitFromEnv(key, fallback string) services.RateLimit {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		value = fallback
+	}
+
+	limit, err := services.ParseRateLimit(value)
+	if err != nil {
+		log.Fatalf("Invalid %s: %v", key, err)
+	}
+	return limit
+}
+
+// trustedProxiesFromEnv reads the comma-separated IPs and CIDRs of the
+// reverse proxies in front of the server from TRUSTED_PROXIES. Only their
+// X-Forwarded-For headers are believed; without any, clients are keyed by
+// the address they connect from.
+func trustedProxiesFromEnv() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		proxy = strings.TrimSpace(proxy)
+		if proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
+// oidcProvidersFromEnv configures the providers listed in OIDC_PROVIDERS.
+// Every provider NAME needs OIDC_NAME_ISSUER, OIDC_NAME_CLIENT_ID and
+// OIDC_NAME_CLIENT_SECRET; OIDC_NAME_SCOPES is optional.
+func oidcProvidersFromEnv(appURL string) []services.IdentityProvider {
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+
+	var providers []services.IdentityProvider
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		config := oidc.Config{
+			Name:         name,
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  strings.TrimSuffix(appURL, "/") + "/auth/oidc/" + name + "/callback",
+			Scopes:       strings.Fields(os.Getenv(prefix + "SCOPES")),
+		}
+		if config.Issuer == "" || config.ClientID == "" {
+			log.Fatalf("OIDC provider %s needs %sISSUER and %sCLIENT_ID", name, prefix, prefix)
+		}
+
+		providers = append(providers, oidc.NewClient(config, httpClient))
+	}
+	return providers
+}
@@ -1,28 +1,64 @@
 package middleware
 
 import (
+	"event-booking/services"
 	"event-booking/utils"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
-func Authenticate(context *gin.Context) {
-	token := context.Request.Header.Get("Authorization")
-	if token == "" {
-		context.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-			"message": "Unauthorized",
-		})
-		return
-	}
-	userId, err := utils.VerifyToken(&token)
-	if err != nil {
-		context.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-			"message": "Unauthorized",
-		})
-		return
-	}
+// Authenticate accepts a session JWT or an API key, either as a bearer token
+// or in the X-API-Key header, and stores the user id in "userId". Requests
+// made with an API key also get the key's scopes in "apiKeyScopes".
+func Authenticate(userService *services.UserService, apiKeyService *services.APIKeyService) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		token := context.Request.Header.Get("X-API-Key")
+		if token == "" {
+			var err error
+			token, err = utils.ParseBearerToken(context.Request.Header.Get("Authorization"))
+			if err != nil {
+				context.Header("WWW-Authenticate", "Bearer")
+				context.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+					"message": "Unauthorized",
+				})
+				return
+			}
+		}
+
+		if services.IsAPIKey(token) {
+			key, err := apiKeyService.Authenticate(token)
+			if err != nil {
+				context.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+					"message": "Unauthorized",
+				})
+				return
+			}
+
+			context.Set("userId", key.UserId)
+			context.Set("apiKeyId", key.Id)
+			context.Set("apiKeyScopes", key.Scopes)
+			context.Next()
+			return
+		}
 
-	context.Set("userId", userId)
-	context.Next()
+		userId, tokenVersion, err := utils.ParseSessionToken(token)
+		if err != nil {
+			context.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"message": "Unauthorized",
+			})
+			return
+		}
+
+		err = userService.ValidateSession(userId, tokenVersion)
+		if err != nil {
+			context.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"message": "Unauthorized",
+			})
+			return
+		}
+
+		context.Set("userId", userId)
+		context.Next()
+	}
 }
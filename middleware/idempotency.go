@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"event-booking/services"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+func hashRequest(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(path))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Idempotency replays the stored response when a request is retried with the
+// same Idempotency-Key header. It must run after Authenticate since keys are
+// scoped to the user.
+func Idempotency(idempotencyService *services.IdempotencyService) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		key := context.Request.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			context.Next()
+			return
+		}
+
+		if len(key) > 255 {
+			context.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"message": "Idempotency key must not exceed 255 characters",
+			})
+			return
+		}
+
+		body, err := io.ReadAll(context.Request.Body)
+		if err != nil {
+			context.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"message": "Cannot read request data",
+			})
+			return
+		}
+		context.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		userId := context.GetInt64("userId")
+		requestHash := hashRequest(context.Request.Method, context.Request.URL.Path, body)
+		record, replay, err := idempotencyService.Begin(userId, key, requestHash)
+		if err != nil {
+			if errors.Is(err, services.ErrIdempotencyKeyReused) {
+				context.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+					"message": err.Error(),
+				})
+			} else if errors.Is(err, services.ErrIdempotencyKeyInProgress) {
+				context.AbortWithStatusJSON(http.StatusConflict, gin.H{
+					"message": err.Error(),
+				})
+			} else {
+				context.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+					"message": "Could not process idempotency key",
+				})
+			}
+			return
+		}
+
+		if replay {
+			context.Header("Idempotent-Replayed", "true")
+			context.Data(record.StatusCode, record.ContentType, record.ResponseBody)
+			context.Abort()
+			return
+		}
+
+		stored := false
+		defer func() {
+			if !stored {
+				if err := idempotencyService.Release(userId, key); err != nil {
+					log.Printf("could not release idempotency key: %v", err)
+				}
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: context.Writer}
+		context.Writer = recorder
+		context.Next()
+
+		// Server errors are not stored so that the client can retry them.
+		if recorder.Status() >= http.StatusInternalServerError {
+			return
+		}
+
+		record.StatusCode = recorder.Status()
+		record.ContentType = recorder.Header().Get("Content-Type")
+		record.ResponseBody = recorder.body.Bytes()
+		if err := idempotencyService.Complete(&record); err != nil {
+			log.Printf("could not store idempotent response: %v", err)
+			return
+		}
+		stored = true
+	}
+}
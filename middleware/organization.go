@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"errors"
+	"event-booking/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OrganizationHeader selects the organization a request acts in.
+const OrganizationHeader = "X-Organization-Id"
+
+// RequireOrganization resolves the organization named by the
+// X-Organization-Id header and rejects users who are not a member of it.
+// With roles, the member must also have one of them. It sets orgId and
+// orgRole and must run after Authenticate.
+func RequireOrganization(organizationService *services.OrganizationService, roles ...string) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		header := context.GetHeader(OrganizationHeader)
+		if header == "" {
+			context.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"message": "The " + OrganizationHeader + " header is required",
+			})
+			return
+		}
+
+		orgId, err := strconv.ParseInt(header, 10, 64)
+		if err != nil {
+			context.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"message": "Could not parse " + OrganizationHeader,
+			})
+			return
+		}
+
+		membership, err := organizationService.Authorize(orgId, context.GetInt64("userId"), roles...)
+		if err != nil {
+			if errors.Is(err, services.ErrOrganizationNotFound) {
+				context.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+					"message": err.Error(),
+				})
+			} else if errors.Is(err, services.ErrForbidden) {
+				context.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+					"message": err.Error(),
+				})
+			} else {
+				context.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+					"message": "Could not check organization membership",
+				})
+			}
+			return
+		}
+
+		context.Set("orgId", membership.Id)
+		context.Set("orgRole", membership.Role)
+		context.Next()
+	}
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"event-booking/services"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit limits requests per user, or per client IP for anonymous
+// requests, using a separate bucket for every group. Place it after
+// Authenticate to key by user. A disabled limit lets every request through.
+func RateLimit(rateLimitService *services.RateLimitService, group string, limit services.RateLimit) gin.HandlerFunc {
+	if !limit.Enabled() {
+		return func(context *gin.Context) {
+			context.Next()
+		}
+	}
+
+	policy := fmt.Sprintf("%d;w=%d", limit.Requests, int(math.Ceil(limit.Window.Seconds())))
+
+	return func(context *gin.Context) {
+		result, err := rateLimitService.Allow(rateLimitKey(context, group), limit)
+		if err != nil {
+			// A broken store must not take the API down with it.
+			log.Printf("rate limit store failed: %v", err)
+			context.Next()
+			return
+		}
+
+		context.Header("RateLimit-Policy", policy)
+		context.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		context.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		context.Header("RateLimit-Reset", ceilSeconds(result.Reset))
+
+		if !result.Allowed {
+			context.Header("Retry-After", ceilSeconds(result.RetryAfter))
+			context.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"message": "Too many requests, please try again later",
+			})
+			return
+		}
+
+		context.Next()
+	}
+}
+
+func rateLimitKey(context *gin.Context, group string) string {
+	if userId := context.GetInt64("userId"); userId != 0 {
+		return group + ":user:" + strconv.FormatInt(userId, 10)
+	}
+	return group + ":ip:" + context.ClientIP()
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
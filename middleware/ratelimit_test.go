@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"event-booking/ratelimit"
+	"event-booking/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRateLimitedServer(t *testing.T, trustedProxies []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	server := gin.New()
+	require.NoError(t, server.SetTrustedProxies(trustedProxies))
+
+	rateLimitService := services.NewRateLimitService(ratelimit.NewMemoryStore())
+	server.GET("/limited", RateLimit(rateLimitService, "public", services.RateLimit{Requests: 1, Window: time.Minute}),
+		func(context *gin.Context) {
+			context.Status(http.StatusOK)
+		})
+	return server
+}
+
+func requestFrom(server *gin.Engine, remoteAddr, forwardedFor string) int {
+	request := httptest.NewRequest(http.MethodGet, "/limited", nil)
+	request.RemoteAddr = remoteAddr
+	if forwardedFor != "" {
+		request.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, request)
+	return recorder.Code
+}
+
+func TestRateLimit_IgnoresSpoofedForwardedFor(t *testing.T) {
+	server := newTestRateLimitedServer(t, nil)
+
+	assert.Equal(t, http.StatusOK, requestFrom(server, "203.0.113.7:1234", "198.51.100.1"))
+	assert.Equal(t, http.StatusTooManyRequests, requestFrom(server, "203.0.113.7:1234", "198.51.100.2"),
+		"A client cannot pick a new bucket by sending X-Forwarded-For")
+}
+
+func TestRateLimit_TrustedProxyForwardsClientIP(t *testing.T) {
+	server := newTestRateLimitedServer(t, []string{"10.0.0.0/8"})
+
+	assert.Equal(t, http.StatusOK, requestFrom(server, "10.0.0.2:1234", "198.51.100.1"))
+	assert.Equal(t, http.StatusOK, requestFrom(server, "10.0.0.2:1234", "198.51.100.2"))
+	assert.Equal(t, http.StatusTooManyRequests, requestFrom(server, "10.0.0.2:1234", "198.51.100.1"))
+}
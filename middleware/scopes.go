@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"event-booking/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireScope rejects requests made with an API key that lacks scope and
+// every scope implying it.
+// Session tokens are not limited by scopes. It must run after Authenticate.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		scopes, usesAPIKey := context.Get("apiKeyScopes")
+		if usesAPIKey && !services.HasScope(scopes.([]string), scope) {
+			context.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"message": "API key is missing the " + scope + " scope",
+			})
+			return
+		}
+
+		context.Next()
+	}
+}
+
+// RequireSession rejects requests made with an API key, so keys cannot be
+// used to manage the account or to create more keys. It must run after
+// Authenticate.
+func RequireSession() gin.HandlerFunc {
+	return func(context *gin.Context) {
+		if _, usesAPIKey := context.Get("apiKeyScopes"); usesAPIKey {
+			context.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"message": "This endpoint cannot be used with an API key",
+			})
+			return
+		}
+
+		context.Next()
+	}
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"event-booking/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireOrganizerTwoFactor rejects organizer actions from users without
+// two-factor authentication when the policy is enabled. It must run after
+// Authenticate.
+func RequireOrganizerTwoFactor(twoFactorService *services.TwoFactorService) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		err := twoFactorService.RequireForOrganizer(context.GetInt64("userId"))
+		if err != nil {
+			context.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"message": err.Error(),
+			})
+			return
+		}
+
+		context.Next()
+	}
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// APIKey lets scripts call the API on behalf of a user. Only a hash of the
+// secret is stored; the full key is shown once when it is created.
+type APIKey struct {
+	Id         int64
+	UserId     int64 `json:"-"`
+	Name       string
+	Prefix     string
+	SecretHash string `json:"-"`
+	Scopes     []string
+	LastUsedAt *time.Time
+	ExpiresAt  *time.Time
+	CreatedAt  time.Time
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// EventAttendance compares the approved registrations of an event with the
+// attendees checked in. NoShows and AttendanceRate are only set once check-in
+// for the event has closed.
+type EventAttendance struct {
+	EventId    int64
+	Name       string
+	DateTime   time.Time
+	Registered int64
+	CheckedIn  int64
+	NoShows    int64
+	// AttendanceRate is CheckedIn divided by Registered.
+	AttendanceRate float64
+	// Closed is set once tickets of the event can no longer be checked in.
+	Closed bool
+}
+
+// AttendeeAttendance is the attendance of a user across the past events of
+// an organization that used check-in.
+type AttendeeAttendance struct {
+	UserId int64
+	Email  string
+	// Events is the number of events the user had an approved registration
+	// for.
+	Events  int64
+	NoShows int64
+	// NoShowRate is NoShows divided by Events.
+	NoShowRate float64
+}
+
+// AttendanceReport is the attendance of an organization's past events and
+// of their attendees.
+type AttendanceReport struct {
+	Events    []EventAttendance
+	Attendees []AttendeeAttendance
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+type AuditEvent struct {
+	Id        int64
+	Action    string
+	UserId    int64
+	Subject   string
+	IP        string
+	Details   string
+	CreatedAt time.Time
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// CancellationPolicy decides how much of a paid ticket is refunded when its
+// registration is cancelled: all of it until FullRefundDays before the
+// event, PartialRefundPercent of it from then until the event starts, and
+// nothing after. Events without a policy refund in full until they start.
+type CancellationPolicy struct {
+	EventId              int64
+	OrganizationId       int64 `json:"-"`
+	FullRefundDays       int64 `binding:"min=0,max=365"`
+	PartialRefundPercent int64 `binding:"min=0,max=100"`
+}
+
+// Cancellation records a cancelled registration and the refund of its
+// order, if it had a paid one. RefundAmount is in the smallest unit of
+// Currency.
+type Cancellation struct {
+	Id             int64
+	RegistrationId int64
+	UserId         int64
+	EventId        int64
+	OrganizationId int64 `json:"-"`
+	OrderId        int64 `json:",omitempty"`
+	RefundAmount   int64
+	Currency       string `json:",omitempty"`
+	// RefundStatus is none, pending, refunded or failed.
+	RefundStatus string
+	CancelledAt  time.Time
+}
@@ -5,10 +5,19 @@ import (
 )
 
 type Event struct {
-	Id          int64
-	Name        string    `binding:"required,min=3,max=100"`
-	Description string    `binding:"required,min=5,max=500"`
-	Location    string    `binding:"required,min=3,max=100"`
-	DateTime    time.Time `binding:"required,futuredate"`
-	UserId      int64
+	Id             int64
+	Name           string    `binding:"required,min=3,max=100"`
+	Description    string    `binding:"required,min=5,max=500"`
+	Location       string    `binding:"required,min=3,max=100"`
+	DateTime       time.Time `binding:"required,futuredate"`
+	UserId         int64
+	OrganizationId int64
+	// Visibility is public, unlisted or private. It defaults to public.
+	Visibility string `binding:"omitempty,oneof=public unlisted private"`
+	// Capacity is the number of approved registrations the event accepts,
+	// 0 means unlimited.
+	Capacity int64 `binding:"min=0"`
+	// RequiresApproval makes new registrations pending until an organizer
+	// approves them.
+	RequiresApproval bool
 }
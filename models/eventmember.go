@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// EventMember grants a user other than the event's creator a role on the
+// event, such as co-organizer or check-in staff.
+type EventMember struct {
+	EventId        int64
+	UserId         int64
+	OrganizationId int64 `json:"-"`
+	Email          string
+	Role           string
+	CreatedAt      time.Time
+}
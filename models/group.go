@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// RegistrationGroup is a booking of several seats of an event in one
+// request. Its registrations are reserved together and managed by the
+// booker, who does not need to hold one of them.
+type RegistrationGroup struct {
+	Id             int64
+	EventId        int64
+	OrganizationId int64 `json:"-"`
+	BookerId       int64
+	CreatedAt      time.Time
+	Registrations  []RegisterEvent
+}
+
+// GroupAttendee is one attendee of a group registration. Attendees whose
+// email belongs to a member of the organization are registered as that
+// member; anyone else is registered as a guest and needs a name.
+type GroupAttendee struct {
+	Email   string               `binding:"required,email"`
+	Name    string               `binding:"max=100"`
+	Answers []RegistrationAnswer `binding:"max=50,dive"`
+}
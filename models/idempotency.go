@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+type IdempotencyKey struct {
+	Id           int64
+	UserId       int64
+	Key          string
+	RequestHash  string
+	StatusCode   int
+	ContentType  string
+	ResponseBody []byte
+	Completed    bool
+	CreatedAt    time.Time
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// EventInvitation gives a user access to a private event. Email invitations
+// match the invitee by email address; users who accept an invite link are
+// recorded by UserId.
+type EventInvitation struct {
+	Id             int64
+	EventId        int64
+	OrganizationId int64  `json:"-"`
+	Email          string `json:",omitempty"`
+	UserId         int64  `json:",omitempty"`
+	InvitedBy      int64
+	CreatedAt      time.Time
+}
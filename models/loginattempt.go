@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// LoginAttempt tracks consecutive failed logins for one subject, such as an
+// account or a client IP.
+type LoginAttempt struct {
+	Scope         string
+	Key           string
+	Failures      int
+	LastFailureAt time.Time
+	LockedUntil   time.Time
+}
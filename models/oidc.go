@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// ExternalIdentity is a user as asserted by an OpenID Connect provider.
+type ExternalIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+}
+
+// OIDCLoginState tracks a login that was sent to a provider until the
+// provider redirects back. It is consumed by the callback.
+type OIDCLoginState struct {
+	StateHash    string
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time
+}
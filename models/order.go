@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Order is the payment for a registration with a paid ticket type, or for
+// all registrations of a group at once. Group orders have a GroupId and no
+// RegistrationId. Amount is in the smallest unit of Currency.
+type Order struct {
+	Id             int64
+	RegistrationId int64
+	GroupId        int64 `json:",omitempty"`
+	UserId         int64
+	EventId        int64
+	OrganizationId int64 `json:"-"`
+	Amount         int64
+	Currency       string
+	// RefundedAmount is the part of Amount that was paid back.
+	RefundedAmount int64 `json:",omitempty"`
+	// Status is pending, paid, failed, cancelled or refunded.
+	Status          string
+	PaymentIntentId string
+	ClientSecret    string `json:",omitempty"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// Statuses of a payment intent as reported by the payment provider.
+const (
+	PaymentIntentRequiresPayment = "requires_payment"
+	PaymentIntentSucceeded       = "succeeded"
+	PaymentIntentFailed          = "failed"
+)
+
+// PaymentIntent is a payment at the payment provider. The client secret
+// lets the buyer's client complete the payment with the provider.
+type PaymentIntent struct {
+	Id           string
+	ClientSecret string `json:",omitempty"`
+	Amount       int64
+	Currency     string
+	Status       string
+}
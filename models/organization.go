@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Organization is a tenant. Events and registrations belong to exactly one
+// organization and are only visible to its members.
+type Organization struct {
+	Id   int64
+	Name string
+	// DeprioritizeNoShows lists pending registrations of users with at
+	// least NoShowThreshold no-shows after all other pending registrations.
+	DeprioritizeNoShows bool
+	NoShowThreshold     int64
+	CreatedAt           time.Time
+}
+
+// OrganizationMember is the membership of a user in an organization. Role
+// is one of owner, admin or member.
+type OrganizationMember struct {
+	OrganizationId int64
+	UserId         int64
+	Email          string
+	Role           string
+	CreatedAt      time.Time
+}
+
+// Membership is an organization together with the role of the current user.
+type Membership struct {
+	Organization
+	Role string
+}
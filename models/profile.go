@@ -0,0 +1,19 @@
+package models
+
+type Profile struct {
+	UserId      int64
+	DisplayName string `binding:"max=100"`
+	AvatarURL   string `binding:"omitempty,http_url,max=500"`
+	Bio         string `binding:"max=1000"`
+	TimeZone    string `binding:"omitempty,timezone"`
+	Locale      string `binding:"omitempty,bcp47_language_tag"`
+}
+
+// Account is what the authenticated user sees about themselves.
+type Account struct {
+	Id               int64
+	Email            string
+	Verified         bool
+	TwoFactorEnabled bool
+	Profile          Profile
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// PromoCode discounts the tickets of an event. A percentage discount takes
+// DiscountValue percent off the price, a fixed discount takes DiscountValue
+// off in the smallest unit of the ticket's currency. MaxUses of 0 means the
+// code can be used any number of times, and an empty TicketTypeIds makes it
+// apply to every ticket type.
+type PromoCode struct {
+	Id             int64
+	EventId        int64
+	OrganizationId int64  `json:"-"`
+	Code           string `binding:"required,min=3,max=50"`
+	DiscountType   string `binding:"required,oneof=percentage fixed"`
+	DiscountValue  int64  `binding:"required,min=1"`
+	MaxUses        int64  `binding:"min=0"`
+	ValidFrom      *time.Time
+	ValidUntil     *time.Time
+	TicketTypeIds  []int64 `binding:"max=100"`
+	// Used counts the registrations made with the code.
+	Used int64
+}
@@ -0,0 +1,21 @@
+package models
+
+// RegistrationQuestion is asked when registering for an event. Choice
+// questions offer Options to pick from.
+type RegistrationQuestion struct {
+	Id             int64
+	EventId        int64
+	OrganizationId int64    `json:"-"`
+	Label          string   `binding:"required,min=1,max=200"`
+	Type           string   `binding:"required,oneof=text single_choice multi_choice"`
+	Options        []string `json:",omitempty" binding:"max=50,dive,min=1,max=100"`
+	Required       bool
+}
+
+// RegistrationAnswer answers a question. Text and single choice questions
+// are answered with Value, multiple choice questions with Values.
+type RegistrationAnswer struct {
+	QuestionId int64    `binding:"required"`
+	Value      string   `json:",omitempty"`
+	Values     []string `json:",omitempty"`
+}
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// RateLimitBucket is the state of a token bucket. A bucket that has never
+// been used has a zero UpdatedAt.
+type RateLimitBucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
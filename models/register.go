@@ -1,7 +1,33 @@
 package models
 
+import "time"
+
 type RegisterEvent struct {
-	Id      int64
-	UserId  int64 `binding:"required"`
-	EventId int64 `binding:"required"`
+	Id             int64
+	UserId         int64 `binding:"required"`
+	EventId        int64 `binding:"required"`
+	OrganizationId int64
+	// TicketTypeId is 0 for events without ticket types.
+	TicketTypeId int64 `json:",omitempty"`
+	// PromoCodeId is the promo code used for the ticket, if any.
+	PromoCodeId int64 `json:",omitempty"`
+	// Amount is the price of the ticket after discounts.
+	Amount int64 `json:",omitempty"`
+	// Status is awaiting_payment, pending, approved, rejected or cancelled.
+	Status string
+	Email  string `json:",omitempty"`
+	// GroupId is the group registration the registration was booked in, if
+	// any. GuestName is set for guests booked in a group, who have no user;
+	// their UserId is 0 and Email is the guest's email address.
+	GroupId   int64  `json:",omitempty"`
+	GuestName string `json:",omitempty"`
+	// CheckedInAt is set when the attendee was checked in at the event.
+	CheckedInAt *time.Time `json:",omitempty"`
+	// NoShows is the number of past events of the organization the user
+	// did not show up for. Deprioritized is set on pending registrations
+	// moved to the end of the waitlist because of them.
+	NoShows       int64 `json:",omitempty"`
+	Deprioritized bool  `json:",omitempty"`
+	CreatedAt     time.Time
+	Answers       []RegistrationAnswer `json:",omitempty"`
 }
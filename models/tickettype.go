@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// TicketType is a tier of tickets for an event. Price is in the smallest
+// unit of Currency, e.g. cents. Tickets can only be bought between
+// SalesStart and SalesEnd when they are set.
+type TicketType struct {
+	Id             int64
+	EventId        int64
+	OrganizationId int64  `json:"-"`
+	Name           string `binding:"required,min=1,max=100"`
+	Price          int64  `binding:"min=0"`
+	Currency       string `binding:"required,iso4217"`
+	Quantity       int64  `binding:"required,min=1"`
+	SalesStart     *time.Time
+	SalesEnd       *time.Time
+	// Sold counts the approved registrations with this ticket type.
+	Sold int64
+}
+
+// TicketAvailability is a ticket type with what is left of it.
+type TicketAvailability struct {
+	TicketType
+	Available int64
+	OnSale    bool
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// RegistrationTransfer hands an approved registration from one user to
+// another. The registration keeps its seat and only changes hands when the
+// recipient accepts.
+type RegistrationTransfer struct {
+	Id             int64
+	RegistrationId int64
+	EventId        int64
+	OrganizationId int64 `json:"-"`
+	FromUserId     int64
+	FromEmail      string `json:",omitempty"`
+	ToUserId       int64
+	ToEmail        string `json:",omitempty"`
+	// Status is pending, accepted, declined or cancelled.
+	Status    string
+	CreatedAt time.Time
+	DecidedAt *time.Time `json:",omitempty"`
+}
@@ -0,0 +1,8 @@
+package models
+
+type TwoFactor struct {
+	UserId       int64
+	Secret       string `json:"-"`
+	Enabled      bool
+	LastUsedStep int64 `json:"-"`
+}
@@ -1,7 +1,10 @@
 package models
 
 type User struct {
-	Id       int64
-	Email    string `binding:"required,email"`
-	Password string `binding:"required,min=8"`
+	Id               int64
+	Email            string `binding:"required,email"`
+	Password         string `binding:"required,min=8"`
+	Verified         bool
+	TwoFactorEnabled bool  `json:"-"`
+	TokenVersion     int64 `json:"-"`
 }
@@ -0,0 +1,345 @@
+package oidc
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"event-booking/models"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Config describes an OpenID Connect provider registered for this API.
+type Config struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+var ErrInvalidIDToken = errors.New("Invalid ID token")
+
+type metadata struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	JWKSURI               string   `json:"jwks_uri"`
+	CodeChallengeMethods  []string `json:"code_challenge_methods_supported"`
+}
+
+// Client runs the authorization code flow with PKCE against one provider.
+// Provider metadata and signing keys are discovered on first use and cached.
+type Client struct {
+	config     Config
+	httpClient *http.Client
+	now        func() time.Time
+
+	mu       sync.Mutex
+	metadata *metadata
+	keys     map[string]any
+}
+
+func NewClient(config Config, httpClient *http.Client) *Client {
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+	return &Client{
+		config:     config,
+		httpClient: httpClient,
+		now:        time.Now,
+	}
+}
+
+func (c *Client) Name() string {
+	return c.config.Name
+}
+
+// AuthCodeURL returns the provider URL the user has to be sent to. The code
+// challenge is derived from codeVerifier with S256.
+func (c *Client) AuthCodeURL(state, nonce, codeVerifier string) (string, error) {
+	md, err := c.discover()
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {c.config.ClientID},
+		"redirect_uri":          {c.config.RedirectURL},
+		"scope":                 {strings.Join(c.config.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {CodeChallenge(codeVerifier)},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(md.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return md.AuthorizationEndpoint + separator + params.Encode(), nil
+}
+
+// Exchange redeems an authorization code and returns the identity asserted by
+// the verified ID token.
+func (c *Client) Exchange(code, codeVerifier, nonce string) (models.ExternalIdentity, error) {
+	md, err := c.discover()
+	if err != nil {
+		return models.ExternalIdentity{}, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {c.config.RedirectURL},
+		"code_verifier": {codeVerifier},
+	}
+	req, err := http.NewRequest(http.MethodPost, md.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return models.ExternalIdentity{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(c.config.ClientID), url.QueryEscape(c.config.ClientSecret))
+
+	var tokens struct {
+		IDToken string `json:"id_token"`
+	}
+	err = c.doJSON(req, &tokens)
+	if err != nil {
+		return models.ExternalIdentity{}, fmt.Errorf("token exchange failed: %w", err)
+	}
+	if tokens.IDToken == "" {
+		return models.ExternalIdentity{}, errors.New("token response has no id_token")
+	}
+
+	return c.verifyIDToken(md, tokens.IDToken, nonce)
+}
+
+type idTokenClaims struct {
+	Nonce         string `json:"nonce"`
+	Email         string `json:"email"`
+	EmailVerified any    `json:"email_verified"`
+	jwt.RegisteredClaims
+}
+
+func (c *Client) verifyIDToken(md *metadata, token, nonce string) (models.ExternalIdentity, error) {
+	claims := &idTokenClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, c.keyFunc,
+		jwt.WithValidMethods([]string{"RS256", "ES256", "EdDSA"}),
+		jwt.WithIssuer(md.Issuer),
+		jwt.WithAudience(c.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(time.Minute),
+		jwt.WithTimeFunc(c.now),
+	)
+	if err != nil {
+		return models.ExternalIdentity{}, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	if claims.Nonce == "" || claims.Nonce != nonce {
+		return models.ExternalIdentity{}, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+	if claims.Subject == "" {
+		return models.ExternalIdentity{}, fmt.Errorf("%w: missing subject", ErrInvalidIDToken)
+	}
+
+	// Some providers send email_verified as a string.
+	verified := claims.EmailVerified == true || claims.EmailVerified == "true"
+	return models.ExternalIdentity{
+		Provider:      c.config.Name,
+		Subject:       claims.Subject,
+		Email:         strings.ToLower(claims.Email),
+		EmailVerified: verified,
+	}, nil
+}
+
+// keyFunc looks up the key named by the token's kid. The key set is fetched
+// again once when the kid is unknown, so provider key rotation is picked up.
+func (c *Client) keyFunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	c.mu.Lock()
+	key, ok := c.keys[kid]
+	c.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+
+	err := c.refreshKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key, ok = c.keys[kid]
+	if !ok {
+		return nil, errors.New("unknown signing key")
+	}
+	return key, nil
+}
+
+func (c *Client) discover() (*metadata, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.metadata != nil {
+		return c.metadata, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(c.config.Issuer, "/")+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var md metadata
+	err = c.doJSON(req, &md)
+	if err != nil {
+		return nil, fmt.Errorf("discovery failed: %w", err)
+	}
+
+	// The issuer must match exactly, otherwise a compromised discovery
+	// document could make us accept tokens of another issuer.
+	if md.Issuer != c.config.Issuer {
+		return nil, fmt.Errorf("discovery returned issuer %q, expected %q", md.Issuer, c.config.Issuer)
+	}
+	if md.AuthorizationEndpoint == "" || md.TokenEndpoint == "" || md.JWKSURI == "" {
+		return nil, errors.New("discovery document is incomplete")
+	}
+	if len(md.CodeChallengeMethods) > 0 && !slices.Contains(md.CodeChallengeMethods, "S256") {
+		return nil, errors.New("provider does not support PKCE with S256")
+	}
+
+	c.metadata = &md
+	return c.metadata, nil
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (c *Client) refreshKeys() error {
+	md, err := c.discover()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, md.JWKSURI, nil)
+	if err != nil {
+		return err
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	err = c.doJSON(req, &set)
+	if err != nil {
+		return fmt.Errorf("fetching signing keys failed: %w", err)
+	}
+
+	keys := map[string]any{}
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			// Unsupported key types are skipped instead of failing the
+			// whole set.
+			continue
+		}
+		keys[k.Kid] = key
+	}
+
+	c.mu.Lock()
+	c.keys = keys
+	c.mu.Unlock()
+	return nil
+}
+
+func (k jwk) publicKey() (any, error) {
+	decode := base64.RawURLEncoding.DecodeString
+	switch k.Kty {
+	case "RSA":
+		n, err := decode(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decode(k.E)
+		if err != nil {
+			return nil, err
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, errors.New("unsupported curve")
+		}
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decode(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	case "OKP":
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if k.Crv != "Ed25519" || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("unsupported curve")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, errors.New("unsupported key type")
+	}
+}
+
+func (c *Client) doJSON(req *http.Request, v any) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s returned %s", req.Method, req.URL, resp.Status)
+	}
+	return json.NewDecoder(http.MaxBytesReader(nil, resp.Body, 1<<20)).Decode(v)
+}
+
+// CodeChallenge derives the S256 PKCE code challenge from a verifier.
+func CodeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
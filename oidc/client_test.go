@@ -0,0 +1,149 @@
+package oidc
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthCodeURL_UsesPKCE(t *testing.T) {
+	provider := newMockProvider(t)
+	client := provider.client()
+
+	authURL, err := client.AuthCodeURL("state", "nonce", "verifier")
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	q := parsed.Query()
+	assert.Equal(t, provider.issuer()+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+	assert.Equal(t, "S256", q.Get("code_challenge_method"))
+	assert.Equal(t, CodeChallenge("verifier"), q.Get("code_challenge"))
+	assert.Equal(t, "openid email profile", q.Get("scope"))
+	assert.Equal(t, testRedirectURL, q.Get("redirect_uri"))
+	assert.Equal(t, "state", q.Get("state"))
+	assert.Equal(t, "nonce", q.Get("nonce"))
+}
+
+func TestExchange_ReturnsVerifiedIdentity(t *testing.T) {
+	provider := newMockProvider(t)
+	client := provider.client()
+
+	authURL, err := client.AuthCodeURL("state", "nonce", "verifier")
+	require.NoError(t, err)
+	code := provider.login(authURL)
+
+	identity, err := client.Exchange(code, "verifier", "nonce")
+
+	require.NoError(t, err)
+	assert.Equal(t, "mock", identity.Provider)
+	assert.Equal(t, "user-123", identity.Subject)
+	assert.Equal(t, "jane@example.com", identity.Email)
+	assert.True(t, identity.EmailVerified)
+}
+
+func TestExchange_WrongCodeVerifier(t *testing.T) {
+	provider := newMockProvider(t)
+	client := provider.client()
+
+	authURL, err := client.AuthCodeURL("state", "nonce", "verifier")
+	require.NoError(t, err)
+	code := provider.login(authURL)
+
+	_, err = client.Exchange(code, "another-verifier", "nonce")
+
+	assert.Error(t, err)
+}
+
+func TestExchange_CodeCanOnlyBeUsedOnce(t *testing.T) {
+	provider := newMockProvider(t)
+	client := provider.client()
+
+	authURL, err := client.AuthCodeURL("state", "nonce", "verifier")
+	require.NoError(t, err)
+	code := provider.login(authURL)
+
+	_, err = client.Exchange(code, "verifier", "nonce")
+	require.NoError(t, err)
+	_, err = client.Exchange(code, "verifier", "nonce")
+	assert.Error(t, err)
+}
+
+func TestExchange_RejectsInvalidIDTokens(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		nonce  string
+	}{
+		{"wrong nonce", nil, "other-nonce"},
+		{"wrong audience", jwt.MapClaims{"aud": "another-client"}, "nonce"},
+		{"wrong issuer", jwt.MapClaims{"iss": "https://evil.example.com"}, "nonce"},
+		{"expired", jwt.MapClaims{"exp": 1}, "nonce"},
+		{"missing subject", jwt.MapClaims{"sub": ""}, "nonce"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newMockProvider(t)
+			provider.claims = tt.claims
+			client := provider.client()
+
+			authURL, err := client.AuthCodeURL("state", "nonce", "verifier")
+			require.NoError(t, err)
+			code := provider.login(authURL)
+
+			_, err = client.Exchange(code, "verifier", tt.nonce)
+
+			assert.ErrorIs(t, err, ErrInvalidIDToken)
+		})
+	}
+}
+
+func TestExchange_EmailVerifiedAsString(t *testing.T) {
+	provider := newMockProvider(t)
+	provider.claims = jwt.MapClaims{"email_verified": "false"}
+	client := provider.client()
+
+	authURL, err := client.AuthCodeURL("state", "nonce", "verifier")
+	require.NoError(t, err)
+	code := provider.login(authURL)
+
+	identity, err := client.Exchange(code, "verifier", "nonce")
+
+	require.NoError(t, err)
+	assert.False(t, identity.EmailVerified)
+}
+
+func TestExchange_PicksUpRotatedKeys(t *testing.T) {
+	provider := newMockProvider(t)
+	client := provider.client()
+
+	for _, kid := range []string{"key-1", "key-2"} {
+		if kid != "key-1" {
+			provider.rotateKey(kid)
+		}
+
+		authURL, err := client.AuthCodeURL("state", "nonce", "verifier")
+		require.NoError(t, err)
+		code := provider.login(authURL)
+
+		_, err = client.Exchange(code, "verifier", "nonce")
+		require.NoError(t, err, kid)
+	}
+}
+
+func TestDiscovery_RejectsIssuerMismatch(t *testing.T) {
+	provider := newMockProvider(t)
+	client := NewClient(Config{
+		Name:     "mock",
+		Issuer:   provider.issuer() + "/",
+		ClientID: testClientID,
+	}, provider.server.Client())
+
+	_, err := client.AuthCodeURL("state", "nonce", "verifier")
+
+	assert.Error(t, err)
+}
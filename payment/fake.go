@@ -0,0 +1,166 @@
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"event-booking/models"
+	"sync"
+)
+
+// Payment methods understood by FakeProvider. Any other method is paid
+// successfully.
+const (
+	MethodDeclined = "pm_card_declined"
+	MethodError    = "pm_card_error"
+)
+
+var ErrUnknownIntent = errors.New("Unknown payment intent")
+var ErrInvalidSignature = errors.New("Invalid webhook signature")
+var ErrNotRefundable = errors.New("Payment cannot be refunded")
+var ErrProcessing = errors.New("Payment could not be processed")
+
+// FakeProvider is an in-process payment provider for development and
+// tests. It keeps intents in memory under random ids, so intents of
+// earlier runs are never handed out again. Webhook payloads are signed with HMAC-SHA256
+// of secret.
+type FakeProvider struct {
+	secret []byte
+
+	mu          sync.Mutex
+	intents     map[string]*fakeIntent
+	byReference map[string]string
+}
+
+type fakeIntent struct {
+	intent   models.PaymentIntent
+	refunded int64
+}
+
+func NewFakeProvider(secret string) *FakeProvider {
+	return &FakeProvider{
+		secret:      []byte(secret),
+		intents:     map[string]*fakeIntent{},
+		byReference: map[string]string{},
+	}
+}
+
+func (p *FakeProvider) CreateIntent(reference string, amount int64, currency string) (models.PaymentIntent, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if id, ok := p.byReference[reference]; ok {
+		return p.intents[id].intent, nil
+	}
+
+	id := "pi_fake_" + rand.Text()
+	intent := models.PaymentIntent{
+		Id:           id,
+		ClientSecret: id + "_secret_" + p.sign([]byte(id))[:16],
+		Amount:       amount,
+		Currency:     currency,
+		Status:       models.PaymentIntentRequiresPayment,
+	}
+	p.intents[id] = &fakeIntent{intent: intent}
+	p.byReference[reference] = id
+	return intent, nil
+}
+
+// Confirm pays the intent with paymentMethod. MethodDeclined fails the
+// payment and MethodError fails the call itself.
+func (p *FakeProvider) Confirm(intentId, paymentMethod string) (models.PaymentIntent, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	i, ok := p.intents[intentId]
+	if !ok {
+		return models.PaymentIntent{}, ErrUnknownIntent
+	}
+	if i.intent.Status == models.PaymentIntentSucceeded {
+		return i.intent, nil
+	}
+
+	switch paymentMethod {
+	case MethodError:
+		return models.PaymentIntent{}, ErrProcessing
+	case MethodDeclined:
+		i.intent.Status = models.PaymentIntentFailed
+	default:
+		i.intent.Status = models.PaymentIntentSucceeded
+	}
+	return i.intent, nil
+}
+
+// Refund pays back amount of a successful payment, at most what is left of
+// it.
+func (p *FakeProvider) Refund(intentId string, amount int64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	i, ok := p.intents[intentId]
+	if !ok {
+		return ErrUnknownIntent
+	}
+	if i.intent.Status != models.PaymentIntentSucceeded || amount <= 0 || i.refunded+amount > i.intent.Amount {
+		return ErrNotRefundable
+	}
+
+	i.refunded += amount
+	return nil
+}
+
+// Refunded returns how much of an intent was refunded.
+func (p *FakeProvider) Refunded(intentId string) int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if i, ok := p.intents[intentId]; ok {
+		return i.refunded
+	}
+	return 0
+}
+
+// Webhook returns the signed webhook call reporting the current state of
+// an intent, as the provider would send it.
+func (p *FakeProvider) Webhook(intentId string) ([]byte, string, error) {
+	p.mu.Lock()
+	i, ok := p.intents[intentId]
+	var intent models.PaymentIntent
+	if ok {
+		intent = i.intent
+		intent.ClientSecret = ""
+	}
+	p.mu.Unlock()
+
+	if !ok {
+		return nil, "", ErrUnknownIntent
+	}
+
+	payload, err := json.Marshal(intent)
+	if err != nil {
+		return nil, "", err
+	}
+	return payload, p.sign(payload), nil
+}
+
+func (p *FakeProvider) VerifyWebhook(payload []byte, signature string) (models.PaymentIntent, error) {
+	if !hmac.Equal([]byte(p.sign(payload)), []byte(signature)) {
+		return models.PaymentIntent{}, ErrInvalidSignature
+	}
+
+	var intent models.PaymentIntent
+	err := json.Unmarshal(payload, &intent)
+	if err != nil || intent.Id == "" {
+		return models.PaymentIntent{}, ErrInvalidSignature
+	}
+	return intent, nil
+}
+
+func (p *FakeProvider) sign(payload []byte) string {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,93 @@
+package payment
+
+import (
+	"strings"
+	"testing"
+
+	"event-booking/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeProvider_CreateIntentIsIdempotent(t *testing.T) {
+	p := NewFakeProvider("secret")
+
+	first, err := p.CreateIntent("registration-1", 2500, "EUR")
+	require.NoError(t, err)
+	again, err := p.CreateIntent("registration-1", 2500, "EUR")
+	require.NoError(t, err)
+	other, err := p.CreateIntent("registration-2", 1000, "EUR")
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(first.Id, "pi_fake_"))
+	assert.Equal(t, first, again)
+	assert.NotEqual(t, first.Id, other.Id)
+	assert.Equal(t, models.PaymentIntentRequiresPayment, first.Status)
+}
+
+func TestFakeProvider_IntentIdsDifferAcrossRuns(t *testing.T) {
+	before, err := NewFakeProvider("secret").CreateIntent("registration-1", 2500, "EUR")
+	require.NoError(t, err)
+	after, err := NewFakeProvider("secret").CreateIntent("registration-1", 2500, "EUR")
+	require.NoError(t, err)
+
+	assert.NotEqual(t, before.Id, after.Id, "A restarted provider does not reuse intent ids")
+}
+
+func TestFakeProvider_Confirm(t *testing.T) {
+	p := NewFakeProvider("secret")
+	declined, _ := p.CreateIntent("registration-1", 2500, "EUR")
+	paid, _ := p.CreateIntent("registration-2", 2500, "EUR")
+
+	intent, err := p.Confirm(declined.Id, MethodDeclined)
+	require.NoError(t, err)
+	assert.Equal(t, models.PaymentIntentFailed, intent.Status)
+
+	intent, err = p.Confirm(declined.Id, "pm_card_visa")
+	require.NoError(t, err)
+	assert.Equal(t, models.PaymentIntentSucceeded, intent.Status, "Failed payments can be retried")
+
+	_, err = p.Confirm(paid.Id, MethodError)
+	assert.Equal(t, ErrProcessing, err)
+
+	_, err = p.Confirm("pi_unknown", "pm_card_visa")
+	assert.Equal(t, ErrUnknownIntent, err)
+}
+
+func TestFakeProvider_Refund(t *testing.T) {
+	p := NewFakeProvider("secret")
+	intent, _ := p.CreateIntent("registration-1", 2500, "EUR")
+
+	assert.Equal(t, ErrNotRefundable, p.Refund(intent.Id, 2500), "Unpaid intents cannot be refunded")
+
+	_, err := p.Confirm(intent.Id, "pm_card_visa")
+	require.NoError(t, err)
+
+	require.NoError(t, p.Refund(intent.Id, 1000))
+	require.NoError(t, p.Refund(intent.Id, 1500))
+	assert.Equal(t, ErrNotRefundable, p.Refund(intent.Id, 1), "Refunds cannot exceed the payment")
+	assert.Equal(t, int64(2500), p.Refunded(intent.Id))
+}
+
+func TestFakeProvider_Webhook(t *testing.T) {
+	p := NewFakeProvider("secret")
+	created, _ := p.CreateIntent("registration-1", 2500, "EUR")
+	_, err := p.Confirm(created.Id, "pm_card_visa")
+	require.NoError(t, err)
+
+	payload, signature, err := p.Webhook(created.Id)
+	require.NoError(t, err)
+
+	intent, err := p.VerifyWebhook(payload, signature)
+	require.NoError(t, err)
+	assert.Equal(t, created.Id, intent.Id)
+	assert.Equal(t, models.PaymentIntentSucceeded, intent.Status)
+	assert.Empty(t, intent.ClientSecret)
+
+	_, err = NewFakeProvider("other").VerifyWebhook(payload, signature)
+	assert.Equal(t, ErrInvalidSignature, err, "Signatures depend on the secret")
+
+	_, err = p.VerifyWebhook([]byte(`{"id":"pi_fake_1","status":"succeeded","amount":1}`), signature)
+	assert.Equal(t, ErrInvalidSignature, err, "Tampered payloads are rejected")
+}
@@ -0,0 +1,242 @@
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"event-booking/models"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultStripeURL is the base URL of the Stripe API.
+const DefaultStripeURL = "https://api.stripe.com"
+
+// webhookTolerance is how old a signed webhook call may be, so that
+// recorded calls cannot be replayed later.
+const webhookTolerance = 5 * time.Minute
+
+var ErrInvalidConfig = errors.New("Payment provider is not configured")
+
+// StripeProvider takes payments through the Stripe API. Intents are created
+// with the order reference as idempotency key, and webhook calls are
+// verified with the endpoint's signing secret as described for the
+// Stripe-Signature header.
+type StripeProvider struct {
+	secretKey     string
+	webhookSecret []byte
+	baseURL       string
+	httpClient    *http.Client
+	now           func() time.Time
+}
+
+func NewStripeProvider(secretKey, webhookSecret, baseURL string, httpClient *http.Client) (*StripeProvider, error) {
+	if secretKey == "" || webhookSecret == "" {
+		return nil, ErrInvalidConfig
+	}
+	if baseURL == "" {
+		baseURL = DefaultStripeURL
+	}
+	return &StripeProvider{
+		secretKey:     secretKey,
+		webhookSecret: []byte(webhookSecret),
+		baseURL:       strings.TrimSuffix(baseURL, "/"),
+		httpClient:    httpClient,
+		now:           time.Now,
+	}, nil
+}
+
+type stripeIntent struct {
+	Id               string `json:"id"`
+	ClientSecret     string `json:"client_secret"`
+	Amount           int64  `json:"amount"`
+	Currency         string `json:"currency"`
+	Status           string `json:"status"`
+	LastPaymentError *struct {
+		Code string `json:"code"`
+	} `json:"last_payment_error"`
+}
+
+type stripeError struct {
+	Error struct {
+		Type          string        `json:"type"`
+		Message       string        `json:"message"`
+		PaymentIntent *stripeIntent `json:"payment_intent"`
+	} `json:"error"`
+}
+
+// intent converts a Stripe payment intent. Intents that need a new payment
+// method after an attempt have failed; any other status than succeeded
+// still requires payment.
+func (i stripeIntent) intent() models.PaymentIntent {
+	status := models.PaymentIntentRequiresPayment
+	switch {
+	case i.Status == "succeeded":
+		status = models.PaymentIntentSucceeded
+	case i.Status == "requires_payment_method" && i.LastPaymentError != nil:
+		status = models.PaymentIntentFailed
+	}
+	return models.PaymentIntent{
+		Id:           i.Id,
+		ClientSecret: i.ClientSecret,
+		Amount:       i.Amount,
+		Currency:     strings.ToUpper(i.Currency),
+		Status:       status,
+	}
+}
+
+func (p *StripeProvider) CreateIntent(reference string, amount int64, currency string) (models.PaymentIntent, error) {
+	form := url.Values{
+		"amount":              {strconv.FormatInt(amount, 10)},
+		"currency":            {strings.ToLower(currency)},
+		"metadata[reference]": {reference},
+	}
+	var intent stripeIntent
+	err := p.post("/v1/payment_intents", reference, form, &intent)
+	if err != nil {
+		return models.PaymentIntent{}, err
+	}
+	return intent.intent(), nil
+}
+
+// Confirm pays the intent with paymentMethod. A declined card fails the
+// payment; other errors fail the call.
+func (p *StripeProvider) Confirm(intentId, paymentMethod string) (models.PaymentIntent, error) {
+	form := url.Values{"payment_method": {paymentMethod}}
+	var intent stripeIntent
+	err := p.post("/v1/payment_intents/"+url.PathEscape(intentId)+"/confirm", "", form, &intent)
+	var declined *cardError
+	if errors.As(err, &declined) {
+		failed := declined.intent.intent()
+		failed.Status = models.PaymentIntentFailed
+		return failed, nil
+	}
+	if err != nil {
+		return models.PaymentIntent{}, err
+	}
+	return intent.intent(), nil
+}
+
+func (p *StripeProvider) Refund(intentId string, amount int64) error {
+	if amount <= 0 {
+		return ErrNotRefundable
+	}
+	form := url.Values{
+		"payment_intent": {intentId},
+		"amount":         {strconv.FormatInt(amount, 10)},
+	}
+	var refund struct {
+		Status string `json:"status"`
+	}
+	err := p.post("/v1/refunds", "", form, &refund)
+	if err != nil {
+		return err
+	}
+	if refund.Status == "failed" || refund.Status == "canceled" {
+		return ErrNotRefundable
+	}
+	return nil
+}
+
+// VerifyWebhook checks the Stripe-Signature header of a webhook call,
+// "t=<timestamp>,v1=<signature>", and returns the payment intent of
+// payment_intent events.
+func (p *StripeProvider) VerifyWebhook(payload []byte, signature string) (models.PaymentIntent, error) {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(signature, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return models.PaymentIntent{}, ErrInvalidSignature
+	}
+	age := p.now().Sub(time.Unix(seconds, 0))
+	if age > webhookTolerance || age < -webhookTolerance {
+		return models.PaymentIntent{}, ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, p.webhookSecret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	valid := false
+	for _, s := range signatures {
+		valid = valid || hmac.Equal([]byte(expected), []byte(s))
+	}
+	if !valid {
+		return models.PaymentIntent{}, ErrInvalidSignature
+	}
+
+	var event struct {
+		Type string `json:"type"`
+		Data struct {
+			Object stripeIntent `json:"object"`
+		} `json:"data"`
+	}
+	err = json.Unmarshal(payload, &event)
+	if err != nil || !strings.HasPrefix(event.Type, "payment_intent.") || event.Data.Object.Id == "" {
+		return models.PaymentIntent{}, ErrInvalidSignature
+	}
+	intent := event.Data.Object.intent()
+	if event.Type == "payment_intent.payment_failed" {
+		intent.Status = models.PaymentIntentFailed
+	}
+	intent.ClientSecret = ""
+	return intent, nil
+}
+
+// cardError is a payment declined by the card issuer.
+type cardError struct {
+	intent stripeIntent
+}
+
+func (e *cardError) Error() string {
+	return "card declined for payment intent " + e.intent.Id
+}
+
+// post sends a form to the API and decodes the answer into v. Requests
+// with an idempotency key can be retried without creating duplicates.
+func (p *StripeProvider) post(path, idempotencyKey string, form url.Values, v any) error {
+	request, err := http.NewRequest(http.MethodPost, p.baseURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	request.SetBasicAuth(p.secretKey, "")
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if idempotencyKey != "" {
+		request.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	response, err := p.httpClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrProcessing, err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrProcessing, err)
+	}
+	if response.StatusCode >= 300 {
+		var apiErr stripeError
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Error.Type == "card_error" && apiErr.Error.PaymentIntent != nil {
+			return &cardError{intent: *apiErr.Error.PaymentIntent}
+		}
+		return fmt.Errorf("%w: status %d: %s", ErrProcessing, response.StatusCode, apiErr.Error.Message)
+	}
+	return json.Unmarshal(body, v)
+}
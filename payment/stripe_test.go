@@ -0,0 +1,111 @@
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"event-booking/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStripeProvider(t *testing.T, handler http.HandlerFunc) *StripeProvider {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	p, err := NewStripeProvider("sk_test_123", "whsec_123", server.URL, server.Client())
+	require.NoError(t, err)
+	return p
+}
+
+func signStripeWebhook(secret string, at time.Time, payload []byte) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	return fmt.Sprintf("t=%s,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+func TestNewStripeProvider_RequiresKeys(t *testing.T) {
+	_, err := NewStripeProvider("", "whsec_123", "", http.DefaultClient)
+	assert.Equal(t, ErrInvalidConfig, err)
+
+	_, err = NewStripeProvider("sk_test_123", "", "", http.DefaultClient)
+	assert.Equal(t, ErrInvalidConfig, err)
+}
+
+func TestStripeProvider_CreateIntent(t *testing.T) {
+	p := newTestStripeProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/payment_intents", r.URL.Path)
+		assert.Equal(t, "registration-1", r.Header.Get("Idempotency-Key"))
+		key, _, _ := r.BasicAuth()
+		assert.Equal(t, "sk_test_123", key)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "2500", r.PostForm.Get("amount"))
+		assert.Equal(t, "eur", r.PostForm.Get("currency"))
+
+		fmt.Fprint(w, `{"id": "pi_123", "client_secret": "pi_123_secret_abc", "amount": 2500, "currency": "eur",
+			"status": "requires_payment_method"}`)
+	})
+
+	intent, err := p.CreateIntent("registration-1", 2500, "EUR")
+
+	require.NoError(t, err)
+	assert.Equal(t, models.PaymentIntent{
+		Id: "pi_123", ClientSecret: "pi_123_secret_abc", Amount: 2500, Currency: "EUR",
+		Status: models.PaymentIntentRequiresPayment,
+	}, intent)
+}
+
+func TestStripeProvider_ConfirmDeclined(t *testing.T) {
+	p := newTestStripeProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/payment_intents/pi_123/confirm", r.URL.Path)
+		w.WriteHeader(http.StatusPaymentRequired)
+		fmt.Fprint(w, `{"error": {"type": "card_error", "message": "Your card was declined.",
+			"payment_intent": {"id": "pi_123", "amount": 2500, "currency": "eur", "status": "requires_payment_method"}}}`)
+	})
+
+	intent, err := p.Confirm("pi_123", "pm_card_visa")
+
+	require.NoError(t, err)
+	assert.Equal(t, models.PaymentIntentFailed, intent.Status)
+}
+
+func TestStripeProvider_ServerError(t *testing.T) {
+	p := newTestStripeProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	_, err := p.Confirm("pi_123", "pm_card_visa")
+	assert.ErrorIs(t, err, ErrProcessing)
+
+	assert.ErrorIs(t, p.Refund("pi_123", 2500), ErrProcessing)
+}
+
+func TestStripeProvider_VerifyWebhook(t *testing.T) {
+	p := newTestStripeProvider(t, func(w http.ResponseWriter, r *http.Request) {})
+	payload := []byte(`{"type": "payment_intent.succeeded",
+		"data": {"object": {"id": "pi_123", "amount": 2500, "currency": "eur", "status": "succeeded"}}}`)
+
+	intent, err := p.VerifyWebhook(payload, signStripeWebhook("whsec_123", time.Now(), payload))
+	require.NoError(t, err)
+	assert.Equal(t, "pi_123", intent.Id)
+	assert.Equal(t, models.PaymentIntentSucceeded, intent.Status)
+
+	_, err = p.VerifyWebhook(payload, signStripeWebhook("whsec_other", time.Now(), payload))
+	assert.Equal(t, ErrInvalidSignature, err)
+
+	_, err = p.VerifyWebhook(payload, signStripeWebhook("whsec_123", time.Now().Add(-time.Hour), payload))
+	assert.Equal(t, ErrInvalidSignature, err, "Old calls cannot be replayed")
+
+	_, err = p.VerifyWebhook(payload, "")
+	assert.Equal(t, ErrInvalidSignature, err)
+}
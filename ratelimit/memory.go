@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"event-booking/models"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often expired buckets are removed from a MemoryStore.
+const sweepInterval = time.Minute
+
+type entry struct {
+	bucket    models.RateLimitBucket
+	expiresAt time.Time
+}
+
+// MemoryStore keeps token buckets in process memory. Limits are not shared
+// between instances of the application.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*entry
+	lastSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: map[string]*entry{},
+	}
+}
+
+func (s *MemoryStore) UpdateBucket(key string, now time.Time, ttl time.Duration, update func(*models.RateLimitBucket)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		s.sweep(now)
+	}
+
+	e, ok := s.buckets[key]
+	if !ok || !e.expiresAt.After(now) {
+		e = &entry{}
+		s.buckets[key] = e
+	}
+
+	update(&e.bucket)
+	e.expiresAt = now.Add(ttl)
+	return nil
+}
+
+// Len returns the number of buckets currently held.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.buckets)
+}
+
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, e := range s.buckets {
+		if !e.expiresAt.After(now) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
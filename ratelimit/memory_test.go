@@ -0,0 +1,48 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"event-booking/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func increment(bucket *models.RateLimitBucket) {
+	bucket.Tokens++
+}
+
+func TestMemoryStore_KeepsBucketPerKey(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, store.UpdateBucket("a", now, time.Minute, increment))
+	require.NoError(t, store.UpdateBucket("a", now, time.Minute, increment))
+	require.NoError(t, store.UpdateBucket("b", now, time.Minute, increment))
+
+	var tokens float64
+	require.NoError(t, store.UpdateBucket("a", now, time.Minute, func(bucket *models.RateLimitBucket) {
+		tokens = bucket.Tokens
+	}))
+	assert.Equal(t, float64(2), tokens)
+	assert.Equal(t, 2, store.Len())
+}
+
+func TestMemoryStore_ExpiresBuckets(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, store.UpdateBucket("a", now, time.Second, increment))
+	require.NoError(t, store.UpdateBucket("b", now, time.Hour, increment))
+
+	later := now.Add(2 * time.Minute)
+	var tokens float64
+	require.NoError(t, store.UpdateBucket("b", later, time.Hour, func(bucket *models.RateLimitBucket) {
+		tokens = bucket.Tokens
+	}))
+
+	assert.Equal(t, float64(1), tokens)
+	assert.Equal(t, 1, store.Len())
+}
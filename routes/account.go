@@ -0,0 +1,129 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"event-booking/services"
+)
+
+func changePassword(context *gin.Context, userService *services.UserService) {
+	var request struct {
+		CurrentPassword string `binding:"required"`
+		NewPassword     string `binding:"required,min=8"`
+	}
+	err := context.ShouldBindJSON(&request)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Cannot parse request data",
+		})
+		return
+	}
+
+	userId := context.GetInt64("userId")
+	token, err := userService.ChangePassword(userId, request.CurrentPassword, request.NewPassword)
+	if err != nil {
+		if errors.Is(err, services.ErrIncorrectPassword) {
+			context.JSON(http.StatusForbidden, gin.H{
+				"message": err.Error(),
+			})
+		} else if errors.Is(err, services.ErrUserNotFound) {
+			context.JSON(http.StatusNotFound, gin.H{
+				"message": err.Error(),
+			})
+		} else {
+			context.JSON(http.StatusInternalServerError, gin.H{
+				"message": "Could not change password",
+			})
+		}
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Password has been changed successfully",
+		"token":   token,
+	})
+}
+
+func changeEmail(context *gin.Context, userService *services.UserService) {
+	var request struct {
+		CurrentPassword string `binding:"required"`
+		NewEmail        string `binding:"required,email"`
+	}
+	err := context.ShouldBindJSON(&request)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Cannot parse request data",
+		})
+		return
+	}
+
+	userId := context.GetInt64("userId")
+	err = userService.ChangeEmail(userId, request.CurrentPassword, request.NewEmail)
+	if err != nil {
+		if errors.Is(err, services.ErrIncorrectPassword) {
+			context.JSON(http.StatusForbidden, gin.H{
+				"message": err.Error(),
+			})
+		} else if errors.Is(err, services.ErrEmailTaken) {
+			context.JSON(http.StatusConflict, gin.H{
+				"message": err.Error(),
+			})
+		} else if errors.Is(err, services.ErrUserNotFound) {
+			context.JSON(http.StatusNotFound, gin.H{
+				"message": err.Error(),
+			})
+		} else {
+			context.JSON(http.StatusInternalServerError, gin.H{
+				"message": "Could not change email address",
+			})
+		}
+		return
+	}
+
+	context.JSON(http.StatusAccepted, gin.H{
+		"message": "Please check your new email address to confirm the change",
+	})
+}
+
+func deleteAccount(context *gin.Context, userService *services.UserService) {
+	var request struct {
+		CurrentPassword string `binding:"required"`
+	}
+	err := context.ShouldBindJSON(&request)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Cannot parse request data",
+		})
+		return
+	}
+
+	userId := context.GetInt64("userId")
+	err = userService.DeleteAccount(userId, request.CurrentPassword)
+	if err != nil {
+		if errors.Is(err, services.ErrIncorrectPassword) {
+			context.JSON(http.StatusForbidden, gin.H{
+				"message": err.Error(),
+			})
+		} else if errors.Is(err, services.ErrUserNotFound) {
+			context.JSON(http.StatusNotFound, gin.H{
+				"message": err.Error(),
+			})
+		} else if errors.Is(err, services.ErrLastOrganizationOwner) || errors.Is(err, services.ErrAccountHasPaidRegistrations) {
+			context.JSON(http.StatusConflict, gin.H{
+				"message": err.Error(),
+			})
+		} else {
+			context.JSON(http.StatusInternalServerError, gin.H{
+				"message": "Could not delete account",
+			})
+		}
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Account has been deleted successfully",
+	})
+}
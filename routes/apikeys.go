@@ -0,0 +1,157 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"event-booking/services"
+)
+
+func listAPIKeys(context *gin.Context, apiKeyService *services.APIKeyService) {
+	keys, err := apiKeyService.List(context.GetInt64("userId"))
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{
+			"message": "Failed to retrieve API keys",
+		})
+		return
+	}
+
+	context.JSON(http.StatusOK, keys)
+}
+
+func createAPIKey(context *gin.Context, apiKeyService *services.APIKeyService) {
+	var request struct {
+		Name      string   `binding:"required,max=100"`
+		Scopes    []string `binding:"required"`
+		ExpiresAt *time.Time
+	}
+	err := context.ShouldBindJSON(&request)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Cannot parse request data",
+		})
+		return
+	}
+
+	userId := context.GetInt64("userId")
+	key, token, err := apiKeyService.Create(userId, request.Name, request.Scopes, request.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidAPIKeyScope) || errors.Is(err, services.ErrAPIKeyExpiryInPast) {
+			context.JSON(http.StatusBadRequest, gin.H{
+				"message": err.Error(),
+			})
+		} else if errors.Is(err, services.ErrTooManyAPIKeys) {
+			context.JSON(http.StatusConflict, gin.H{
+				"message": err.Error(),
+			})
+		} else {
+			context.JSON(http.StatusInternalServerError, gin.H{
+				"message": "Could not create API key",
+			})
+		}
+		return
+	}
+
+	context.JSON(http.StatusCreated, gin.H{
+		"message": "API key created, store it now as it will not be shown again",
+		"key":     token,
+		"apiKey":  key,
+	})
+}
+
+func getAPIKey(context *gin.Context, apiKeyService *services.APIKeyService) {
+	id, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse API key id",
+		})
+		return
+	}
+
+	key, err := apiKeyService.Get(context.GetInt64("userId"), id)
+	if err != nil {
+		context.JSON(http.StatusNotFound, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	context.JSON(http.StatusOK, key)
+}
+
+func updateAPIKey(context *gin.Context, apiKeyService *services.APIKeyService) {
+	id, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse API key id",
+		})
+		return
+	}
+
+	var request struct {
+		Name   string   `binding:"required,max=100"`
+		Scopes []string `binding:"required"`
+	}
+	err = context.ShouldBindJSON(&request)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Cannot parse request data",
+		})
+		return
+	}
+
+	key, err := apiKeyService.Update(context.GetInt64("userId"), id, request.Name, request.Scopes)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidAPIKeyScope) {
+			context.JSON(http.StatusBadRequest, gin.H{
+				"message": err.Error(),
+			})
+		} else if errors.Is(err, services.ErrAPIKeyNotFound) {
+			context.JSON(http.StatusNotFound, gin.H{
+				"message": err.Error(),
+			})
+		} else {
+			context.JSON(http.StatusInternalServerError, gin.H{
+				"message": "Could not update API key",
+			})
+		}
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "API key updated",
+		"apiKey":  key,
+	})
+}
+
+func deleteAPIKey(context *gin.Context, apiKeyService *services.APIKeyService) {
+	id, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse API key id",
+		})
+		return
+	}
+
+	err = apiKeyService.Revoke(context.GetInt64("userId"), id)
+	if err != nil {
+		if errors.Is(err, services.ErrAPIKeyNotFound) {
+			context.JSON(http.StatusNotFound, gin.H{
+				"message": err.Error(),
+			})
+		} else {
+			context.JSON(http.StatusInternalServerError, gin.H{
+				"message": "Could not revoke API key",
+			})
+		}
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "API key revoked",
+	})
+}
@@ -0,0 +1,84 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"event-booking/services"
+)
+
+func getEventAttendance(context *gin.Context, eventService *services.EventService) {
+	eventId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse event id",
+		})
+		return
+	}
+
+	attendance, err := eventService.Attendance(context.GetInt64("orgId"), eventId, context.GetInt64("userId"))
+	if err != nil {
+		if errors.Is(err, services.ErrEventNotFound) {
+			context.JSON(http.StatusNotFound, gin.H{
+				"message": err.Error(),
+			})
+		} else if errors.Is(err, services.ErrForbidden) {
+			context.JSON(http.StatusForbidden, gin.H{
+				"message": err.Error(),
+			})
+		} else {
+			context.JSON(http.StatusInternalServerError, gin.H{
+				"message": "Failed to retrieve attendance",
+			})
+		}
+		return
+	}
+
+	context.JSON(http.StatusOK, attendance)
+}
+
+func getAttendanceReport(context *gin.Context, organizationService *services.OrganizationService) {
+	orgId, ok := organizationIdParam(context)
+	if !ok {
+		return
+	}
+
+	report, err := organizationService.AttendanceReport(context.GetInt64("userId"), orgId)
+	if err != nil {
+		organizationFailed(context, err, "Failed to retrieve attendance report")
+		return
+	}
+
+	context.JSON(http.StatusOK, report)
+}
+
+func updateAttendanceSettings(context *gin.Context, organizationService *services.OrganizationService) {
+	orgId, ok := organizationIdParam(context)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		DeprioritizeNoShows bool
+		NoShowThreshold     int64 `binding:"required,min=1,max=100"`
+	}
+	err := context.ShouldBindJSON(&request)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Cannot parse request data",
+		})
+		return
+	}
+
+	membership, err := organizationService.UpdateAttendanceSettings(context.GetInt64("userId"), orgId,
+		request.DeprioritizeNoShows, request.NoShowThreshold)
+	if err != nil {
+		organizationFailed(context, err, "Could not update attendance settings")
+		return
+	}
+
+	context.JSON(http.StatusOK, membership)
+}
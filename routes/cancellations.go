@@ -0,0 +1,99 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"event-booking/models"
+	"event-booking/services"
+)
+
+func getCancellationPolicy(context *gin.Context, eventService *services.EventService) {
+	eventId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse event id",
+		})
+		return
+	}
+
+	event, err := eventService.GetEventById(context.GetInt64("orgId"), eventId, context.GetInt64("userId"))
+	if err != nil {
+		cancellationFailed(context, err, "Failed to retrieve cancellation policy")
+		return
+	}
+
+	policy, err := eventService.CancellationPolicy(event)
+	if err != nil {
+		cancellationFailed(context, err, "Failed to retrieve cancellation policy")
+		return
+	}
+
+	context.JSON(http.StatusOK, policy)
+}
+
+func setCancellationPolicy(context *gin.Context, eventService *services.EventService) {
+	eventId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse event id",
+		})
+		return
+	}
+
+	var policy models.CancellationPolicy
+	err = context.ShouldBindJSON(&policy)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Cannot parse request data",
+		})
+		return
+	}
+
+	err = eventService.SetCancellationPolicy(context.GetInt64("orgId"), eventId, context.GetInt64("userId"), &policy)
+	if err != nil {
+		cancellationFailed(context, err, "Could not update cancellation policy")
+		return
+	}
+
+	context.JSON(http.StatusOK, policy)
+}
+
+func listCancellations(context *gin.Context, eventService *services.EventService) {
+	eventId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse event id",
+		})
+		return
+	}
+
+	cancellations, err := eventService.Cancellations(context.GetInt64("orgId"), eventId, context.GetInt64("userId"))
+	if err != nil {
+		cancellationFailed(context, err, "Failed to retrieve cancellations")
+		return
+	}
+
+	context.JSON(http.StatusOK, cancellations)
+}
+
+// cancellationFailed answers with the status matching an error of the
+// cancellation policy methods.
+func cancellationFailed(context *gin.Context, err error, message string) {
+	if errors.Is(err, services.ErrEventNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{
+			"message": err.Error(),
+		})
+	} else if errors.Is(err, services.ErrForbidden) {
+		context.JSON(http.StatusForbidden, gin.H{
+			"message": err.Error(),
+		})
+	} else {
+		context.JSON(http.StatusInternalServerError, gin.H{
+			"message": message,
+		})
+	}
+}
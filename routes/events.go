@@ -12,7 +12,7 @@ import (
 )
 
 func getEvents(context *gin.Context, eventService *services.EventService) {
-	events, err := eventService.GetAllEvents()
+	events, err := eventService.GetAllEvents(context.GetInt64("orgId"), context.GetInt64("userId"))
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{
 			"message": "Failed to retrieve events",
@@ -31,14 +31,32 @@ func getEventById(context *gin.Context, eventService *services.EventService) {
 		return
 	}
 
-	event, err := eventService.GetEventById(eventId)
+	event, err := eventService.GetEventById(context.GetInt64("orgId"), eventId, context.GetInt64("userId"))
+	if err != nil {
+		if errors.Is(err, services.ErrEventNotFound) {
+			context.JSON(http.StatusNotFound, gin.H{
+				"message": err.Error(),
+			})
+		} else {
+			context.JSON(http.StatusInternalServerError, gin.H{
+				"message": "Failed to retrieve event",
+			})
+		}
+		return
+	}
+
+	ticketTypes, err := eventService.TicketTypes(event)
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{
 			"message": "Failed to retrieve event",
 		})
+		return
 	}
 
-	context.JSON(http.StatusOK, event)
+	context.JSON(http.StatusOK, struct {
+		models.Event
+		TicketTypes []models.TicketAvailability
+	}{event, ticketTypes})
 }
 
 func createEvent(context *gin.Context, eventService *services.EventService) {
@@ -53,6 +71,7 @@ func createEvent(context *gin.Context, eventService *services.EventService) {
 	}
 
 	event.UserId = context.GetInt64("userId")
+	event.OrganizationId = context.GetInt64("orgId")
 	err = eventService.CreateEvent(&event)
 
 	if err != nil {
@@ -87,7 +106,7 @@ func updateEvent(context *gin.Context, eventService *services.EventService) {
 	}
 
 	userId := context.GetInt64("userId")
-	err = eventService.UpdateEvent(eventId, userId, &updatedEvent)
+	err = eventService.UpdateEvent(context.GetInt64("orgId"), eventId, userId, &updatedEvent)
 	if err != nil {
 		if errors.Is(err, services.ErrEventNotFound) {
 			context.JSON(http.StatusNotFound, gin.H{
@@ -110,7 +129,9 @@ func updateEvent(context *gin.Context, eventService *services.EventService) {
 	})
 }
 
-func deleteEvent(context *gin.Context, eventService *services.EventService) {
+// deleteEvent cancels the registrations of an event, refunding paid ones,
+// and deletes it.
+func deleteEvent(context *gin.Context, eventService *services.EventService, eventRegisterService *services.EventRegisterService) {
 	eventId, err := strconv.ParseInt(context.Param("id"), 10, 64)
 	if err != nil {
 		context.JSON(http.StatusBadRequest, gin.H{
@@ -120,7 +141,10 @@ func deleteEvent(context *gin.Context, eventService *services.EventService) {
 	}
 
 	UserId := context.GetInt64("userId")
-	err = eventService.DeleteEvent(UserId, eventId)
+	cancellations, err := eventRegisterService.CancelAllRegistrations(context.GetInt64("orgId"), eventId, UserId)
+	if err == nil {
+		err = eventService.DeleteEvent(context.GetInt64("orgId"), UserId, eventId)
+	}
 	if err != nil {
 		if errors.Is(err, services.ErrEventNotFound) {
 			context.JSON(http.StatusNotFound, gin.H{
@@ -130,6 +154,15 @@ func deleteEvent(context *gin.Context, eventService *services.EventService) {
 			context.JSON(http.StatusForbidden, gin.H{
 				"message": err.Error(),
 			})
+		} else if errors.Is(err, services.ErrEventHasPaidRegistrations) {
+			context.JSON(http.StatusConflict, gin.H{
+				"message": err.Error(),
+			})
+		} else if errors.Is(err, services.ErrEventRefundFailed) {
+			context.JSON(http.StatusBadGateway, gin.H{
+				"message":       err.Error(),
+				"cancellations": cancellations,
+			})
 		} else {
 			context.JSON(http.StatusInternalServerError, gin.H{
 				"message": "Event could not be deleted",
@@ -139,6 +172,111 @@ func deleteEvent(context *gin.Context, eventService *services.EventService) {
 	}
 
 	context.JSON(http.StatusOK, gin.H{
-		"message": "Event has been deleted successfully",
+		"message":       "Event has been deleted successfully",
+		"cancellations": cancellations,
+	})
+}
+
+func listEventMembers(context *gin.Context, eventService *services.EventService) {
+	eventId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse event id",
+		})
+		return
+	}
+
+	members, err := eventService.EventMembers(context.GetInt64("orgId"), eventId, context.GetInt64("userId"))
+	if err != nil {
+		eventMemberFailed(context, err, "Failed to retrieve event members")
+		return
+	}
+
+	context.JSON(http.StatusOK, members)
+}
+
+func addEventMember(context *gin.Context, eventService *services.EventService) {
+	eventId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse event id",
+		})
+		return
+	}
+
+	var request struct {
+		Email string `binding:"required,email"`
+		Role  string `binding:"required"`
+	}
+	err = context.ShouldBindJSON(&request)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Cannot parse request data",
+		})
+		return
+	}
+
+	member, err := eventService.AddEventMember(context.GetInt64("orgId"), eventId, context.GetInt64("userId"), request.Email, request.Role)
+	if err != nil {
+		eventMemberFailed(context, err, "Could not add event member")
+		return
+	}
+
+	context.JSON(http.StatusCreated, gin.H{
+		"message": "Event member added successfully",
+		"member":  member,
+	})
+}
+
+func removeEventMember(context *gin.Context, eventService *services.EventService) {
+	eventId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse event id",
+		})
+		return
+	}
+	memberId, err := strconv.ParseInt(context.Param("userId"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse user id",
+		})
+		return
+	}
+
+	err = eventService.RemoveEventMember(context.GetInt64("orgId"), eventId, context.GetInt64("userId"), memberId)
+	if err != nil {
+		eventMemberFailed(context, err, "Could not remove event member")
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Event member has been removed successfully",
 	})
 }
+
+// eventMemberFailed answers with the status matching an error of the event
+// member methods.
+func eventMemberFailed(context *gin.Context, err error, message string) {
+	if errors.Is(err, services.ErrEventNotFound) || errors.Is(err, services.ErrEventMemberNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{
+			"message": err.Error(),
+		})
+	} else if errors.Is(err, services.ErrForbidden) {
+		context.JSON(http.StatusForbidden, gin.H{
+			"message": err.Error(),
+		})
+	} else if errors.Is(err, services.ErrInvalidEventRole) || errors.Is(err, services.ErrInviteeNotInOrganization) {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+	} else if errors.Is(err, services.ErrAlreadyEventMember) {
+		context.JSON(http.StatusConflict, gin.H{
+			"message": err.Error(),
+		})
+	} else {
+		context.JSON(http.StatusInternalServerError, gin.H{
+			"message": message,
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"event-booking/services"
+)
+
+func listGroups(context *gin.Context, eventRegisterService *services.EventRegisterService) {
+	groups, err := eventRegisterService.Groups(context.GetInt64("orgId"), context.GetInt64("userId"))
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{
+			"message": "Failed to retrieve groups",
+		})
+		return
+	}
+
+	context.JSON(http.StatusOK, groups)
+}
+
+func getGroup(context *gin.Context, eventRegisterService *services.EventRegisterService) {
+	groupId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse group id",
+		})
+		return
+	}
+
+	group, err := eventRegisterService.Group(context.GetInt64("orgId"), context.GetInt64("userId"), groupId)
+	if err != nil {
+		groupFailed(context, err, "Failed to retrieve group")
+		return
+	}
+
+	context.JSON(http.StatusOK, group)
+}
+
+func cancelGroup(context *gin.Context, eventRegisterService *services.EventRegisterService) {
+	groupId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse group id",
+		})
+		return
+	}
+
+	cancellations, err := eventRegisterService.CancelGroup(context.GetInt64("orgId"), context.GetInt64("userId"), groupId)
+	if err != nil {
+		groupFailed(context, err, "Could not cancel group")
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message":       "Group registrations have been cancelled",
+		"cancellations": cancellations,
+	})
+}
+
+func cancelGroupRegistration(context *gin.Context, eventRegisterService *services.EventRegisterService) {
+	groupId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse group id",
+		})
+		return
+	}
+	registrationId, err := strconv.ParseInt(context.Param("registrationId"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse registration id",
+		})
+		return
+	}
+
+	cancellation, err := eventRegisterService.CancelGroupRegistration(context.GetInt64("orgId"), context.GetInt64("userId"), groupId, registrationId)
+	if err != nil {
+		groupFailed(context, err, "Could not cancel registration")
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message":      "Registration has been cancelled",
+		"cancellation": cancellation,
+	})
+}
+
+// groupFailed answers with the status matching an error of managing a group
+// registration.
+func groupFailed(context *gin.Context, err error, message string) {
+	if errors.Is(err, services.ErrGroupNotFound) || errors.Is(err, services.ErrEventNotFound) ||
+		errors.Is(err, services.ErrRegisterEventNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{
+			"message": err.Error(),
+		})
+	} else if errors.Is(err, services.ErrRefundFailed) {
+		context.JSON(http.StatusBadGateway, gin.H{
+			"message": err.Error(),
+		})
+	} else {
+		context.JSON(http.StatusInternalServerError, gin.H{
+			"message": message,
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"event-booking/services"
+)
+
+func listEventInvitations(context *gin.Context, invitationService *services.EventInvitationService) {
+	eventId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse event id",
+		})
+		return
+	}
+
+	invitations, err := invitationService.List(context.GetInt64("orgId"), eventId, context.GetInt64("userId"))
+	if err != nil {
+		invitationFailed(context, err, "Failed to retrieve invitations")
+		return
+	}
+
+	context.JSON(http.StatusOK, invitations)
+}
+
+func inviteToEvent(context *gin.Context, invitationService *services.EventInvitationService) {
+	eventId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse event id",
+		})
+		return
+	}
+
+	var request struct {
+		Emails []string `binding:"required,min=1,max=100,dive,email"`
+	}
+	err = context.ShouldBindJSON(&request)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Cannot parse request data",
+		})
+		return
+	}
+
+	invitations, err := invitationService.InviteByEmail(context.GetInt64("orgId"), eventId, context.GetInt64("userId"), request.Emails)
+	if errors.Is(err, services.ErrInvitationEmailNotSent) {
+		context.JSON(http.StatusBadGateway, gin.H{
+			"message":     err.Error(),
+			"invitations": invitations,
+		})
+		return
+	}
+	if err != nil {
+		invitationFailed(context, err, "Could not invite to event")
+		return
+	}
+
+	context.JSON(http.StatusCreated, gin.H{
+		"message":     "Invitations sent successfully",
+		"invitations": invitations,
+	})
+}
+
+func createEventInviteLink(context *gin.Context, invitationService *services.EventInvitationService) {
+	eventId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse event id",
+		})
+		return
+	}
+
+	link, expiresAt, err := invitationService.CreateInviteLink(context.GetInt64("orgId"), eventId, context.GetInt64("userId"))
+	if err != nil {
+		invitationFailed(context, err, "Could not create invite link")
+		return
+	}
+
+	context.JSON(http.StatusCreated, gin.H{
+		"url":       link,
+		"expiresAt": expiresAt,
+	})
+}
+
+func revokeEventInvitation(context *gin.Context, invitationService *services.EventInvitationService) {
+	eventId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse event id",
+		})
+		return
+	}
+	invitationId, err := strconv.ParseInt(context.Param("invitationId"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse invitation id",
+		})
+		return
+	}
+
+	err = invitationService.Revoke(context.GetInt64("orgId"), eventId, context.GetInt64("userId"), invitationId)
+	if err != nil {
+		invitationFailed(context, err, "Could not revoke invitation")
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Invitation has been revoked successfully",
+	})
+}
+
+func acceptEventInvitation(context *gin.Context, invitationService *services.EventInvitationService) {
+	token := context.Query("token")
+	if token == "" {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Invitation token is required",
+		})
+		return
+	}
+
+	event, err := invitationService.Accept(context.GetInt64("userId"), token)
+	if err != nil {
+		invitationFailed(context, err, "Could not accept invitation")
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Invitation accepted successfully",
+		"event":   event,
+	})
+}
+
+// checkEventInvitation answers the invitation link, which is opened with GET
+// and without logging in, and tells whether it can still be accepted.
+func checkEventInvitation(context *gin.Context, invitationService *services.EventInvitationService) {
+	token := context.Query("token")
+	if token == "" {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Invitation token is required",
+		})
+		return
+	}
+
+	err := invitationService.CheckInvitation(token)
+	if err != nil {
+		invitationFailed(context, err, "Could not check invitation")
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Invitation is valid, log in and send it to POST /invitations/accept to accept it",
+	})
+}
+
+// invitationFailed answers with the status matching an error of the event
+// invitation service.
+func invitationFailed(context *gin.Context, err error, message string) {
+	if errors.Is(err, services.ErrEventNotFound) || errors.Is(err, services.ErrInvitationNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{
+			"message": err.Error(),
+		})
+	} else if errors.Is(err, services.ErrForbidden) {
+		context.JSON(http.StatusForbidden, gin.H{
+			"message": err.Error(),
+		})
+	} else if errors.Is(err, services.ErrInvalidInvitation) {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+	} else {
+		context.JSON(http.StatusInternalServerError, gin.H{
+			"message": message,
+		})
+	}
+}
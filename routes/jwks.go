@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"event-booking/utils"
+)
+
+// getJWKS publishes the public keys other services can use to verify tokens
+// issued by this API.
+func getJWKS(context *gin.Context) {
+	jwks, err := utils.PublicJWKS()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{
+			"message": "Could not load signing keys",
+		})
+		return
+	}
+
+	context.Header("Cache-Control", "public, max-age=300")
+	context.JSON(http.StatusOK, jwks)
+}
@@ -0,0 +1,84 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"event-booking/services"
+)
+
+func listOIDCProviders(context *gin.Context, oidcService *services.OIDCService) {
+	context.JSON(http.StatusOK, gin.H{
+		"providers": oidcService.Providers(),
+	})
+}
+
+func startOIDCLogin(context *gin.Context, oidcService *services.OIDCService) {
+	redirectURL, err := oidcService.StartLogin(context.Param("provider"))
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownOIDCProvider) {
+			context.JSON(http.StatusNotFound, gin.H{
+				"message": err.Error(),
+			})
+		} else {
+			context.JSON(http.StatusBadGateway, gin.H{
+				"message": "Could not reach the identity provider",
+			})
+		}
+		return
+	}
+
+	context.Redirect(http.StatusFound, redirectURL)
+}
+
+func completeOIDCLogin(context *gin.Context, oidcService *services.OIDCService, twoFactorService *services.TwoFactorService) {
+	if providerError := context.Query("error"); providerError != "" {
+		context.JSON(http.StatusUnauthorized, gin.H{
+			"message": "Identity provider login failed: " + providerError,
+		})
+		return
+	}
+
+	var request struct {
+		Code  string `form:"code" binding:"required"`
+		State string `form:"state" binding:"required"`
+	}
+	err := context.ShouldBindQuery(&request)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Cannot parse request data",
+		})
+		return
+	}
+
+	token, user, err := oidcService.CompleteLogin(context.Param("provider"), request.State, request.Code)
+	if err != nil {
+		if errors.Is(err, services.ErrTwoFactorRequired) {
+			loginChallenge(context, user.Id, twoFactorService)
+		} else if errors.Is(err, services.ErrUnknownOIDCProvider) {
+			context.JSON(http.StatusNotFound, gin.H{
+				"message": err.Error(),
+			})
+		} else if errors.Is(err, services.ErrInvalidOIDCState) || errors.Is(err, services.ErrOIDCLoginFailed) {
+			context.JSON(http.StatusUnauthorized, gin.H{
+				"message": err.Error(),
+			})
+		} else if errors.Is(err, services.ErrOIDCEmailNotVerified) {
+			context.JSON(http.StatusForbidden, gin.H{
+				"message": err.Error(),
+			})
+		} else {
+			context.JSON(http.StatusInternalServerError, gin.H{
+				"message": "Could not authenticate user",
+			})
+		}
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Logged in successfully",
+		"token":   token,
+	})
+}
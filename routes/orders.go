@@ -0,0 +1,157 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"event-booking/services"
+)
+
+func checkout(context *gin.Context, orderService *services.OrderService) {
+	eventId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse event id",
+		})
+		return
+	}
+
+	order, err := orderService.Checkout(context.GetInt64("orgId"), context.GetInt64("userId"), eventId)
+	if err != nil {
+		orderFailed(context, err, "Could not start checkout")
+		return
+	}
+
+	context.JSON(http.StatusOK, order)
+}
+
+func checkoutGroup(context *gin.Context, orderService *services.OrderService) {
+	groupId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse group id",
+		})
+		return
+	}
+
+	order, err := orderService.CheckoutGroup(context.GetInt64("orgId"), context.GetInt64("userId"), groupId)
+	if err != nil {
+		orderFailed(context, err, "Could not start checkout")
+		return
+	}
+
+	context.JSON(http.StatusOK, order)
+}
+
+func getOrder(context *gin.Context, orderService *services.OrderService) {
+	id, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse order id",
+		})
+		return
+	}
+
+	order, err := orderService.GetOrder(context.GetInt64("orgId"), context.GetInt64("userId"), id)
+	if err != nil {
+		orderFailed(context, err, "Could not retrieve order")
+		return
+	}
+
+	context.JSON(http.StatusOK, order)
+}
+
+func confirmOrder(context *gin.Context, orderService *services.OrderService) {
+	id, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse order id",
+		})
+		return
+	}
+
+	var request struct {
+		PaymentMethod string `binding:"required,max=255"`
+	}
+	err = context.ShouldBindJSON(&request)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Cannot parse request data",
+		})
+		return
+	}
+
+	order, err := orderService.Confirm(context.GetInt64("orgId"), context.GetInt64("userId"), id, request.PaymentMethod)
+	if errors.Is(err, services.ErrPaymentFailed) || errors.Is(err, services.ErrEventFull) {
+		context.JSON(http.StatusPaymentRequired, gin.H{
+			"message": err.Error(),
+			"order":   order,
+		})
+		return
+	}
+	if err != nil {
+		orderFailed(context, err, "Could not confirm payment")
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Payment succeeded",
+		"order":   order,
+	})
+}
+
+func paymentWebhook(context *gin.Context, orderService *services.OrderService) {
+	payload, err := context.GetRawData()
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Cannot read request body",
+		})
+		return
+	}
+
+	signature := context.GetHeader("Payment-Signature")
+	if signature == "" {
+		signature = context.GetHeader("Stripe-Signature")
+	}
+	err = orderService.HandleWebhook(payload, signature)
+	if err != nil {
+		orderFailed(context, err, "Could not process webhook")
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Webhook processed",
+	})
+}
+
+// orderFailed answers with the status matching an error of the order
+// service.
+func orderFailed(context *gin.Context, err error, message string) {
+	if errors.Is(err, services.ErrOrderNotFound) || errors.Is(err, services.ErrRegisterEventNotFound) ||
+		errors.Is(err, services.ErrGroupNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{
+			"message": err.Error(),
+		})
+	} else if errors.Is(err, services.ErrInvalidWebhook) {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+	} else if errors.Is(err, services.ErrNothingToPay) || errors.Is(err, services.ErrPaidWithGroup) ||
+		errors.Is(err, services.ErrOrderClosed) || errors.Is(err, services.ErrOrderChanged) ||
+		errors.Is(err, services.ErrInvalidOrderTransition) {
+		context.JSON(http.StatusConflict, gin.H{
+			"message": err.Error(),
+		})
+	} else if errors.Is(err, services.ErrPaymentProvider) {
+		context.JSON(http.StatusBadGateway, gin.H{
+			"message": err.Error(),
+		})
+	} else {
+		context.JSON(http.StatusInternalServerError, gin.H{
+			"message": message,
+		})
+	}
+}
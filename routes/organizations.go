@@ -0,0 +1,230 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"event-booking/services"
+)
+
+func listOrganizations(context *gin.Context, organizationService *services.OrganizationService) {
+	memberships, err := organizationService.List(context.GetInt64("userId"))
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{
+			"message": "Failed to retrieve organizations",
+		})
+		return
+	}
+
+	context.JSON(http.StatusOK, memberships)
+}
+
+func createOrganization(context *gin.Context, organizationService *services.OrganizationService) {
+	var request struct {
+		Name string `binding:"required,min=3,max=100"`
+	}
+	err := context.ShouldBindJSON(&request)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Cannot parse request data",
+		})
+		return
+	}
+
+	membership, err := organizationService.Create(context.GetInt64("userId"), request.Name)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{
+			"message": "Could not create organization",
+		})
+		return
+	}
+
+	context.JSON(http.StatusCreated, gin.H{
+		"message":      "Organization created successfully",
+		"organization": membership,
+	})
+}
+
+func getOrganization(context *gin.Context, organizationService *services.OrganizationService) {
+	orgId, ok := organizationIdParam(context)
+	if !ok {
+		return
+	}
+
+	membership, err := organizationService.Authorize(orgId, context.GetInt64("userId"))
+	if err != nil {
+		organizationFailed(context, err, "Failed to retrieve organization")
+		return
+	}
+
+	context.JSON(http.StatusOK, membership)
+}
+
+func updateOrganization(context *gin.Context, organizationService *services.OrganizationService) {
+	orgId, ok := organizationIdParam(context)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Name string `binding:"required,min=3,max=100"`
+	}
+	err := context.ShouldBindJSON(&request)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Cannot parse request data",
+		})
+		return
+	}
+
+	membership, err := organizationService.Rename(context.GetInt64("userId"), orgId, request.Name)
+	if err != nil {
+		organizationFailed(context, err, "Could not update organization")
+		return
+	}
+
+	context.JSON(http.StatusOK, membership)
+}
+
+func listOrganizationMembers(context *gin.Context, organizationService *services.OrganizationService) {
+	orgId, ok := organizationIdParam(context)
+	if !ok {
+		return
+	}
+
+	members, err := organizationService.Members(context.GetInt64("userId"), orgId)
+	if err != nil {
+		organizationFailed(context, err, "Failed to retrieve members")
+		return
+	}
+
+	context.JSON(http.StatusOK, members)
+}
+
+func addOrganizationMember(context *gin.Context, organizationService *services.OrganizationService) {
+	orgId, ok := organizationIdParam(context)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Email string `binding:"required,email"`
+		Role  string `binding:"required"`
+	}
+	err := context.ShouldBindJSON(&request)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Cannot parse request data",
+		})
+		return
+	}
+
+	member, err := organizationService.AddMember(context.GetInt64("userId"), orgId, request.Email, request.Role)
+	if err != nil {
+		organizationFailed(context, err, "Could not add member")
+		return
+	}
+
+	context.JSON(http.StatusCreated, gin.H{
+		"message": "Member added successfully",
+		"member":  member,
+	})
+}
+
+func updateOrganizationMember(context *gin.Context, organizationService *services.OrganizationService) {
+	orgId, ok := organizationIdParam(context)
+	if !ok {
+		return
+	}
+	memberId, err := strconv.ParseInt(context.Param("userId"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse user id",
+		})
+		return
+	}
+
+	var request struct {
+		Role string `binding:"required"`
+	}
+	err = context.ShouldBindJSON(&request)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Cannot parse request data",
+		})
+		return
+	}
+
+	member, err := organizationService.UpdateMemberRole(context.GetInt64("userId"), orgId, memberId, request.Role)
+	if err != nil {
+		organizationFailed(context, err, "Could not update member")
+		return
+	}
+
+	context.JSON(http.StatusOK, member)
+}
+
+func removeOrganizationMember(context *gin.Context, organizationService *services.OrganizationService) {
+	orgId, ok := organizationIdParam(context)
+	if !ok {
+		return
+	}
+	memberId, err := strconv.ParseInt(context.Param("userId"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse user id",
+		})
+		return
+	}
+
+	err = organizationService.RemoveMember(context.GetInt64("userId"), orgId, memberId)
+	if err != nil {
+		organizationFailed(context, err, "Could not remove member")
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Member has been removed successfully",
+	})
+}
+
+func organizationIdParam(context *gin.Context) (int64, bool) {
+	orgId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse organization id",
+		})
+		return 0, false
+	}
+	return orgId, true
+}
+
+// organizationFailed answers with the status matching an error of the
+// organization service.
+func organizationFailed(context *gin.Context, err error, message string) {
+	if errors.Is(err, services.ErrOrganizationNotFound) || errors.Is(err, services.ErrOrganizationMemberNotFound) ||
+		errors.Is(err, services.ErrUserNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{
+			"message": err.Error(),
+		})
+	} else if errors.Is(err, services.ErrForbidden) {
+		context.JSON(http.StatusForbidden, gin.H{
+			"message": err.Error(),
+		})
+	} else if errors.Is(err, services.ErrInvalidOrganizationRole) {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+	} else if errors.Is(err, services.ErrAlreadyOrganizationMember) || errors.Is(err, services.ErrLastOrganizationOwner) {
+		context.JSON(http.StatusConflict, gin.H{
+			"message": err.Error(),
+		})
+	} else {
+		context.JSON(http.StatusInternalServerError, gin.H{
+			"message": message,
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"event-booking/models"
+	"event-booking/services"
+)
+
+func getAccount(context *gin.Context, userService *services.UserService) {
+	userId := context.GetInt64("userId")
+	account, err := userService.GetAccount(userId)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			context.JSON(http.StatusNotFound, gin.H{
+				"message": err.Error(),
+			})
+		} else {
+			context.JSON(http.StatusInternalServerError, gin.H{
+				"message": "Failed to retrieve account",
+			})
+		}
+		return
+	}
+
+	context.JSON(http.StatusOK, account)
+}
+
+func updateProfile(context *gin.Context, userService *services.UserService) {
+	var profile models.Profile
+	err := context.ShouldBindJSON(&profile)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Cannot parse request data",
+		})
+		return
+	}
+
+	userId := context.GetInt64("userId")
+	err = userService.UpdateProfile(userId, &profile)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{
+			"message": "Profile could not be updated",
+		})
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Profile has been updated successfully",
+		"profile": profile,
+	})
+}
+
+func getProfile(context *gin.Context, userService *services.UserService) {
+	userId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse user id",
+		})
+		return
+	}
+
+	profile, err := userService.GetProfile(userId)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			context.JSON(http.StatusNotFound, gin.H{
+				"message": err.Error(),
+			})
+		} else {
+			context.JSON(http.StatusInternalServerError, gin.H{
+				"message": "Failed to retrieve profile",
+			})
+		}
+		return
+	}
+
+	context.JSON(http.StatusOK, profile)
+}
@@ -0,0 +1,148 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"event-booking/models"
+	"event-booking/services"
+)
+
+func listPromoCodes(context *gin.Context, eventService *services.EventService) {
+	eventId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse event id",
+		})
+		return
+	}
+
+	promoCodes, err := eventService.PromoCodes(context.GetInt64("orgId"), eventId, context.GetInt64("userId"))
+	if err != nil {
+		promoCodeFailed(context, err, "Failed to retrieve promo codes")
+		return
+	}
+
+	context.JSON(http.StatusOK, promoCodes)
+}
+
+func createPromoCode(context *gin.Context, eventService *services.EventService) {
+	eventId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse event id",
+		})
+		return
+	}
+
+	var promoCode models.PromoCode
+	err = context.ShouldBindJSON(&promoCode)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Cannot parse request data",
+		})
+		return
+	}
+
+	err = eventService.CreatePromoCode(context.GetInt64("orgId"), eventId, context.GetInt64("userId"), &promoCode)
+	if err != nil {
+		promoCodeFailed(context, err, "Could not create promo code")
+		return
+	}
+
+	context.JSON(http.StatusCreated, gin.H{
+		"message":   "Promo code created successfully",
+		"promoCode": promoCode,
+	})
+}
+
+func updatePromoCode(context *gin.Context, eventService *services.EventService) {
+	eventId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse event id",
+		})
+		return
+	}
+	promoCodeId, err := strconv.ParseInt(context.Param("promoCodeId"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse promo code id",
+		})
+		return
+	}
+
+	var promoCode models.PromoCode
+	err = context.ShouldBindJSON(&promoCode)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Cannot parse request data",
+		})
+		return
+	}
+
+	promoCode.Id = promoCodeId
+	err = eventService.UpdatePromoCode(context.GetInt64("orgId"), eventId, context.GetInt64("userId"), &promoCode)
+	if err != nil {
+		promoCodeFailed(context, err, "Could not update promo code")
+		return
+	}
+
+	context.JSON(http.StatusOK, promoCode)
+}
+
+func deletePromoCode(context *gin.Context, eventService *services.EventService) {
+	eventId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse event id",
+		})
+		return
+	}
+	promoCodeId, err := strconv.ParseInt(context.Param("promoCodeId"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse promo code id",
+		})
+		return
+	}
+
+	err = eventService.DeletePromoCode(context.GetInt64("orgId"), eventId, context.GetInt64("userId"), promoCodeId)
+	if err != nil {
+		promoCodeFailed(context, err, "Could not delete promo code")
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Promo code has been deleted successfully",
+	})
+}
+
+// promoCodeFailed answers with the status matching an error of the promo
+// code methods.
+func promoCodeFailed(context *gin.Context, err error, message string) {
+	if errors.Is(err, services.ErrEventNotFound) || errors.Is(err, services.ErrPromoCodeNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{
+			"message": err.Error(),
+		})
+	} else if errors.Is(err, services.ErrForbidden) {
+		context.JSON(http.StatusForbidden, gin.H{
+			"message": err.Error(),
+		})
+	} else if errors.Is(err, services.ErrInvalidPromoCode) {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+	} else if errors.Is(err, services.ErrPromoCodeExists) || errors.Is(err, services.ErrPromoCodeInUse) {
+		context.JSON(http.StatusConflict, gin.H{
+			"message": err.Error(),
+		})
+	} else {
+		context.JSON(http.StatusInternalServerError, gin.H{
+			"message": message,
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"event-booking/models"
+	"event-booking/services"
+)
+
+func getRegistrationQuestions(context *gin.Context, eventService *services.EventService) {
+	eventId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse event id",
+		})
+		return
+	}
+
+	questions, err := eventService.RegistrationQuestions(context.GetInt64("orgId"), eventId, context.GetInt64("userId"))
+	if err != nil {
+		questionsFailed(context, err, "Failed to retrieve registration questions")
+		return
+	}
+
+	context.JSON(http.StatusOK, questions)
+}
+
+func setRegistrationQuestions(context *gin.Context, eventService *services.EventService) {
+	eventId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse event id",
+		})
+		return
+	}
+
+	var request struct {
+		Questions []models.RegistrationQuestion `binding:"required,dive"`
+	}
+	err = context.ShouldBindJSON(&request)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Cannot parse request data",
+		})
+		return
+	}
+
+	questions, err := eventService.SetRegistrationQuestions(context.GetInt64("orgId"), eventId, context.GetInt64("userId"), request.Questions)
+	if err != nil {
+		questionsFailed(context, err, "Could not update registration questions")
+		return
+	}
+
+	context.JSON(http.StatusOK, questions)
+}
+
+// questionsFailed answers with the status matching an error of the
+// registration question methods.
+func questionsFailed(context *gin.Context, err error, message string) {
+	if errors.Is(err, services.ErrEventNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{
+			"message": err.Error(),
+		})
+	} else if errors.Is(err, services.ErrForbidden) {
+		context.JSON(http.StatusForbidden, gin.H{
+			"message": err.Error(),
+		})
+	} else if errors.Is(err, services.ErrInvalidRegistrationQuestions) {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+	} else {
+		context.JSON(http.StatusInternalServerError, gin.H{
+			"message": message,
+		})
+	}
+}
@@ -1,15 +1,20 @@
 package routes
 
 import (
+	"encoding/csv"
 	"errors"
+	"event-booking/models"
 	"event-booking/services"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/skip2/go-qrcode"
 )
 
-func registerEvent(context *gin.Context, eventRegisterService *services.EventRegisterService) {
+func registerEvent(context *gin.Context, eventRegisterService *services.EventRegisterService, orderService *services.OrderService) {
 	userId := context.GetInt64("userId")
 	eventId, err := strconv.ParseInt(context.Param("id"), 10, 64)
 
@@ -20,27 +25,141 @@ func registerEvent(context *gin.Context, eventRegisterService *services.EventReg
 		return
 	}
 
-	err = eventRegisterService.RegisterEvent(userId, eventId)
+	// The body is optional for events without registration questions.
+	// Listing attendees books a group registration instead of registering
+	// the user.
+	var request struct {
+		TicketTypeId int64
+		PromoCode    string                      `binding:"max=50"`
+		Answers      []models.RegistrationAnswer `binding:"max=50,dive"`
+		Attendees    []models.GroupAttendee      `binding:"max=20,dive"`
+	}
+	err = context.ShouldBindJSON(&request)
+	if err != nil && !errors.Is(err, io.EOF) {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Cannot parse request data",
+		})
+		return
+	}
+
+	if len(request.Attendees) > 0 {
+		group, err := eventRegisterService.RegisterGroup(context.GetInt64("orgId"), userId, eventId, request.TicketTypeId, request.PromoCode, request.Attendees)
+		if err != nil {
+			registrationFailed(context, err, "Could not register group")
+			return
+		}
+
+		message := "Group has been registered successfully"
+		if group.Registrations[0].Status == services.RegistrationPending {
+			message = "Group registrations are waiting for approval by the organizer"
+		}
+		context.JSON(http.StatusCreated, gin.H{
+			"message": message,
+			"group":   group,
+		})
+		return
+	}
+
+	registration, err := eventRegisterService.RegisterEvent(context.GetInt64("orgId"), userId, eventId, request.TicketTypeId, request.PromoCode, request.Answers)
 	if err != nil {
-		if errors.Is(err, services.ErrEventNotFound) {
+		registrationFailed(context, err, "Could not register for event")
+		return
+	}
+
+	if registration.Status == services.RegistrationAwaitingPayment {
+		order, err := orderService.Checkout(context.GetInt64("orgId"), userId, eventId)
+		if err != nil {
+			context.JSON(http.StatusBadGateway, gin.H{
+				"message":      "Registration is awaiting payment, but checkout failed. Retry the checkout to pay",
+				"registration": registration,
+			})
+			return
+		}
+
+		context.JSON(http.StatusCreated, gin.H{
+			"message":      "Complete the payment to confirm your registration",
+			"registration": registration,
+			"order":        order,
+		})
+		return
+	}
+
+	message := "Event has been registered successfully"
+	if registration.Status == services.RegistrationPending {
+		message = "Registration is waiting for approval by the organizer"
+	}
+	context.JSON(http.StatusCreated, gin.H{
+		"message":      message,
+		"registration": registration,
+	})
+}
+
+// registrationFailed answers with the status matching an error of
+// registering for an event, alone or as a group.
+func registrationFailed(context *gin.Context, err error, message string) {
+	if errors.Is(err, services.ErrEventNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{
+			"message": err.Error(),
+		})
+	} else if errors.Is(err, services.ErrEmailNotVerified) {
+		context.JSON(http.StatusForbidden, gin.H{
+			"message": err.Error(),
+		})
+	} else if errors.Is(err, services.ErrInvalidAnswers) || errors.Is(err, services.ErrTicketTypeRequired) ||
+		errors.Is(err, services.ErrTicketTypeNotFound) || errors.Is(err, services.ErrPromoCodeNotApplicable) ||
+		errors.Is(err, services.ErrInvalidAttendees) {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+	} else if errors.Is(err, services.ErrAlreadyRegistered) || errors.Is(err, services.ErrEventFull) ||
+		errors.Is(err, services.ErrTicketTypeSoldOut) || errors.Is(err, services.ErrTicketNotOnSale) ||
+		errors.Is(err, services.ErrPromoCodeUsedUp) || errors.Is(err, services.ErrAttendeeAlreadyRegistered) {
+		context.JSON(http.StatusConflict, gin.H{
+			"message": err.Error(),
+		})
+	} else {
+		context.JSON(http.StatusInternalServerError, gin.H{
+			"message": message,
+		})
+	}
+}
+
+func cancelEventRegister(context *gin.Context, eventRegisterService *services.EventRegisterService) {
+	userId := context.GetInt64("userId")
+	eventId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse eventId",
+		})
+		return
+	}
+
+	cancellation, err := eventRegisterService.CancelEvent(context.GetInt64("orgId"), userId, eventId)
+	if err != nil {
+		if errors.Is(err, services.ErrEventNotFound) || errors.Is(err, services.ErrRegisterEventNotFound) {
 			context.JSON(http.StatusNotFound, gin.H{
 				"message": err.Error(),
 			})
+		} else if errors.Is(err, services.ErrRefundFailed) {
+			context.JSON(http.StatusBadGateway, gin.H{
+				"message":      err.Error(),
+				"cancellation": cancellation,
+			})
 		} else {
 			context.JSON(http.StatusInternalServerError, gin.H{
-				"message": "Could not register for event",
+				"message": "Could not cancel registration",
 			})
 		}
 		return
 	}
 
-	context.JSON(http.StatusCreated, gin.H{
-		"message": "Event has been registered successfully",
+	context.JSON(http.StatusOK, gin.H{
+		"message":      "Event registration has been canceled successfully",
+		"cancellation": cancellation,
 	})
 }
 
-func cancelEventRegister(context *gin.Context, eventRegisterService *services.EventRegisterService) {
-	userId := context.GetInt64("userId")
+func listEventRegistrations(context *gin.Context, eventRegisterService *services.EventRegisterService) {
 	eventId, err := strconv.ParseInt(context.Param("id"), 10, 64)
 	if err != nil {
 		context.JSON(http.StatusBadRequest, gin.H{
@@ -49,21 +168,233 @@ func cancelEventRegister(context *gin.Context, eventRegisterService *services.Ev
 		return
 	}
 
-	err = eventRegisterService.CancelEvent(userId, eventId)
+	registrations, err := eventRegisterService.Registrations(context.GetInt64("orgId"), eventId, context.GetInt64("userId"), context.Query("status"))
 	if err != nil {
-		if errors.Is(err, services.ErrEventNotFound) || errors.Is(err, services.ErrRegisterEventNotFound) {
+		registrationDecisionFailed(context, err, "Failed to retrieve registrations")
+		return
+	}
+
+	context.JSON(http.StatusOK, registrations)
+}
+
+func exportEventAttendees(context *gin.Context, eventRegisterService *services.EventRegisterService) {
+	eventId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse eventId",
+		})
+		return
+	}
+
+	records, err := eventRegisterService.ExportAttendees(context.GetInt64("orgId"), eventId, context.GetInt64("userId"))
+	if err != nil {
+		registrationDecisionFailed(context, err, "Failed to export attendees")
+		return
+	}
+
+	context.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="event-%d-attendees.csv"`, eventId))
+	context.Header("Content-Type", "text/csv; charset=utf-8")
+	context.Status(http.StatusOK)
+	writer := csv.NewWriter(context.Writer)
+	writer.WriteAll(records)
+}
+
+func approveEventRegistrations(context *gin.Context, eventRegisterService *services.EventRegisterService) {
+	decideEventRegistrations(context, eventRegisterService.ApproveRegistrations, "Registrations have been approved successfully")
+}
+
+func rejectEventRegistrations(context *gin.Context, eventRegisterService *services.EventRegisterService) {
+	decideEventRegistrations(context, eventRegisterService.RejectRegistrations, "Registrations have been rejected successfully")
+}
+
+func promoteEventRegistrations(context *gin.Context, eventRegisterService *services.EventRegisterService) {
+	eventId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse eventId",
+		})
+		return
+	}
+
+	var request struct {
+		Count int `binding:"required,min=1,max=500"`
+	}
+	err = context.ShouldBindJSON(&request)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Cannot parse request data",
+		})
+		return
+	}
+
+	promoted, err := eventRegisterService.PromoteRegistrations(context.GetInt64("orgId"), eventId, context.GetInt64("userId"), request.Count)
+	if err != nil {
+		registrationDecisionFailed(context, err, "Could not promote registrations")
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message":       "Registrations have been promoted successfully",
+		"registrations": promoted,
+	})
+}
+
+func decideEventRegistrations(context *gin.Context, decide func(int64, int64, int64, []int64) error, message string) {
+	eventId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse eventId",
+		})
+		return
+	}
+
+	var request struct {
+		RegistrationIds []int64 `binding:"required,min=1,max=500"`
+	}
+	err = context.ShouldBindJSON(&request)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Cannot parse request data",
+		})
+		return
+	}
+
+	err = decide(context.GetInt64("orgId"), eventId, context.GetInt64("userId"), request.RegistrationIds)
+	if err != nil {
+		registrationDecisionFailed(context, err, "Could not update registrations")
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": message,
+	})
+}
+
+// registrationDecisionFailed answers with the status matching an error of
+// the organizer methods of the registration service.
+func registrationDecisionFailed(context *gin.Context, err error, message string) {
+	if errors.Is(err, services.ErrEventNotFound) || errors.Is(err, services.ErrRegisterEventNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{
+			"message": err.Error(),
+		})
+	} else if errors.Is(err, services.ErrForbidden) {
+		context.JSON(http.StatusForbidden, gin.H{
+			"message": err.Error(),
+		})
+	} else if errors.Is(err, services.ErrInvalidRegistrationStatus) {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+	} else if errors.Is(err, services.ErrEventFull) || errors.Is(err, services.ErrRegistrationNotPending) {
+		context.JSON(http.StatusConflict, gin.H{
+			"message": err.Error(),
+		})
+	} else if errors.Is(err, services.ErrRejectionRefundFailed) {
+		context.JSON(http.StatusBadGateway, gin.H{
+			"message": err.Error(),
+		})
+	} else {
+		context.JSON(http.StatusInternalServerError, gin.H{
+			"message": message,
+		})
+	}
+}
+
+// getTicket renders the ticket of a registration as a QR code to show at the
+// door.
+func getTicket(context *gin.Context, eventRegisterService *services.EventRegisterService) {
+	registrationId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse registration id",
+		})
+		return
+	}
+
+	token, err := eventRegisterService.TicketToken(context.GetInt64("orgId"), context.GetInt64("userId"), registrationId)
+	if err != nil {
+		if errors.Is(err, services.ErrRegisterEventNotFound) || errors.Is(err, services.ErrEventNotFound) {
+			context.JSON(http.StatusNotFound, gin.H{
+				"message": err.Error(),
+			})
+		} else if errors.Is(err, services.ErrTicketNotIssued) {
+			context.JSON(http.StatusConflict, gin.H{
+				"message": err.Error(),
+			})
+		} else {
+			context.JSON(http.StatusInternalServerError, gin.H{
+				"message": "Could not create ticket",
+			})
+		}
+		return
+	}
+
+	png, err := qrcode.Encode(token, qrcode.Medium, 512)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{
+			"message": "Could not create ticket",
+		})
+		return
+	}
+
+	// The ticket lets its holder in, so it must not be cached by proxies.
+	context.Header("Cache-Control", "no-store")
+	context.Data(http.StatusOK, "image/png", png)
+}
+
+func checkIn(context *gin.Context, eventRegisterService *services.EventRegisterService) {
+	eventId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse eventId",
+		})
+		return
+	}
+
+	var request struct {
+		Token string `binding:"required,max=4096"`
+	}
+	err = context.ShouldBindJSON(&request)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Cannot parse request data",
+		})
+		return
+	}
+
+	registration, err := eventRegisterService.CheckIn(context.GetInt64("orgId"), eventId, context.GetInt64("userId"), request.Token)
+	if err != nil {
+		if errors.Is(err, services.ErrEventNotFound) {
 			context.JSON(http.StatusNotFound, gin.H{
 				"message": err.Error(),
 			})
+		} else if errors.Is(err, services.ErrForbidden) {
+			context.JSON(http.StatusForbidden, gin.H{
+				"message": err.Error(),
+			})
+		} else if errors.Is(err, services.ErrInvalidTicket) || errors.Is(err, services.ErrTicketForOtherEvent) {
+			context.JSON(http.StatusBadRequest, gin.H{
+				"message": err.Error(),
+			})
+		} else if errors.Is(err, services.ErrAlreadyCheckedIn) {
+			context.JSON(http.StatusConflict, gin.H{
+				"message":      err.Error(),
+				"registration": registration,
+			})
+		} else if errors.Is(err, services.ErrTicketRevoked) {
+			context.JSON(http.StatusConflict, gin.H{
+				"message": err.Error(),
+			})
 		} else {
 			context.JSON(http.StatusInternalServerError, gin.H{
-				"message": "Could not register for event",
+				"message": "Could not check in ticket",
 			})
 		}
 		return
 	}
 
 	context.JSON(http.StatusOK, gin.H{
-		"message": "Event registration has been canceled successfully",
+		"message":      "Ticket has been checked in",
+		"registration": registration,
 	})
 }
@@ -7,45 +7,322 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// RateLimits configures the rate limit of each route group.
+type RateLimits struct {
+	Public        services.RateLimit
+	Authenticated services.RateLimit
+}
+
 func RegisterRoutes(
 	server *gin.Engine,
 	userService *services.UserService,
 	eventService *services.EventService,
 	eventRegisterService *services.EventRegisterService,
+	idempotencyService *services.IdempotencyService,
+	twoFactorService *services.TwoFactorService,
+	loginThrottleService *services.LoginThrottleService,
+	apiKeyService *services.APIKeyService,
+	oidcService *services.OIDCService,
+	organizationService *services.OrganizationService,
+	invitationService *services.EventInvitationService,
+	orderService *services.OrderService,
+	transferService *services.RegistrationTransferService,
+	rateLimitService *services.RateLimitService,
+	rateLimits RateLimits,
 ) {
+	idempotent := middleware.Idempotency(idempotencyService)
+	organizer := middleware.RequireOrganizerTwoFactor(twoFactorService)
+	session := middleware.RequireSession()
+	eventsRead := middleware.RequireScope(services.ScopeEventsRead)
+	eventsWrite := middleware.RequireScope(services.ScopeEventsWrite)
+	registrationsRead := middleware.RequireScope(services.ScopeRegistrationsRead)
+	registrationsWrite := middleware.RequireScope(services.ScopeRegistrationsWrite)
+	usersRead := middleware.RequireScope(services.ScopeUsersRead)
+	inOrg := middleware.RequireOrganization(organizationService)
+	orgAdmin := middleware.RequireOrganization(organizationService, services.RoleOwner, services.RoleAdmin)
+
 	authenticated := server.Group("/")
-	authenticated.Use(middleware.Authenticate)
-	authenticated.GET("/events", func(c *gin.Context) {
+	authenticated.Use(
+		middleware.Authenticate(userService, apiKeyService),
+		middleware.RateLimit(rateLimitService, "authenticated", rateLimits.Authenticated),
+	)
+	authenticated.GET("/events", eventsRead, inOrg, func(c *gin.Context) {
 		getEvents(c, eventService)
 	})
-	authenticated.GET("/events/:id", func(c *gin.Context) {
+	authenticated.GET("/events/:id", eventsRead, inOrg, func(c *gin.Context) {
 		getEventById(c, eventService)
 	})
-	authenticated.POST("/events", func(c *gin.Context) {
+	authenticated.POST("/events", eventsWrite, orgAdmin, organizer, idempotent, func(c *gin.Context) {
 		createEvent(c, eventService)
 	})
-	authenticated.PUT("/events/:id", func(c *gin.Context) {
+	authenticated.PUT("/events/:id", eventsWrite, inOrg, organizer, func(c *gin.Context) {
 		updateEvent(c, eventService)
 	})
-	authenticated.DELETE("/events/:id", func(c *gin.Context) {
-		deleteEvent(c, eventService)
+	authenticated.DELETE("/events/:id", eventsWrite, inOrg, organizer, func(c *gin.Context) {
+		deleteEvent(c, eventService, eventRegisterService)
+	})
+
+	authenticated.GET("/events/:id/members", eventsRead, inOrg, func(c *gin.Context) {
+		listEventMembers(c, eventService)
+	})
+	authenticated.POST("/events/:id/members", eventsWrite, inOrg, organizer, func(c *gin.Context) {
+		addEventMember(c, eventService)
+	})
+	authenticated.DELETE("/events/:id/members/:userId", eventsWrite, inOrg, organizer, func(c *gin.Context) {
+		removeEventMember(c, eventService)
+	})
+
+	authenticated.GET("/events/:id/invitations", eventsRead, inOrg, func(c *gin.Context) {
+		listEventInvitations(c, invitationService)
+	})
+	authenticated.POST("/events/:id/invitations", eventsWrite, inOrg, organizer, func(c *gin.Context) {
+		inviteToEvent(c, invitationService)
+	})
+	authenticated.DELETE("/events/:id/invitations/:invitationId", eventsWrite, inOrg, organizer, func(c *gin.Context) {
+		revokeEventInvitation(c, invitationService)
+	})
+	authenticated.POST("/events/:id/invite-link", eventsWrite, inOrg, organizer, func(c *gin.Context) {
+		createEventInviteLink(c, invitationService)
+	})
+	authenticated.POST("/invitations/accept", registrationsWrite, func(c *gin.Context) {
+		acceptEventInvitation(c, invitationService)
+	})
+
+	authenticated.GET("/events/:id/ticket-types", eventsRead, inOrg, func(c *gin.Context) {
+		listTicketTypes(c, eventService)
+	})
+	authenticated.POST("/events/:id/ticket-types", eventsWrite, inOrg, organizer, func(c *gin.Context) {
+		createTicketType(c, eventService)
+	})
+	authenticated.PUT("/events/:id/ticket-types/:ticketTypeId", eventsWrite, inOrg, organizer, func(c *gin.Context) {
+		updateTicketType(c, eventService)
+	})
+	authenticated.DELETE("/events/:id/ticket-types/:ticketTypeId", eventsWrite, inOrg, organizer, func(c *gin.Context) {
+		deleteTicketType(c, eventService)
+	})
+
+	authenticated.GET("/events/:id/promo-codes", eventsWrite, inOrg, organizer, func(c *gin.Context) {
+		listPromoCodes(c, eventService)
+	})
+	authenticated.POST("/events/:id/promo-codes", eventsWrite, inOrg, organizer, func(c *gin.Context) {
+		createPromoCode(c, eventService)
+	})
+	authenticated.PUT("/events/:id/promo-codes/:promoCodeId", eventsWrite, inOrg, organizer, func(c *gin.Context) {
+		updatePromoCode(c, eventService)
+	})
+	authenticated.DELETE("/events/:id/promo-codes/:promoCodeId", eventsWrite, inOrg, organizer, func(c *gin.Context) {
+		deletePromoCode(c, eventService)
 	})
 
-	authenticated.POST("/events/:id/register", func(c *gin.Context) {
-		registerEvent(c, eventRegisterService)
+	authenticated.GET("/events/:id/questions", eventsRead, inOrg, func(c *gin.Context) {
+		getRegistrationQuestions(c, eventService)
 	})
-	authenticated.DELETE("/events/:id/register", func(c *gin.Context) {
+	authenticated.PUT("/events/:id/questions", eventsWrite, inOrg, organizer, func(c *gin.Context) {
+		setRegistrationQuestions(c, eventService)
+	})
+
+	authenticated.GET("/events/:id/cancellation-policy", eventsRead, inOrg, func(c *gin.Context) {
+		getCancellationPolicy(c, eventService)
+	})
+	authenticated.PUT("/events/:id/cancellation-policy", eventsWrite, inOrg, organizer, func(c *gin.Context) {
+		setCancellationPolicy(c, eventService)
+	})
+	authenticated.GET("/events/:id/cancellations", eventsRead, inOrg, func(c *gin.Context) {
+		listCancellations(c, eventService)
+	})
+
+	authenticated.POST("/events/:id/register", registrationsWrite, inOrg, idempotent, func(c *gin.Context) {
+		registerEvent(c, eventRegisterService, orderService)
+	})
+	authenticated.DELETE("/events/:id/register", registrationsWrite, inOrg, func(c *gin.Context) {
 		cancelEventRegister(c, eventRegisterService)
 	})
+	authenticated.GET("/events/:id/attendance", eventsRead, inOrg, func(c *gin.Context) {
+		getEventAttendance(c, eventService)
+	})
+	authenticated.POST("/events/:id/checkin", eventsWrite, inOrg, func(c *gin.Context) {
+		checkIn(c, eventRegisterService)
+	})
+	authenticated.GET("/me/registrations/:id/ticket.png", registrationsRead, inOrg, func(c *gin.Context) {
+		getTicket(c, eventRegisterService)
+	})
+	authenticated.POST("/me/registrations/:id/transfer", registrationsWrite, inOrg, func(c *gin.Context) {
+		transferRegistration(c, transferService)
+	})
+	authenticated.DELETE("/me/registrations/:id/transfer", registrationsWrite, inOrg, func(c *gin.Context) {
+		cancelRegistrationTransfer(c, transferService)
+	})
+	authenticated.GET("/me/transfers", registrationsRead, inOrg, func(c *gin.Context) {
+		listIncomingTransfers(c, transferService)
+	})
+	authenticated.POST("/me/transfers/:id/accept", registrationsWrite, inOrg, func(c *gin.Context) {
+		acceptRegistrationTransfer(c, transferService)
+	})
+	authenticated.POST("/me/transfers/:id/decline", registrationsWrite, inOrg, func(c *gin.Context) {
+		declineRegistrationTransfer(c, transferService)
+	})
+	authenticated.GET("/me/groups", registrationsRead, inOrg, func(c *gin.Context) {
+		listGroups(c, eventRegisterService)
+	})
+	authenticated.GET("/me/groups/:id", registrationsRead, inOrg, func(c *gin.Context) {
+		getGroup(c, eventRegisterService)
+	})
+	authenticated.DELETE("/me/groups/:id", registrationsWrite, inOrg, func(c *gin.Context) {
+		cancelGroup(c, eventRegisterService)
+	})
+	authenticated.DELETE("/me/groups/:id/registrations/:registrationId", registrationsWrite, inOrg, func(c *gin.Context) {
+		cancelGroupRegistration(c, eventRegisterService)
+	})
+	authenticated.POST("/events/:id/checkout", registrationsWrite, inOrg, func(c *gin.Context) {
+		checkout(c, orderService)
+	})
+	authenticated.POST("/me/groups/:id/checkout", registrationsWrite, inOrg, func(c *gin.Context) {
+		checkoutGroup(c, orderService)
+	})
+	authenticated.GET("/orders/:id", registrationsRead, inOrg, func(c *gin.Context) {
+		getOrder(c, orderService)
+	})
+	authenticated.POST("/orders/:id/confirm", registrationsWrite, inOrg, func(c *gin.Context) {
+		confirmOrder(c, orderService)
+	})
+	authenticated.GET("/events/:id/registrations", eventsRead, inOrg, organizer, func(c *gin.Context) {
+		listEventRegistrations(c, eventRegisterService)
+	})
+	authenticated.GET("/events/:id/registrations/export", eventsRead, inOrg, organizer, func(c *gin.Context) {
+		exportEventAttendees(c, eventRegisterService)
+	})
+	authenticated.POST("/events/:id/registrations/approve", eventsWrite, inOrg, organizer, func(c *gin.Context) {
+		approveEventRegistrations(c, eventRegisterService)
+	})
+	authenticated.POST("/events/:id/registrations/reject", eventsWrite, inOrg, organizer, func(c *gin.Context) {
+		rejectEventRegistrations(c, eventRegisterService)
+	})
+	authenticated.POST("/events/:id/registrations/promote", eventsWrite, inOrg, organizer, func(c *gin.Context) {
+		promoteEventRegistrations(c, eventRegisterService)
+	})
 
-	authenticated.GET("/users", func(c *gin.Context) {
+	authenticated.GET("/organizations", usersRead, func(c *gin.Context) {
+		listOrganizations(c, organizationService)
+	})
+	authenticated.POST("/organizations", session, func(c *gin.Context) {
+		createOrganization(c, organizationService)
+	})
+	authenticated.GET("/organizations/:id", usersRead, func(c *gin.Context) {
+		getOrganization(c, organizationService)
+	})
+	authenticated.PUT("/organizations/:id", session, func(c *gin.Context) {
+		updateOrganization(c, organizationService)
+	})
+	authenticated.GET("/organizations/:id/attendance", eventsRead, func(c *gin.Context) {
+		getAttendanceReport(c, organizationService)
+	})
+	authenticated.PUT("/organizations/:id/attendance-settings", session, func(c *gin.Context) {
+		updateAttendanceSettings(c, organizationService)
+	})
+	authenticated.GET("/organizations/:id/members", usersRead, func(c *gin.Context) {
+		listOrganizationMembers(c, organizationService)
+	})
+	authenticated.POST("/organizations/:id/members", session, func(c *gin.Context) {
+		addOrganizationMember(c, organizationService)
+	})
+	authenticated.PUT("/organizations/:id/members/:userId", session, func(c *gin.Context) {
+		updateOrganizationMember(c, organizationService)
+	})
+	authenticated.DELETE("/organizations/:id/members/:userId", session, func(c *gin.Context) {
+		removeOrganizationMember(c, organizationService)
+	})
+
+	authenticated.GET("/users", usersRead, inOrg, func(c *gin.Context) {
 		getAllUsers(c, userService)
 	})
 
-	server.POST("/signup", func(c *gin.Context) {
+	authenticated.GET("/me", usersRead, func(c *gin.Context) {
+		getAccount(c, userService)
+	})
+	authenticated.PUT("/me", session, func(c *gin.Context) {
+		updateProfile(c, userService)
+	})
+	authenticated.PUT("/me/password", session, func(c *gin.Context) {
+		changePassword(c, userService)
+	})
+	authenticated.PUT("/me/email", session, func(c *gin.Context) {
+		changeEmail(c, userService)
+	})
+	authenticated.DELETE("/me", session, func(c *gin.Context) {
+		deleteAccount(c, userService)
+	})
+
+	authenticated.POST("/me/2fa/setup", session, func(c *gin.Context) {
+		setupTwoFactor(c, twoFactorService)
+	})
+	authenticated.POST("/me/2fa/enable", session, func(c *gin.Context) {
+		enableTwoFactor(c, twoFactorService)
+	})
+	authenticated.POST("/me/2fa/disable", session, func(c *gin.Context) {
+		disableTwoFactor(c, twoFactorService)
+	})
+
+	authenticated.GET("/me/api-keys", session, func(c *gin.Context) {
+		listAPIKeys(c, apiKeyService)
+	})
+	authenticated.POST("/me/api-keys", session, func(c *gin.Context) {
+		createAPIKey(c, apiKeyService)
+	})
+	authenticated.GET("/me/api-keys/:id", session, func(c *gin.Context) {
+		getAPIKey(c, apiKeyService)
+	})
+	authenticated.PUT("/me/api-keys/:id", session, func(c *gin.Context) {
+		updateAPIKey(c, apiKeyService)
+	})
+	authenticated.DELETE("/me/api-keys/:id", session, func(c *gin.Context) {
+		deleteAPIKey(c, apiKeyService)
+	})
+
+	public := server.Group("/")
+	public.Use(middleware.RateLimit(rateLimitService, "public", rateLimits.Public))
+	public.GET("/.well-known/jwks.json", getJWKS)
+	public.GET("/users/:id/profile", func(c *gin.Context) {
+		getProfile(c, userService)
+	})
+	public.POST("/signup", func(c *gin.Context) {
 		signup(c, userService)
 	})
-	server.POST("/login", func(c *gin.Context) {
-		login(c, userService)
+	public.POST("/login", func(c *gin.Context) {
+		login(c, userService, twoFactorService, loginThrottleService)
+	})
+	public.POST("/login/2fa", func(c *gin.Context) {
+		loginTwoFactor(c, twoFactorService, loginThrottleService)
+	})
+	public.GET("/auth/oidc/providers", func(c *gin.Context) {
+		listOIDCProviders(c, oidcService)
+	})
+	public.GET("/auth/oidc/:provider/login", func(c *gin.Context) {
+		startOIDCLogin(c, oidcService)
+	})
+	public.GET("/auth/oidc/:provider/callback", func(c *gin.Context) {
+		completeOIDCLogin(c, oidcService, twoFactorService)
+	})
+	public.GET("/invitations/accept", func(c *gin.Context) {
+		checkEventInvitation(c, invitationService)
+	})
+	public.GET("/verify-email", func(c *gin.Context) {
+		verifyEmail(c, userService)
+	})
+	public.POST("/verify-email/resend", func(c *gin.Context) {
+		resendVerification(c, userService)
+	})
+	public.POST("/password/forgot", func(c *gin.Context) {
+		forgotPassword(c, userService)
+	})
+	public.GET("/password/reset", func(c *gin.Context) {
+		checkResetToken(c, userService)
+	})
+	public.POST("/password/reset", func(c *gin.Context) {
+		resetPassword(c, userService)
+	})
+	// The payment provider is authenticated by the webhook signature and is
+	// not rate limited, so that bursts of payments are not dropped.
+	server.POST("/payments/webhook", func(c *gin.Context) {
+		paymentWebhook(c, orderService)
 	})
 }
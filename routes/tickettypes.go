@@ -0,0 +1,154 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"event-booking/models"
+	"event-booking/services"
+)
+
+func listTicketTypes(context *gin.Context, eventService *services.EventService) {
+	eventId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse event id",
+		})
+		return
+	}
+
+	event, err := eventService.GetEventById(context.GetInt64("orgId"), eventId, context.GetInt64("userId"))
+	if err != nil {
+		ticketTypeFailed(context, err, "Failed to retrieve ticket types")
+		return
+	}
+
+	ticketTypes, err := eventService.TicketTypes(event)
+	if err != nil {
+		ticketTypeFailed(context, err, "Failed to retrieve ticket types")
+		return
+	}
+
+	context.JSON(http.StatusOK, ticketTypes)
+}
+
+func createTicketType(context *gin.Context, eventService *services.EventService) {
+	eventId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse event id",
+		})
+		return
+	}
+
+	var ticketType models.TicketType
+	err = context.ShouldBindJSON(&ticketType)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Cannot parse request data",
+		})
+		return
+	}
+
+	err = eventService.CreateTicketType(context.GetInt64("orgId"), eventId, context.GetInt64("userId"), &ticketType)
+	if err != nil {
+		ticketTypeFailed(context, err, "Could not create ticket type")
+		return
+	}
+
+	context.JSON(http.StatusCreated, gin.H{
+		"message":    "Ticket type created successfully",
+		"ticketType": ticketType,
+	})
+}
+
+func updateTicketType(context *gin.Context, eventService *services.EventService) {
+	eventId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse event id",
+		})
+		return
+	}
+	ticketTypeId, err := strconv.ParseInt(context.Param("ticketTypeId"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse ticket type id",
+		})
+		return
+	}
+
+	var ticketType models.TicketType
+	err = context.ShouldBindJSON(&ticketType)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Cannot parse request data",
+		})
+		return
+	}
+
+	ticketType.Id = ticketTypeId
+	err = eventService.UpdateTicketType(context.GetInt64("orgId"), eventId, context.GetInt64("userId"), &ticketType)
+	if err != nil {
+		ticketTypeFailed(context, err, "Could not update ticket type")
+		return
+	}
+
+	context.JSON(http.StatusOK, ticketType)
+}
+
+func deleteTicketType(context *gin.Context, eventService *services.EventService) {
+	eventId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse event id",
+		})
+		return
+	}
+	ticketTypeId, err := strconv.ParseInt(context.Param("ticketTypeId"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse ticket type id",
+		})
+		return
+	}
+
+	err = eventService.DeleteTicketType(context.GetInt64("orgId"), eventId, context.GetInt64("userId"), ticketTypeId)
+	if err != nil {
+		ticketTypeFailed(context, err, "Could not delete ticket type")
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Ticket type has been deleted successfully",
+	})
+}
+
+// ticketTypeFailed answers with the status matching an error of the ticket
+// type methods.
+func ticketTypeFailed(context *gin.Context, err error, message string) {
+	if errors.Is(err, services.ErrEventNotFound) || errors.Is(err, services.ErrTicketTypeNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{
+			"message": err.Error(),
+		})
+	} else if errors.Is(err, services.ErrForbidden) {
+		context.JSON(http.StatusForbidden, gin.H{
+			"message": err.Error(),
+		})
+	} else if errors.Is(err, services.ErrInvalidTicketType) {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+	} else if errors.Is(err, services.ErrTicketTypeInUse) {
+		context.JSON(http.StatusConflict, gin.H{
+			"message": err.Error(),
+		})
+	} else {
+		context.JSON(http.StatusInternalServerError, gin.H{
+			"message": message,
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"event-booking/services"
+)
+
+func transferRegistration(context *gin.Context, transferService *services.RegistrationTransferService) {
+	registrationId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse registration id",
+		})
+		return
+	}
+
+	var request struct {
+		Email string `binding:"required,email"`
+	}
+	err = context.ShouldBindJSON(&request)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Cannot parse request data",
+		})
+		return
+	}
+
+	transfer, err := transferService.Transfer(context.GetInt64("orgId"), context.GetInt64("userId"), registrationId, request.Email)
+	if err != nil {
+		transferFailed(context, err, "Could not transfer registration")
+		return
+	}
+
+	context.JSON(http.StatusCreated, gin.H{
+		"message":  "The recipient has been asked to accept the registration",
+		"transfer": transfer,
+	})
+}
+
+func cancelRegistrationTransfer(context *gin.Context, transferService *services.RegistrationTransferService) {
+	registrationId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse registration id",
+		})
+		return
+	}
+
+	err = transferService.CancelTransfer(context.GetInt64("orgId"), context.GetInt64("userId"), registrationId)
+	if err != nil {
+		transferFailed(context, err, "Could not cancel transfer")
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Transfer has been cancelled",
+	})
+}
+
+func listIncomingTransfers(context *gin.Context, transferService *services.RegistrationTransferService) {
+	transfers, err := transferService.IncomingTransfers(context.GetInt64("orgId"), context.GetInt64("userId"))
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{
+			"message": "Failed to retrieve transfers",
+		})
+		return
+	}
+
+	context.JSON(http.StatusOK, transfers)
+}
+
+func acceptRegistrationTransfer(context *gin.Context, transferService *services.RegistrationTransferService) {
+	transferId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse transfer id",
+		})
+		return
+	}
+
+	registration, err := transferService.Accept(context.GetInt64("orgId"), context.GetInt64("userId"), transferId)
+	if err != nil {
+		transferFailed(context, err, "Could not accept transfer")
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message":      "The registration is now yours",
+		"registration": registration,
+	})
+}
+
+func declineRegistrationTransfer(context *gin.Context, transferService *services.RegistrationTransferService) {
+	transferId, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Could not parse transfer id",
+		})
+		return
+	}
+
+	err = transferService.Decline(context.GetInt64("orgId"), context.GetInt64("userId"), transferId)
+	if err != nil {
+		transferFailed(context, err, "Could not decline transfer")
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Transfer has been declined",
+	})
+}
+
+// transferFailed answers with the status matching an error of the transfer
+// service.
+func transferFailed(context *gin.Context, err error, message string) {
+	if errors.Is(err, services.ErrRegisterEventNotFound) || errors.Is(err, services.ErrEventNotFound) ||
+		errors.Is(err, services.ErrTransferNotFound) {
+		context.JSON(http.StatusNotFound, gin.H{
+			"message": err.Error(),
+		})
+	} else if errors.Is(err, services.ErrEmailNotVerified) {
+		context.JSON(http.StatusForbidden, gin.H{
+			"message": err.Error(),
+		})
+	} else if errors.Is(err, services.ErrTransferRecipientNotFound) || errors.Is(err, services.ErrTransferToSelf) {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+	} else if errors.Is(err, services.ErrRegistrationNotTransferable) || errors.Is(err, services.ErrTransferPending) ||
+		errors.Is(err, services.ErrTransferNotPending) || errors.Is(err, services.ErrRecipientAlreadyRegistered) ||
+		errors.Is(err, services.ErrAlreadyRegistered) {
+		context.JSON(http.StatusConflict, gin.H{
+			"message": err.Error(),
+		})
+	} else {
+		context.JSON(http.StatusInternalServerError, gin.H{
+			"message": message,
+		})
+	}
+}
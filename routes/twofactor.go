@@ -0,0 +1,168 @@
+package routes
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"event-booking/services"
+)
+
+func setupTwoFactor(context *gin.Context, twoFactorService *services.TwoFactorService) {
+	userId := context.GetInt64("userId")
+	secret, uri, err := twoFactorService.Setup(userId)
+	if err != nil {
+		if errors.Is(err, services.ErrTwoFactorAlreadyEnabled) {
+			context.JSON(http.StatusConflict, gin.H{
+				"message": err.Error(),
+			})
+		} else if errors.Is(err, services.ErrUserNotFound) {
+			context.JSON(http.StatusNotFound, gin.H{
+				"message": err.Error(),
+			})
+		} else {
+			context.JSON(http.StatusInternalServerError, gin.H{
+				"message": "Could not set up two-factor authentication",
+			})
+		}
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message":         "Scan the provisioning URI with your authenticator app, then confirm with a code",
+		"secret":          secret,
+		"provisioningUri": uri,
+	})
+}
+
+func enableTwoFactor(context *gin.Context, twoFactorService *services.TwoFactorService) {
+	var request struct {
+		Code string `binding:"required"`
+	}
+	err := context.ShouldBindJSON(&request)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Cannot parse request data",
+		})
+		return
+	}
+
+	userId := context.GetInt64("userId")
+	recoveryCodes, err := twoFactorService.Enable(userId, request.Code)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTwoFactorCode) || errors.Is(err, services.ErrTwoFactorNotSetUp) {
+			context.JSON(http.StatusBadRequest, gin.H{
+				"message": err.Error(),
+			})
+		} else if errors.Is(err, services.ErrTwoFactorAlreadyEnabled) {
+			context.JSON(http.StatusConflict, gin.H{
+				"message": err.Error(),
+			})
+		} else {
+			context.JSON(http.StatusInternalServerError, gin.H{
+				"message": "Could not enable two-factor authentication",
+			})
+		}
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message":       "Two-factor authentication has been enabled, store the recovery codes somewhere safe",
+		"recoveryCodes": recoveryCodes,
+	})
+}
+
+func disableTwoFactor(context *gin.Context, twoFactorService *services.TwoFactorService) {
+	var request struct {
+		CurrentPassword string `binding:"required"`
+		Code            string `binding:"required"`
+	}
+	err := context.ShouldBindJSON(&request)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Cannot parse request data",
+		})
+		return
+	}
+
+	userId := context.GetInt64("userId")
+	err = twoFactorService.Disable(userId, request.CurrentPassword, request.Code)
+	if err != nil {
+		if errors.Is(err, services.ErrIncorrectPassword) || errors.Is(err, services.ErrInvalidTwoFactorCode) {
+			context.JSON(http.StatusForbidden, gin.H{
+				"message": err.Error(),
+			})
+		} else if errors.Is(err, services.ErrTwoFactorNotSetUp) {
+			context.JSON(http.StatusBadRequest, gin.H{
+				"message": err.Error(),
+			})
+		} else if errors.Is(err, services.ErrUserNotFound) {
+			context.JSON(http.StatusNotFound, gin.H{
+				"message": err.Error(),
+			})
+		} else {
+			context.JSON(http.StatusInternalServerError, gin.H{
+				"message": "Could not disable two-factor authentication",
+			})
+		}
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Two-factor authentication has been disabled",
+	})
+}
+
+func loginTwoFactor(
+	context *gin.Context,
+	twoFactorService *services.TwoFactorService,
+	loginThrottleService *services.LoginThrottleService,
+) {
+	var request struct {
+		MfaToken string `binding:"required"`
+		Code     string `binding:"required"`
+	}
+	err := context.ShouldBindJSON(&request)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Cannot parse request data",
+		})
+		return
+	}
+
+	retryAfter, err := loginThrottleService.CheckTwoFactor(request.MfaToken, context.ClientIP())
+	if err != nil {
+		loginThrottled(context, retryAfter, err)
+		return
+	}
+
+	token, user, err := twoFactorService.CompleteLogin(request.MfaToken, request.Code)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidMFAChallenge) || errors.Is(err, services.ErrInvalidTwoFactorCode) ||
+			errors.Is(err, services.ErrTwoFactorNotSetUp) || errors.Is(err, services.ErrUserNotFound) {
+			err = loginThrottleService.RecordTwoFactorFailure(request.MfaToken, context.ClientIP())
+			if err != nil {
+				log.Printf("could not record failed two-factor login: %v", err)
+			}
+			context.JSON(http.StatusUnauthorized, gin.H{
+				"message": "Could not authenticate user",
+			})
+		} else {
+			context.JSON(http.StatusInternalServerError, gin.H{
+				"message": "Could not authenticate user",
+			})
+		}
+		return
+	}
+
+	err = loginThrottleService.RecordTwoFactorSuccess(user)
+	if err != nil {
+		log.Printf("could not reset failed logins: %v", err)
+	}
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Logged in successfully",
+		"token":   token,
+	})
+}
@@ -1,7 +1,12 @@
 package routes
 
 import (
+	"errors"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -21,18 +26,29 @@ func signup(context *gin.Context, userService *services.UserService) {
 
 	err = userService.CreateUser(&user)
 	if err != nil {
-		context.JSON(http.StatusInternalServerError, gin.H{
-			"message": "Failed to create user",
-		})
+		if errors.Is(err, services.ErrVerificationEmailNotSent) {
+			context.JSON(http.StatusCreated, gin.H{
+				"message": "User created successfully, but the verification email could not be sent",
+			})
+		} else {
+			context.JSON(http.StatusInternalServerError, gin.H{
+				"message": "Failed to create user",
+			})
+		}
 		return
 	}
 
 	context.JSON(http.StatusCreated, gin.H{
-		"message": "User created successfully",
+		"message": "User created successfully, please check your email to verify your account",
 	})
 }
 
-func login(context *gin.Context, userService *services.UserService) {
+func login(
+	context *gin.Context,
+	userService *services.UserService,
+	twoFactorService *services.TwoFactorService,
+	loginThrottleService *services.LoginThrottleService,
+) {
 	var user models.User
 	err := context.ShouldBindJSON(&user)
 	if err != nil {
@@ -41,14 +57,36 @@ func login(context *gin.Context, userService *services.UserService) {
 		})
 		return
 	}
+
+	email := user.Email
+	retryAfter, err := loginThrottleService.CheckLogin(email, context.ClientIP())
+	if err != nil {
+		loginThrottled(context, retryAfter, err)
+		return
+	}
+
 	token, err := userService.Login(&user)
 	if err != nil {
-		context.JSON(http.StatusUnauthorized, gin.H{
-			"message": "Could not authenticate user",
-		})
+		if errors.Is(err, services.ErrTwoFactorRequired) {
+			// Failures are only cleared once the second factor passed too.
+			loginChallenge(context, user.Id, twoFactorService)
+		} else if errors.Is(err, services.ErrEmailNotVerified) {
+			context.JSON(http.StatusForbidden, gin.H{
+				"message": err.Error(),
+			})
+		} else {
+			err = loginThrottleService.RecordLoginFailure(email, context.ClientIP())
+			if err != nil {
+				log.Printf("could not record failed login: %v", err)
+			}
+			context.JSON(http.StatusUnauthorized, gin.H{
+				"message": "Could not authenticate user",
+			})
+		}
 		return
 	}
 
+	recordLoginSuccess(loginThrottleService, email)
 	context.JSON(http.StatusOK, gin.H{
 		"message": "Logged in successfully",
 		"token":   token,
@@ -56,8 +94,52 @@ func login(context *gin.Context, userService *services.UserService) {
 
 }
 
+func recordLoginSuccess(loginThrottleService *services.LoginThrottleService, email string) {
+	err := loginThrottleService.RecordLoginSuccess(email)
+	if err != nil {
+		log.Printf("could not reset failed logins: %v", err)
+	}
+}
+
+// loginThrottled answers a login attempt made while the account or the client
+// is locked out. Retry-After is rounded up to whole seconds.
+func loginThrottled(context *gin.Context, retryAfter time.Duration, err error) {
+	if !errors.Is(err, services.ErrTooManyLoginAttempts) {
+		context.JSON(http.StatusInternalServerError, gin.H{
+			"message": "Could not authenticate user",
+		})
+		return
+	}
+
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	context.Header("Retry-After", strconv.Itoa(seconds))
+	context.JSON(http.StatusTooManyRequests, gin.H{
+		"message":    err.Error(),
+		"retryAfter": seconds,
+	})
+}
+
+// loginChallenge answers the password step of a two-step login with a token
+// that has to be exchanged at /login/2fa together with a code.
+func loginChallenge(context *gin.Context, userId int64, twoFactorService *services.TwoFactorService) {
+	mfaToken, err := twoFactorService.IssueChallenge(userId)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{
+			"message": "Could not authenticate user",
+		})
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message":     "Two-factor authentication code required",
+		"mfaRequired": true,
+		"mfaToken":    mfaToken,
+	})
+}
+
+// getAllUsers lists the members of the organization of the request.
 func getAllUsers(context *gin.Context, userService *services.UserService) {
-	users, err := userService.GetUsers()
+	users, err := userService.GetUsers(context.GetInt64("orgId"))
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{
 			"message": "Failed to retrieve users",
@@ -66,3 +148,147 @@ func getAllUsers(context *gin.Context, userService *services.UserService) {
 	}
 	context.JSON(http.StatusOK, users)
 }
+
+func verifyEmail(context *gin.Context, userService *services.UserService) {
+	token := context.Query("token")
+	if token == "" {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Missing verification token",
+		})
+		return
+	}
+
+	err := userService.VerifyEmail(token)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidVerificationToken) {
+			context.JSON(http.StatusBadRequest, gin.H{
+				"message": err.Error(),
+			})
+		} else if errors.Is(err, services.ErrEmailTaken) {
+			context.JSON(http.StatusConflict, gin.H{
+				"message": err.Error(),
+			})
+		} else {
+			context.JSON(http.StatusInternalServerError, gin.H{
+				"message": "Could not verify email address",
+			})
+		}
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Email address has been verified successfully",
+	})
+}
+
+func resendVerification(context *gin.Context, userService *services.UserService) {
+	var request struct {
+		Email string `binding:"required,email"`
+	}
+	err := context.ShouldBindJSON(&request)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Cannot parse request data",
+		})
+		return
+	}
+
+	err = userService.ResendVerification(request.Email)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{
+			"message": "Could not send verification email",
+		})
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "If the account exists and is not verified, a new verification email has been sent",
+	})
+}
+
+func forgotPassword(context *gin.Context, userService *services.UserService) {
+	var request struct {
+		Email string `binding:"required,email"`
+	}
+	err := context.ShouldBindJSON(&request)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Cannot parse request data",
+		})
+		return
+	}
+
+	err = userService.RequestPasswordReset(request.Email)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{
+			"message": "Could not send password reset email",
+		})
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "If the account exists, a password reset email has been sent",
+	})
+}
+
+// checkResetToken answers the reset link sent by email, which opens the
+// link with GET, and tells whether its token can still be used.
+func checkResetToken(context *gin.Context, userService *services.UserService) {
+	token := context.Query("token")
+	if token == "" {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Missing reset token",
+		})
+		return
+	}
+
+	err := userService.CheckResetToken(token)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidResetToken) {
+			context.JSON(http.StatusBadRequest, gin.H{
+				"message": err.Error(),
+			})
+		} else {
+			context.JSON(http.StatusInternalServerError, gin.H{
+				"message": "Could not check reset token",
+			})
+		}
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Reset token is valid, send it with the new password to POST /password/reset",
+	})
+}
+
+func resetPassword(context *gin.Context, userService *services.UserService) {
+	var request struct {
+		Token    string `binding:"required"`
+		Password string `binding:"required,min=8"`
+	}
+	err := context.ShouldBindJSON(&request)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{
+			"message": "Cannot parse request data",
+		})
+		return
+	}
+
+	err = userService.ResetPassword(request.Token, request.Password)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidResetToken) {
+			context.JSON(http.StatusBadRequest, gin.H{
+				"message": err.Error(),
+			})
+		} else {
+			context.JSON(http.StatusInternalServerError, gin.H{
+				"message": "Could not reset password",
+			})
+		}
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Password has been reset successfully",
+	})
+}
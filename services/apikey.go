@@ -0,0 +1,235 @@
+package services
+
+import (
+	"crypto/subtle"
+	"errors"
+	"event-booking/models"
+	"event-booking/utils"
+	"slices"
+	"strings"
+	"time"
+)
+
+type APIKeyRepository interface {
+	CreateAPIKey(*models.APIKey) error
+	GetAPIKeys(int64) ([]models.APIKey, error)
+	GetAPIKeyById(int64, int64) (models.APIKey, error)
+	GetAPIKeyByPrefix(string) (models.APIKey, error)
+	UpdateAPIKey(*models.APIKey) error
+	DeleteAPIKey(int64, int64) (bool, error)
+	TouchAPIKey(int64, time.Time) error
+}
+
+// Scopes limit what an API key may do. Session tokens are not limited.
+const (
+	ScopeEventsRead         = "events:read"
+	ScopeEventsWrite        = "events:write"
+	ScopeRegistrationsRead  = "registrations:read"
+	ScopeRegistrationsWrite = "registrations:write"
+	ScopeUsersRead          = "users:read"
+)
+
+var APIKeyScopes = []string{ScopeEventsRead, ScopeEventsWrite, ScopeRegistrationsRead, ScopeRegistrationsWrite, ScopeUsersRead}
+
+// impliedScopes lists the scopes a scope grants as well.
+var impliedScopes = map[string][]string{
+	ScopeEventsWrite:        {ScopeEventsRead},
+	ScopeRegistrationsWrite: {ScopeRegistrationsRead},
+}
+
+const (
+	// APIKeyPrefix starts every key so keys are easy to spot in logs and in
+	// secret scanners, and to tell them apart from JWTs.
+	APIKeyPrefix = "eb_"
+
+	apiKeyLookupLength = 12
+	maxAPIKeysPerUser  = 20
+
+	// lastUsedResolution limits how often last-used timestamps are written.
+	lastUsedResolution = time.Minute
+)
+
+var ErrInvalidAPIKey = errors.New("API key is invalid or has expired")
+var ErrAPIKeyNotFound = errors.New("API key not found")
+var ErrInvalidAPIKeyScope = errors.New("API keys need at least one of the scopes " + strings.Join(APIKeyScopes, ", "))
+var ErrAPIKeyExpiryInPast = errors.New("API key expiry must be in the future")
+var ErrTooManyAPIKeys = errors.New("API key limit reached, revoke an unused key first")
+
+type APIKeyService struct {
+	repo APIKeyRepository
+	now  func() time.Time
+}
+
+func NewAPIKeyService(repo APIKeyRepository) *APIKeyService {
+	return &APIKeyService{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+// Create issues a new key and returns it together with the full key. The
+// full key is not stored and cannot be retrieved again.
+func (s *APIKeyService) Create(userId int64, name string, scopes []string, expiresAt *time.Time) (models.APIKey, string, error) {
+	scopes, err := normalizeScopes(scopes)
+	if err != nil {
+		return models.APIKey{}, "", err
+	}
+
+	now := s.now()
+	if expiresAt != nil && !expiresAt.After(now) {
+		return models.APIKey{}, "", ErrAPIKeyExpiryInPast
+	}
+
+	keys, err := s.repo.GetAPIKeys(userId)
+	if err != nil {
+		return models.APIKey{}, "", err
+	}
+	if len(keys) >= maxAPIKeysPerUser {
+		return models.APIKey{}, "", ErrTooManyAPIKeys
+	}
+
+	lookup, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		return models.APIKey{}, "", err
+	}
+	secret, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return models.APIKey{}, "", err
+	}
+
+	key := models.APIKey{
+		UserId:     userId,
+		Name:       name,
+		Prefix:     utils.HashToken(lookup)[:apiKeyLookupLength],
+		SecretHash: utils.HashToken(secret),
+		Scopes:     scopes,
+		ExpiresAt:  expiresAt,
+		CreatedAt:  now,
+	}
+	err = s.repo.CreateAPIKey(&key)
+	if err != nil {
+		return models.APIKey{}, "", err
+	}
+
+	return key, APIKeyPrefix + key.Prefix + "_" + secret, nil
+}
+
+func (s *APIKeyService) List(userId int64) ([]models.APIKey, error) {
+	return s.repo.GetAPIKeys(userId)
+}
+
+func (s *APIKeyService) Get(userId, id int64) (models.APIKey, error) {
+	key, err := s.repo.GetAPIKeyById(userId, id)
+	if err != nil {
+		return models.APIKey{}, ErrAPIKeyNotFound
+	}
+	return key, nil
+}
+
+// Update renames a key and replaces its scopes.
+func (s *APIKeyService) Update(userId, id int64, name string, scopes []string) (models.APIKey, error) {
+	scopes, err := normalizeScopes(scopes)
+	if err != nil {
+		return models.APIKey{}, err
+	}
+
+	key, err := s.Get(userId, id)
+	if err != nil {
+		return models.APIKey{}, err
+	}
+
+	key.Name = name
+	key.Scopes = scopes
+	err = s.repo.UpdateAPIKey(&key)
+	if err != nil {
+		return models.APIKey{}, err
+	}
+	return key, nil
+}
+
+func (s *APIKeyService) Revoke(userId, id int64) error {
+	deleted, err := s.repo.DeleteAPIKey(userId, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// Authenticate resolves a full API key to the stored key. It records when the
+// key was last used.
+func (s *APIKeyService) Authenticate(token string) (models.APIKey, error) {
+	prefix, secret, ok := splitAPIKey(token)
+	if !ok {
+		return models.APIKey{}, ErrInvalidAPIKey
+	}
+
+	key, err := s.repo.GetAPIKeyByPrefix(prefix)
+	if err != nil {
+		return models.APIKey{}, ErrInvalidAPIKey
+	}
+
+	if subtle.ConstantTimeCompare([]byte(key.SecretHash), []byte(utils.HashToken(secret))) != 1 {
+		return models.APIKey{}, ErrInvalidAPIKey
+	}
+
+	now := s.now()
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(now) {
+		return models.APIKey{}, ErrInvalidAPIKey
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedResolution {
+		err = s.repo.TouchAPIKey(key.Id, now)
+		if err != nil {
+			return models.APIKey{}, err
+		}
+		key.LastUsedAt = &now
+	}
+
+	return key, nil
+}
+
+// IsAPIKey reports whether token looks like an API key rather than a JWT.
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
+
+func splitAPIKey(token string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(token, APIKeyPrefix)
+	if !ok || len(rest) <= apiKeyLookupLength+1 || rest[apiKeyLookupLength] != '_' {
+		return "", "", false
+	}
+	return rest[:apiKeyLookupLength], rest[apiKeyLookupLength+1:], true
+}
+
+// normalizeScopes rejects unknown scopes and removes duplicates.
+func normalizeScopes(scopes []string) ([]string, error) {
+	normalized := []string{}
+	for _, scope := range scopes {
+		if !slices.Contains(APIKeyScopes, scope) {
+			return nil, ErrInvalidAPIKeyScope
+		}
+		if !slices.Contains(normalized, scope) {
+			normalized = append(normalized, scope)
+		}
+	}
+
+	if len(normalized) == 0 {
+		return nil, ErrInvalidAPIKeyScope
+	}
+	slices.Sort(normalized)
+	return normalized, nil
+}
+
+// HasScope reports whether scopes grant scope, directly or through a scope
+// that implies it.
+func HasScope(scopes []string, scope string) bool {
+	for _, granted := range scopes {
+		if granted == scope || slices.Contains(impliedScopes[granted], scope) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"event-booking/models"
+	"event-booking/services/mocks"
+	"event-booking/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAPIKeyCreate_ReturnsKeyOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	service := NewAPIKeyService(mockRepo)
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	var stored models.APIKey
+	mockRepo.EXPECT().GetAPIKeys(int64(1)).Return([]models.APIKey{}, nil)
+	mockRepo.EXPECT().CreateAPIKey(gomock.Any()).DoAndReturn(func(k *models.APIKey) error {
+		k.Id = 7
+		stored = *k
+		return nil
+	})
+
+	key, token, err := service.Create(1, "ci", []string{ScopeEventsWrite, ScopeEventsRead, ScopeEventsRead}, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), key.Id)
+	assert.Equal(t, []string{ScopeEventsRead, ScopeEventsWrite}, key.Scopes)
+	assert.Equal(t, now, key.CreatedAt)
+	assert.True(t, strings.HasPrefix(token, APIKeyPrefix+stored.Prefix+"_"))
+	assert.NotContains(t, token, stored.SecretHash)
+
+	_, secret, ok := splitAPIKey(token)
+	require.True(t, ok)
+	assert.Equal(t, utils.HashToken(secret), stored.SecretHash)
+}
+
+func TestAPIKeyCreate_InvalidScopes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewAPIKeyService(mocks.NewMockAPIKeyRepository(ctrl))
+
+	_, _, err := service.Create(1, "ci", []string{"admin"}, nil)
+	assert.Equal(t, ErrInvalidAPIKeyScope, err)
+
+	_, _, err = service.Create(1, "ci", []string{}, nil)
+	assert.Equal(t, ErrInvalidAPIKeyScope, err)
+}
+
+func TestHasScope_WriteImpliesRead(t *testing.T) {
+	assert.True(t, HasScope([]string{ScopeRegistrationsRead}, ScopeRegistrationsRead))
+	assert.True(t, HasScope([]string{ScopeEventsRead, ScopeRegistrationsWrite}, ScopeRegistrationsRead))
+	assert.False(t, HasScope([]string{ScopeRegistrationsRead}, ScopeRegistrationsWrite))
+	assert.True(t, HasScope([]string{ScopeEventsWrite}, ScopeEventsRead))
+	assert.False(t, HasScope([]string{ScopeEventsRead}, ScopeEventsWrite))
+}
+
+func TestAPIKeyCreate_ExpiryInPast(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewAPIKeyService(mocks.NewMockAPIKeyRepository(ctrl))
+	expiresAt := time.Now().Add(-time.Minute)
+
+	_, _, err := service.Create(1, "ci", []string{ScopeEventsRead}, &expiresAt)
+
+	assert.Equal(t, ErrAPIKeyExpiryInPast, err)
+}
+
+func TestAPIKeyCreate_TooManyKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	service := NewAPIKeyService(mockRepo)
+
+	mockRepo.EXPECT().GetAPIKeys(int64(1)).Return(make([]models.APIKey, maxAPIKeysPerUser), nil)
+
+	_, _, err := service.Create(1, "ci", []string{ScopeEventsRead}, nil)
+
+	assert.Equal(t, ErrTooManyAPIKeys, err)
+}
+
+func TestAPIKeyAuthenticate_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	service := NewAPIKeyService(mockRepo)
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	stored := models.APIKey{Id: 7, UserId: 1, Prefix: "0123456789ab", SecretHash: utils.HashToken("secret")}
+	mockRepo.EXPECT().GetAPIKeyByPrefix("0123456789ab").Return(stored, nil)
+	mockRepo.EXPECT().TouchAPIKey(int64(7), now).Return(nil)
+
+	key, err := service.Authenticate("eb_0123456789ab_secret")
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), key.UserId)
+	assert.Equal(t, now, *key.LastUsedAt)
+}
+
+func TestAPIKeyAuthenticate_SkipsRecentLastUsedUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	service := NewAPIKeyService(mockRepo)
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	lastUsed := now.Add(-10 * time.Second)
+	stored := models.APIKey{Id: 7, UserId: 1, Prefix: "0123456789ab", SecretHash: utils.HashToken("secret"), LastUsedAt: &lastUsed}
+	mockRepo.EXPECT().GetAPIKeyByPrefix("0123456789ab").Return(stored, nil)
+
+	_, err := service.Authenticate("eb_0123456789ab_secret")
+
+	require.NoError(t, err)
+}
+
+func TestAPIKeyAuthenticate_Rejects(t *testing.T) {
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	expired := now.Add(-time.Second)
+
+	tests := []struct {
+		name   string
+		token  string
+		stored *models.APIKey
+	}{
+		{"malformed", "eb_short", nil},
+		{"missing separator", "eb_0123456789abXsecret", nil},
+		{"wrong secret", "eb_0123456789ab_guess", &models.APIKey{Prefix: "0123456789ab", SecretHash: utils.HashToken("secret")}},
+		{"expired", "eb_0123456789ab_secret", &models.APIKey{Prefix: "0123456789ab", SecretHash: utils.HashToken("secret"), ExpiresAt: &expired}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockAPIKeyRepository(ctrl)
+			service := NewAPIKeyService(mockRepo)
+			service.now = func() time.Time { return now }
+
+			if tt.stored != nil {
+				mockRepo.EXPECT().GetAPIKeyByPrefix(tt.stored.Prefix).Return(*tt.stored, nil)
+			}
+
+			_, err := service.Authenticate(tt.token)
+
+			assert.Equal(t, ErrInvalidAPIKey, err)
+		})
+	}
+}
+
+func TestAPIKeyAuthenticate_UnknownPrefix(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	service := NewAPIKeyService(mockRepo)
+
+	mockRepo.EXPECT().GetAPIKeyByPrefix("0123456789ab").Return(models.APIKey{}, errors.New("no rows"))
+
+	_, err := service.Authenticate("eb_0123456789ab_secret")
+
+	assert.Equal(t, ErrInvalidAPIKey, err)
+}
+
+func TestAPIKeyUpdate_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	service := NewAPIKeyService(mockRepo)
+
+	mockRepo.EXPECT().GetAPIKeyById(int64(1), int64(9)).Return(models.APIKey{}, errors.New("no rows"))
+
+	_, err := service.Update(1, 9, "renamed", []string{ScopeUsersRead})
+
+	assert.Equal(t, ErrAPIKeyNotFound, err)
+}
+
+func TestAPIKeyRevoke_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	service := NewAPIKeyService(mockRepo)
+
+	mockRepo.EXPECT().DeleteAPIKey(int64(1), int64(9)).Return(false, nil)
+
+	err := service.Revoke(1, 9)
+
+	assert.Equal(t, ErrAPIKeyNotFound, err)
+}
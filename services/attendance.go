@@ -0,0 +1,124 @@
+package services
+
+import (
+	"cmp"
+	"event-booking/models"
+	"slices"
+	"time"
+)
+
+// DefaultNoShowThreshold is the number of no-shows after which new
+// organizations deprioritize a user's pending registrations, once enabled.
+const DefaultNoShowThreshold = 2
+
+// Attendance returns how many of the approved registrations of an event
+// were checked in. The owner, co-organizers and check-in staff can see it.
+func (s *EventService) Attendance(orgId, eventId, userId int64) (models.EventAttendance, error) {
+	_, err := s.authorizeEvent(orgId, eventId, userId, EventRoleOwner, EventRoleCoOrganizer, EventRoleCheckInStaff)
+	if err != nil {
+		return models.EventAttendance{}, err
+	}
+
+	attendance, err := s.repo.GetAttendance(orgId, eventId)
+	if err != nil {
+		return models.EventAttendance{}, err
+	}
+	completeAttendance(&attendance, s.now())
+	return attendance, nil
+}
+
+// AttendanceReport returns the attendance of the organization's events
+// whose check-in has closed and the no-show rate of their attendees.
+// Owners and admins can see it.
+func (s *OrganizationService) AttendanceReport(userId, orgId int64) (models.AttendanceReport, error) {
+	_, err := s.Authorize(orgId, userId, RoleOwner, RoleAdmin)
+	if err != nil {
+		return models.AttendanceReport{}, err
+	}
+
+	now := s.now()
+	closedBefore := now.Add(-ticketValidity)
+	events, err := s.repo.GetPastAttendance(orgId, closedBefore)
+	if err != nil {
+		return models.AttendanceReport{}, err
+	}
+	for i := range events {
+		completeAttendance(&events[i], now)
+	}
+	attendees, err := s.repo.GetAttendeeAttendance(orgId, closedBefore)
+	if err != nil {
+		return models.AttendanceReport{}, err
+	}
+	return models.AttendanceReport{Events: events, Attendees: attendees}, nil
+}
+
+// UpdateAttendanceSettings configures whether pending registrations of
+// repeat no-shows are deprioritized. Owners and admins can change it.
+func (s *OrganizationService) UpdateAttendanceSettings(userId, orgId int64, deprioritize bool, threshold int64) (models.Membership, error) {
+	membership, err := s.Authorize(orgId, userId, RoleOwner, RoleAdmin)
+	if err != nil {
+		return models.Membership{}, err
+	}
+
+	membership.DeprioritizeNoShows = deprioritize
+	membership.NoShowThreshold = threshold
+	err = s.repo.UpdateOrganization(&membership.Organization)
+	if err != nil {
+		return models.Membership{}, err
+	}
+	return membership, nil
+}
+
+// completeAttendance derives the rate and, once tickets of the event can no
+// longer be checked in, the no-shows.
+func completeAttendance(a *models.EventAttendance, now time.Time) {
+	if a.Registered > 0 {
+		a.AttendanceRate = float64(a.CheckedIn) / float64(a.Registered)
+	}
+	a.Closed = !now.Before(a.DateTime.Add(ticketValidity))
+	if a.Closed {
+		a.NoShows = a.Registered - a.CheckedIn
+	}
+}
+
+// rankByNoShows sets the past no-shows of each registration's user in the
+// organization. When the organization deprioritizes no-shows, pending
+// registrations of users with at least its threshold of no-shows are
+// listed after all other registrations, so organizers working through the
+// waitlist from the top reach them last, and PromoteRegistrations promotes
+// them last.
+func (s *EventRegisterService) rankByNoShows(orgId int64, registrations []models.RegisterEvent) error {
+	attendees, err := s.repo.GetAttendeeAttendance(orgId, s.now().Add(-ticketValidity))
+	if err != nil {
+		return err
+	}
+	noShows := map[int64]int64{}
+	for _, a := range attendees {
+		noShows[a.UserId] = a.NoShows
+	}
+	for i := range registrations {
+		registrations[i].NoShows = noShows[registrations[i].UserId]
+	}
+
+	org, err := s.repo.GetOrganization(orgId)
+	if err != nil {
+		return err
+	}
+	if !org.DeprioritizeNoShows {
+		return nil
+	}
+	for i, r := range registrations {
+		registrations[i].Deprioritized = r.Status == RegistrationPending && r.NoShows >= org.NoShowThreshold
+	}
+	slices.SortStableFunc(registrations, func(a, b models.RegisterEvent) int {
+		return cmp.Compare(btoi(a.Deprioritized), btoi(b.Deprioritized))
+	})
+	return nil
+}
+
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,240 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"event-booking/models"
+	"event-booking/services/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAttendance_ClosedEventCountsNoShows(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepository(ctrl)
+	service := NewEventService(mockRepo)
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().GetAttendance(testOrgId, int64(1)).Return(models.EventAttendance{
+		EventId: 1, DateTime: now.Add(-48 * time.Hour), Registered: 4, CheckedIn: 3,
+	}, nil)
+
+	attendance, err := service.Attendance(testOrgId, 1, 5)
+
+	require.NoError(t, err)
+	assert.True(t, attendance.Closed)
+	assert.Equal(t, int64(1), attendance.NoShows)
+	assert.Equal(t, 0.75, attendance.AttendanceRate)
+}
+
+func TestAttendance_OpenEventHasNoNoShows(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepository(ctrl)
+	service := NewEventService(mockRepo)
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().GetEventMember(testOrgId, int64(1), int64(20)).Return(models.EventMember{Role: EventRoleCheckInStaff}, nil)
+	mockRepo.EXPECT().GetAttendance(testOrgId, int64(1)).Return(models.EventAttendance{
+		EventId: 1, DateTime: now.Add(-time.Hour), Registered: 4, CheckedIn: 1,
+	}, nil)
+
+	attendance, err := service.Attendance(testOrgId, 1, 20)
+
+	require.NoError(t, err)
+	assert.False(t, attendance.Closed)
+	assert.Equal(t, int64(0), attendance.NoShows)
+	assert.Equal(t, 0.25, attendance.AttendanceRate)
+}
+
+func TestAttendance_NonMemberForbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepository(ctrl)
+	service := NewEventService(mockRepo)
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().GetEventMember(testOrgId, int64(1), int64(30)).Return(models.EventMember{}, errors.New("no rows"))
+
+	_, err := service.Attendance(testOrgId, 1, 30)
+
+	assert.Equal(t, ErrForbidden, err)
+}
+
+func TestAttendanceReport_OnlyClosedEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrganizationRepository(ctrl)
+	service := NewOrganizationService(mockRepo)
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	closedBefore := now.Add(-ticketValidity)
+
+	mockRepo.EXPECT().GetMembership(int64(4), int64(10)).Return(createTestMembership(4, RoleAdmin), nil)
+	mockRepo.EXPECT().GetPastAttendance(int64(4), closedBefore).Return([]models.EventAttendance{
+		{EventId: 1, DateTime: now.Add(-72 * time.Hour), Registered: 2, CheckedIn: 1},
+	}, nil)
+	attendees := []models.AttendeeAttendance{{UserId: 10, Events: 2, NoShows: 1, NoShowRate: 0.5}}
+	mockRepo.EXPECT().GetAttendeeAttendance(int64(4), closedBefore).Return(attendees, nil)
+
+	report, err := service.AttendanceReport(10, 4)
+
+	require.NoError(t, err)
+	require.Len(t, report.Events, 1)
+	assert.Equal(t, int64(1), report.Events[0].NoShows)
+	assert.Equal(t, attendees, report.Attendees)
+}
+
+func TestAttendanceReport_MemberForbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrganizationRepository(ctrl)
+	service := NewOrganizationService(mockRepo)
+
+	mockRepo.EXPECT().GetMembership(int64(4), int64(10)).Return(createTestMembership(4, RoleMember), nil)
+
+	_, err := service.AttendanceReport(10, 4)
+
+	assert.Equal(t, ErrForbidden, err)
+}
+
+func TestUpdateAttendanceSettings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrganizationRepository(ctrl)
+	service := NewOrganizationService(mockRepo)
+
+	mockRepo.EXPECT().GetMembership(int64(4), int64(10)).Return(createTestMembership(4, RoleOwner), nil)
+	mockRepo.EXPECT().UpdateOrganization(gomock.Any()).DoAndReturn(func(org *models.Organization) error {
+		assert.Equal(t, "Acme", org.Name)
+		assert.True(t, org.DeprioritizeNoShows)
+		assert.Equal(t, int64(3), org.NoShowThreshold)
+		return nil
+	})
+
+	membership, err := service.UpdateAttendanceSettings(10, 4, true, 3)
+
+	require.NoError(t, err)
+	assert.True(t, membership.DeprioritizeNoShows)
+}
+
+func createTestWaitlist() []models.RegisterEvent {
+	var registrations []models.RegisterEvent
+	for i, userId := range []int64{10, 11, 12} {
+		r := createTestRegisteredEvent(int64(100+i), userId, 1)
+		r.Status = RegistrationPending
+		registrations = append(registrations, r)
+	}
+	return registrations
+}
+
+func TestRegistrations_DeprioritizesRepeatNoShows(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().GetRegistrations(testOrgId, int64(1), RegistrationPending).Return(createTestWaitlist(), nil)
+	mockRepo.EXPECT().GetAttendeeAttendance(testOrgId, gomock.Any()).Return([]models.AttendeeAttendance{
+		{UserId: 10, Events: 3, NoShows: 2}, {UserId: 11, Events: 3, NoShows: 1},
+	}, nil)
+	mockRepo.EXPECT().GetOrganization(testOrgId).Return(models.Organization{
+		Id: testOrgId, DeprioritizeNoShows: true, NoShowThreshold: 2,
+	}, nil)
+
+	registrations, err := service.Registrations(testOrgId, 1, 5, RegistrationPending)
+
+	require.NoError(t, err)
+	require.Len(t, registrations, 3)
+	assert.Equal(t, []int64{11, 12, 10}, []int64{registrations[0].UserId, registrations[1].UserId, registrations[2].UserId})
+	assert.True(t, registrations[2].Deprioritized)
+	assert.Equal(t, int64(2), registrations[2].NoShows)
+	assert.Equal(t, int64(1), registrations[0].NoShows)
+}
+
+func TestRegistrations_KeepsOrderWhenNotDeprioritizing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().GetRegistrations(testOrgId, int64(1), "").Return(createTestWaitlist(), nil)
+	mockRepo.EXPECT().GetAttendeeAttendance(testOrgId, gomock.Any()).Return([]models.AttendeeAttendance{
+		{UserId: 10, Events: 3, NoShows: 3},
+	}, nil)
+	mockRepo.EXPECT().GetOrganization(testOrgId).Return(models.Organization{Id: testOrgId, NoShowThreshold: 2}, nil)
+
+	registrations, err := service.Registrations(testOrgId, 1, 5, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), registrations[0].UserId)
+	assert.Equal(t, int64(3), registrations[0].NoShows)
+	assert.False(t, registrations[0].Deprioritized)
+}
+
+func TestPromoteRegistrations_RepeatNoShowsLast(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().GetRegistrations(testOrgId, int64(1), RegistrationPending).Return(createTestWaitlist(), nil)
+	mockRepo.EXPECT().GetAttendeeAttendance(testOrgId, gomock.Any()).Return([]models.AttendeeAttendance{
+		{UserId: 10, Events: 3, NoShows: 3},
+	}, nil)
+	mockRepo.EXPECT().GetOrganization(testOrgId).Return(models.Organization{
+		Id: testOrgId, DeprioritizeNoShows: true, NoShowThreshold: 2,
+	}, nil)
+	gomock.InOrder(
+		mockRepo.EXPECT().SetRegistrationStatus(testOrgId, int64(1), []int64{101}, RegistrationApproved).Return(true, nil),
+		mockRepo.EXPECT().SetRegistrationStatus(testOrgId, int64(1), []int64{102}, RegistrationApproved).Return(true, nil),
+	)
+
+	promoted, err := service.PromoteRegistrations(testOrgId, 1, 5, 2)
+
+	require.NoError(t, err)
+	require.Len(t, promoted, 2)
+	assert.Equal(t, []int64{11, 12}, []int64{promoted[0].UserId, promoted[1].UserId},
+		"The repeat no-show is promoted after reliable attendees, although registered first")
+	assert.Equal(t, RegistrationApproved, promoted[0].Status)
+}
+
+func TestPromoteRegistrations_SkipsWhatDoesNotFit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().GetRegistrations(testOrgId, int64(1), RegistrationPending).Return(createTestWaitlist(), nil)
+	mockRepo.EXPECT().GetAttendeeAttendance(testOrgId, gomock.Any()).Return([]models.AttendeeAttendance{}, nil)
+	mockRepo.EXPECT().GetOrganization(testOrgId).Return(models.Organization{Id: testOrgId, NoShowThreshold: 2}, nil)
+	mockRepo.EXPECT().SetRegistrationStatus(testOrgId, int64(1), gomock.Any(), RegistrationApproved).Return(false, nil).Times(3)
+
+	promoted, err := service.PromoteRegistrations(testOrgId, 1, 5, 1)
+
+	assert.Equal(t, ErrEventFull, err)
+	assert.Empty(t, promoted)
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"event-booking/models"
+	"time"
+)
+
+type AuditRepository interface {
+	CreateAuditEvent(*models.AuditEvent) error
+}
+
+// AuditService records security relevant events in the audit trail.
+type AuditService struct {
+	repo AuditRepository
+	now  func() time.Time
+}
+
+const (
+	AuditLoginLockout      = "login.lockout"
+	AuditTransferRequested = "registration.transfer_requested"
+	AuditTransferAccepted  = "registration.transfer_accepted"
+	AuditTransferDeclined  = "registration.transfer_declined"
+	AuditTransferCancelled = "registration.transfer_cancelled"
+)
+
+func NewAuditService(repo AuditRepository) *AuditService {
+	return &AuditService{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+func (s *AuditService) Record(e *models.AuditEvent) error {
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = s.now()
+	}
+	return s.repo.CreateAuditEvent(e)
+}
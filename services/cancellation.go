@@ -0,0 +1,62 @@
+package services
+
+import (
+	"errors"
+	"event-booking/models"
+	"time"
+)
+
+// Refund statuses of a cancellation. A refund is pending while the payment
+// provider is asked to pay it back.
+const (
+	RefundNone      = "none"
+	RefundPending   = "pending"
+	RefundSucceeded = "refunded"
+	RefundFailed    = "failed"
+)
+
+var ErrRefundFailed = errors.New("Registration was cancelled, but the refund failed. The organizer will follow up")
+var ErrRejectionRefundFailed = errors.New("Registrations were rejected, but a refund failed")
+var ErrEventRefundFailed = errors.New("A refund failed, so the event was not deleted")
+
+// CancellationPolicy returns the cancellation policy of an event the caller
+// may already see.
+func (s *EventService) CancellationPolicy(event models.Event) (models.CancellationPolicy, error) {
+	return s.repo.GetCancellationPolicy(event.OrganizationId, event.Id)
+}
+
+// SetCancellationPolicy replaces the cancellation policy of an event. The
+// owner and co-organizers can change it; it applies to cancellations from
+// then on.
+func (s *EventService) SetCancellationPolicy(orgId, eventId, userId int64, p *models.CancellationPolicy) error {
+	_, err := s.authorizeEvent(orgId, eventId, userId, EventRoleOwner, EventRoleCoOrganizer)
+	if err != nil {
+		return err
+	}
+
+	p.EventId = eventId
+	p.OrganizationId = orgId
+	return s.repo.SetCancellationPolicy(p)
+}
+
+// Cancellations lists the cancelled registrations of an event with their
+// refunds to the owner and co-organizers.
+func (s *EventService) Cancellations(orgId, eventId, userId int64) ([]models.Cancellation, error) {
+	_, err := s.authorizeEvent(orgId, eventId, userId, EventRoleOwner, EventRoleCoOrganizer)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetCancellations(orgId, eventId)
+}
+
+// refundAmount returns how much of amount the policy refunds when cancelling
+// at now for an event starting at start.
+func refundAmount(policy models.CancellationPolicy, amount int64, start, now time.Time) int64 {
+	if !now.Before(start) {
+		return 0
+	}
+	if now.Before(start.AddDate(0, 0, -int(policy.FullRefundDays))) {
+		return amount
+	}
+	return amount * policy.PartialRefundPercent / 100
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"event-booking/models"
+	"event-booking/services/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func createTestPaidOrder() models.Order {
+	return models.Order{
+		Id: 7, RegistrationId: 100, UserId: 10, EventId: 1, OrganizationId: testOrgId, Amount: 2500, Currency: "EUR",
+		Status: OrderPaid, PaymentIntentId: "pi_fake_1",
+	}
+}
+
+func TestRefundAmount(t *testing.T) {
+	now := time.Now()
+	policy := models.CancellationPolicy{FullRefundDays: 7, PartialRefundPercent: 40}
+
+	tests := []struct {
+		name   string
+		policy models.CancellationPolicy
+		start  time.Time
+		refund int64
+	}{
+		{"before full refund deadline", policy, now.AddDate(0, 0, 10), 2500},
+		{"after full refund deadline", policy, now.AddDate(0, 0, 3), 1000},
+		{"after the event started", policy, now.Add(-time.Minute), 0},
+		{"default policy", models.CancellationPolicy{}, now.Add(time.Hour), 2500},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.refund, refundAmount(test.policy, 2500, test.start, now))
+		})
+	}
+}
+
+func TestCancelEvent_RefundsPaidOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	mockProvider := mocks.NewMockPaymentProvider(ctrl)
+	service := NewEventRegisterService(mockRepo, mockProvider)
+
+	policy := models.CancellationPolicy{EventId: 1, FullRefundDays: 7, PartialRefundPercent: 50}
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().GetRegisteredEventById(testOrgId, int64(10), int64(1)).Return(createTestRegisteredEvent(100, 10, 1), nil)
+	mockRepo.EXPECT().GetPaidOrder(testOrgId, int64(100)).Return(createTestPaidOrder(), nil)
+	mockRepo.EXPECT().GetCancellationPolicy(testOrgId, int64(1)).Return(policy, nil)
+	mockRepo.EXPECT().RecordCancellation(gomock.Any()).DoAndReturn(func(c *models.Cancellation) (bool, error) {
+		assert.Equal(t, RefundPending, c.RefundStatus, "The refund is recorded before it is issued")
+		c.Id = 3
+		return true, nil
+	})
+	mockProvider.EXPECT().Refund("pi_fake_1", int64(1250)).Return(nil)
+	mockRepo.EXPECT().SetRefundStatus(gomock.Any()).DoAndReturn(func(c models.Cancellation) error {
+		assert.Equal(t, RefundSucceeded, c.RefundStatus)
+		return nil
+	})
+
+	cancellation, err := service.CancelEvent(testOrgId, 10, 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), cancellation.OrderId)
+	assert.Equal(t, int64(1250), cancellation.RefundAmount, "The event starts within the full refund period")
+	assert.Equal(t, "EUR", cancellation.Currency)
+	assert.Equal(t, RefundSucceeded, cancellation.RefundStatus)
+}
+
+func TestCancelEvent_RefundFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	mockProvider := mocks.NewMockPaymentProvider(ctrl)
+	service := NewEventRegisterService(mockRepo, mockProvider)
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().GetRegisteredEventById(testOrgId, int64(10), int64(1)).Return(createTestRegisteredEvent(100, 10, 1), nil)
+	mockRepo.EXPECT().GetPaidOrder(testOrgId, int64(100)).Return(createTestPaidOrder(), nil)
+	mockRepo.EXPECT().GetCancellationPolicy(testOrgId, int64(1)).Return(models.CancellationPolicy{EventId: 1}, nil)
+	mockRepo.EXPECT().RecordCancellation(gomock.Any()).Return(true, nil)
+	mockProvider.EXPECT().Refund("pi_fake_1", int64(2500)).Return(errors.New("provider down"))
+	mockRepo.EXPECT().SetRefundStatus(gomock.Any()).DoAndReturn(func(c models.Cancellation) error {
+		assert.Equal(t, RefundFailed, c.RefundStatus)
+		return nil
+	})
+
+	cancellation, err := service.CancelEvent(testOrgId, 10, 1)
+
+	assert.Equal(t, ErrRefundFailed, err)
+	assert.Equal(t, RefundFailed, cancellation.RefundStatus, "The registration stays cancelled")
+}
+
+func TestCancelEvent_CancelledConcurrently(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().GetRegisteredEventById(testOrgId, int64(10), int64(1)).Return(createTestRegisteredEvent(100, 10, 1), nil)
+	mockRepo.EXPECT().GetPaidOrder(testOrgId, int64(100)).Return(createTestPaidOrder(), nil)
+	mockRepo.EXPECT().GetCancellationPolicy(testOrgId, int64(1)).Return(models.CancellationPolicy{EventId: 1}, nil)
+	mockRepo.EXPECT().RecordCancellation(gomock.Any()).Return(false, nil)
+
+	_, err := service.CancelEvent(testOrgId, 10, 1)
+
+	assert.Equal(t, ErrRegisterEventNotFound, err, "Only one request refunds the order")
+}
+
+func TestSetCancellationPolicy_Forbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepository(ctrl)
+	service := NewEventService(mockRepo)
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().GetEventMember(testOrgId, int64(1), int64(10)).Return(models.EventMember{}, errors.New("not found"))
+
+	err := service.SetCancellationPolicy(testOrgId, 1, 10, &models.CancellationPolicy{FullRefundDays: 7})
+
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestCancelAllRegistrations_RefundsInFull(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	mockProvider := mocks.NewMockPaymentProvider(ctrl)
+	service := NewEventRegisterService(mockRepo, mockProvider)
+
+	event := createTestEvent(1, 5)
+	event.DateTime = time.Now().Add(time.Hour)
+	registrations := []models.RegisterEvent{
+		{Id: 100, EventId: 1, UserId: 10, OrganizationId: testOrgId, Status: RegistrationApproved},
+		{Id: 101, EventId: 1, UserId: 11, OrganizationId: testOrgId, Status: RegistrationCancelled},
+		{Id: 102, EventId: 1, UserId: 12, OrganizationId: testOrgId, Status: RegistrationPending},
+	}
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(event, nil)
+	mockRepo.EXPECT().GetRegistrations(testOrgId, int64(1), "").Return(registrations, nil)
+	mockRepo.EXPECT().GetPaidOrder(testOrgId, int64(100)).Return(createTestPaidOrder(), nil)
+	mockRepo.EXPECT().GetPaidOrder(testOrgId, int64(102)).Return(models.Order{}, errors.New("no rows"))
+	mockRepo.EXPECT().RecordCancellation(gomock.Any()).DoAndReturn(func(c *models.Cancellation) (bool, error) {
+		c.Id = c.RegistrationId
+		return true, nil
+	}).Times(2)
+	mockProvider.EXPECT().Refund("pi_fake_1", int64(2500)).Return(nil)
+	mockRepo.EXPECT().SetRefundStatus(gomock.Any()).Return(nil)
+	mockRepo.EXPECT().GetCancellations(testOrgId, int64(1)).Return([]models.Cancellation{
+		{Id: 100, RegistrationId: 100, RefundStatus: RefundSucceeded},
+		{Id: 102, RegistrationId: 102, RefundStatus: RefundNone},
+	}, nil)
+
+	cancellations, err := service.CancelAllRegistrations(testOrgId, 1, 5)
+
+	require.NoError(t, err)
+	require.Len(t, cancellations, 2)
+	assert.Equal(t, int64(2500), cancellations[0].RefundAmount, "The cancellation policy does not apply")
+	assert.Equal(t, RefundSucceeded, cancellations[0].RefundStatus)
+	assert.Equal(t, RefundNone, cancellations[1].RefundStatus)
+}
+
+func TestCancelAllRegistrations_RetriesFailedRefunds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	mockProvider := mocks.NewMockPaymentProvider(ctrl)
+	service := NewEventRegisterService(mockRepo, mockProvider)
+
+	failed := models.Cancellation{
+		Id: 7, RegistrationId: 100, EventId: 1, OrganizationId: testOrgId, OrderId: 1,
+		RefundAmount: 2500, Currency: "EUR", RefundStatus: RefundFailed,
+	}
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil).Times(2)
+	mockRepo.EXPECT().GetRegistrations(testOrgId, int64(1), "").Return([]models.RegisterEvent{
+		{Id: 100, EventId: 1, UserId: 10, OrganizationId: testOrgId, Status: RegistrationCancelled},
+	}, nil).Times(2)
+	mockRepo.EXPECT().GetCancellations(testOrgId, int64(1)).Return([]models.Cancellation{failed}, nil).Times(2)
+	mockRepo.EXPECT().GetPaidOrder(testOrgId, int64(100)).Return(createTestPaidOrder(), nil).Times(2)
+	mockRepo.EXPECT().RetryRefund(testOrgId, int64(7)).Return(true, nil).Times(2)
+	gomock.InOrder(
+		mockProvider.EXPECT().Refund("pi_fake_1", int64(2500)).Return(errors.New("provider down")),
+		mockProvider.EXPECT().Refund("pi_fake_1", int64(2500)).Return(nil),
+	)
+	mockRepo.EXPECT().SetRefundStatus(gomock.Any()).Return(nil).Times(2)
+
+	cancellations, err := service.CancelAllRegistrations(testOrgId, 1, 5)
+
+	assert.Equal(t, ErrEventRefundFailed, err, "The event is not deleted while a refund keeps failing")
+	require.Len(t, cancellations, 1)
+	assert.Equal(t, RefundFailed, cancellations[0].RefundStatus)
+
+	cancellations, err = service.CancelAllRegistrations(testOrgId, 1, 5)
+
+	require.NoError(t, err)
+	require.Len(t, cancellations, 1)
+	assert.Equal(t, RefundSucceeded, cancellations[0].RefundStatus)
+}
+
+func TestCancelAllRegistrations_OnlyOwner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().GetEventMember(testOrgId, int64(1), int64(20)).
+		Return(models.EventMember{EventId: 1, UserId: 20, Role: EventRoleCoOrganizer}, nil)
+
+	_, err := service.CancelAllRegistrations(testOrgId, 1, 20)
+
+	assert.Equal(t, ErrForbidden, err)
+}
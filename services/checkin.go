@@ -0,0 +1,97 @@
+package services
+
+import (
+	"errors"
+	"event-booking/models"
+	"event-booking/utils"
+	"time"
+)
+
+// ticketValidity is how long after the start of an event its tickets can
+// still be checked in.
+const ticketValidity = 24 * time.Hour
+
+var ErrTicketNotIssued = errors.New("Tickets are only issued for approved registrations")
+var ErrInvalidTicket = errors.New("Ticket is invalid")
+var ErrTicketForOtherEvent = errors.New("Ticket is for another event")
+var ErrAlreadyCheckedIn = errors.New("Ticket was already checked in")
+var ErrTicketRevoked = errors.New("Registration of the ticket is no longer approved")
+
+// TicketToken returns the signed ticket for an approved registration of
+// userId, or of a guest in a group booked by userId, who then holds the
+// guest's ticket. The token expires a day after the event starts.
+func (s *EventRegisterService) TicketToken(orgId, userId, registrationId int64) (string, error) {
+	registration, err := s.repo.GetRegistration(orgId, registrationId)
+	if err != nil || (registration.UserId != userId && !s.bookedGuest(orgId, userId, registration)) {
+		return "", ErrRegisterEventNotFound
+	}
+	if registration.Status != RegistrationApproved {
+		return "", ErrTicketNotIssued
+	}
+
+	event, err := s.repo.GetEventById(orgId, registration.EventId)
+	if err != nil {
+		return "", ErrEventNotFound
+	}
+
+	return utils.GenerateTicketToken(utils.Ticket{
+		RegistrationId: registration.Id,
+		UserId:         userId,
+		OrganizationId: orgId,
+		EventId:        event.Id,
+	}, event.DateTime.Add(ticketValidity))
+}
+
+// bookedGuest reports whether registration is of a guest in a group booked
+// by userId.
+func (s *EventRegisterService) bookedGuest(orgId, userId int64, registration models.RegisterEvent) bool {
+	if registration.UserId != 0 || registration.GroupId == 0 {
+		return false
+	}
+	group, err := s.repo.GetGroup(orgId, registration.GroupId)
+	return err == nil && group.BookerId == userId
+}
+
+// CheckIn lets the holder of a ticket token into the event. The owner,
+// co-organizers and check-in staff can check in tickets. Each ticket is
+// only checked in once; the registration is returned with the attendee's
+// email address.
+func (s *EventRegisterService) CheckIn(orgId, eventId, staffId int64, token string) (models.RegisterEvent, error) {
+	_, err := authorizeEventRole(s.repo, orgId, eventId, staffId, EventRoleOwner, EventRoleCoOrganizer, EventRoleCheckInStaff)
+	if err != nil {
+		return models.RegisterEvent{}, err
+	}
+
+	ticket, err := utils.VerifyTicketToken(token)
+	if err != nil {
+		return models.RegisterEvent{}, ErrInvalidTicket
+	}
+	if ticket.OrganizationId != orgId || ticket.EventId != eventId {
+		return models.RegisterEvent{}, ErrTicketForOtherEvent
+	}
+
+	checkedIn, err := s.repo.CheckIn(orgId, eventId, ticket.RegistrationId, ticket.UserId, s.now())
+	if err != nil {
+		return models.RegisterEvent{}, err
+	}
+
+	registration, err := s.repo.GetRegistration(orgId, ticket.RegistrationId)
+	if err != nil {
+		return models.RegisterEvent{}, ErrInvalidTicket
+	}
+	if registration.UserId != 0 {
+		if user, err := s.repo.GetUserById(registration.UserId); err == nil {
+			registration.Email = user.Email
+		}
+	}
+
+	// Guest registrations never change hands, so their ticket is the one
+	// that was checked in.
+	if !checkedIn {
+		if registration.CheckedInAt != nil && (registration.UserId == ticket.UserId || registration.UserId == 0) {
+			return registration, ErrAlreadyCheckedIn
+		}
+		return models.RegisterEvent{}, ErrTicketRevoked
+	}
+	return registration, nil
+}
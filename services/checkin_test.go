@@ -0,0 +1,166 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"event-booking/models"
+	"event-booking/services/mocks"
+	"event-booking/testutil"
+	"event-booking/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func createTestApprovedRegistration() models.RegisterEvent {
+	registration := createTestRegisteredEvent(100, 10, 1)
+	registration.OrganizationId = testOrgId
+	registration.Status = RegistrationApproved
+	return registration
+}
+
+func createTestTicketToken(t *testing.T, eventId int64) string {
+	token, err := utils.GenerateTicketToken(utils.Ticket{
+		RegistrationId: 100, UserId: 10, OrganizationId: testOrgId, EventId: eventId,
+	}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	return token
+}
+
+func TestTicketToken_ApprovedRegistration(t *testing.T) {
+	testutil.SetupTestEnv(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	mockRepo.EXPECT().GetRegistration(testOrgId, int64(100)).Return(createTestApprovedRegistration(), nil)
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+
+	token, err := service.TicketToken(testOrgId, 10, 100)
+
+	require.NoError(t, err)
+	ticket, err := utils.VerifyTicketToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, utils.Ticket{RegistrationId: 100, UserId: 10, OrganizationId: testOrgId, EventId: 1}, ticket)
+}
+
+func TestTicketToken_NotIssued(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	pending := createTestApprovedRegistration()
+	pending.Status = RegistrationPending
+	mockRepo.EXPECT().GetRegistration(testOrgId, int64(100)).Return(pending, nil).Times(2)
+
+	_, err := service.TicketToken(testOrgId, 10, 100)
+	assert.Equal(t, ErrTicketNotIssued, err)
+
+	_, err = service.TicketToken(testOrgId, 11, 100)
+	assert.Equal(t, ErrRegisterEventNotFound, err, "Tickets are only shown to their attendee")
+}
+
+func TestCheckIn_Success(t *testing.T) {
+	testutil.SetupTestEnv(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	checkedIn := createTestApprovedRegistration()
+	now := time.Now()
+	checkedIn.CheckedInAt = &now
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().GetEventMember(testOrgId, int64(1), int64(20)).Return(models.EventMember{Role: EventRoleCheckInStaff}, nil)
+	mockRepo.EXPECT().CheckIn(testOrgId, int64(1), int64(100), int64(10), gomock.Any()).Return(true, nil)
+	mockRepo.EXPECT().GetRegistration(testOrgId, int64(100)).Return(checkedIn, nil)
+	mockRepo.EXPECT().GetUserById(int64(10)).Return(createVerifiedTestUser(10), nil)
+
+	registration, err := service.CheckIn(testOrgId, 1, 20, createTestTicketToken(t, 1))
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), registration.Id)
+	assert.Equal(t, "user@example.com", registration.Email)
+}
+
+func TestCheckIn_Replay(t *testing.T) {
+	testutil.SetupTestEnv(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	checkedIn := createTestApprovedRegistration()
+	earlier := time.Now().Add(-time.Minute)
+	checkedIn.CheckedInAt = &earlier
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().CheckIn(testOrgId, int64(1), int64(100), int64(10), gomock.Any()).Return(false, nil)
+	mockRepo.EXPECT().GetRegistration(testOrgId, int64(100)).Return(checkedIn, nil)
+	mockRepo.EXPECT().GetUserById(int64(10)).Return(createVerifiedTestUser(10), nil)
+
+	registration, err := service.CheckIn(testOrgId, 1, 5, createTestTicketToken(t, 1))
+
+	assert.Equal(t, ErrAlreadyCheckedIn, err)
+	assert.Equal(t, &earlier, registration.CheckedInAt, "The first check-in is shown")
+}
+
+func TestCheckIn_CancelledRegistration(t *testing.T) {
+	testutil.SetupTestEnv(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	cancelled := createTestApprovedRegistration()
+	cancelled.Status = RegistrationCancelled
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().CheckIn(testOrgId, int64(1), int64(100), int64(10), gomock.Any()).Return(false, nil)
+	mockRepo.EXPECT().GetRegistration(testOrgId, int64(100)).Return(cancelled, nil)
+	mockRepo.EXPECT().GetUserById(int64(10)).Return(createVerifiedTestUser(10), nil)
+
+	_, err := service.CheckIn(testOrgId, 1, 5, createTestTicketToken(t, 1))
+
+	assert.Equal(t, ErrTicketRevoked, err)
+}
+
+func TestCheckIn_RejectsInvalidTickets(t *testing.T) {
+	testutil.SetupTestEnv(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil).Times(2)
+
+	_, err := service.CheckIn(testOrgId, 1, 5, createTestTicketToken(t, 2))
+	assert.Equal(t, ErrTicketForOtherEvent, err)
+
+	_, err = service.CheckIn(testOrgId, 1, 5, "not-a-ticket")
+	assert.Equal(t, ErrInvalidTicket, err)
+}
+
+func TestCheckIn_Forbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().GetEventMember(testOrgId, int64(1), int64(10)).Return(models.EventMember{}, errors.New("not found"))
+
+	_, err := service.CheckIn(testOrgId, 1, 10, "token")
+
+	assert.ErrorIs(t, err, ErrForbidden, "Attendees cannot check themselves in")
+}
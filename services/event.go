@@ -3,31 +3,70 @@ package services
 import (
 	"errors"
 	"event-booking/models"
+	"time"
 )
 
 type EventRepository interface {
-	GetEvents() ([]models.Event, error)
-	GetEventById(int64) (models.Event, error)
+	GetEvents(int64, int64) ([]models.Event, error)
+	GetEventById(int64, int64) (models.Event, error)
+	CanAccessEvent(int64, int64, int64) (bool, error)
 	CreateEvent(*models.Event) (int64, error)
 	UpdateEvent(*models.Event) error
-	DeleteEvent(int64) error
+	DeleteEvent(int64, int64) (bool, error)
+	GetEventMembers(int64, int64) ([]models.EventMember, error)
+	GetEventMember(int64, int64, int64) (models.EventMember, error)
+	GetOrganizationMemberByEmail(int64, string) (models.OrganizationMember, error)
+	AddEventMember(*models.EventMember) error
+	RemoveEventMember(int64, int64, int64) (bool, error)
+	GetRegistrationQuestions(int64, int64) ([]models.RegistrationQuestion, error)
+	ReplaceRegistrationQuestions(int64, int64, []models.RegistrationQuestion) error
+	GetTicketTypes(int64, int64) ([]models.TicketType, error)
+	GetTicketType(int64, int64, int64) (models.TicketType, error)
+	CreateTicketType(*models.TicketType) error
+	UpdateTicketType(*models.TicketType) error
+	DeleteTicketType(int64, int64, int64) (bool, error)
+	GetPromoCodes(int64, int64) ([]models.PromoCode, error)
+	GetPromoCode(int64, int64, int64) (models.PromoCode, error)
+	GetPromoCodeByCode(int64, int64, string) (models.PromoCode, error)
+	CreatePromoCode(*models.PromoCode) error
+	UpdatePromoCode(*models.PromoCode) error
+	DeletePromoCode(int64, int64, int64) (bool, error)
+	GetCancellationPolicy(int64, int64) (models.CancellationPolicy, error)
+	SetCancellationPolicy(*models.CancellationPolicy) error
+	GetCancellations(int64, int64) ([]models.Cancellation, error)
+	GetAttendance(int64, int64) (models.EventAttendance, error)
 }
 
 type EventService struct {
 	repo EventRepository
+	now  func() time.Time
 }
 
+// Visibility of events. Public events are listed to every member of the
+// organization, unlisted events can be opened by anyone who knows their id,
+// and private events are only visible to invitees and the organizers.
+const (
+	VisibilityPublic   = "public"
+	VisibilityUnlisted = "unlisted"
+	VisibilityPrivate  = "private"
+)
+
 var ErrForbidden = errors.New("You're not allowed to perform this action")
 var ErrEventNotFound = errors.New("Event could not be retrieved")
+var ErrEventHasPaidRegistrations = errors.New("Event has paid registrations that have to be cancelled first")
 
 func NewEventService(repo EventRepository) *EventService {
 	return &EventService{
 		repo: repo,
+		now:  time.Now,
 	}
 }
 
-func (s *EventService) GetAllEvents() ([]models.Event, error) {
-	events, err := s.repo.GetEvents()
+// GetAllEvents returns the events of organization orgId listed for userId.
+// Every method takes the organization of the request, and events of other
+// organizations are reported as not found.
+func (s *EventService) GetAllEvents(orgId, userId int64) ([]models.Event, error) {
+	events, err := s.repo.GetEvents(orgId, userId)
 	if err != nil {
 		return []models.Event{}, err
 	}
@@ -35,6 +74,10 @@ func (s *EventService) GetAllEvents() ([]models.Event, error) {
 }
 
 func (s *EventService) CreateEvent(e *models.Event) error {
+	if e.Visibility == "" {
+		e.Visibility = VisibilityPublic
+	}
+
 	id, err := s.repo.CreateEvent(e)
 	if err != nil {
 		return err
@@ -44,37 +87,72 @@ func (s *EventService) CreateEvent(e *models.Event) error {
 	return nil
 }
 
-func (s *EventService) GetEventById(id int64) (models.Event, error) {
-	e, err := s.repo.GetEventById(id)
+// GetEventById returns the event when userId may see it. Private events are
+// reported as not found to users without access.
+func (s *EventService) GetEventById(orgId, id, userId int64) (models.Event, error) {
+	e, err := s.repo.GetEventById(orgId, id)
+	if err != nil {
+		return models.Event{}, ErrEventNotFound
+	}
+
+	err = checkEventVisible(s.repo, e, userId)
 	if err != nil {
 		return models.Event{}, err
 	}
 	return e, nil
 }
 
-func (s *EventService) UpdateEvent(eventId, userId int64, updatedEvent *models.Event) error {
-	event, err := s.repo.GetEventById(eventId)
+type eventAccessChecker interface {
+	CanAccessEvent(int64, int64, int64) (bool, error)
+}
+
+// checkEventVisible returns ErrEventNotFound when event is private and
+// userId was not invited to it.
+func checkEventVisible(repo eventAccessChecker, event models.Event, userId int64) error {
+	if event.Visibility != VisibilityPrivate {
+		return nil
+	}
+
+	access, err := repo.CanAccessEvent(event.OrganizationId, event.Id, userId)
 	if err != nil {
+		return err
+	}
+	if !access {
 		return ErrEventNotFound
 	}
+	return nil
+}
 
-	if event.UserId != userId {
-		return ErrForbidden
+// UpdateEvent can be used by the creator of the event and its co-organizers.
+func (s *EventService) UpdateEvent(orgId, eventId, userId int64, updatedEvent *models.Event) error {
+	event, err := s.authorizeEvent(orgId, eventId, userId, EventRoleOwner, EventRoleCoOrganizer)
+	if err != nil {
+		return err
 	}
 
+	if updatedEvent.Visibility == "" {
+		updatedEvent.Visibility = event.Visibility
+	}
 	updatedEvent.Id = eventId
+	updatedEvent.OrganizationId = orgId
 	return s.repo.UpdateEvent(updatedEvent)
 }
 
-func (s *EventService) DeleteEvent(userId, eventId int64) error {
-	event, err := s.repo.GetEventById(eventId)
+// DeleteEvent can only be used by the creator of the event. Events with
+// paid registrations are only deleted once those have been cancelled and
+// refunded, see EventRegisterService.CancelAllRegistrations.
+func (s *EventService) DeleteEvent(orgId, userId, eventId int64) error {
+	_, err := s.authorizeEvent(orgId, eventId, userId, EventRoleOwner)
 	if err != nil {
-		return ErrEventNotFound
+		return err
 	}
 
-	if event.UserId != userId {
-		return ErrForbidden
+	deleted, err := s.repo.DeleteEvent(orgId, eventId)
+	if err != nil {
+		return err
 	}
-
-	return s.repo.DeleteEvent(eventId)
+	if !deleted {
+		return ErrEventHasPaidRegistrations
+	}
+	return nil
 }
@@ -13,14 +13,18 @@ import (
 	"go.uber.org/mock/gomock"
 )
 
+// testOrgId is the organization of the requests in service tests.
+const testOrgId int64 = 3
+
 func createTestEvent(id, userId int64) models.Event {
 	return models.Event{
-		Id:          id,
-		Name:        "Test Event",
-		Description: "Test Description for event",
-		Location:    "Test Location",
-		DateTime:    time.Now().Add(24 * time.Hour),
-		UserId:      userId,
+		Id:             id,
+		Name:           "Test Event",
+		Description:    "Test Description for event",
+		Location:       "Test Location",
+		DateTime:       time.Now().Add(24 * time.Hour),
+		UserId:         userId,
+		OrganizationId: testOrgId,
 	}
 }
 
@@ -36,9 +40,9 @@ func TestGetAllEvents_Success(t *testing.T) {
 		createTestEvent(2, 2),
 	}
 
-	mockRepo.EXPECT().GetEvents().Return(expectedEvents, nil)
+	mockRepo.EXPECT().GetEvents(testOrgId, int64(10)).Return(expectedEvents, nil)
 
-	result, err := service.GetAllEvents()
+	result, err := service.GetAllEvents(testOrgId, 10)
 
 	require.NoError(t, err)
 	assert.Equal(t, expectedEvents, result)
@@ -53,9 +57,9 @@ func TestGetAllEvents_RepositoryError(t *testing.T) {
 	service := NewEventService(mockRepo)
 
 	expectedError := errors.New("database connection failed")
-	mockRepo.EXPECT().GetEvents().Return([]models.Event{}, expectedError)
+	mockRepo.EXPECT().GetEvents(testOrgId, int64(10)).Return([]models.Event{}, expectedError)
 
-	result, err := service.GetAllEvents()
+	result, err := service.GetAllEvents(testOrgId, 10)
 
 	require.Error(t, err)
 	assert.Equal(t, expectedError, err)
@@ -69,9 +73,9 @@ func TestGetAllEvents_EmptyList(t *testing.T) {
 	mockRepo := mocks.NewMockEventRepository(ctrl)
 	service := NewEventService(mockRepo)
 
-	mockRepo.EXPECT().GetEvents().Return([]models.Event{}, nil)
+	mockRepo.EXPECT().GetEvents(testOrgId, int64(10)).Return([]models.Event{}, nil)
 
-	result, err := service.GetAllEvents()
+	result, err := service.GetAllEvents(testOrgId, 10)
 
 	require.NoError(t, err)
 	assert.Empty(t, result)
@@ -85,9 +89,9 @@ func TestGetEventById_Success(t *testing.T) {
 	service := NewEventService(mockRepo)
 
 	expectedEvent := createTestEvent(1, 1)
-	mockRepo.EXPECT().GetEventById(int64(1)).Return(expectedEvent, nil)
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(expectedEvent, nil)
 
-	result, err := service.GetEventById(1)
+	result, err := service.GetEventById(testOrgId, 1, 10)
 
 	require.NoError(t, err)
 	assert.Equal(t, expectedEvent, result)
@@ -100,14 +104,29 @@ func TestGetEventById_NotFound(t *testing.T) {
 	mockRepo := mocks.NewMockEventRepository(ctrl)
 	service := NewEventService(mockRepo)
 
-	mockRepo.EXPECT().GetEventById(int64(999)).Return(models.Event{}, errors.New("not found"))
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(999)).Return(models.Event{}, errors.New("not found"))
 
-	result, err := service.GetEventById(999)
+	result, err := service.GetEventById(testOrgId, 999, 10)
 
 	require.Error(t, err)
 	assert.Equal(t, models.Event{}, result)
 }
 
+func TestGetEventById_OtherOrganization(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepository(ctrl)
+	service := NewEventService(mockRepo)
+
+	// The repository only finds events of the requested organization.
+	mockRepo.EXPECT().GetEventById(int64(99), int64(1)).Return(models.Event{}, errors.New("no rows"))
+
+	_, err := service.GetEventById(99, 1, 10)
+
+	assert.Equal(t, ErrEventNotFound, err)
+}
+
 func TestCreateEvent_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -158,13 +177,14 @@ func TestUpdateEvent_Success_OwnerCanUpdate(t *testing.T) {
 	updatedEvent := createTestEvent(0, userId)
 	updatedEvent.Name = "Updated Name"
 
-	mockRepo.EXPECT().GetEventById(eventId).Return(existingEvent, nil)
+	mockRepo.EXPECT().GetEventById(testOrgId, eventId).Return(existingEvent, nil)
 	mockRepo.EXPECT().UpdateEvent(gomock.Any()).DoAndReturn(func(e *models.Event) error {
 		assert.Equal(t, eventId, e.Id)
+		assert.Equal(t, testOrgId, e.OrganizationId)
 		return nil
 	})
 
-	err := service.UpdateEvent(eventId, userId, &updatedEvent)
+	err := service.UpdateEvent(testOrgId, eventId, userId, &updatedEvent)
 
 	require.NoError(t, err)
 	assert.Equal(t, eventId, updatedEvent.Id)
@@ -183,9 +203,10 @@ func TestUpdateEvent_Forbidden_NonOwnerCannotUpdate(t *testing.T) {
 	existingEvent := createTestEvent(eventId, ownerUserId)
 	updatedEvent := createTestEvent(0, otherUserId)
 
-	mockRepo.EXPECT().GetEventById(eventId).Return(existingEvent, nil)
+	mockRepo.EXPECT().GetEventById(testOrgId, eventId).Return(existingEvent, nil)
+	mockRepo.EXPECT().GetEventMember(testOrgId, eventId, otherUserId).Return(models.EventMember{}, errors.New("not found"))
 
-	err := service.UpdateEvent(eventId, otherUserId, &updatedEvent)
+	err := service.UpdateEvent(testOrgId, eventId, otherUserId, &updatedEvent)
 
 	require.Error(t, err)
 	assert.Equal(t, ErrForbidden, err)
@@ -202,9 +223,9 @@ func TestUpdateEvent_EventNotFound(t *testing.T) {
 	userId := int64(10)
 	updatedEvent := createTestEvent(0, userId)
 
-	mockRepo.EXPECT().GetEventById(eventId).Return(models.Event{}, errors.New("not found"))
+	mockRepo.EXPECT().GetEventById(testOrgId, eventId).Return(models.Event{}, errors.New("not found"))
 
-	err := service.UpdateEvent(eventId, userId, &updatedEvent)
+	err := service.UpdateEvent(testOrgId, eventId, userId, &updatedEvent)
 
 	require.Error(t, err)
 	assert.Equal(t, ErrEventNotFound, err)
@@ -223,10 +244,10 @@ func TestUpdateEvent_RepositoryUpdateError(t *testing.T) {
 	updatedEvent := createTestEvent(0, userId)
 	expectedError := errors.New("update failed")
 
-	mockRepo.EXPECT().GetEventById(eventId).Return(existingEvent, nil)
+	mockRepo.EXPECT().GetEventById(testOrgId, eventId).Return(existingEvent, nil)
 	mockRepo.EXPECT().UpdateEvent(gomock.Any()).Return(expectedError)
 
-	err := service.UpdateEvent(eventId, userId, &updatedEvent)
+	err := service.UpdateEvent(testOrgId, eventId, userId, &updatedEvent)
 
 	require.Error(t, err)
 	assert.Equal(t, expectedError, err)
@@ -243,10 +264,10 @@ func TestDeleteEvent_Success_OwnerCanDelete(t *testing.T) {
 	userId := int64(10)
 	existingEvent := createTestEvent(eventId, userId)
 
-	mockRepo.EXPECT().GetEventById(eventId).Return(existingEvent, nil)
-	mockRepo.EXPECT().DeleteEvent(eventId).Return(nil)
+	mockRepo.EXPECT().GetEventById(testOrgId, eventId).Return(existingEvent, nil)
+	mockRepo.EXPECT().DeleteEvent(testOrgId, eventId).Return(true, nil)
 
-	err := service.DeleteEvent(userId, eventId)
+	err := service.DeleteEvent(testOrgId, userId, eventId)
 
 	require.NoError(t, err)
 }
@@ -263,9 +284,10 @@ func TestDeleteEvent_Forbidden_NonOwnerCannotDelete(t *testing.T) {
 	otherUserId := int64(20)
 	existingEvent := createTestEvent(eventId, ownerUserId)
 
-	mockRepo.EXPECT().GetEventById(eventId).Return(existingEvent, nil)
+	mockRepo.EXPECT().GetEventById(testOrgId, eventId).Return(existingEvent, nil)
+	mockRepo.EXPECT().GetEventMember(testOrgId, eventId, otherUserId).Return(models.EventMember{}, errors.New("not found"))
 
-	err := service.DeleteEvent(otherUserId, eventId)
+	err := service.DeleteEvent(testOrgId, otherUserId, eventId)
 
 	require.Error(t, err)
 	assert.Equal(t, ErrForbidden, err)
@@ -281,14 +303,29 @@ func TestDeleteEvent_EventNotFound(t *testing.T) {
 	eventId := int64(999)
 	userId := int64(10)
 
-	mockRepo.EXPECT().GetEventById(eventId).Return(models.Event{}, errors.New("not found"))
+	mockRepo.EXPECT().GetEventById(testOrgId, eventId).Return(models.Event{}, errors.New("not found"))
 
-	err := service.DeleteEvent(userId, eventId)
+	err := service.DeleteEvent(testOrgId, userId, eventId)
 
 	require.Error(t, err)
 	assert.Equal(t, ErrEventNotFound, err)
 }
 
+func TestDeleteEvent_PaidRegistrations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepository(ctrl)
+	service := NewEventService(mockRepo)
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 10), nil)
+	mockRepo.EXPECT().DeleteEvent(testOrgId, int64(1)).Return(false, nil)
+
+	err := service.DeleteEvent(testOrgId, 10, 1)
+
+	assert.Equal(t, ErrEventHasPaidRegistrations, err)
+}
+
 func TestDeleteEvent_RepositoryDeleteError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -301,10 +338,10 @@ func TestDeleteEvent_RepositoryDeleteError(t *testing.T) {
 	existingEvent := createTestEvent(eventId, userId)
 	expectedError := errors.New("delete failed")
 
-	mockRepo.EXPECT().GetEventById(eventId).Return(existingEvent, nil)
-	mockRepo.EXPECT().DeleteEvent(eventId).Return(expectedError)
+	mockRepo.EXPECT().GetEventById(testOrgId, eventId).Return(existingEvent, nil)
+	mockRepo.EXPECT().DeleteEvent(testOrgId, eventId).Return(false, expectedError)
 
-	err := service.DeleteEvent(userId, eventId)
+	err := service.DeleteEvent(testOrgId, userId, eventId)
 
 	require.Error(t, err)
 	assert.Equal(t, expectedError, err)
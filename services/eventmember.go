@@ -0,0 +1,134 @@
+package services
+
+import (
+	"errors"
+	"event-booking/models"
+	"slices"
+)
+
+// Roles on a single event. The creator of the event is its owner; other
+// users get a role through the event_members table.
+const (
+	EventRoleOwner        = "owner"
+	EventRoleCoOrganizer  = "co_organizer"
+	EventRoleCheckInStaff = "checkin_staff"
+)
+
+var EventMemberRoles = []string{EventRoleCoOrganizer, EventRoleCheckInStaff}
+
+var ErrEventMemberNotFound = errors.New("Event member not found")
+var ErrAlreadyEventMember = errors.New("User already has a role on this event")
+var ErrInvalidEventRole = errors.New("Role must be co_organizer or checkin_staff")
+var ErrInviteeNotInOrganization = errors.New("Only members of the organization can be added to an event")
+
+type eventMemberGetter interface {
+	GetEventMember(int64, int64, int64) (models.EventMember, error)
+}
+
+// EventRole returns the role of userId on the event, or an empty role when
+// the user has none.
+func (s *EventService) EventRole(orgId int64, event models.Event, userId int64) string {
+	return eventRole(s.repo, orgId, event, userId)
+}
+
+func eventRole(repo eventMemberGetter, orgId int64, event models.Event, userId int64) string {
+	if event.UserId == userId {
+		return EventRoleOwner
+	}
+
+	member, err := repo.GetEventMember(orgId, event.Id, userId)
+	if err != nil {
+		return ""
+	}
+	return member.Role
+}
+
+type eventRoleRepository interface {
+	GetEventById(int64, int64) (models.Event, error)
+	eventMemberGetter
+}
+
+// authorizeEvent returns the event when userId has one of roles on it.
+func (s *EventService) authorizeEvent(orgId, eventId, userId int64, roles ...string) (models.Event, error) {
+	return authorizeEventRole(s.repo, orgId, eventId, userId, roles...)
+}
+
+func authorizeEventRole(repo eventRoleRepository, orgId, eventId, userId int64, roles ...string) (models.Event, error) {
+	event, err := repo.GetEventById(orgId, eventId)
+	if err != nil {
+		return models.Event{}, ErrEventNotFound
+	}
+
+	if !slices.Contains(roles, eventRole(repo, orgId, event, userId)) {
+		return models.Event{}, ErrForbidden
+	}
+	return event, nil
+}
+
+// EventMembers lists the members of an event to anyone with a role on it.
+func (s *EventService) EventMembers(orgId, eventId, userId int64) ([]models.EventMember, error) {
+	_, err := s.authorizeEvent(orgId, eventId, userId, EventRoleOwner, EventRoleCoOrganizer, EventRoleCheckInStaff)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetEventMembers(orgId, eventId)
+}
+
+// AddEventMember gives the organization member with email a role on the
+// event. Only the owner of the event can add members.
+func (s *EventService) AddEventMember(orgId, eventId, userId int64, email, role string) (models.EventMember, error) {
+	if !slices.Contains(EventMemberRoles, role) {
+		return models.EventMember{}, ErrInvalidEventRole
+	}
+
+	event, err := s.authorizeEvent(orgId, eventId, userId, EventRoleOwner)
+	if err != nil {
+		return models.EventMember{}, err
+	}
+
+	invitee, err := s.repo.GetOrganizationMemberByEmail(orgId, email)
+	if err != nil {
+		return models.EventMember{}, ErrInviteeNotInOrganization
+	}
+
+	if s.EventRole(orgId, event, invitee.UserId) != "" {
+		return models.EventMember{}, ErrAlreadyEventMember
+	}
+
+	member := models.EventMember{
+		EventId:        eventId,
+		UserId:         invitee.UserId,
+		OrganizationId: orgId,
+		Email:          invitee.Email,
+		Role:           role,
+		CreatedAt:      s.now(),
+	}
+	err = s.repo.AddEventMember(&member)
+	if err != nil {
+		return models.EventMember{}, err
+	}
+	return member, nil
+}
+
+// RemoveEventMember takes the role of memberId away. The owner can remove
+// anyone, and members can remove themselves.
+func (s *EventService) RemoveEventMember(orgId, eventId, userId, memberId int64) error {
+	roles := []string{EventRoleOwner}
+	if memberId == userId {
+		roles = append(roles, EventMemberRoles...)
+	}
+
+	_, err := s.authorizeEvent(orgId, eventId, userId, roles...)
+	if err != nil {
+		return err
+	}
+
+	removed, err := s.repo.RemoveEventMember(orgId, eventId, memberId)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrEventMemberNotFound
+	}
+	return nil
+}
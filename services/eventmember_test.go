@@ -0,0 +1,193 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"event-booking/models"
+	"event-booking/services/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUpdateEvent_CoOrganizerCanUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepository(ctrl)
+	service := NewEventService(mockRepo)
+
+	existingEvent := createTestEvent(1, 10)
+	updatedEvent := createTestEvent(0, 20)
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(existingEvent, nil)
+	mockRepo.EXPECT().GetEventMember(testOrgId, int64(1), int64(20)).Return(models.EventMember{Role: EventRoleCoOrganizer}, nil)
+	mockRepo.EXPECT().UpdateEvent(&updatedEvent).Return(nil)
+
+	err := service.UpdateEvent(testOrgId, 1, 20, &updatedEvent)
+
+	require.NoError(t, err)
+}
+
+func TestUpdateEvent_CheckInStaffCannotUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepository(ctrl)
+	service := NewEventService(mockRepo)
+
+	updatedEvent := createTestEvent(0, 20)
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 10), nil)
+	mockRepo.EXPECT().GetEventMember(testOrgId, int64(1), int64(20)).Return(models.EventMember{Role: EventRoleCheckInStaff}, nil)
+
+	err := service.UpdateEvent(testOrgId, 1, 20, &updatedEvent)
+
+	assert.Equal(t, ErrForbidden, err)
+}
+
+func TestDeleteEvent_CoOrganizerCannotDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepository(ctrl)
+	service := NewEventService(mockRepo)
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 10), nil)
+	mockRepo.EXPECT().GetEventMember(testOrgId, int64(1), int64(20)).Return(models.EventMember{Role: EventRoleCoOrganizer}, nil)
+
+	err := service.DeleteEvent(testOrgId, 20, 1)
+
+	assert.Equal(t, ErrForbidden, err)
+}
+
+func TestAddEventMember_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepository(ctrl)
+	service := NewEventService(mockRepo)
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 10), nil)
+	mockRepo.EXPECT().GetOrganizationMemberByEmail(testOrgId, "staff@example.com").
+		Return(models.OrganizationMember{UserId: 20, Email: "staff@example.com"}, nil)
+	mockRepo.EXPECT().GetEventMember(testOrgId, int64(1), int64(20)).Return(models.EventMember{}, errors.New("not found"))
+	mockRepo.EXPECT().AddEventMember(gomock.Any()).DoAndReturn(func(m *models.EventMember) error {
+		assert.Equal(t, int64(1), m.EventId)
+		assert.Equal(t, int64(20), m.UserId)
+		assert.Equal(t, testOrgId, m.OrganizationId)
+		assert.Equal(t, EventRoleCheckInStaff, m.Role)
+		return nil
+	})
+
+	member, err := service.AddEventMember(testOrgId, 1, 10, "staff@example.com", EventRoleCheckInStaff)
+
+	require.NoError(t, err)
+	assert.Equal(t, "staff@example.com", member.Email)
+}
+
+func TestAddEventMember_OnlyOwner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepository(ctrl)
+	service := NewEventService(mockRepo)
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 10), nil)
+	mockRepo.EXPECT().GetEventMember(testOrgId, int64(1), int64(20)).Return(models.EventMember{Role: EventRoleCoOrganizer}, nil)
+
+	_, err := service.AddEventMember(testOrgId, 1, 20, "staff@example.com", EventRoleCheckInStaff)
+
+	assert.Equal(t, ErrForbidden, err)
+}
+
+func TestAddEventMember_InviteeOutsideOrganization(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepository(ctrl)
+	service := NewEventService(mockRepo)
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 10), nil)
+	mockRepo.EXPECT().GetOrganizationMemberByEmail(testOrgId, "stranger@example.com").
+		Return(models.OrganizationMember{}, errors.New("not found"))
+
+	_, err := service.AddEventMember(testOrgId, 1, 10, "stranger@example.com", EventRoleCoOrganizer)
+
+	assert.Equal(t, ErrInviteeNotInOrganization, err)
+}
+
+func TestAddEventMember_OwnerCannotBeAdded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepository(ctrl)
+	service := NewEventService(mockRepo)
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 10), nil)
+	mockRepo.EXPECT().GetOrganizationMemberByEmail(testOrgId, "owner@example.com").
+		Return(models.OrganizationMember{UserId: 10}, nil)
+
+	_, err := service.AddEventMember(testOrgId, 1, 10, "owner@example.com", EventRoleCoOrganizer)
+
+	assert.Equal(t, ErrAlreadyEventMember, err)
+}
+
+func TestAddEventMember_InvalidRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewEventService(mocks.NewMockEventRepository(ctrl))
+
+	_, err := service.AddEventMember(testOrgId, 1, 10, "staff@example.com", EventRoleOwner)
+
+	assert.Equal(t, ErrInvalidEventRole, err)
+}
+
+func TestRemoveEventMember_MemberCanLeave(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepository(ctrl)
+	service := NewEventService(mockRepo)
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 10), nil)
+	mockRepo.EXPECT().GetEventMember(testOrgId, int64(1), int64(20)).Return(models.EventMember{Role: EventRoleCheckInStaff}, nil)
+	mockRepo.EXPECT().RemoveEventMember(testOrgId, int64(1), int64(20)).Return(true, nil)
+
+	err := service.RemoveEventMember(testOrgId, 1, 20, 20)
+
+	require.NoError(t, err)
+}
+
+func TestRemoveEventMember_MemberCannotRemoveOthers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepository(ctrl)
+	service := NewEventService(mockRepo)
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 10), nil)
+	mockRepo.EXPECT().GetEventMember(testOrgId, int64(1), int64(20)).Return(models.EventMember{Role: EventRoleCoOrganizer}, nil)
+
+	err := service.RemoveEventMember(testOrgId, 1, 20, 30)
+
+	assert.Equal(t, ErrForbidden, err)
+}
+
+func TestRemoveEventMember_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepository(ctrl)
+	service := NewEventService(mockRepo)
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 10), nil)
+	mockRepo.EXPECT().RemoveEventMember(testOrgId, int64(1), int64(30)).Return(false, nil)
+
+	err := service.RemoveEventMember(testOrgId, 1, 10, 30)
+
+	assert.Equal(t, ErrEventMemberNotFound, err)
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"errors"
+	"event-booking/models"
+	"fmt"
+	"strings"
+)
+
+// MaxGroupSize is the most attendees a group registration can book.
+const MaxGroupSize = 20
+
+var ErrInvalidAttendees = errors.New("Attendees are invalid")
+var ErrAttendeeAlreadyRegistered = errors.New("Attendee is already registered for this event")
+var ErrGroupNotFound = errors.New("Group registration not found")
+
+// RegisterGroup registers several attendees for an event of organization
+// orgId on behalf of bookerId, with tickets of type ticketTypeId and each
+// attendee's answers to the registration questions. Attendees who are
+// members of the organization get their own registration; anyone else is
+// registered as a guest. Private events only take invited members. Either
+// all attendees get a seat or, when they do not fit, none. A promo code is
+// used once for every attendee. Registrations with a paid ticket await
+// payment, which the booker makes for the whole group with CheckoutGroup.
+func (s *EventRegisterService) RegisterGroup(orgId, bookerId, eventId, ticketTypeId int64, promoCode string, attendees []models.GroupAttendee) (models.RegistrationGroup, error) {
+	if len(attendees) == 0 || len(attendees) > MaxGroupSize {
+		return models.RegistrationGroup{}, fmt.Errorf("%w: a group has 1 to %d attendees", ErrInvalidAttendees, MaxGroupSize)
+	}
+
+	booker, err := s.repo.GetUserById(bookerId)
+	if err != nil {
+		return models.RegistrationGroup{}, err
+	}
+	if !booker.Verified {
+		return models.RegistrationGroup{}, ErrEmailNotVerified
+	}
+
+	event, err := s.repo.GetEventById(orgId, eventId)
+	if err != nil {
+		return models.RegistrationGroup{}, ErrEventNotFound
+	}
+	err = checkEventVisible(s.repo, event, bookerId)
+	if err != nil {
+		return models.RegistrationGroup{}, err
+	}
+
+	ticketTypes, err := s.repo.GetTicketTypes(orgId, eventId)
+	if err != nil {
+		return models.RegistrationGroup{}, err
+	}
+	ticketType, err := chooseTicketType(ticketTypes, ticketTypeId, s.now())
+	if err != nil {
+		return models.RegistrationGroup{}, err
+	}
+	amount, promoCodeId, err := s.price(orgId, eventId, ticketType, promoCode)
+	if err != nil {
+		return models.RegistrationGroup{}, err
+	}
+
+	questions, err := s.repo.GetRegistrationQuestions(orgId, eventId)
+	if err != nil {
+		return models.RegistrationGroup{}, err
+	}
+
+	now := s.now()
+	status := RegistrationApproved
+	if event.RequiresApproval {
+		status = RegistrationPending
+	}
+	if amount > 0 {
+		status = RegistrationAwaitingPayment
+	}
+	group := models.RegistrationGroup{
+		EventId:        eventId,
+		OrganizationId: orgId,
+		BookerId:       bookerId,
+		CreatedAt:      now,
+	}
+	seen := map[string]bool{}
+	for _, attendee := range attendees {
+		registration, err := s.groupRegistration(event, attendee)
+		if err != nil {
+			return models.RegistrationGroup{}, err
+		}
+		key := strings.ToLower(registration.Email)
+		if seen[key] {
+			return models.RegistrationGroup{}, fmt.Errorf("%w: %s is listed more than once", ErrInvalidAttendees, registration.Email)
+		}
+		seen[key] = true
+
+		registration.Answers, err = validateAnswers(questions, attendee.Answers)
+		if err != nil {
+			return models.RegistrationGroup{}, fmt.Errorf("%w (%s)", err, registration.Email)
+		}
+		registration.TicketTypeId = ticketType.Id
+		registration.PromoCodeId = promoCodeId
+		registration.Amount = amount
+		registration.Status = status
+		registration.CreatedAt = now
+		group.Registrations = append(group.Registrations, registration)
+	}
+
+	registered, err := s.repo.RegisterGroup(&group)
+	if err != nil {
+		return models.RegistrationGroup{}, err
+	}
+	for _, registration := range group.Registrations {
+		if registered || registration.UserId == 0 {
+			break
+		}
+		// Another request may have registered the attendee in the meantime.
+		_, err = s.repo.GetRegisteredEventById(orgId, registration.UserId, eventId)
+		if err == nil {
+			return models.RegistrationGroup{}, fmt.Errorf("%w: %s", ErrAttendeeAlreadyRegistered, registration.Email)
+		}
+	}
+	if !registered && promoCodeId != 0 {
+		code, err := s.repo.GetPromoCode(orgId, eventId, promoCodeId)
+		if err == nil && code.MaxUses > 0 && code.Used+int64(len(group.Registrations)) > code.MaxUses {
+			return models.RegistrationGroup{}, ErrPromoCodeUsedUp
+		}
+	}
+	if !registered && event.Capacity == 0 && ticketType.Id != 0 {
+		return models.RegistrationGroup{}, ErrTicketTypeSoldOut
+	}
+	if !registered {
+		return models.RegistrationGroup{}, ErrEventFull
+	}
+	return group, nil
+}
+
+// groupRegistration returns the registration of attendee for event, for the
+// member of the organization with the attendee's email or for a guest.
+func (s *EventRegisterService) groupRegistration(event models.Event, attendee models.GroupAttendee) (models.RegisterEvent, error) {
+	registration := models.RegisterEvent{
+		EventId:        event.Id,
+		OrganizationId: event.OrganizationId,
+		Email:          strings.TrimSpace(attendee.Email),
+	}
+
+	member, err := s.repo.GetOrganizationMemberByEmail(event.OrganizationId, registration.Email)
+	if err != nil {
+		if event.Visibility == VisibilityPrivate {
+			return models.RegisterEvent{}, fmt.Errorf("%w: %s is not a member of the organization and private events take no guests", ErrInvalidAttendees, registration.Email)
+		}
+		registration.GuestName = strings.TrimSpace(attendee.Name)
+		if registration.GuestName == "" {
+			return models.RegisterEvent{}, fmt.Errorf("%w: guest %s needs a name", ErrInvalidAttendees, registration.Email)
+		}
+		return registration, nil
+	}
+
+	err = checkEventVisible(s.repo, event, member.UserId)
+	if errors.Is(err, ErrEventNotFound) {
+		return models.RegisterEvent{}, fmt.Errorf("%w: %s was not invited to the event", ErrInvalidAttendees, member.Email)
+	}
+	if err != nil {
+		return models.RegisterEvent{}, err
+	}
+	_, err = s.repo.GetRegisteredEventById(event.OrganizationId, member.UserId, event.Id)
+	if err == nil {
+		return models.RegisterEvent{}, fmt.Errorf("%w: %s", ErrAttendeeAlreadyRegistered, member.Email)
+	}
+
+	registration.UserId = member.UserId
+	registration.Email = member.Email
+	return registration, nil
+}
+
+// Groups lists the group registrations booked by bookerId.
+func (s *EventRegisterService) Groups(orgId, bookerId int64) ([]models.RegistrationGroup, error) {
+	return s.repo.GetGroups(orgId, bookerId)
+}
+
+// Group returns a group registration booked by bookerId.
+func (s *EventRegisterService) Group(orgId, bookerId, id int64) (models.RegistrationGroup, error) {
+	group, err := s.repo.GetGroup(orgId, id)
+	if err != nil || group.BookerId != bookerId {
+		return models.RegistrationGroup{}, ErrGroupNotFound
+	}
+	return group, nil
+}
+
+// CancelGroupRegistration cancels one registration of a group booked by
+// bookerId, for a member of the group as well as for a guest.
+func (s *EventRegisterService) CancelGroupRegistration(orgId, bookerId, groupId, registrationId int64) (models.Cancellation, error) {
+	group, err := s.Group(orgId, bookerId, groupId)
+	if err != nil {
+		return models.Cancellation{}, err
+	}
+
+	event, err := s.repo.GetEventById(orgId, group.EventId)
+	if err != nil {
+		return models.Cancellation{}, ErrEventNotFound
+	}
+
+	for _, registration := range group.Registrations {
+		if registration.Id == registrationId {
+			return s.cancel(event, registration)
+		}
+	}
+	return models.Cancellation{}, ErrRegisterEventNotFound
+}
+
+// CancelGroup cancels the registrations of a group booked by bookerId that
+// are still active, including those awaiting payment. Registrations
+// cancelled before are left alone.
+func (s *EventRegisterService) CancelGroup(orgId, bookerId, groupId int64) ([]models.Cancellation, error) {
+	group, err := s.Group(orgId, bookerId, groupId)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := s.repo.GetEventById(orgId, group.EventId)
+	if err != nil {
+		return nil, ErrEventNotFound
+	}
+
+	cancellations := []models.Cancellation{}
+	for _, registration := range group.Registrations {
+		if registration.Status != RegistrationAwaitingPayment && registration.Status != RegistrationPending &&
+			registration.Status != RegistrationApproved {
+			continue
+		}
+		cancellation, err := s.cancel(event, registration)
+		if errors.Is(err, ErrRegisterEventNotFound) {
+			continue
+		}
+		if err != nil {
+			return cancellations, err
+		}
+		cancellations = append(cancellations, cancellation)
+	}
+	return cancellations, nil
+}
@@ -0,0 +1,303 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"event-booking/models"
+	"event-booking/services/mocks"
+	"event-booking/testutil"
+	"event-booking/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func createTestGroup() models.RegistrationGroup {
+	guest := createTestRegisteredEvent(101, 0, 1)
+	guest.OrganizationId = testOrgId
+	guest.Status = RegistrationApproved
+	guest.GroupId = 7
+	guest.GuestName = "Grace Guest"
+	guest.Email = "grace@example.com"
+
+	member := createTestApprovedRegistration()
+	member.GroupId = 7
+
+	return models.RegistrationGroup{
+		Id: 7, EventId: 1, OrganizationId: testOrgId, BookerId: 10,
+		Registrations: []models.RegisterEvent{member, guest},
+	}
+}
+
+func expectTestGroupEvent(mockRepo *mocks.MockRegisterRepository, ticketTypes []models.TicketType) {
+	mockRepo.EXPECT().GetUserById(int64(10)).Return(createVerifiedTestUser(10), nil)
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().GetTicketTypes(testOrgId, int64(1)).Return(ticketTypes, nil)
+	mockRepo.EXPECT().GetRegistrationQuestions(testOrgId, int64(1)).Return([]models.RegistrationQuestion{}, nil)
+}
+
+func TestRegisterGroup_MembersAndGuests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	expectTestGroupEvent(mockRepo, []models.TicketType{})
+	mockRepo.EXPECT().GetOrganizationMemberByEmail(testOrgId, "colleague@example.com").
+		Return(models.OrganizationMember{OrganizationId: testOrgId, UserId: 11, Email: "colleague@example.com"}, nil)
+	mockRepo.EXPECT().GetRegisteredEventById(testOrgId, int64(11), int64(1)).Return(models.RegisterEvent{}, errors.New("no rows"))
+	mockRepo.EXPECT().GetOrganizationMemberByEmail(testOrgId, "grace@example.com").Return(models.OrganizationMember{}, errors.New("no rows"))
+	mockRepo.EXPECT().RegisterGroup(gomock.Any()).DoAndReturn(func(group *models.RegistrationGroup) (bool, error) {
+		assert.Equal(t, int64(10), group.BookerId)
+		require.Len(t, group.Registrations, 2)
+		assert.Equal(t, int64(11), group.Registrations[0].UserId)
+		assert.Equal(t, int64(0), group.Registrations[1].UserId)
+		assert.Equal(t, "Grace Guest", group.Registrations[1].GuestName)
+		assert.Equal(t, "grace@example.com", group.Registrations[1].Email)
+		for _, r := range group.Registrations {
+			assert.Equal(t, RegistrationApproved, r.Status)
+		}
+		group.Id = 7
+		return true, nil
+	})
+
+	group, err := service.RegisterGroup(testOrgId, 10, 1, 0, "", []models.GroupAttendee{
+		{Email: "colleague@example.com"},
+		{Email: " grace@example.com ", Name: " Grace Guest "},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), group.Id)
+}
+
+func TestRegisterGroup_NotEnoughSeats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	expectTestGroupEvent(mockRepo, []models.TicketType{})
+	mockRepo.EXPECT().GetOrganizationMemberByEmail(testOrgId, gomock.Any()).Return(models.OrganizationMember{}, errors.New("no rows")).Times(2)
+	mockRepo.EXPECT().RegisterGroup(gomock.Any()).Return(false, nil)
+
+	_, err := service.RegisterGroup(testOrgId, 10, 1, 0, "", []models.GroupAttendee{
+		{Email: "ada@example.com", Name: "Ada"},
+		{Email: "grace@example.com", Name: "Grace"},
+	})
+
+	assert.Equal(t, ErrEventFull, err)
+}
+
+func TestRegisterGroup_PaidTicketWithPromoCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	expectTestGroupEvent(mockRepo, createTestTicketTypes())
+	mockRepo.EXPECT().GetPromoCodeByCode(testOrgId, int64(1), "SPRING").
+		Return(models.PromoCode{Id: 3, Code: "SPRING", DiscountType: DiscountFixed, DiscountValue: 500}, nil)
+	mockRepo.EXPECT().GetOrganizationMemberByEmail(testOrgId, gomock.Any()).Return(models.OrganizationMember{}, errors.New("no rows")).Times(2)
+	mockRepo.EXPECT().RegisterGroup(gomock.Any()).DoAndReturn(func(group *models.RegistrationGroup) (bool, error) {
+		for _, r := range group.Registrations {
+			assert.Equal(t, RegistrationAwaitingPayment, r.Status)
+			assert.Equal(t, int64(2), r.TicketTypeId)
+			assert.Equal(t, int64(3), r.PromoCodeId)
+			assert.Equal(t, int64(2000), r.Amount)
+		}
+		return true, nil
+	})
+
+	group, err := service.RegisterGroup(testOrgId, 10, 1, 2, "spring", []models.GroupAttendee{
+		{Email: "ada@example.com", Name: "Ada"},
+		{Email: "grace@example.com", Name: "Grace"},
+	})
+
+	require.NoError(t, err)
+	assert.Len(t, group.Registrations, 2)
+}
+
+func TestRegisterGroup_PromoCodeUsedUp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	promoCode := models.PromoCode{Id: 3, Code: "LAST", DiscountType: DiscountFixed, DiscountValue: 500, MaxUses: 10, Used: 9}
+	expectTestGroupEvent(mockRepo, createTestTicketTypes())
+	mockRepo.EXPECT().GetPromoCodeByCode(testOrgId, int64(1), "LAST").Return(promoCode, nil)
+	mockRepo.EXPECT().GetOrganizationMemberByEmail(testOrgId, gomock.Any()).Return(models.OrganizationMember{}, errors.New("no rows")).Times(2)
+	mockRepo.EXPECT().RegisterGroup(gomock.Any()).Return(false, nil)
+	mockRepo.EXPECT().GetPromoCode(testOrgId, int64(1), int64(3)).Return(promoCode, nil)
+
+	_, err := service.RegisterGroup(testOrgId, 10, 1, 2, "LAST", []models.GroupAttendee{
+		{Email: "ada@example.com", Name: "Ada"},
+		{Email: "grace@example.com", Name: "Grace"},
+	})
+
+	assert.Equal(t, ErrPromoCodeUsedUp, err)
+}
+
+func TestCancelGroupRegistration_RefundsShareOfGroupOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	mockProvider := mocks.NewMockPaymentProvider(ctrl)
+	service := NewEventRegisterService(mockRepo, mockProvider)
+
+	group := createTestGroup()
+	for i := range group.Registrations {
+		group.Registrations[i].Amount = 2500
+	}
+	order := models.Order{
+		Id: 9, GroupId: 7, UserId: 10, EventId: 1, OrganizationId: testOrgId, Amount: 5000, Currency: "EUR",
+		Status: OrderPaid, PaymentIntentId: "pi_group",
+	}
+
+	mockRepo.EXPECT().GetGroup(testOrgId, int64(7)).Return(group, nil)
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().GetPaidOrder(testOrgId, int64(101)).Return(order, nil)
+	mockRepo.EXPECT().GetCancellationPolicy(testOrgId, int64(1)).Return(models.CancellationPolicy{}, nil)
+	mockRepo.EXPECT().RecordCancellation(gomock.Any()).Return(true, nil)
+	mockProvider.EXPECT().Refund("pi_group", int64(2500)).Return(nil)
+	mockRepo.EXPECT().SetRefundStatus(gomock.Any()).Return(nil)
+
+	cancellation, err := service.CancelGroupRegistration(testOrgId, 10, 7, 101)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2500), cancellation.RefundAmount, "Only the seat's share is refunded")
+	assert.Equal(t, RefundSucceeded, cancellation.RefundStatus)
+}
+
+func TestRegisterGroup_InvalidAttendees(t *testing.T) {
+	tests := []struct {
+		name      string
+		attendees []models.GroupAttendee
+	}{
+		{"duplicate", []models.GroupAttendee{{Email: "ada@example.com", Name: "Ada"}, {Email: "ADA@example.com", Name: "Ada"}}},
+		{"guest without name", []models.GroupAttendee{{Email: "ada@example.com"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockRegisterRepository(ctrl)
+			service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+			expectTestGroupEvent(mockRepo, []models.TicketType{})
+			mockRepo.EXPECT().GetOrganizationMemberByEmail(testOrgId, gomock.Any()).Return(models.OrganizationMember{}, errors.New("no rows")).AnyTimes()
+
+			_, err := service.RegisterGroup(testOrgId, 10, 1, 0, "", tt.attendees)
+
+			assert.ErrorIs(t, err, ErrInvalidAttendees)
+		})
+	}
+}
+
+func TestRegisterGroup_AttendeeAlreadyRegistered(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	expectTestGroupEvent(mockRepo, []models.TicketType{})
+	mockRepo.EXPECT().GetOrganizationMemberByEmail(testOrgId, "colleague@example.com").
+		Return(models.OrganizationMember{OrganizationId: testOrgId, UserId: 11, Email: "colleague@example.com"}, nil)
+	mockRepo.EXPECT().GetRegisteredEventById(testOrgId, int64(11), int64(1)).Return(createTestRegisteredEvent(50, 11, 1), nil)
+
+	_, err := service.RegisterGroup(testOrgId, 10, 1, 0, "", []models.GroupAttendee{{Email: "colleague@example.com"}})
+
+	assert.ErrorIs(t, err, ErrAttendeeAlreadyRegistered)
+}
+
+func TestCancelGroupRegistration_Guest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	mockRepo.EXPECT().GetGroup(testOrgId, int64(7)).Return(createTestGroup(), nil)
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().GetPaidOrder(testOrgId, int64(101)).Return(models.Order{}, errors.New("no rows"))
+	mockRepo.EXPECT().RecordCancellation(gomock.Any()).DoAndReturn(func(c *models.Cancellation) (bool, error) {
+		assert.Equal(t, int64(101), c.RegistrationId)
+		assert.Equal(t, int64(0), c.UserId)
+		return true, nil
+	})
+
+	cancellation, err := service.CancelGroupRegistration(testOrgId, 10, 7, 101)
+
+	require.NoError(t, err)
+	assert.Equal(t, RefundNone, cancellation.RefundStatus)
+}
+
+func TestCancelGroupRegistration_OnlyBooker(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	mockRepo.EXPECT().GetGroup(testOrgId, int64(7)).Return(createTestGroup(), nil)
+
+	_, err := service.CancelGroupRegistration(testOrgId, 11, 7, 101)
+
+	assert.Equal(t, ErrGroupNotFound, err)
+}
+
+func TestCancelGroup_SkipsCancelledRegistrations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	group := createTestGroup()
+	group.Registrations[0].Status = RegistrationCancelled
+	mockRepo.EXPECT().GetGroup(testOrgId, int64(7)).Return(group, nil)
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().GetPaidOrder(testOrgId, int64(101)).Return(models.Order{}, errors.New("no rows"))
+	mockRepo.EXPECT().RecordCancellation(gomock.Any()).Return(true, nil)
+
+	cancellations, err := service.CancelGroup(testOrgId, 10, 7)
+
+	require.NoError(t, err)
+	require.Len(t, cancellations, 1)
+	assert.Equal(t, int64(101), cancellations[0].RegistrationId)
+}
+
+func TestTicketToken_GuestOfBookedGroup(t *testing.T) {
+	testutil.SetupTestEnv(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	group := createTestGroup()
+	guest := group.Registrations[1]
+	mockRepo.EXPECT().GetRegistration(testOrgId, int64(101)).Return(guest, nil).Times(2)
+	mockRepo.EXPECT().GetGroup(testOrgId, int64(7)).Return(group, nil).Times(2)
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+
+	token, err := service.TicketToken(testOrgId, 10, 101)
+
+	require.NoError(t, err)
+	ticket, err := utils.VerifyTicketToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), ticket.UserId, "The booker holds the guest's ticket")
+
+	_, err = service.TicketToken(testOrgId, 11, 101)
+	assert.Equal(t, ErrRegisterEventNotFound, err)
+}
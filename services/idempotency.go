@@ -0,0 +1,89 @@
+package services
+
+import (
+	"errors"
+	"event-booking/models"
+	"time"
+)
+
+type IdempotencyRepository interface {
+	ReserveIdempotencyKey(*models.IdempotencyKey) (bool, error)
+	GetIdempotencyKey(int64, string) (models.IdempotencyKey, error)
+	CompleteIdempotencyKey(*models.IdempotencyKey) error
+	DeleteIdempotencyKey(int64, string) error
+	DeleteExpiredIdempotencyKeys(time.Time) error
+}
+
+type IdempotencyService struct {
+	repo IdempotencyRepository
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+var ErrIdempotencyKeyReused = errors.New("Idempotency key has already been used for a different request")
+var ErrIdempotencyKeyInProgress = errors.New("A request with this idempotency key is still being processed")
+
+func NewIdempotencyService(repo IdempotencyRepository, ttl time.Duration) *IdempotencyService {
+	return &IdempotencyService{
+		repo: repo,
+		ttl:  ttl,
+		now:  time.Now,
+	}
+}
+
+// Begin reserves key for the user. When the key was already used for the same
+// request and its response has been stored, the stored record is returned with
+// replay set to true.
+func (s *IdempotencyService) Begin(userId int64, key, requestHash string) (models.IdempotencyKey, bool, error) {
+	now := s.now()
+	err := s.repo.DeleteExpiredIdempotencyKeys(now.Add(-s.ttl))
+	if err != nil {
+		return models.IdempotencyKey{}, false, err
+	}
+
+	record := models.IdempotencyKey{
+		UserId:      userId,
+		Key:         key,
+		RequestHash: requestHash,
+		CreatedAt:   now,
+	}
+	created, err := s.repo.ReserveIdempotencyKey(&record)
+	if err != nil {
+		return models.IdempotencyKey{}, false, err
+	}
+	if created {
+		return record, false, nil
+	}
+
+	existing, err := s.repo.GetIdempotencyKey(userId, key)
+	if err != nil {
+		return models.IdempotencyKey{}, false, err
+	}
+
+	if existing.RequestHash != requestHash {
+		return models.IdempotencyKey{}, false, ErrIdempotencyKeyReused
+	}
+
+	if !existing.Completed {
+		return models.IdempotencyKey{}, false, ErrIdempotencyKeyInProgress
+	}
+
+	return existing, true, nil
+}
+
+func (s *IdempotencyService) Complete(record *models.IdempotencyKey) error {
+	err := s.repo.CompleteIdempotencyKey(record)
+	if err != nil {
+		return err
+	}
+
+	record.Completed = true
+	return nil
+}
+
+// Release drops a reserved key so the request can be retried with it.
+func (s *IdempotencyService) Release(userId int64, key string) error {
+	return s.repo.DeleteIdempotencyKey(userId, key)
+}
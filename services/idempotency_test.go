@@ -0,0 +1,155 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"event-booking/models"
+	"event-booking/services/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newTestIdempotencyService(repo IdempotencyRepository, now time.Time) *IdempotencyService {
+	service := NewIdempotencyService(repo, time.Hour)
+	service.now = func() time.Time { return now }
+	return service
+}
+
+func TestIdempotencyBegin_NewKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	mockRepo := mocks.NewMockIdempotencyRepository(ctrl)
+	service := newTestIdempotencyService(mockRepo, now)
+
+	mockRepo.EXPECT().DeleteExpiredIdempotencyKeys(now.Add(-time.Hour)).Return(nil)
+	mockRepo.EXPECT().ReserveIdempotencyKey(gomock.Any()).Return(true, nil)
+
+	record, replay, err := service.Begin(1, "key-1", "hash")
+
+	require.NoError(t, err)
+	assert.False(t, replay)
+	assert.Equal(t, int64(1), record.UserId)
+	assert.Equal(t, "key-1", record.Key)
+	assert.Equal(t, "hash", record.RequestHash)
+	assert.Equal(t, now, record.CreatedAt)
+}
+
+func TestIdempotencyBegin_ReplaysCompletedRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	mockRepo := mocks.NewMockIdempotencyRepository(ctrl)
+	service := newTestIdempotencyService(mockRepo, now)
+
+	stored := models.IdempotencyKey{
+		UserId:       1,
+		Key:          "key-1",
+		RequestHash:  "hash",
+		StatusCode:   201,
+		ResponseBody: []byte(`{"message":"ok"}`),
+		Completed:    true,
+	}
+
+	mockRepo.EXPECT().DeleteExpiredIdempotencyKeys(gomock.Any()).Return(nil)
+	mockRepo.EXPECT().ReserveIdempotencyKey(gomock.Any()).Return(false, nil)
+	mockRepo.EXPECT().GetIdempotencyKey(int64(1), "key-1").Return(stored, nil)
+
+	record, replay, err := service.Begin(1, "key-1", "hash")
+
+	require.NoError(t, err)
+	assert.True(t, replay)
+	assert.Equal(t, stored, record)
+}
+
+func TestIdempotencyBegin_DifferentRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIdempotencyRepository(ctrl)
+	service := newTestIdempotencyService(mockRepo, time.Now())
+
+	stored := models.IdempotencyKey{UserId: 1, Key: "key-1", RequestHash: "other-hash", Completed: true}
+
+	mockRepo.EXPECT().DeleteExpiredIdempotencyKeys(gomock.Any()).Return(nil)
+	mockRepo.EXPECT().ReserveIdempotencyKey(gomock.Any()).Return(false, nil)
+	mockRepo.EXPECT().GetIdempotencyKey(int64(1), "key-1").Return(stored, nil)
+
+	_, replay, err := service.Begin(1, "key-1", "hash")
+
+	require.Error(t, err)
+	assert.Equal(t, ErrIdempotencyKeyReused, err)
+	assert.False(t, replay)
+}
+
+func TestIdempotencyBegin_InProgress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIdempotencyRepository(ctrl)
+	service := newTestIdempotencyService(mockRepo, time.Now())
+
+	stored := models.IdempotencyKey{UserId: 1, Key: "key-1", RequestHash: "hash"}
+
+	mockRepo.EXPECT().DeleteExpiredIdempotencyKeys(gomock.Any()).Return(nil)
+	mockRepo.EXPECT().ReserveIdempotencyKey(gomock.Any()).Return(false, nil)
+	mockRepo.EXPECT().GetIdempotencyKey(int64(1), "key-1").Return(stored, nil)
+
+	_, _, err := service.Begin(1, "key-1", "hash")
+
+	require.Error(t, err)
+	assert.Equal(t, ErrIdempotencyKeyInProgress, err)
+}
+
+func TestIdempotencyBegin_RepositoryError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIdempotencyRepository(ctrl)
+	service := newTestIdempotencyService(mockRepo, time.Now())
+
+	expectedError := errors.New("database error")
+	mockRepo.EXPECT().DeleteExpiredIdempotencyKeys(gomock.Any()).Return(nil)
+	mockRepo.EXPECT().ReserveIdempotencyKey(gomock.Any()).Return(false, expectedError)
+
+	_, _, err := service.Begin(1, "key-1", "hash")
+
+	require.Error(t, err)
+	assert.Equal(t, expectedError, err)
+}
+
+func TestIdempotencyComplete_MarksCompleted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIdempotencyRepository(ctrl)
+	service := newTestIdempotencyService(mockRepo, time.Now())
+
+	record := models.IdempotencyKey{UserId: 1, Key: "key-1", StatusCode: 201}
+	mockRepo.EXPECT().CompleteIdempotencyKey(&record).Return(nil)
+
+	err := service.Complete(&record)
+
+	require.NoError(t, err)
+	assert.True(t, record.Completed)
+}
+
+func TestIdempotencyRelease(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIdempotencyRepository(ctrl)
+	service := newTestIdempotencyService(mockRepo, time.Now())
+
+	mockRepo.EXPECT().DeleteIdempotencyKey(int64(1), "key-1").Return(nil)
+
+	err := service.Release(1, "key-1")
+
+	require.NoError(t, err)
+}
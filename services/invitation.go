@@ -0,0 +1,249 @@
+package services
+
+import (
+	"errors"
+	"event-booking/models"
+	"event-booking/utils"
+	"fmt"
+	"log"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+type EventInvitationRepository interface {
+	GetEventById(int64, int64) (models.Event, error)
+	GetEventMember(int64, int64, int64) (models.EventMember, error)
+	GetMembership(int64, int64) (models.Membership, error)
+	GetUserById(int64) (models.User, error)
+	CreateEventInvitation(*models.EventInvitation) error
+	AcceptEventInvitation(*models.EventInvitation) error
+	GetEventInvitations(int64, int64) ([]models.EventInvitation, error)
+	DeleteEventInvitation(int64, int64, int64) (bool, error)
+}
+
+// eventInviteTTL is how long invite links and emailed invitations can be
+// accepted.
+const eventInviteTTL = 7 * 24 * time.Hour
+
+var ErrInvalidInvitation = errors.New("Invitation is invalid or has expired")
+var ErrInvitationNotFound = errors.New("Invitation not found")
+var ErrInvitationEmailNotSent = errors.New("Invitation email could not be sent to every address")
+
+// EventInvitationService invites users to events, which gives them access to
+// private events. Invitees must be members of the event's organization.
+type EventInvitationService struct {
+	repo   EventInvitationRepository
+	mailer Mailer
+	appURL string
+	now    func() time.Time
+}
+
+func NewEventInvitationService(repo EventInvitationRepository, mailer Mailer, appURL string) *EventInvitationService {
+	return &EventInvitationService{
+		repo:   repo,
+		mailer: mailer,
+		appURL: strings.TrimSuffix(appURL, "/"),
+		now:    time.Now,
+	}
+}
+
+// authorize returns the event when userId may invite people to it, which
+// the owner and the co-organizers can.
+func (s *EventInvitationService) authorize(orgId, eventId, userId int64) (models.Event, error) {
+	return authorizeEventRole(s.repo, orgId, eventId, userId, EventRoleOwner, EventRoleCoOrganizer)
+}
+
+// CreateInviteLink returns a link that lets any member of the organization
+// who opens it access the event until the link expires.
+func (s *EventInvitationService) CreateInviteLink(orgId, eventId, userId int64) (string, time.Time, error) {
+	_, err := s.authorize(orgId, eventId, userId)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	expiresAt := s.now().Add(eventInviteTTL)
+	link, err := s.inviteLink(utils.EventInvite{InviterId: userId, OrganizationId: orgId, EventId: eventId}, expiresAt)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return link, expiresAt, nil
+}
+
+// InviteByEmail invites every address in emails and mails each a personal
+// invite link. Inviting an address again sends the email again. All
+// invitations are stored even when some emails could not be sent.
+func (s *EventInvitationService) InviteByEmail(orgId, eventId, userId int64, emails []string) ([]models.EventInvitation, error) {
+	event, err := s.authorize(orgId, eventId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	invitations := []models.EventInvitation{}
+	var sendFailed bool
+	for _, email := range normalizeEmails(emails) {
+		invitation := models.EventInvitation{
+			EventId:        eventId,
+			OrganizationId: orgId,
+			Email:          email,
+			InvitedBy:      userId,
+			CreatedAt:      now,
+		}
+		err = s.repo.CreateEventInvitation(&invitation)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, invitation)
+
+		err = s.sendInvitation(event, userId, email, now.Add(eventInviteTTL))
+		if err != nil {
+			log.Printf("sending invitation for event %d failed: %v", eventId, err)
+			sendFailed = true
+		}
+	}
+
+	if sendFailed {
+		return invitations, ErrInvitationEmailNotSent
+	}
+	return invitations, nil
+}
+
+func (s *EventInvitationService) List(orgId, eventId, userId int64) ([]models.EventInvitation, error) {
+	_, err := s.authorize(orgId, eventId, userId)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetEventInvitations(orgId, eventId)
+}
+
+// Revoke removes an invitation. Emailed links stop working, but an invite
+// link stays usable until it expires.
+func (s *EventInvitationService) Revoke(orgId, eventId, userId, id int64) error {
+	_, err := s.authorize(orgId, eventId, userId)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.repo.DeleteEventInvitation(orgId, eventId, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrInvitationNotFound
+	}
+	return nil
+}
+
+// Accept gives userId access to the event of an invite token. Emailed
+// invitations can only be accepted by the invited address, and only members
+// of the event's organization can accept.
+func (s *EventInvitationService) Accept(userId int64, token string) (models.Event, error) {
+	invite, err := utils.VerifyEventInviteToken(token)
+	if err != nil {
+		return models.Event{}, ErrInvalidInvitation
+	}
+
+	_, err = s.repo.GetMembership(invite.OrganizationId, userId)
+	if err != nil {
+		return models.Event{}, ErrInvalidInvitation
+	}
+
+	event, err := s.repo.GetEventById(invite.OrganizationId, invite.EventId)
+	if err != nil {
+		return models.Event{}, ErrInvalidInvitation
+	}
+
+	if invite.Email != "" {
+		u, err := s.repo.GetUserById(userId)
+		if err != nil {
+			return models.Event{}, err
+		}
+		if !strings.EqualFold(u.Email, invite.Email) {
+			return models.Event{}, ErrInvalidInvitation
+		}
+	}
+
+	err = s.repo.AcceptEventInvitation(&models.EventInvitation{
+		EventId:        event.Id,
+		OrganizationId: event.OrganizationId,
+		Email:          invite.Email,
+		UserId:         userId,
+		InvitedBy:      invite.InviterId,
+		CreatedAt:      s.now(),
+	})
+	if err != nil {
+		if invite.Email != "" {
+			return models.Event{}, ErrInvalidInvitation
+		}
+		return models.Event{}, err
+	}
+	return event, nil
+}
+
+// CheckInvitation checks that an invite token can still be accepted, so that
+// the link can be opened before logging in. Revoked email invitations cannot.
+func (s *EventInvitationService) CheckInvitation(token string) error {
+	invite, err := utils.VerifyEventInviteToken(token)
+	if err != nil {
+		return ErrInvalidInvitation
+	}
+
+	_, err = s.repo.GetEventById(invite.OrganizationId, invite.EventId)
+	if err != nil {
+		return ErrInvalidInvitation
+	}
+	if invite.Email == "" {
+		return nil
+	}
+
+	invitations, err := s.repo.GetEventInvitations(invite.OrganizationId, invite.EventId)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(invitations, func(i models.EventInvitation) bool { return strings.EqualFold(i.Email, invite.Email) }) {
+		return ErrInvalidInvitation
+	}
+	return nil
+}
+
+func (s *EventInvitationService) inviteLink(invite utils.EventInvite, expiresAt time.Time) (string, error) {
+	token, err := utils.GenerateEventInviteToken(invite, expiresAt)
+	if err != nil {
+		return "", err
+	}
+	return s.appURL + "/invitations/accept?token=" + url.QueryEscape(token), nil
+}
+
+func (s *EventInvitationService) sendInvitation(event models.Event, inviterId int64, email string, expiresAt time.Time) error {
+	link, err := s.inviteLink(utils.EventInvite{
+		InviterId:      inviterId,
+		OrganizationId: event.OrganizationId,
+		EventId:        event.Id,
+		Email:          email,
+	}, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("You have been invited to %s on %s in %s.\n\nAccept the invitation with the link below.\n\n%s\n\nThe link expires in 7 days.",
+		event.Name, event.DateTime.Format("Monday, January 2, 2006 15:04 MST"), event.Location, link)
+	return s.mailer.Send(email, "Invitation: "+event.Name, body)
+}
+
+// normalizeEmails trims the addresses and removes duplicates.
+func normalizeEmails(emails []string) []string {
+	normalized := []string{}
+	seen := map[string]bool{}
+	for _, email := range emails {
+		email = strings.TrimSpace(email)
+		key := strings.ToLower(email)
+		if email == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, email)
+	}
+	return normalized
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"event-booking/models"
+	"event-booking/services/mocks"
+	"event-booking/testutil"
+	"event-booking/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func inviteTokenFromLink(t *testing.T, link string) string {
+	parsed, err := url.Parse(link)
+	require.NoError(t, err)
+	return parsed.Query().Get("token")
+}
+
+func TestInviteByEmail_SendsPersonalLinks(t *testing.T) {
+	testutil.SetupTestEnv(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventInvitationRepository(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	service := NewEventInvitationService(mockRepo, mockMailer, testAppURL)
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 10), nil)
+	mockRepo.EXPECT().CreateEventInvitation(gomock.Any()).DoAndReturn(func(i *models.EventInvitation) error {
+		assert.Equal(t, "guest@example.com", i.Email)
+		assert.Equal(t, int64(10), i.InvitedBy)
+		i.Id = 7
+		return nil
+	})
+	mockMailer.EXPECT().Send("guest@example.com", gomock.Any(), gomock.Any()).DoAndReturn(func(to, subject, body string) error {
+		start := strings.Index(body, testAppURL+"/invitations/accept?token=")
+		require.GreaterOrEqual(t, start, 0)
+		link := strings.Fields(body[start:])[0]
+
+		invite, err := utils.VerifyEventInviteToken(inviteTokenFromLink(t, link))
+		require.NoError(t, err)
+		assert.Equal(t, "guest@example.com", invite.Email)
+		assert.Equal(t, int64(1), invite.EventId)
+		assert.Equal(t, testOrgId, invite.OrganizationId)
+		return nil
+	})
+
+	invitations, err := service.InviteByEmail(testOrgId, 1, 10, []string{"guest@example.com", " Guest@example.com ", ""})
+
+	require.NoError(t, err)
+	require.Len(t, invitations, 1)
+	assert.Equal(t, int64(7), invitations[0].Id)
+}
+
+func TestInviteByEmail_MailerFailure(t *testing.T) {
+	testutil.SetupTestEnv(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventInvitationRepository(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	service := NewEventInvitationService(mockRepo, mockMailer, testAppURL)
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 10), nil)
+	mockRepo.EXPECT().CreateEventInvitation(gomock.Any()).Return(nil)
+	mockMailer.EXPECT().Send("guest@example.com", gomock.Any(), gomock.Any()).Return(errors.New("smtp down"))
+
+	invitations, err := service.InviteByEmail(testOrgId, 1, 10, []string{"guest@example.com"})
+
+	assert.Equal(t, ErrInvitationEmailNotSent, err)
+	assert.Len(t, invitations, 1, "Invitations are stored even when the email fails")
+}
+
+func TestInviteByEmail_CheckInStaffForbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventInvitationRepository(ctrl)
+	service := NewEventInvitationService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 10), nil)
+	mockRepo.EXPECT().GetEventMember(testOrgId, int64(1), int64(20)).Return(models.EventMember{Role: EventRoleCheckInStaff}, nil)
+
+	_, err := service.InviteByEmail(testOrgId, 1, 20, []string{"guest@example.com"})
+
+	assert.Equal(t, ErrForbidden, err)
+}
+
+func TestAccept_InviteLink(t *testing.T) {
+	testutil.SetupTestEnv(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventInvitationRepository(ctrl)
+	service := NewEventInvitationService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
+
+	event := createTestEvent(1, 10)
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(event, nil).Times(2)
+	link, expiresAt, err := service.CreateInviteLink(testOrgId, 1, 10)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(eventInviteTTL), expiresAt, time.Minute)
+
+	mockRepo.EXPECT().GetMembership(testOrgId, int64(30)).Return(createTestMembership(testOrgId, RoleMember), nil)
+	mockRepo.EXPECT().AcceptEventInvitation(gomock.Any()).DoAndReturn(func(i *models.EventInvitation) error {
+		assert.Equal(t, int64(30), i.UserId)
+		assert.Equal(t, int64(10), i.InvitedBy)
+		assert.Empty(t, i.Email)
+		return nil
+	})
+
+	accepted, err := service.Accept(30, inviteTokenFromLink(t, link))
+
+	require.NoError(t, err)
+	assert.Equal(t, event.Id, accepted.Id)
+}
+
+func TestAccept_EmailInvitationForOtherUser(t *testing.T) {
+	testutil.SetupTestEnv(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventInvitationRepository(ctrl)
+	service := NewEventInvitationService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
+
+	token, err := utils.GenerateEventInviteToken(utils.EventInvite{
+		InviterId: 10, OrganizationId: testOrgId, EventId: 1, Email: "guest@example.com",
+	}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	mockRepo.EXPECT().GetMembership(testOrgId, int64(30)).Return(createTestMembership(testOrgId, RoleMember), nil)
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 10), nil)
+	mockRepo.EXPECT().GetUserById(int64(30)).Return(models.User{Id: 30, Email: "someone@example.com"}, nil)
+
+	_, err = service.Accept(30, token)
+
+	assert.Equal(t, ErrInvalidInvitation, err)
+}
+
+func TestCheckInvitation_RevokedEmailInvitation(t *testing.T) {
+	testutil.SetupTestEnv(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventInvitationRepository(ctrl)
+	service := NewEventInvitationService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
+
+	token, err := utils.GenerateEventInviteToken(utils.EventInvite{
+		InviterId: 10, OrganizationId: testOrgId, EventId: 1, Email: "guest@example.com",
+	}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 10), nil).Times(2)
+	gomock.InOrder(
+		mockRepo.EXPECT().GetEventInvitations(testOrgId, int64(1)).Return([]models.EventInvitation{
+			{Id: 7, EventId: 1, Email: "Guest@example.com"},
+		}, nil),
+		mockRepo.EXPECT().GetEventInvitations(testOrgId, int64(1)).Return([]models.EventInvitation{}, nil),
+	)
+
+	require.NoError(t, service.CheckInvitation(token))
+	assert.Equal(t, ErrInvalidInvitation, service.CheckInvitation(token), "Revoked invitations are invalid")
+	assert.Equal(t, ErrInvalidInvitation, service.CheckInvitation("not-a-token"))
+}
+
+func TestAccept_NotInOrganization(t *testing.T) {
+	testutil.SetupTestEnv(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventInvitationRepository(ctrl)
+	service := NewEventInvitationService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
+
+	token, err := utils.GenerateEventInviteToken(utils.EventInvite{
+		InviterId: 10, OrganizationId: testOrgId, EventId: 1,
+	}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	mockRepo.EXPECT().GetMembership(testOrgId, int64(30)).Return(models.Membership{}, errors.New("no rows"))
+
+	_, err = service.Accept(30, token)
+
+	assert.Equal(t, ErrInvalidInvitation, err)
+}
+
+func TestRevoke_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventInvitationRepository(ctrl)
+	service := NewEventInvitationService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 10), nil)
+	mockRepo.EXPECT().DeleteEventInvitation(testOrgId, int64(1), int64(7)).Return(false, nil)
+
+	err := service.Revoke(testOrgId, 1, 10, 7)
+
+	assert.Equal(t, ErrInvitationNotFound, err)
+}
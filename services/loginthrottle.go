@@ -0,0 +1,190 @@
+package services
+
+import (
+	"errors"
+	"event-booking/models"
+	"event-booking/utils"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type LoginAttemptRepository interface {
+	GetLoginAttempt(string, string) (models.LoginAttempt, error)
+	RecordLoginFailure(string, string, time.Time, time.Time) (int, error)
+	LockLoginAttempt(string, string, time.Time) error
+	DeleteLoginAttempt(string, string) error
+}
+
+// throttlePolicy describes how many failures a subject gets for free and how
+// the lockout grows after that.
+type throttlePolicy struct {
+	freeAttempts int
+	baseLockout  time.Duration
+	maxLockout   time.Duration
+}
+
+// lockout doubles the lockout with every failure past the free attempts.
+func (p throttlePolicy) lockout(failures int) time.Duration {
+	if failures <= p.freeAttempts {
+		return 0
+	}
+
+	lockout := p.baseLockout
+	for i := p.freeAttempts + 1; i < failures && lockout < p.maxLockout; i++ {
+		lockout *= 2
+	}
+	return min(lockout, p.maxLockout)
+}
+
+const (
+	throttleScopeAccount   = "account"
+	throttleScopeIP        = "ip"
+	throttleScopeTwoFactor = "mfa_user"
+
+	// failureWindow is how long failures are remembered after the last one.
+	failureWindow = time.Hour
+)
+
+var throttlePolicies = map[string]throttlePolicy{
+	throttleScopeAccount:   {freeAttempts: 5, baseLockout: 30 * time.Second, maxLockout: 15 * time.Minute},
+	throttleScopeIP:        {freeAttempts: 20, baseLockout: 30 * time.Second, maxLockout: 15 * time.Minute},
+	throttleScopeTwoFactor: {freeAttempts: 5, baseLockout: 30 * time.Second, maxLockout: 15 * time.Minute},
+}
+
+var ErrTooManyLoginAttempts = errors.New("Too many failed login attempts, please try again later")
+
+type throttleSubject struct {
+	scope string
+	key   string
+}
+
+// LoginThrottleService slows down password guessing by tracking failed logins
+// per account and per client IP.
+type LoginThrottleService struct {
+	repo  LoginAttemptRepository
+	audit *AuditService
+	now   func() time.Time
+}
+
+func NewLoginThrottleService(repo LoginAttemptRepository, audit *AuditService) *LoginThrottleService {
+	return &LoginThrottleService{
+		repo:  repo,
+		audit: audit,
+		now:   time.Now,
+	}
+}
+
+// CheckLogin returns ErrTooManyLoginAttempts and the time until the next
+// attempt is allowed when the account or the IP is locked.
+func (s *LoginThrottleService) CheckLogin(email, ip string) (time.Duration, error) {
+	return s.check(accountSubject(email), ipSubject(ip))
+}
+
+func (s *LoginThrottleService) RecordLoginFailure(email, ip string) error {
+	return s.recordFailure(ip, accountSubject(email), ipSubject(ip))
+}
+
+// RecordLoginSuccess clears the failures of the account. Failures of the IP
+// are kept so one valid account cannot be used to reset them.
+func (s *LoginThrottleService) RecordLoginSuccess(email string) error {
+	subject := accountSubject(email)
+	return s.repo.DeleteLoginAttempt(subject.scope, subject.key)
+}
+
+// CheckTwoFactor is CheckLogin for the second step of a two-step login.
+// Attempts are tracked per user of the challenge token, so that fetching a
+// new challenge does not restart the count, and per IP. Invalid challenges
+// only count against the IP.
+func (s *LoginThrottleService) CheckTwoFactor(challenge, ip string) (time.Duration, error) {
+	return s.check(twoFactorSubjects(challenge, ip)...)
+}
+
+func (s *LoginThrottleService) RecordTwoFactorFailure(challenge, ip string) error {
+	return s.recordFailure(ip, twoFactorSubjects(challenge, ip)...)
+}
+
+// RecordTwoFactorSuccess clears the failures of the account and of its
+// second factor once both steps of a login have passed.
+func (s *LoginThrottleService) RecordTwoFactorSuccess(user models.User) error {
+	subject := twoFactorSubject(user.Id)
+	err := s.repo.DeleteLoginAttempt(subject.scope, subject.key)
+	if err != nil {
+		return err
+	}
+	return s.RecordLoginSuccess(user.Email)
+}
+
+func (s *LoginThrottleService) check(subjects ...throttleSubject) (time.Duration, error) {
+	now := s.now()
+	var retryAfter time.Duration
+	for _, subject := range subjects {
+		attempt, err := s.repo.GetLoginAttempt(subject.scope, subject.key)
+		if err != nil {
+			return 0, err
+		}
+		if attempt.LockedUntil.After(now) {
+			retryAfter = max(retryAfter, attempt.LockedUntil.Sub(now))
+		}
+	}
+
+	if retryAfter > 0 {
+		return retryAfter, ErrTooManyLoginAttempts
+	}
+	return 0, nil
+}
+
+func (s *LoginThrottleService) recordFailure(ip string, subjects ...throttleSubject) error {
+	now := s.now()
+	for _, subject := range subjects {
+		failures, err := s.repo.RecordLoginFailure(subject.scope, subject.key, now, now.Add(-failureWindow))
+		if err != nil {
+			return err
+		}
+
+		lockout := throttlePolicies[subject.scope].lockout(failures)
+		if lockout == 0 {
+			continue
+		}
+
+		lockedUntil := now.Add(lockout)
+		err = s.repo.LockLoginAttempt(subject.scope, subject.key, lockedUntil)
+		if err != nil {
+			return err
+		}
+
+		err = s.audit.Record(&models.AuditEvent{
+			Action:    AuditLoginLockout,
+			Subject:   subject.scope + ":" + subject.key,
+			IP:        ip,
+			Details:   fmt.Sprintf("failures=%d locked_until=%s", failures, lockedUntil.UTC().Format(time.RFC3339)),
+			CreatedAt: now,
+		})
+		if err != nil {
+			log.Printf("could not record lockout in audit trail: %v", err)
+		}
+	}
+	return nil
+}
+
+func accountSubject(email string) throttleSubject {
+	return throttleSubject{scope: throttleScopeAccount, key: strings.ToLower(strings.TrimSpace(email))}
+}
+
+func ipSubject(ip string) throttleSubject {
+	return throttleSubject{scope: throttleScopeIP, key: ip}
+}
+
+func twoFactorSubject(userId int64) throttleSubject {
+	return throttleSubject{scope: throttleScopeTwoFactor, key: strconv.FormatInt(userId, 10)}
+}
+
+func twoFactorSubjects(challenge, ip string) []throttleSubject {
+	userId, err := utils.VerifyMFAChallengeToken(challenge)
+	if err != nil {
+		return []throttleSubject{ipSubject(ip)}
+	}
+	return []throttleSubject{twoFactorSubject(userId), ipSubject(ip)}
+}
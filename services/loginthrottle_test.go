@@ -0,0 +1,188 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"event-booking/models"
+	"event-booking/services/mocks"
+	"event-booking/testutil"
+	"event-booking/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newTestLoginThrottleService(ctrl *gomock.Controller, now time.Time) (*LoginThrottleService, *mocks.MockLoginAttemptRepository, *mocks.MockAuditRepository) {
+	mockRepo := mocks.NewMockLoginAttemptRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	service := NewLoginThrottleService(mockRepo, NewAuditService(mockAudit))
+	service.now = func() time.Time { return now }
+	return service, mockRepo, mockAudit
+}
+
+func TestThrottlePolicy_Lockout(t *testing.T) {
+	policy := throttlePolicy{freeAttempts: 5, baseLockout: 30 * time.Second, maxLockout: 15 * time.Minute}
+
+	assert.Equal(t, time.Duration(0), policy.lockout(5))
+	assert.Equal(t, 30*time.Second, policy.lockout(6))
+	assert.Equal(t, time.Minute, policy.lockout(7))
+	assert.Equal(t, 2*time.Minute, policy.lockout(8))
+	assert.Equal(t, 15*time.Minute, policy.lockout(20))
+	assert.Equal(t, 15*time.Minute, policy.lockout(1000))
+}
+
+func TestCheckLogin_NotLocked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	service, mockRepo, _ := newTestLoginThrottleService(ctrl, now)
+
+	mockRepo.EXPECT().GetLoginAttempt("account", "test@example.com").Return(models.LoginAttempt{Failures: 3}, nil)
+	mockRepo.EXPECT().GetLoginAttempt("ip", "127.0.0.1").Return(models.LoginAttempt{}, nil)
+
+	retryAfter, err := service.CheckLogin("Test@Example.com", "127.0.0.1")
+
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(0), retryAfter)
+}
+
+func TestCheckLogin_ReturnsLongestLockout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	service, mockRepo, _ := newTestLoginThrottleService(ctrl, now)
+
+	mockRepo.EXPECT().GetLoginAttempt("account", "test@example.com").Return(models.LoginAttempt{LockedUntil: now.Add(30 * time.Second)}, nil)
+	mockRepo.EXPECT().GetLoginAttempt("ip", "127.0.0.1").Return(models.LoginAttempt{LockedUntil: now.Add(2 * time.Minute)}, nil)
+
+	retryAfter, err := service.CheckLogin("test@example.com", "127.0.0.1")
+
+	require.Error(t, err)
+	assert.Equal(t, ErrTooManyLoginAttempts, err)
+	assert.Equal(t, 2*time.Minute, retryAfter)
+}
+
+func TestCheckLogin_ExpiredLockout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	service, mockRepo, _ := newTestLoginThrottleService(ctrl, now)
+
+	mockRepo.EXPECT().GetLoginAttempt("account", "test@example.com").Return(models.LoginAttempt{Failures: 6, LockedUntil: now.Add(-time.Second)}, nil)
+	mockRepo.EXPECT().GetLoginAttempt("ip", "127.0.0.1").Return(models.LoginAttempt{}, nil)
+
+	_, err := service.CheckLogin("test@example.com", "127.0.0.1")
+
+	require.NoError(t, err)
+}
+
+func TestRecordLoginFailure_BelowThreshold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	service, mockRepo, _ := newTestLoginThrottleService(ctrl, now)
+
+	mockRepo.EXPECT().RecordLoginFailure("account", "test@example.com", now, now.Add(-failureWindow)).Return(5, nil)
+	mockRepo.EXPECT().RecordLoginFailure("ip", "127.0.0.1", now, now.Add(-failureWindow)).Return(5, nil)
+
+	err := service.RecordLoginFailure("test@example.com", "127.0.0.1")
+
+	require.NoError(t, err)
+}
+
+func TestRecordLoginFailure_LocksAccountAndAudits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	service, mockRepo, mockAudit := newTestLoginThrottleService(ctrl, now)
+
+	mockRepo.EXPECT().RecordLoginFailure("account", "test@example.com", now, now.Add(-failureWindow)).Return(7, nil)
+	mockRepo.EXPECT().LockLoginAttempt("account", "test@example.com", now.Add(time.Minute)).Return(nil)
+	mockAudit.EXPECT().CreateAuditEvent(gomock.Any()).DoAndReturn(func(e *models.AuditEvent) error {
+		assert.Equal(t, AuditLoginLockout, e.Action)
+		assert.Equal(t, "account:test@example.com", e.Subject)
+		assert.Equal(t, "127.0.0.1", e.IP)
+		assert.Equal(t, now, e.CreatedAt)
+		return nil
+	})
+	mockRepo.EXPECT().RecordLoginFailure("ip", "127.0.0.1", now, now.Add(-failureWindow)).Return(7, nil)
+
+	err := service.RecordLoginFailure("test@example.com", "127.0.0.1")
+
+	require.NoError(t, err)
+}
+
+func TestRecordLoginSuccess_ClearsAccountOnly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	service, mockRepo, _ := newTestLoginThrottleService(ctrl, now)
+
+	mockRepo.EXPECT().DeleteLoginAttempt("account", "test@example.com").Return(nil)
+
+	err := service.RecordLoginSuccess("test@example.com")
+
+	require.NoError(t, err)
+}
+
+func TestRecordTwoFactorFailure_TracksUserAcrossChallenges(t *testing.T) {
+	testutil.SetupTestEnv(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	service, mockRepo, _ := newTestLoginThrottleService(ctrl, now)
+
+	first, err := utils.GenerateMFAChallengeToken(42, time.Now().Add(5*time.Minute))
+	require.NoError(t, err)
+	second, err := utils.GenerateMFAChallengeToken(42, time.Now().Add(5*time.Minute))
+	require.NoError(t, err)
+
+	mockRepo.EXPECT().RecordLoginFailure("mfa_user", "42", now, now.Add(-failureWindow)).Return(1, nil)
+	mockRepo.EXPECT().RecordLoginFailure("ip", "127.0.0.1", now, now.Add(-failureWindow)).Return(1, nil)
+	require.NoError(t, service.RecordTwoFactorFailure(first, "127.0.0.1"))
+
+	mockRepo.EXPECT().GetLoginAttempt("mfa_user", "42").Return(models.LoginAttempt{LockedUntil: now.Add(time.Minute)}, nil)
+	mockRepo.EXPECT().GetLoginAttempt("ip", "127.0.0.1").Return(models.LoginAttempt{}, nil)
+	retryAfter, err := service.CheckTwoFactor(second, "127.0.0.1")
+	assert.Equal(t, ErrTooManyLoginAttempts, err, "A new challenge does not lift the lockout of the user")
+	assert.Equal(t, time.Minute, retryAfter)
+}
+
+func TestRecordTwoFactorFailure_InvalidChallengeCountsIPOnly(t *testing.T) {
+	testutil.SetupTestEnv(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	service, mockRepo, _ := newTestLoginThrottleService(ctrl, now)
+
+	mockRepo.EXPECT().RecordLoginFailure("ip", "127.0.0.1", now, now.Add(-failureWindow)).Return(1, nil)
+
+	err := service.RecordTwoFactorFailure("mfa-token", "127.0.0.1")
+
+	require.NoError(t, err)
+}
+
+func TestRecordTwoFactorSuccess_ClearsUserAndAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	service, mockRepo, _ := newTestLoginThrottleService(ctrl, now)
+
+	mockRepo.EXPECT().DeleteLoginAttempt("mfa_user", "42").Return(nil)
+	mockRepo.EXPECT().DeleteLoginAttempt("account", "test@example.com").Return(nil)
+
+	err := service.RecordTwoFactorSuccess(models.User{Id: 42, Email: "test@example.com"})
+
+	require.NoError(t, err)
+}
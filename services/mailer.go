@@ -0,0 +1,6 @@
+package services
+
+// Mailer delivers transactional emails such as verification links.
+type Mailer interface {
+	Send(to, subject, body string) error
+}
@@ -3,7 +3,7 @@
 //
 // Generated by this command:
 //
-//	mockgen -source=services/event.go -destination=services/mocks/mock_event_repository.go -package=mocks EventRepository
+//	mockgen -source=services/event.go -destination=services/mocks/mock_event_repository.go -package=mocks
 //
 
 // Package mocks is a generated GoMock package.
@@ -40,6 +40,35 @@ func (m *MockEventRepository) EXPECT() *MockEventRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AddEventMember mocks base method.
+func (m *MockEventRepository) AddEventMember(arg0 *models.EventMember) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddEventMember", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddEventMember indicates an expected call of AddEventMember.
+func (mr *MockEventRepositoryMockRecorder) AddEventMember(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEventMember", reflect.TypeOf((*MockEventRepository)(nil).AddEventMember), arg0)
+}
+
+// CanAccessEvent mocks base method.
+func (m *MockEventRepository) CanAccessEvent(arg0, arg1, arg2 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CanAccessEvent", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CanAccessEvent indicates an expected call of CanAccessEvent.
+func (mr *MockEventRepositoryMockRecorder) CanAccessEvent(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CanAccessEvent", reflect.TypeOf((*MockEventRepository)(nil).CanAccessEvent), arg0, arg1, arg2)
+}
+
 // CreateEvent mocks base method.
 func (m *MockEventRepository) CreateEvent(arg0 *models.Event) (int64, error) {
 	m.ctrl.T.Helper()
@@ -55,48 +84,330 @@ func (mr *MockEventRepositoryMockRecorder) CreateEvent(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEvent", reflect.TypeOf((*MockEventRepository)(nil).CreateEvent), arg0)
 }
 
-// DeleteEvent mocks base method.
-func (m *MockEventRepository) DeleteEvent(arg0 int64) error {
+// CreatePromoCode mocks base method.
+func (m *MockEventRepository) CreatePromoCode(arg0 *models.PromoCode) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteEvent", arg0)
+	ret := m.ctrl.Call(m, "CreatePromoCode", arg0)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// CreatePromoCode indicates an expected call of CreatePromoCode.
+func (mr *MockEventRepositoryMockRecorder) CreatePromoCode(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePromoCode", reflect.TypeOf((*MockEventRepository)(nil).CreatePromoCode), arg0)
+}
+
+// CreateTicketType mocks base method.
+func (m *MockEventRepository) CreateTicketType(arg0 *models.TicketType) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTicketType", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTicketType indicates an expected call of CreateTicketType.
+func (mr *MockEventRepositoryMockRecorder) CreateTicketType(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTicketType", reflect.TypeOf((*MockEventRepository)(nil).CreateTicketType), arg0)
+}
+
+// DeleteEvent mocks base method.
+func (m *MockEventRepository) DeleteEvent(arg0, arg1 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEvent", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // DeleteEvent indicates an expected call of DeleteEvent.
-func (mr *MockEventRepositoryMockRecorder) DeleteEvent(arg0 any) *gomock.Call {
+func (mr *MockEventRepositoryMockRecorder) DeleteEvent(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEvent", reflect.TypeOf((*MockEventRepository)(nil).DeleteEvent), arg0, arg1)
+}
+
+// DeletePromoCode mocks base method.
+func (m *MockEventRepository) DeletePromoCode(arg0, arg1, arg2 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePromoCode", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeletePromoCode indicates an expected call of DeletePromoCode.
+func (mr *MockEventRepositoryMockRecorder) DeletePromoCode(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePromoCode", reflect.TypeOf((*MockEventRepository)(nil).DeletePromoCode), arg0, arg1, arg2)
+}
+
+// DeleteTicketType mocks base method.
+func (m *MockEventRepository) DeleteTicketType(arg0, arg1, arg2 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTicketType", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTicketType indicates an expected call of DeleteTicketType.
+func (mr *MockEventRepositoryMockRecorder) DeleteTicketType(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTicketType", reflect.TypeOf((*MockEventRepository)(nil).DeleteTicketType), arg0, arg1, arg2)
+}
+
+// GetAttendance mocks base method.
+func (m *MockEventRepository) GetAttendance(arg0, arg1 int64) (models.EventAttendance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAttendance", arg0, arg1)
+	ret0, _ := ret[0].(models.EventAttendance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAttendance indicates an expected call of GetAttendance.
+func (mr *MockEventRepositoryMockRecorder) GetAttendance(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttendance", reflect.TypeOf((*MockEventRepository)(nil).GetAttendance), arg0, arg1)
+}
+
+// GetCancellationPolicy mocks base method.
+func (m *MockEventRepository) GetCancellationPolicy(arg0, arg1 int64) (models.CancellationPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCancellationPolicy", arg0, arg1)
+	ret0, _ := ret[0].(models.CancellationPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCancellationPolicy indicates an expected call of GetCancellationPolicy.
+func (mr *MockEventRepositoryMockRecorder) GetCancellationPolicy(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEvent", reflect.TypeOf((*MockEventRepository)(nil).DeleteEvent), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCancellationPolicy", reflect.TypeOf((*MockEventRepository)(nil).GetCancellationPolicy), arg0, arg1)
+}
+
+// GetCancellations mocks base method.
+func (m *MockEventRepository) GetCancellations(arg0, arg1 int64) ([]models.Cancellation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCancellations", arg0, arg1)
+	ret0, _ := ret[0].([]models.Cancellation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCancellations indicates an expected call of GetCancellations.
+func (mr *MockEventRepositoryMockRecorder) GetCancellations(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCancellations", reflect.TypeOf((*MockEventRepository)(nil).GetCancellations), arg0, arg1)
 }
 
 // GetEventById mocks base method.
-func (m *MockEventRepository) GetEventById(arg0 int64) (models.Event, error) {
+func (m *MockEventRepository) GetEventById(arg0, arg1 int64) (models.Event, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEventById", arg0)
+	ret := m.ctrl.Call(m, "GetEventById", arg0, arg1)
 	ret0, _ := ret[0].(models.Event)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetEventById indicates an expected call of GetEventById.
-func (mr *MockEventRepositoryMockRecorder) GetEventById(arg0 any) *gomock.Call {
+func (mr *MockEventRepositoryMockRecorder) GetEventById(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventById", reflect.TypeOf((*MockEventRepository)(nil).GetEventById), arg0, arg1)
+}
+
+// GetEventMember mocks base method.
+func (m *MockEventRepository) GetEventMember(arg0, arg1, arg2 int64) (models.EventMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventMember", arg0, arg1, arg2)
+	ret0, _ := ret[0].(models.EventMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventMember indicates an expected call of GetEventMember.
+func (mr *MockEventRepositoryMockRecorder) GetEventMember(arg0, arg1, arg2 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventById", reflect.TypeOf((*MockEventRepository)(nil).GetEventById), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventMember", reflect.TypeOf((*MockEventRepository)(nil).GetEventMember), arg0, arg1, arg2)
+}
+
+// GetEventMembers mocks base method.
+func (m *MockEventRepository) GetEventMembers(arg0, arg1 int64) ([]models.EventMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventMembers", arg0, arg1)
+	ret0, _ := ret[0].([]models.EventMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventMembers indicates an expected call of GetEventMembers.
+func (mr *MockEventRepositoryMockRecorder) GetEventMembers(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventMembers", reflect.TypeOf((*MockEventRepository)(nil).GetEventMembers), arg0, arg1)
 }
 
 // GetEvents mocks base method.
-func (m *MockEventRepository) GetEvents() ([]models.Event, error) {
+func (m *MockEventRepository) GetEvents(arg0, arg1 int64) ([]models.Event, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEvents")
+	ret := m.ctrl.Call(m, "GetEvents", arg0, arg1)
 	ret0, _ := ret[0].([]models.Event)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetEvents indicates an expected call of GetEvents.
-func (mr *MockEventRepositoryMockRecorder) GetEvents() *gomock.Call {
+func (mr *MockEventRepositoryMockRecorder) GetEvents(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvents", reflect.TypeOf((*MockEventRepository)(nil).GetEvents), arg0, arg1)
+}
+
+// GetOrganizationMemberByEmail mocks base method.
+func (m *MockEventRepository) GetOrganizationMemberByEmail(arg0 int64, arg1 string) (models.OrganizationMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganizationMemberByEmail", arg0, arg1)
+	ret0, _ := ret[0].(models.OrganizationMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganizationMemberByEmail indicates an expected call of GetOrganizationMemberByEmail.
+func (mr *MockEventRepositoryMockRecorder) GetOrganizationMemberByEmail(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvents", reflect.TypeOf((*MockEventRepository)(nil).GetEvents))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationMemberByEmail", reflect.TypeOf((*MockEventRepository)(nil).GetOrganizationMemberByEmail), arg0, arg1)
+}
+
+// GetPromoCode mocks base method.
+func (m *MockEventRepository) GetPromoCode(arg0, arg1, arg2 int64) (models.PromoCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPromoCode", arg0, arg1, arg2)
+	ret0, _ := ret[0].(models.PromoCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPromoCode indicates an expected call of GetPromoCode.
+func (mr *MockEventRepositoryMockRecorder) GetPromoCode(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPromoCode", reflect.TypeOf((*MockEventRepository)(nil).GetPromoCode), arg0, arg1, arg2)
+}
+
+// GetPromoCodeByCode mocks base method.
+func (m *MockEventRepository) GetPromoCodeByCode(arg0, arg1 int64, arg2 string) (models.PromoCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPromoCodeByCode", arg0, arg1, arg2)
+	ret0, _ := ret[0].(models.PromoCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPromoCodeByCode indicates an expected call of GetPromoCodeByCode.
+func (mr *MockEventRepositoryMockRecorder) GetPromoCodeByCode(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPromoCodeByCode", reflect.TypeOf((*MockEventRepository)(nil).GetPromoCodeByCode), arg0, arg1, arg2)
+}
+
+// GetPromoCodes mocks base method.
+func (m *MockEventRepository) GetPromoCodes(arg0, arg1 int64) ([]models.PromoCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPromoCodes", arg0, arg1)
+	ret0, _ := ret[0].([]models.PromoCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPromoCodes indicates an expected call of GetPromoCodes.
+func (mr *MockEventRepositoryMockRecorder) GetPromoCodes(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPromoCodes", reflect.TypeOf((*MockEventRepository)(nil).GetPromoCodes), arg0, arg1)
+}
+
+// GetRegistrationQuestions mocks base method.
+func (m *MockEventRepository) GetRegistrationQuestions(arg0, arg1 int64) ([]models.RegistrationQuestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRegistrationQuestions", arg0, arg1)
+	ret0, _ := ret[0].([]models.RegistrationQuestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRegistrationQuestions indicates an expected call of GetRegistrationQuestions.
+func (mr *MockEventRepositoryMockRecorder) GetRegistrationQuestions(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegistrationQuestions", reflect.TypeOf((*MockEventRepository)(nil).GetRegistrationQuestions), arg0, arg1)
+}
+
+// GetTicketType mocks base method.
+func (m *MockEventRepository) GetTicketType(arg0, arg1, arg2 int64) (models.TicketType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTicketType", arg0, arg1, arg2)
+	ret0, _ := ret[0].(models.TicketType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTicketType indicates an expected call of GetTicketType.
+func (mr *MockEventRepositoryMockRecorder) GetTicketType(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTicketType", reflect.TypeOf((*MockEventRepository)(nil).GetTicketType), arg0, arg1, arg2)
+}
+
+// GetTicketTypes mocks base method.
+func (m *MockEventRepository) GetTicketTypes(arg0, arg1 int64) ([]models.TicketType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTicketTypes", arg0, arg1)
+	ret0, _ := ret[0].([]models.TicketType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTicketTypes indicates an expected call of GetTicketTypes.
+func (mr *MockEventRepositoryMockRecorder) GetTicketTypes(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTicketTypes", reflect.TypeOf((*MockEventRepository)(nil).GetTicketTypes), arg0, arg1)
+}
+
+// RemoveEventMember mocks base method.
+func (m *MockEventRepository) RemoveEventMember(arg0, arg1, arg2 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveEventMember", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveEventMember indicates an expected call of RemoveEventMember.
+func (mr *MockEventRepositoryMockRecorder) RemoveEventMember(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveEventMember", reflect.TypeOf((*MockEventRepository)(nil).RemoveEventMember), arg0, arg1, arg2)
+}
+
+// ReplaceRegistrationQuestions mocks base method.
+func (m *MockEventRepository) ReplaceRegistrationQuestions(arg0, arg1 int64, arg2 []models.RegistrationQuestion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceRegistrationQuestions", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceRegistrationQuestions indicates an expected call of ReplaceRegistrationQuestions.
+func (mr *MockEventRepositoryMockRecorder) ReplaceRegistrationQuestions(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceRegistrationQuestions", reflect.TypeOf((*MockEventRepository)(nil).ReplaceRegistrationQuestions), arg0, arg1, arg2)
+}
+
+// SetCancellationPolicy mocks base method.
+func (m *MockEventRepository) SetCancellationPolicy(arg0 *models.CancellationPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCancellationPolicy", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCancellationPolicy indicates an expected call of SetCancellationPolicy.
+func (mr *MockEventRepositoryMockRecorder) SetCancellationPolicy(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCancellationPolicy", reflect.TypeOf((*MockEventRepository)(nil).SetCancellationPolicy), arg0)
 }
 
 // UpdateEvent mocks base method.
@@ -112,3 +423,70 @@ func (mr *MockEventRepositoryMockRecorder) UpdateEvent(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEvent", reflect.TypeOf((*MockEventRepository)(nil).UpdateEvent), arg0)
 }
+
+// UpdatePromoCode mocks base method.
+func (m *MockEventRepository) UpdatePromoCode(arg0 *models.PromoCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePromoCode", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePromoCode indicates an expected call of UpdatePromoCode.
+func (mr *MockEventRepositoryMockRecorder) UpdatePromoCode(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePromoCode", reflect.TypeOf((*MockEventRepository)(nil).UpdatePromoCode), arg0)
+}
+
+// UpdateTicketType mocks base method.
+func (m *MockEventRepository) UpdateTicketType(arg0 *models.TicketType) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTicketType", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTicketType indicates an expected call of UpdateTicketType.
+func (mr *MockEventRepositoryMockRecorder) UpdateTicketType(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTicketType", reflect.TypeOf((*MockEventRepository)(nil).UpdateTicketType), arg0)
+}
+
+// MockeventAccessChecker is a mock of eventAccessChecker interface.
+type MockeventAccessChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockeventAccessCheckerMockRecorder
+	isgomock struct{}
+}
+
+// MockeventAccessCheckerMockRecorder is the mock recorder for MockeventAccessChecker.
+type MockeventAccessCheckerMockRecorder struct {
+	mock *MockeventAccessChecker
+}
+
+// NewMockeventAccessChecker creates a new mock instance.
+func NewMockeventAccessChecker(ctrl *gomock.Controller) *MockeventAccessChecker {
+	mock := &MockeventAccessChecker{ctrl: ctrl}
+	mock.recorder = &MockeventAccessCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockeventAccessChecker) EXPECT() *MockeventAccessCheckerMockRecorder {
+	return m.recorder
+}
+
+// CanAccessEvent mocks base method.
+func (m *MockeventAccessChecker) CanAccessEvent(arg0, arg1, arg2 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CanAccessEvent", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CanAccessEvent indicates an expected call of CanAccessEvent.
+func (mr *MockeventAccessCheckerMockRecorder) CanAccessEvent(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CanAccessEvent", reflect.TypeOf((*MockeventAccessChecker)(nil).CanAccessEvent), arg0, arg1, arg2)
+}
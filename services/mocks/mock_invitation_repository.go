@@ -0,0 +1,159 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: services/invitation.go
+//
+// Generated by this command:
+//
+//	mockgen -source=services/invitation.go -destination=services/mocks/mock_invitation_repository.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	models "event-booking/models"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockEventInvitationRepository is a mock of EventInvitationRepository interface.
+type MockEventInvitationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventInvitationRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockEventInvitationRepositoryMockRecorder is the mock recorder for MockEventInvitationRepository.
+type MockEventInvitationRepositoryMockRecorder struct {
+	mock *MockEventInvitationRepository
+}
+
+// NewMockEventInvitationRepository creates a new mock instance.
+func NewMockEventInvitationRepository(ctrl *gomock.Controller) *MockEventInvitationRepository {
+	mock := &MockEventInvitationRepository{ctrl: ctrl}
+	mock.recorder = &MockEventInvitationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventInvitationRepository) EXPECT() *MockEventInvitationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AcceptEventInvitation mocks base method.
+func (m *MockEventInvitationRepository) AcceptEventInvitation(arg0 *models.EventInvitation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptEventInvitation", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AcceptEventInvitation indicates an expected call of AcceptEventInvitation.
+func (mr *MockEventInvitationRepositoryMockRecorder) AcceptEventInvitation(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptEventInvitation", reflect.TypeOf((*MockEventInvitationRepository)(nil).AcceptEventInvitation), arg0)
+}
+
+// CreateEventInvitation mocks base method.
+func (m *MockEventInvitationRepository) CreateEventInvitation(arg0 *models.EventInvitation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEventInvitation", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateEventInvitation indicates an expected call of CreateEventInvitation.
+func (mr *MockEventInvitationRepositoryMockRecorder) CreateEventInvitation(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEventInvitation", reflect.TypeOf((*MockEventInvitationRepository)(nil).CreateEventInvitation), arg0)
+}
+
+// DeleteEventInvitation mocks base method.
+func (m *MockEventInvitationRepository) DeleteEventInvitation(arg0, arg1, arg2 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEventInvitation", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteEventInvitation indicates an expected call of DeleteEventInvitation.
+func (mr *MockEventInvitationRepositoryMockRecorder) DeleteEventInvitation(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEventInvitation", reflect.TypeOf((*MockEventInvitationRepository)(nil).DeleteEventInvitation), arg0, arg1, arg2)
+}
+
+// GetEventById mocks base method.
+func (m *MockEventInvitationRepository) GetEventById(arg0, arg1 int64) (models.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventById", arg0, arg1)
+	ret0, _ := ret[0].(models.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventById indicates an expected call of GetEventById.
+func (mr *MockEventInvitationRepositoryMockRecorder) GetEventById(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventById", reflect.TypeOf((*MockEventInvitationRepository)(nil).GetEventById), arg0, arg1)
+}
+
+// GetEventInvitations mocks base method.
+func (m *MockEventInvitationRepository) GetEventInvitations(arg0, arg1 int64) ([]models.EventInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventInvitations", arg0, arg1)
+	ret0, _ := ret[0].([]models.EventInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventInvitations indicates an expected call of GetEventInvitations.
+func (mr *MockEventInvitationRepositoryMockRecorder) GetEventInvitations(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventInvitations", reflect.TypeOf((*MockEventInvitationRepository)(nil).GetEventInvitations), arg0, arg1)
+}
+
+// GetEventMember mocks base method.
+func (m *MockEventInvitationRepository) GetEventMember(arg0, arg1, arg2 int64) (models.EventMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventMember", arg0, arg1, arg2)
+	ret0, _ := ret[0].(models.EventMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventMember indicates an expected call of GetEventMember.
+func (mr *MockEventInvitationRepositoryMockRecorder) GetEventMember(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventMember", reflect.TypeOf((*MockEventInvitationRepository)(nil).GetEventMember), arg0, arg1, arg2)
+}
+
+// GetMembership mocks base method.
+func (m *MockEventInvitationRepository) GetMembership(arg0, arg1 int64) (models.Membership, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMembership", arg0, arg1)
+	ret0, _ := ret[0].(models.Membership)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMembership indicates an expected call of GetMembership.
+func (mr *MockEventInvitationRepositoryMockRecorder) GetMembership(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMembership", reflect.TypeOf((*MockEventInvitationRepository)(nil).GetMembership), arg0, arg1)
+}
+
+// GetUserById mocks base method.
+func (m *MockEventInvitationRepository) GetUserById(arg0 int64) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserById", arg0)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserById indicates an expected call of GetUserById.
+func (mr *MockEventInvitationRepositoryMockRecorder) GetUserById(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserById", reflect.TypeOf((*MockEventInvitationRepository)(nil).GetUserById), arg0)
+}
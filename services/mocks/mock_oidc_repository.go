@@ -0,0 +1,199 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: services/oidc.go
+//
+// Generated by this command:
+//
+//	mockgen -source=services/oidc.go -destination=services/mocks/mock_oidc_repository.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	models "event-booking/models"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIdentityProvider is a mock of IdentityProvider interface.
+type MockIdentityProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdentityProviderMockRecorder
+	isgomock struct{}
+}
+
+// MockIdentityProviderMockRecorder is the mock recorder for MockIdentityProvider.
+type MockIdentityProviderMockRecorder struct {
+	mock *MockIdentityProvider
+}
+
+// NewMockIdentityProvider creates a new mock instance.
+func NewMockIdentityProvider(ctrl *gomock.Controller) *MockIdentityProvider {
+	mock := &MockIdentityProvider{ctrl: ctrl}
+	mock.recorder = &MockIdentityProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdentityProvider) EXPECT() *MockIdentityProviderMockRecorder {
+	return m.recorder
+}
+
+// AuthCodeURL mocks base method.
+func (m *MockIdentityProvider) AuthCodeURL(state, nonce, codeVerifier string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthCodeURL", state, nonce, codeVerifier)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthCodeURL indicates an expected call of AuthCodeURL.
+func (mr *MockIdentityProviderMockRecorder) AuthCodeURL(state, nonce, codeVerifier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthCodeURL", reflect.TypeOf((*MockIdentityProvider)(nil).AuthCodeURL), state, nonce, codeVerifier)
+}
+
+// Exchange mocks base method.
+func (m *MockIdentityProvider) Exchange(code, codeVerifier, nonce string) (models.ExternalIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exchange", code, codeVerifier, nonce)
+	ret0, _ := ret[0].(models.ExternalIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exchange indicates an expected call of Exchange.
+func (mr *MockIdentityProviderMockRecorder) Exchange(code, codeVerifier, nonce any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exchange", reflect.TypeOf((*MockIdentityProvider)(nil).Exchange), code, codeVerifier, nonce)
+}
+
+// Name mocks base method.
+func (m *MockIdentityProvider) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockIdentityProviderMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockIdentityProvider)(nil).Name))
+}
+
+// MockOIDCRepository is a mock of OIDCRepository interface.
+type MockOIDCRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOIDCRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockOIDCRepositoryMockRecorder is the mock recorder for MockOIDCRepository.
+type MockOIDCRepositoryMockRecorder struct {
+	mock *MockOIDCRepository
+}
+
+// NewMockOIDCRepository creates a new mock instance.
+func NewMockOIDCRepository(ctrl *gomock.Controller) *MockOIDCRepository {
+	mock := &MockOIDCRepository{ctrl: ctrl}
+	mock.recorder = &MockOIDCRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOIDCRepository) EXPECT() *MockOIDCRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ConsumeOIDCLoginState mocks base method.
+func (m *MockOIDCRepository) ConsumeOIDCLoginState(arg0, arg1 string, arg2 time.Time) (models.OIDCLoginState, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeOIDCLoginState", arg0, arg1, arg2)
+	ret0, _ := ret[0].(models.OIDCLoginState)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ConsumeOIDCLoginState indicates an expected call of ConsumeOIDCLoginState.
+func (mr *MockOIDCRepositoryMockRecorder) ConsumeOIDCLoginState(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeOIDCLoginState", reflect.TypeOf((*MockOIDCRepository)(nil).ConsumeOIDCLoginState), arg0, arg1, arg2)
+}
+
+// CreateExternalUser mocks base method.
+func (m *MockOIDCRepository) CreateExternalUser(arg0 models.ExternalIdentity, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExternalUser", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateExternalUser indicates an expected call of CreateExternalUser.
+func (mr *MockOIDCRepositoryMockRecorder) CreateExternalUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExternalUser", reflect.TypeOf((*MockOIDCRepository)(nil).CreateExternalUser), arg0, arg1)
+}
+
+// CreateOIDCLoginState mocks base method.
+func (m *MockOIDCRepository) CreateOIDCLoginState(arg0 *models.OIDCLoginState) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOIDCLoginState", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOIDCLoginState indicates an expected call of CreateOIDCLoginState.
+func (mr *MockOIDCRepositoryMockRecorder) CreateOIDCLoginState(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOIDCLoginState", reflect.TypeOf((*MockOIDCRepository)(nil).CreateOIDCLoginState), arg0)
+}
+
+// GetUserByEmail mocks base method.
+func (m *MockOIDCRepository) GetUserByEmail(arg0 string) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByEmail", arg0)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByEmail indicates an expected call of GetUserByEmail.
+func (mr *MockOIDCRepositoryMockRecorder) GetUserByEmail(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockOIDCRepository)(nil).GetUserByEmail), arg0)
+}
+
+// GetUserByExternalIdentity mocks base method.
+func (m *MockOIDCRepository) GetUserByExternalIdentity(arg0, arg1 string) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByExternalIdentity", arg0, arg1)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByExternalIdentity indicates an expected call of GetUserByExternalIdentity.
+func (mr *MockOIDCRepositoryMockRecorder) GetUserByExternalIdentity(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByExternalIdentity", reflect.TypeOf((*MockOIDCRepository)(nil).GetUserByExternalIdentity), arg0, arg1)
+}
+
+// LinkExternalIdentity mocks base method.
+func (m *MockOIDCRepository) LinkExternalIdentity(arg0 int64, arg1 models.ExternalIdentity, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LinkExternalIdentity", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LinkExternalIdentity indicates an expected call of LinkExternalIdentity.
+func (mr *MockOIDCRepositoryMockRecorder) LinkExternalIdentity(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkExternalIdentity", reflect.TypeOf((*MockOIDCRepository)(nil).LinkExternalIdentity), arg0, arg1, arg2)
+}
@@ -0,0 +1,347 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: services/order.go
+//
+// Generated by this command:
+//
+//	mockgen -source=services/order.go -destination=services/mocks/mock_order_repository.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	models "event-booking/models"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPaymentProvider is a mock of PaymentProvider interface.
+type MockPaymentProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentProviderMockRecorder
+	isgomock struct{}
+}
+
+// MockPaymentProviderMockRecorder is the mock recorder for MockPaymentProvider.
+type MockPaymentProviderMockRecorder struct {
+	mock *MockPaymentProvider
+}
+
+// NewMockPaymentProvider creates a new mock instance.
+func NewMockPaymentProvider(ctrl *gomock.Controller) *MockPaymentProvider {
+	mock := &MockPaymentProvider{ctrl: ctrl}
+	mock.recorder = &MockPaymentProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentProvider) EXPECT() *MockPaymentProviderMockRecorder {
+	return m.recorder
+}
+
+// Confirm mocks base method.
+func (m *MockPaymentProvider) Confirm(intentId, paymentMethod string) (models.PaymentIntent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Confirm", intentId, paymentMethod)
+	ret0, _ := ret[0].(models.PaymentIntent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Confirm indicates an expected call of Confirm.
+func (mr *MockPaymentProviderMockRecorder) Confirm(intentId, paymentMethod any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Confirm", reflect.TypeOf((*MockPaymentProvider)(nil).Confirm), intentId, paymentMethod)
+}
+
+// CreateIntent mocks base method.
+func (m *MockPaymentProvider) CreateIntent(reference string, amount int64, currency string) (models.PaymentIntent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIntent", reference, amount, currency)
+	ret0, _ := ret[0].(models.PaymentIntent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIntent indicates an expected call of CreateIntent.
+func (mr *MockPaymentProviderMockRecorder) CreateIntent(reference, amount, currency any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIntent", reflect.TypeOf((*MockPaymentProvider)(nil).CreateIntent), reference, amount, currency)
+}
+
+// Refund mocks base method.
+func (m *MockPaymentProvider) Refund(intentId string, amount int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refund", intentId, amount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Refund indicates an expected call of Refund.
+func (mr *MockPaymentProviderMockRecorder) Refund(intentId, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refund", reflect.TypeOf((*MockPaymentProvider)(nil).Refund), intentId, amount)
+}
+
+// VerifyWebhook mocks base method.
+func (m *MockPaymentProvider) VerifyWebhook(payload []byte, signature string) (models.PaymentIntent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyWebhook", payload, signature)
+	ret0, _ := ret[0].(models.PaymentIntent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyWebhook indicates an expected call of VerifyWebhook.
+func (mr *MockPaymentProviderMockRecorder) VerifyWebhook(payload, signature any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyWebhook", reflect.TypeOf((*MockPaymentProvider)(nil).VerifyWebhook), payload, signature)
+}
+
+// MockOrderRepository is a mock of OrderRepository interface.
+type MockOrderRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockOrderRepositoryMockRecorder is the mock recorder for MockOrderRepository.
+type MockOrderRepositoryMockRecorder struct {
+	mock *MockOrderRepository
+}
+
+// NewMockOrderRepository creates a new mock instance.
+func NewMockOrderRepository(ctrl *gomock.Controller) *MockOrderRepository {
+	mock := &MockOrderRepository{ctrl: ctrl}
+	mock.recorder = &MockOrderRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderRepository) EXPECT() *MockOrderRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AwaitsPayment mocks base method.
+func (m *MockOrderRepository) AwaitsPayment(arg0, arg1, arg2 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AwaitsPayment", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AwaitsPayment indicates an expected call of AwaitsPayment.
+func (mr *MockOrderRepositoryMockRecorder) AwaitsPayment(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AwaitsPayment", reflect.TypeOf((*MockOrderRepository)(nil).AwaitsPayment), arg0, arg1, arg2)
+}
+
+// CancelGroupRegistrations mocks base method.
+func (m *MockOrderRepository) CancelGroupRegistrations(arg0, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelGroupRegistrations", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelGroupRegistrations indicates an expected call of CancelGroupRegistrations.
+func (mr *MockOrderRepositoryMockRecorder) CancelGroupRegistrations(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelGroupRegistrations", reflect.TypeOf((*MockOrderRepository)(nil).CancelGroupRegistrations), arg0, arg1)
+}
+
+// CancelRegistration mocks base method.
+func (m *MockOrderRepository) CancelRegistration(arg0, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelRegistration", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelRegistration indicates an expected call of CancelRegistration.
+func (mr *MockOrderRepositoryMockRecorder) CancelRegistration(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelRegistration", reflect.TypeOf((*MockOrderRepository)(nil).CancelRegistration), arg0, arg1)
+}
+
+// ConfirmGroup mocks base method.
+func (m *MockOrderRepository) ConfirmGroup(arg0, arg1 int64, arg2 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmGroup", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmGroup indicates an expected call of ConfirmGroup.
+func (mr *MockOrderRepositoryMockRecorder) ConfirmGroup(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmGroup", reflect.TypeOf((*MockOrderRepository)(nil).ConfirmGroup), arg0, arg1, arg2)
+}
+
+// ConfirmRegistration mocks base method.
+func (m *MockOrderRepository) ConfirmRegistration(arg0, arg1 int64, arg2 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmRegistration", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmRegistration indicates an expected call of ConfirmRegistration.
+func (mr *MockOrderRepositoryMockRecorder) ConfirmRegistration(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmRegistration", reflect.TypeOf((*MockOrderRepository)(nil).ConfirmRegistration), arg0, arg1, arg2)
+}
+
+// CreateOrder mocks base method.
+func (m *MockOrderRepository) CreateOrder(arg0 *models.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrder", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOrder indicates an expected call of CreateOrder.
+func (mr *MockOrderRepositoryMockRecorder) CreateOrder(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockOrderRepository)(nil).CreateOrder), arg0)
+}
+
+// GetEventById mocks base method.
+func (m *MockOrderRepository) GetEventById(arg0, arg1 int64) (models.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventById", arg0, arg1)
+	ret0, _ := ret[0].(models.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventById indicates an expected call of GetEventById.
+func (mr *MockOrderRepositoryMockRecorder) GetEventById(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventById", reflect.TypeOf((*MockOrderRepository)(nil).GetEventById), arg0, arg1)
+}
+
+// GetGroup mocks base method.
+func (m *MockOrderRepository) GetGroup(arg0, arg1 int64) (models.RegistrationGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroup", arg0, arg1)
+	ret0, _ := ret[0].(models.RegistrationGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroup indicates an expected call of GetGroup.
+func (mr *MockOrderRepositoryMockRecorder) GetGroup(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroup", reflect.TypeOf((*MockOrderRepository)(nil).GetGroup), arg0, arg1)
+}
+
+// GetOpenGroupOrder mocks base method.
+func (m *MockOrderRepository) GetOpenGroupOrder(arg0, arg1 int64) (models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOpenGroupOrder", arg0, arg1)
+	ret0, _ := ret[0].(models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpenGroupOrder indicates an expected call of GetOpenGroupOrder.
+func (mr *MockOrderRepositoryMockRecorder) GetOpenGroupOrder(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenGroupOrder", reflect.TypeOf((*MockOrderRepository)(nil).GetOpenGroupOrder), arg0, arg1)
+}
+
+// GetOpenOrder mocks base method.
+func (m *MockOrderRepository) GetOpenOrder(arg0, arg1 int64) (models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOpenOrder", arg0, arg1)
+	ret0, _ := ret[0].(models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpenOrder indicates an expected call of GetOpenOrder.
+func (mr *MockOrderRepositoryMockRecorder) GetOpenOrder(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenOrder", reflect.TypeOf((*MockOrderRepository)(nil).GetOpenOrder), arg0, arg1)
+}
+
+// GetOrder mocks base method.
+func (m *MockOrderRepository) GetOrder(arg0, arg1 int64) (models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrder", arg0, arg1)
+	ret0, _ := ret[0].(models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrder indicates an expected call of GetOrder.
+func (mr *MockOrderRepositoryMockRecorder) GetOrder(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockOrderRepository)(nil).GetOrder), arg0, arg1)
+}
+
+// GetOrderByPaymentIntent mocks base method.
+func (m *MockOrderRepository) GetOrderByPaymentIntent(arg0 string) (models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderByPaymentIntent", arg0)
+	ret0, _ := ret[0].(models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderByPaymentIntent indicates an expected call of GetOrderByPaymentIntent.
+func (mr *MockOrderRepositoryMockRecorder) GetOrderByPaymentIntent(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderByPaymentIntent", reflect.TypeOf((*MockOrderRepository)(nil).GetOrderByPaymentIntent), arg0)
+}
+
+// GetRegisteredEventById mocks base method.
+func (m *MockOrderRepository) GetRegisteredEventById(arg0, arg1, arg2 int64) (models.RegisterEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRegisteredEventById", arg0, arg1, arg2)
+	ret0, _ := ret[0].(models.RegisterEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRegisteredEventById indicates an expected call of GetRegisteredEventById.
+func (mr *MockOrderRepositoryMockRecorder) GetRegisteredEventById(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegisteredEventById", reflect.TypeOf((*MockOrderRepository)(nil).GetRegisteredEventById), arg0, arg1, arg2)
+}
+
+// GetTicketType mocks base method.
+func (m *MockOrderRepository) GetTicketType(arg0, arg1, arg2 int64) (models.TicketType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTicketType", arg0, arg1, arg2)
+	ret0, _ := ret[0].(models.TicketType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTicketType indicates an expected call of GetTicketType.
+func (mr *MockOrderRepositoryMockRecorder) GetTicketType(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTicketType", reflect.TypeOf((*MockOrderRepository)(nil).GetTicketType), arg0, arg1, arg2)
+}
+
+// SetOrderStatus mocks base method.
+func (m *MockOrderRepository) SetOrderStatus(arg0 int64, arg1, arg2 string, arg3 time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOrderStatus", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetOrderStatus indicates an expected call of SetOrderStatus.
+func (mr *MockOrderRepositoryMockRecorder) SetOrderStatus(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOrderStatus", reflect.TypeOf((*MockOrderRepository)(nil).SetOrderStatus), arg0, arg1, arg2, arg3)
+}
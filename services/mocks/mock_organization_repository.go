@@ -0,0 +1,233 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: services/organization.go
+//
+// Generated by this command:
+//
+//	mockgen -source=services/organization.go -destination=services/mocks/mock_organization_repository.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	models "event-booking/models"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOrganizationRepository is a mock of OrganizationRepository interface.
+type MockOrganizationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrganizationRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockOrganizationRepositoryMockRecorder is the mock recorder for MockOrganizationRepository.
+type MockOrganizationRepositoryMockRecorder struct {
+	mock *MockOrganizationRepository
+}
+
+// NewMockOrganizationRepository creates a new mock instance.
+func NewMockOrganizationRepository(ctrl *gomock.Controller) *MockOrganizationRepository {
+	mock := &MockOrganizationRepository{ctrl: ctrl}
+	mock.recorder = &MockOrganizationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrganizationRepository) EXPECT() *MockOrganizationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddMember mocks base method.
+func (m *MockOrganizationRepository) AddMember(arg0 *models.OrganizationMember) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMember", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddMember indicates an expected call of AddMember.
+func (mr *MockOrganizationRepositoryMockRecorder) AddMember(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMember", reflect.TypeOf((*MockOrganizationRepository)(nil).AddMember), arg0)
+}
+
+// CountOwners mocks base method.
+func (m *MockOrganizationRepository) CountOwners(arg0 int64) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountOwners", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountOwners indicates an expected call of CountOwners.
+func (mr *MockOrganizationRepositoryMockRecorder) CountOwners(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOwners", reflect.TypeOf((*MockOrganizationRepository)(nil).CountOwners), arg0)
+}
+
+// CreateOrganization mocks base method.
+func (m *MockOrganizationRepository) CreateOrganization(arg0 *models.Organization, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrganization", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOrganization indicates an expected call of CreateOrganization.
+func (mr *MockOrganizationRepositoryMockRecorder) CreateOrganization(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrganization", reflect.TypeOf((*MockOrganizationRepository)(nil).CreateOrganization), arg0, arg1)
+}
+
+// GetAttendeeAttendance mocks base method.
+func (m *MockOrganizationRepository) GetAttendeeAttendance(arg0 int64, arg1 time.Time) ([]models.AttendeeAttendance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAttendeeAttendance", arg0, arg1)
+	ret0, _ := ret[0].([]models.AttendeeAttendance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAttendeeAttendance indicates an expected call of GetAttendeeAttendance.
+func (mr *MockOrganizationRepositoryMockRecorder) GetAttendeeAttendance(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttendeeAttendance", reflect.TypeOf((*MockOrganizationRepository)(nil).GetAttendeeAttendance), arg0, arg1)
+}
+
+// GetMember mocks base method.
+func (m *MockOrganizationRepository) GetMember(arg0, arg1 int64) (models.OrganizationMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMember", arg0, arg1)
+	ret0, _ := ret[0].(models.OrganizationMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMember indicates an expected call of GetMember.
+func (mr *MockOrganizationRepositoryMockRecorder) GetMember(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMember", reflect.TypeOf((*MockOrganizationRepository)(nil).GetMember), arg0, arg1)
+}
+
+// GetMembers mocks base method.
+func (m *MockOrganizationRepository) GetMembers(arg0 int64) ([]models.OrganizationMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMembers", arg0)
+	ret0, _ := ret[0].([]models.OrganizationMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMembers indicates an expected call of GetMembers.
+func (mr *MockOrganizationRepositoryMockRecorder) GetMembers(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMembers", reflect.TypeOf((*MockOrganizationRepository)(nil).GetMembers), arg0)
+}
+
+// GetMembership mocks base method.
+func (m *MockOrganizationRepository) GetMembership(arg0, arg1 int64) (models.Membership, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMembership", arg0, arg1)
+	ret0, _ := ret[0].(models.Membership)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMembership indicates an expected call of GetMembership.
+func (mr *MockOrganizationRepositoryMockRecorder) GetMembership(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMembership", reflect.TypeOf((*MockOrganizationRepository)(nil).GetMembership), arg0, arg1)
+}
+
+// GetMemberships mocks base method.
+func (m *MockOrganizationRepository) GetMemberships(arg0 int64) ([]models.Membership, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMemberships", arg0)
+	ret0, _ := ret[0].([]models.Membership)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMemberships indicates an expected call of GetMemberships.
+func (mr *MockOrganizationRepositoryMockRecorder) GetMemberships(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMemberships", reflect.TypeOf((*MockOrganizationRepository)(nil).GetMemberships), arg0)
+}
+
+// GetPastAttendance mocks base method.
+func (m *MockOrganizationRepository) GetPastAttendance(arg0 int64, arg1 time.Time) ([]models.EventAttendance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPastAttendance", arg0, arg1)
+	ret0, _ := ret[0].([]models.EventAttendance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPastAttendance indicates an expected call of GetPastAttendance.
+func (mr *MockOrganizationRepositoryMockRecorder) GetPastAttendance(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPastAttendance", reflect.TypeOf((*MockOrganizationRepository)(nil).GetPastAttendance), arg0, arg1)
+}
+
+// GetUserByEmail mocks base method.
+func (m *MockOrganizationRepository) GetUserByEmail(arg0 string) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByEmail", arg0)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByEmail indicates an expected call of GetUserByEmail.
+func (mr *MockOrganizationRepositoryMockRecorder) GetUserByEmail(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockOrganizationRepository)(nil).GetUserByEmail), arg0)
+}
+
+// RemoveMember mocks base method.
+func (m *MockOrganizationRepository) RemoveMember(arg0, arg1 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMember", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockOrganizationRepositoryMockRecorder) RemoveMember(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockOrganizationRepository)(nil).RemoveMember), arg0, arg1)
+}
+
+// UpdateMemberRole mocks base method.
+func (m *MockOrganizationRepository) UpdateMemberRole(arg0, arg1 int64, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMemberRole", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMemberRole indicates an expected call of UpdateMemberRole.
+func (mr *MockOrganizationRepositoryMockRecorder) UpdateMemberRole(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMemberRole", reflect.TypeOf((*MockOrganizationRepository)(nil).UpdateMemberRole), arg0, arg1, arg2)
+}
+
+// UpdateOrganization mocks base method.
+func (m *MockOrganizationRepository) UpdateOrganization(arg0 *models.Organization) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOrganization", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOrganization indicates an expected call of UpdateOrganization.
+func (mr *MockOrganizationRepositoryMockRecorder) UpdateOrganization(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrganization", reflect.TypeOf((*MockOrganizationRepository)(nil).UpdateOrganization), arg0)
+}
@@ -3,7 +3,7 @@
 //
 // Generated by this command:
 //
-//	mockgen -source=services/register.go -destination=services/mocks/mock_register_repository.go -package=mocks RegisterRepository
+//	mockgen -source=services/register.go -destination=services/mocks/mock_register_repository.go -package=mocks
 //
 
 // Package mocks is a generated GoMock package.
@@ -12,6 +12,7 @@ package mocks
 import (
 	models "event-booking/models"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -40,60 +41,405 @@ func (m *MockRegisterRepository) EXPECT() *MockRegisterRepositoryMockRecorder {
 	return m.recorder
 }
 
-// DeleteRegisteredEvent mocks base method.
-func (m *MockRegisterRepository) DeleteRegisteredEvent(arg0 int64) error {
+// CanAccessEvent mocks base method.
+func (m *MockRegisterRepository) CanAccessEvent(arg0, arg1, arg2 int64) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteRegisteredEvent", arg0)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "CanAccessEvent", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CanAccessEvent indicates an expected call of CanAccessEvent.
+func (mr *MockRegisterRepositoryMockRecorder) CanAccessEvent(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CanAccessEvent", reflect.TypeOf((*MockRegisterRepository)(nil).CanAccessEvent), arg0, arg1, arg2)
+}
+
+// CheckIn mocks base method.
+func (m *MockRegisterRepository) CheckIn(arg0, arg1, arg2, arg3 int64, arg4 time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckIn", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckIn indicates an expected call of CheckIn.
+func (mr *MockRegisterRepositoryMockRecorder) CheckIn(arg0, arg1, arg2, arg3, arg4 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckIn", reflect.TypeOf((*MockRegisterRepository)(nil).CheckIn), arg0, arg1, arg2, arg3, arg4)
+}
+
+// GetAttendeeAttendance mocks base method.
+func (m *MockRegisterRepository) GetAttendeeAttendance(arg0 int64, arg1 time.Time) ([]models.AttendeeAttendance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAttendeeAttendance", arg0, arg1)
+	ret0, _ := ret[0].([]models.AttendeeAttendance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAttendeeAttendance indicates an expected call of GetAttendeeAttendance.
+func (mr *MockRegisterRepositoryMockRecorder) GetAttendeeAttendance(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttendeeAttendance", reflect.TypeOf((*MockRegisterRepository)(nil).GetAttendeeAttendance), arg0, arg1)
+}
+
+// GetCancellationPolicy mocks base method.
+func (m *MockRegisterRepository) GetCancellationPolicy(arg0, arg1 int64) (models.CancellationPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCancellationPolicy", arg0, arg1)
+	ret0, _ := ret[0].(models.CancellationPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCancellationPolicy indicates an expected call of GetCancellationPolicy.
+func (mr *MockRegisterRepositoryMockRecorder) GetCancellationPolicy(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCancellationPolicy", reflect.TypeOf((*MockRegisterRepository)(nil).GetCancellationPolicy), arg0, arg1)
+}
+
+// GetCancellations mocks base method.
+func (m *MockRegisterRepository) GetCancellations(arg0, arg1 int64) ([]models.Cancellation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCancellations", arg0, arg1)
+	ret0, _ := ret[0].([]models.Cancellation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteRegisteredEvent indicates an expected call of DeleteRegisteredEvent.
-func (mr *MockRegisterRepositoryMockRecorder) DeleteRegisteredEvent(arg0 any) *gomock.Call {
+// GetCancellations indicates an expected call of GetCancellations.
+func (mr *MockRegisterRepositoryMockRecorder) GetCancellations(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRegisteredEvent", reflect.TypeOf((*MockRegisterRepository)(nil).DeleteRegisteredEvent), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCancellations", reflect.TypeOf((*MockRegisterRepository)(nil).GetCancellations), arg0, arg1)
 }
 
 // GetEventById mocks base method.
-func (m *MockRegisterRepository) GetEventById(arg0 int64) (models.Event, error) {
+func (m *MockRegisterRepository) GetEventById(arg0, arg1 int64) (models.Event, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEventById", arg0)
+	ret := m.ctrl.Call(m, "GetEventById", arg0, arg1)
 	ret0, _ := ret[0].(models.Event)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetEventById indicates an expected call of GetEventById.
-func (mr *MockRegisterRepositoryMockRecorder) GetEventById(arg0 any) *gomock.Call {
+func (mr *MockRegisterRepositoryMockRecorder) GetEventById(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventById", reflect.TypeOf((*MockRegisterRepository)(nil).GetEventById), arg0, arg1)
+}
+
+// GetEventMember mocks base method.
+func (m *MockRegisterRepository) GetEventMember(arg0, arg1, arg2 int64) (models.EventMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventMember", arg0, arg1, arg2)
+	ret0, _ := ret[0].(models.EventMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventMember indicates an expected call of GetEventMember.
+func (mr *MockRegisterRepositoryMockRecorder) GetEventMember(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventMember", reflect.TypeOf((*MockRegisterRepository)(nil).GetEventMember), arg0, arg1, arg2)
+}
+
+// GetGroup mocks base method.
+func (m *MockRegisterRepository) GetGroup(arg0, arg1 int64) (models.RegistrationGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroup", arg0, arg1)
+	ret0, _ := ret[0].(models.RegistrationGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroup indicates an expected call of GetGroup.
+func (mr *MockRegisterRepositoryMockRecorder) GetGroup(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroup", reflect.TypeOf((*MockRegisterRepository)(nil).GetGroup), arg0, arg1)
+}
+
+// GetGroups mocks base method.
+func (m *MockRegisterRepository) GetGroups(arg0, arg1 int64) ([]models.RegistrationGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroups", arg0, arg1)
+	ret0, _ := ret[0].([]models.RegistrationGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroups indicates an expected call of GetGroups.
+func (mr *MockRegisterRepositoryMockRecorder) GetGroups(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroups", reflect.TypeOf((*MockRegisterRepository)(nil).GetGroups), arg0, arg1)
+}
+
+// GetOrganization mocks base method.
+func (m *MockRegisterRepository) GetOrganization(arg0 int64) (models.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganization", arg0)
+	ret0, _ := ret[0].(models.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganization indicates an expected call of GetOrganization.
+func (mr *MockRegisterRepositoryMockRecorder) GetOrganization(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganization", reflect.TypeOf((*MockRegisterRepository)(nil).GetOrganization), arg0)
+}
+
+// GetOrganizationMemberByEmail mocks base method.
+func (m *MockRegisterRepository) GetOrganizationMemberByEmail(arg0 int64, arg1 string) (models.OrganizationMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganizationMemberByEmail", arg0, arg1)
+	ret0, _ := ret[0].(models.OrganizationMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganizationMemberByEmail indicates an expected call of GetOrganizationMemberByEmail.
+func (mr *MockRegisterRepositoryMockRecorder) GetOrganizationMemberByEmail(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationMemberByEmail", reflect.TypeOf((*MockRegisterRepository)(nil).GetOrganizationMemberByEmail), arg0, arg1)
+}
+
+// GetPaidOrder mocks base method.
+func (m *MockRegisterRepository) GetPaidOrder(arg0, arg1 int64) (models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaidOrder", arg0, arg1)
+	ret0, _ := ret[0].(models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaidOrder indicates an expected call of GetPaidOrder.
+func (mr *MockRegisterRepositoryMockRecorder) GetPaidOrder(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventById", reflect.TypeOf((*MockRegisterRepository)(nil).GetEventById), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaidOrder", reflect.TypeOf((*MockRegisterRepository)(nil).GetPaidOrder), arg0, arg1)
+}
+
+// GetPromoCode mocks base method.
+func (m *MockRegisterRepository) GetPromoCode(arg0, arg1, arg2 int64) (models.PromoCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPromoCode", arg0, arg1, arg2)
+	ret0, _ := ret[0].(models.PromoCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPromoCode indicates an expected call of GetPromoCode.
+func (mr *MockRegisterRepositoryMockRecorder) GetPromoCode(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPromoCode", reflect.TypeOf((*MockRegisterRepository)(nil).GetPromoCode), arg0, arg1, arg2)
+}
+
+// GetPromoCodeByCode mocks base method.
+func (m *MockRegisterRepository) GetPromoCodeByCode(arg0, arg1 int64, arg2 string) (models.PromoCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPromoCodeByCode", arg0, arg1, arg2)
+	ret0, _ := ret[0].(models.PromoCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPromoCodeByCode indicates an expected call of GetPromoCodeByCode.
+func (mr *MockRegisterRepositoryMockRecorder) GetPromoCodeByCode(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPromoCodeByCode", reflect.TypeOf((*MockRegisterRepository)(nil).GetPromoCodeByCode), arg0, arg1, arg2)
 }
 
 // GetRegisteredEventById mocks base method.
-func (m *MockRegisterRepository) GetRegisteredEventById(arg0, arg1 int64) (models.RegisterEvent, error) {
+func (m *MockRegisterRepository) GetRegisteredEventById(arg0, arg1, arg2 int64) (models.RegisterEvent, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRegisteredEventById", arg0, arg1)
+	ret := m.ctrl.Call(m, "GetRegisteredEventById", arg0, arg1, arg2)
 	ret0, _ := ret[0].(models.RegisterEvent)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetRegisteredEventById indicates an expected call of GetRegisteredEventById.
-func (mr *MockRegisterRepositoryMockRecorder) GetRegisteredEventById(arg0, arg1 any) *gomock.Call {
+func (mr *MockRegisterRepositoryMockRecorder) GetRegisteredEventById(arg0, arg1, arg2 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegisteredEventById", reflect.TypeOf((*MockRegisterRepository)(nil).GetRegisteredEventById), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegisteredEventById", reflect.TypeOf((*MockRegisterRepository)(nil).GetRegisteredEventById), arg0, arg1, arg2)
 }
 
-// RegisterEvent mocks base method.
-func (m *MockRegisterRepository) RegisterEvent(arg0, arg1 int64) error {
+// GetRegistration mocks base method.
+func (m *MockRegisterRepository) GetRegistration(arg0, arg1 int64) (models.RegisterEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRegistration", arg0, arg1)
+	ret0, _ := ret[0].(models.RegisterEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRegistration indicates an expected call of GetRegistration.
+func (mr *MockRegisterRepositoryMockRecorder) GetRegistration(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegistration", reflect.TypeOf((*MockRegisterRepository)(nil).GetRegistration), arg0, arg1)
+}
+
+// GetRegistrationQuestions mocks base method.
+func (m *MockRegisterRepository) GetRegistrationQuestions(arg0, arg1 int64) ([]models.RegistrationQuestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRegistrationQuestions", arg0, arg1)
+	ret0, _ := ret[0].([]models.RegistrationQuestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRegistrationQuestions indicates an expected call of GetRegistrationQuestions.
+func (mr *MockRegisterRepositoryMockRecorder) GetRegistrationQuestions(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegistrationQuestions", reflect.TypeOf((*MockRegisterRepository)(nil).GetRegistrationQuestions), arg0, arg1)
+}
+
+// GetRegistrations mocks base method.
+func (m *MockRegisterRepository) GetRegistrations(arg0, arg1 int64, arg2 string) ([]models.RegisterEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRegistrations", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.RegisterEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRegistrations indicates an expected call of GetRegistrations.
+func (mr *MockRegisterRepositoryMockRecorder) GetRegistrations(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegistrations", reflect.TypeOf((*MockRegisterRepository)(nil).GetRegistrations), arg0, arg1, arg2)
+}
+
+// GetTicketTypes mocks base method.
+func (m *MockRegisterRepository) GetTicketTypes(arg0, arg1 int64) ([]models.TicketType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTicketTypes", arg0, arg1)
+	ret0, _ := ret[0].([]models.TicketType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTicketTypes indicates an expected call of GetTicketTypes.
+func (mr *MockRegisterRepositoryMockRecorder) GetTicketTypes(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTicketTypes", reflect.TypeOf((*MockRegisterRepository)(nil).GetTicketTypes), arg0, arg1)
+}
+
+// GetUserById mocks base method.
+func (m *MockRegisterRepository) GetUserById(arg0 int64) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserById", arg0)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserById indicates an expected call of GetUserById.
+func (mr *MockRegisterRepositoryMockRecorder) GetUserById(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserById", reflect.TypeOf((*MockRegisterRepository)(nil).GetUserById), arg0)
+}
+
+// RecordCancellation mocks base method.
+func (m *MockRegisterRepository) RecordCancellation(arg0 *models.Cancellation) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RegisterEvent", arg0, arg1)
+	ret := m.ctrl.Call(m, "RecordCancellation", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordCancellation indicates an expected call of RecordCancellation.
+func (mr *MockRegisterRepositoryMockRecorder) RecordCancellation(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordCancellation", reflect.TypeOf((*MockRegisterRepository)(nil).RecordCancellation), arg0)
+}
+
+// RecordRefund mocks base method.
+func (m *MockRegisterRepository) RecordRefund(arg0 *models.Cancellation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordRefund", arg0)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// RecordRefund indicates an expected call of RecordRefund.
+func (mr *MockRegisterRepositoryMockRecorder) RecordRefund(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordRefund", reflect.TypeOf((*MockRegisterRepository)(nil).RecordRefund), arg0)
+}
+
+// RegisterEvent mocks base method.
+func (m *MockRegisterRepository) RegisterEvent(arg0 *models.RegisterEvent) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterEvent", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // RegisterEvent indicates an expected call of RegisterEvent.
-func (mr *MockRegisterRepositoryMockRecorder) RegisterEvent(arg0, arg1 any) *gomock.Call {
+func (mr *MockRegisterRepositoryMockRecorder) RegisterEvent(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterEvent", reflect.TypeOf((*MockRegisterRepository)(nil).RegisterEvent), arg0)
+}
+
+// RegisterGroup mocks base method.
+func (m *MockRegisterRepository) RegisterGroup(arg0 *models.RegistrationGroup) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterGroup", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterGroup indicates an expected call of RegisterGroup.
+func (mr *MockRegisterRepositoryMockRecorder) RegisterGroup(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterGroup", reflect.TypeOf((*MockRegisterRepository)(nil).RegisterGroup), arg0)
+}
+
+// RetryRefund mocks base method.
+func (m *MockRegisterRepository) RetryRefund(arg0, arg1 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryRefund", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetryRefund indicates an expected call of RetryRefund.
+func (mr *MockRegisterRepositoryMockRecorder) RetryRefund(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryRefund", reflect.TypeOf((*MockRegisterRepository)(nil).RetryRefund), arg0, arg1)
+}
+
+// SetRefundStatus mocks base method.
+func (m *MockRegisterRepository) SetRefundStatus(arg0 models.Cancellation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRefundStatus", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRefundStatus indicates an expected call of SetRefundStatus.
+func (mr *MockRegisterRepositoryMockRecorder) SetRefundStatus(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRefundStatus", reflect.TypeOf((*MockRegisterRepository)(nil).SetRefundStatus), arg0)
+}
+
+// SetRegistrationStatus mocks base method.
+func (m *MockRegisterRepository) SetRegistrationStatus(arg0, arg1 int64, arg2 []int64, arg3 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRegistrationStatus", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetRegistrationStatus indicates an expected call of SetRegistrationStatus.
+func (mr *MockRegisterRepositoryMockRecorder) SetRegistrationStatus(arg0, arg1, arg2, arg3 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterEvent", reflect.TypeOf((*MockRegisterRepository)(nil).RegisterEvent), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRegistrationStatus", reflect.TypeOf((*MockRegisterRepository)(nil).SetRegistrationStatus), arg0, arg1, arg2, arg3)
 }
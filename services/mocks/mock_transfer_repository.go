@@ -0,0 +1,237 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: services/transfer.go
+//
+// Generated by this command:
+//
+//	mockgen -source=services/transfer.go -destination=services/mocks/mock_transfer_repository.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	models "event-booking/models"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRegistrationTransferRepository is a mock of RegistrationTransferRepository interface.
+type MockRegistrationTransferRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRegistrationTransferRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRegistrationTransferRepositoryMockRecorder is the mock recorder for MockRegistrationTransferRepository.
+type MockRegistrationTransferRepositoryMockRecorder struct {
+	mock *MockRegistrationTransferRepository
+}
+
+// NewMockRegistrationTransferRepository creates a new mock instance.
+func NewMockRegistrationTransferRepository(ctrl *gomock.Controller) *MockRegistrationTransferRepository {
+	mock := &MockRegistrationTransferRepository{ctrl: ctrl}
+	mock.recorder = &MockRegistrationTransferRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRegistrationTransferRepository) EXPECT() *MockRegistrationTransferRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AcceptTransfer mocks base method.
+func (m *MockRegistrationTransferRepository) AcceptTransfer(arg0 models.RegistrationTransfer, arg1 time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptTransfer", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptTransfer indicates an expected call of AcceptTransfer.
+func (mr *MockRegistrationTransferRepositoryMockRecorder) AcceptTransfer(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptTransfer", reflect.TypeOf((*MockRegistrationTransferRepository)(nil).AcceptTransfer), arg0, arg1)
+}
+
+// CanAccessEvent mocks base method.
+func (m *MockRegistrationTransferRepository) CanAccessEvent(arg0, arg1, arg2 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CanAccessEvent", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CanAccessEvent indicates an expected call of CanAccessEvent.
+func (mr *MockRegistrationTransferRepositoryMockRecorder) CanAccessEvent(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CanAccessEvent", reflect.TypeOf((*MockRegistrationTransferRepository)(nil).CanAccessEvent), arg0, arg1, arg2)
+}
+
+// CreateTransfer mocks base method.
+func (m *MockRegistrationTransferRepository) CreateTransfer(arg0 *models.RegistrationTransfer) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransfer", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransfer indicates an expected call of CreateTransfer.
+func (mr *MockRegistrationTransferRepositoryMockRecorder) CreateTransfer(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransfer", reflect.TypeOf((*MockRegistrationTransferRepository)(nil).CreateTransfer), arg0)
+}
+
+// GetEventById mocks base method.
+func (m *MockRegistrationTransferRepository) GetEventById(arg0, arg1 int64) (models.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventById", arg0, arg1)
+	ret0, _ := ret[0].(models.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventById indicates an expected call of GetEventById.
+func (mr *MockRegistrationTransferRepositoryMockRecorder) GetEventById(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventById", reflect.TypeOf((*MockRegistrationTransferRepository)(nil).GetEventById), arg0, arg1)
+}
+
+// GetIncomingTransfers mocks base method.
+func (m *MockRegistrationTransferRepository) GetIncomingTransfers(arg0, arg1 int64) ([]models.RegistrationTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncomingTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]models.RegistrationTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncomingTransfers indicates an expected call of GetIncomingTransfers.
+func (mr *MockRegistrationTransferRepositoryMockRecorder) GetIncomingTransfers(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncomingTransfers", reflect.TypeOf((*MockRegistrationTransferRepository)(nil).GetIncomingTransfers), arg0, arg1)
+}
+
+// GetMembership mocks base method.
+func (m *MockRegistrationTransferRepository) GetMembership(arg0, arg1 int64) (models.Membership, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMembership", arg0, arg1)
+	ret0, _ := ret[0].(models.Membership)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMembership indicates an expected call of GetMembership.
+func (mr *MockRegistrationTransferRepositoryMockRecorder) GetMembership(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMembership", reflect.TypeOf((*MockRegistrationTransferRepository)(nil).GetMembership), arg0, arg1)
+}
+
+// GetPendingTransfer mocks base method.
+func (m *MockRegistrationTransferRepository) GetPendingTransfer(arg0, arg1 int64) (models.RegistrationTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingTransfer", arg0, arg1)
+	ret0, _ := ret[0].(models.RegistrationTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingTransfer indicates an expected call of GetPendingTransfer.
+func (mr *MockRegistrationTransferRepositoryMockRecorder) GetPendingTransfer(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingTransfer", reflect.TypeOf((*MockRegistrationTransferRepository)(nil).GetPendingTransfer), arg0, arg1)
+}
+
+// GetRegisteredEventById mocks base method.
+func (m *MockRegistrationTransferRepository) GetRegisteredEventById(arg0, arg1, arg2 int64) (models.RegisterEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRegisteredEventById", arg0, arg1, arg2)
+	ret0, _ := ret[0].(models.RegisterEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRegisteredEventById indicates an expected call of GetRegisteredEventById.
+func (mr *MockRegistrationTransferRepositoryMockRecorder) GetRegisteredEventById(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegisteredEventById", reflect.TypeOf((*MockRegistrationTransferRepository)(nil).GetRegisteredEventById), arg0, arg1, arg2)
+}
+
+// GetRegistration mocks base method.
+func (m *MockRegistrationTransferRepository) GetRegistration(arg0, arg1 int64) (models.RegisterEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRegistration", arg0, arg1)
+	ret0, _ := ret[0].(models.RegisterEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRegistration indicates an expected call of GetRegistration.
+func (mr *MockRegistrationTransferRepositoryMockRecorder) GetRegistration(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegistration", reflect.TypeOf((*MockRegistrationTransferRepository)(nil).GetRegistration), arg0, arg1)
+}
+
+// GetTransfer mocks base method.
+func (m *MockRegistrationTransferRepository) GetTransfer(arg0, arg1 int64) (models.RegistrationTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransfer", arg0, arg1)
+	ret0, _ := ret[0].(models.RegistrationTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransfer indicates an expected call of GetTransfer.
+func (mr *MockRegistrationTransferRepositoryMockRecorder) GetTransfer(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfer", reflect.TypeOf((*MockRegistrationTransferRepository)(nil).GetTransfer), arg0, arg1)
+}
+
+// GetUserByEmail mocks base method.
+func (m *MockRegistrationTransferRepository) GetUserByEmail(arg0 string) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByEmail", arg0)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByEmail indicates an expected call of GetUserByEmail.
+func (mr *MockRegistrationTransferRepositoryMockRecorder) GetUserByEmail(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockRegistrationTransferRepository)(nil).GetUserByEmail), arg0)
+}
+
+// GetUserById mocks base method.
+func (m *MockRegistrationTransferRepository) GetUserById(arg0 int64) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserById", arg0)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserById indicates an expected call of GetUserById.
+func (mr *MockRegistrationTransferRepositoryMockRecorder) GetUserById(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserById", reflect.TypeOf((*MockRegistrationTransferRepository)(nil).GetUserById), arg0)
+}
+
+// SetTransferStatus mocks base method.
+func (m *MockRegistrationTransferRepository) SetTransferStatus(arg0, arg1 int64, arg2 string, arg3 time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTransferStatus", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetTransferStatus indicates an expected call of SetTransferStatus.
+func (mr *MockRegistrationTransferRepositoryMockRecorder) SetTransferStatus(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTransferStatus", reflect.TypeOf((*MockRegistrationTransferRepository)(nil).SetTransferStatus), arg0, arg1, arg2, arg3)
+}
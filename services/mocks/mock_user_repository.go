@@ -3,7 +3,7 @@
 //
 // Generated by this command:
 //
-//	mockgen -source=services/user.go -destination=services/mocks/mock_user_repository.go -package=mocks UserRepository
+//	mockgen -source=services/user.go -destination=services/mocks/mock_user_repository.go -package=mocks
 //
 
 // Package mocks is a generated GoMock package.
@@ -12,6 +12,7 @@ package mocks
 import (
 	models "event-booking/models"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -40,6 +41,49 @@ func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CountPasswordResetsSince mocks base method.
+func (m *MockUserRepository) CountPasswordResetsSince(arg0 int64, arg1 time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountPasswordResetsSince", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountPasswordResetsSince indicates an expected call of CountPasswordResetsSince.
+func (mr *MockUserRepositoryMockRecorder) CountPasswordResetsSince(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountPasswordResetsSince", reflect.TypeOf((*MockUserRepository)(nil).CountPasswordResetsSince), arg0, arg1)
+}
+
+// CreateEmailVerification mocks base method.
+func (m *MockUserRepository) CreateEmailVerification(arg0 int64, arg1, arg2 string, arg3 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEmailVerification", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateEmailVerification indicates an expected call of CreateEmailVerification.
+func (mr *MockUserRepositoryMockRecorder) CreateEmailVerification(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEmailVerification", reflect.TypeOf((*MockUserRepository)(nil).CreateEmailVerification), arg0, arg1, arg2, arg3)
+}
+
+// CreatePasswordReset mocks base method.
+func (m *MockUserRepository) CreatePasswordReset(arg0 int64, arg1 string, arg2, arg3 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePasswordReset", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePasswordReset indicates an expected call of CreatePasswordReset.
+func (mr *MockUserRepositoryMockRecorder) CreatePasswordReset(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePasswordReset", reflect.TypeOf((*MockUserRepository)(nil).CreatePasswordReset), arg0, arg1, arg2, arg3)
+}
+
 // CreateUser mocks base method.
 func (m *MockUserRepository) CreateUser(arg0 *models.User) (int64, error) {
 	m.ctrl.T.Helper()
@@ -55,19 +99,182 @@ func (mr *MockUserRepositoryMockRecorder) CreateUser(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUserRepository)(nil).CreateUser), arg0)
 }
 
+// DeleteUser mocks base method.
+func (m *MockUserRepository) DeleteUser(arg0 int64, arg1 time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUser", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteUser indicates an expected call of DeleteUser.
+func (mr *MockUserRepositoryMockRecorder) DeleteUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockUserRepository)(nil).DeleteUser), arg0, arg1)
+}
+
+// GetProfile mocks base method.
+func (m *MockUserRepository) GetProfile(arg0 int64) (models.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProfile", arg0)
+	ret0, _ := ret[0].(models.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProfile indicates an expected call of GetProfile.
+func (mr *MockUserRepositoryMockRecorder) GetProfile(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfile", reflect.TypeOf((*MockUserRepository)(nil).GetProfile), arg0)
+}
+
+// GetUserByEmail mocks base method.
+func (m *MockUserRepository) GetUserByEmail(arg0 string) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByEmail", arg0)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByEmail indicates an expected call of GetUserByEmail.
+func (mr *MockUserRepositoryMockRecorder) GetUserByEmail(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockUserRepository)(nil).GetUserByEmail), arg0)
+}
+
+// GetUserById mocks base method.
+func (m *MockUserRepository) GetUserById(arg0 int64) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserById", arg0)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserById indicates an expected call of GetUserById.
+func (mr *MockUserRepositoryMockRecorder) GetUserById(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserById", reflect.TypeOf((*MockUserRepository)(nil).GetUserById), arg0)
+}
+
 // GetUsers mocks base method.
-func (m *MockUserRepository) GetUsers() ([]models.User, error) {
+func (m *MockUserRepository) GetUsers(arg0 int64) ([]models.OrganizationMember, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUsers")
-	ret0, _ := ret[0].([]models.User)
+	ret := m.ctrl.Call(m, "GetUsers", arg0)
+	ret0, _ := ret[0].([]models.OrganizationMember)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUsers indicates an expected call of GetUsers.
-func (mr *MockUserRepositoryMockRecorder) GetUsers() *gomock.Call {
+func (mr *MockUserRepositoryMockRecorder) GetUsers(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*MockUserRepository)(nil).GetUsers))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*MockUserRepository)(nil).GetUsers), arg0)
+}
+
+// HasEmailVerification mocks base method.
+func (m *MockUserRepository) HasEmailVerification(arg0 int64, arg1 string, arg2 time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasEmailVerification", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasEmailVerification indicates an expected call of HasEmailVerification.
+func (mr *MockUserRepositoryMockRecorder) HasEmailVerification(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasEmailVerification", reflect.TypeOf((*MockUserRepository)(nil).HasEmailVerification), arg0, arg1, arg2)
+}
+
+// HasPaidRegistrations mocks base method.
+func (m *MockUserRepository) HasPaidRegistrations(arg0 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasPaidRegistrations", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasPaidRegistrations indicates an expected call of HasPaidRegistrations.
+func (mr *MockUserRepositoryMockRecorder) HasPaidRegistrations(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasPaidRegistrations", reflect.TypeOf((*MockUserRepository)(nil).HasPaidRegistrations), arg0)
+}
+
+// HasPasswordReset mocks base method.
+func (m *MockUserRepository) HasPasswordReset(arg0 string, arg1 time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasPasswordReset", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasPasswordReset indicates an expected call of HasPasswordReset.
+func (mr *MockUserRepositoryMockRecorder) HasPasswordReset(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasPasswordReset", reflect.TypeOf((*MockUserRepository)(nil).HasPasswordReset), arg0, arg1)
+}
+
+// IsEventOrganizer mocks base method.
+func (m *MockUserRepository) IsEventOrganizer(arg0 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsEventOrganizer", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsEventOrganizer indicates an expected call of IsEventOrganizer.
+func (mr *MockUserRepositoryMockRecorder) IsEventOrganizer(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsEventOrganizer", reflect.TypeOf((*MockUserRepository)(nil).IsEventOrganizer), arg0)
+}
+
+// ResetPassword mocks base method.
+func (m *MockUserRepository) ResetPassword(arg0, arg1 string, arg2 time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockUserRepositoryMockRecorder) ResetPassword(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockUserRepository)(nil).ResetPassword), arg0, arg1, arg2)
+}
+
+// SaveProfile mocks base method.
+func (m *MockUserRepository) SaveProfile(arg0 *models.Profile) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveProfile", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveProfile indicates an expected call of SaveProfile.
+func (mr *MockUserRepositoryMockRecorder) SaveProfile(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveProfile", reflect.TypeOf((*MockUserRepository)(nil).SaveProfile), arg0)
+}
+
+// UpdatePassword mocks base method.
+func (m *MockUserRepository) UpdatePassword(arg0 int64, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassword", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *MockUserRepositoryMockRecorder) UpdatePassword(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockUserRepository)(nil).UpdatePassword), arg0, arg1)
 }
 
 // ValidateCredentials mocks base method.
@@ -84,3 +291,18 @@ func (mr *MockUserRepositoryMockRecorder) ValidateCredentials(arg0 any) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateCredentials", reflect.TypeOf((*MockUserRepository)(nil).ValidateCredentials), arg0)
 }
+
+// VerifyEmail mocks base method.
+func (m *MockUserRepository) VerifyEmail(arg0 int64, arg1 string, arg2 time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockUserRepositoryMockRecorder) VerifyEmail(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockUserRepository)(nil).VerifyEmail), arg0, arg1, arg2)
+}
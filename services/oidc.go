@@ -0,0 +1,159 @@
+package services
+
+import (
+	"errors"
+	"event-booking/models"
+	"event-booking/utils"
+	"log"
+	"slices"
+	"time"
+)
+
+// IdentityProvider is an OpenID Connect provider users can log in with.
+type IdentityProvider interface {
+	Name() string
+	AuthCodeURL(state, nonce, codeVerifier string) (string, error)
+	Exchange(code, codeVerifier, nonce string) (models.ExternalIdentity, error)
+}
+
+type OIDCRepository interface {
+	GetUserByEmail(string) (models.User, error)
+	CreateOIDCLoginState(*models.OIDCLoginState) error
+	ConsumeOIDCLoginState(string, string, time.Time) (models.OIDCLoginState, bool, error)
+	GetUserByExternalIdentity(string, string) (models.User, error)
+	LinkExternalIdentity(int64, models.ExternalIdentity, time.Time) error
+	CreateExternalUser(models.ExternalIdentity, time.Time) (int64, error)
+}
+
+const oidcLoginStateTTL = 10 * time.Minute
+
+var ErrUnknownOIDCProvider = errors.New("Unknown identity provider")
+var ErrInvalidOIDCState = errors.New("Login request is invalid or has expired, please start again")
+var ErrOIDCLoginFailed = errors.New("Identity provider login failed")
+var ErrOIDCEmailNotVerified = errors.New("The identity provider has not verified your email address")
+
+// OIDCService logs users in through external identity providers. Identities
+// are linked to local accounts by verified email address, and new accounts
+// are created for unknown addresses.
+type OIDCService struct {
+	repo      OIDCRepository
+	providers map[string]IdentityProvider
+	now       func() time.Time
+}
+
+func NewOIDCService(repo OIDCRepository, providers ...IdentityProvider) *OIDCService {
+	byName := map[string]IdentityProvider{}
+	for _, p := range providers {
+		byName[p.Name()] = p
+	}
+	return &OIDCService{
+		repo:      repo,
+		providers: byName,
+		now:       time.Now,
+	}
+}
+
+// Providers returns the names of the configured providers.
+func (s *OIDCService) Providers() []string {
+	names := []string{}
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// StartLogin returns the provider URL to redirect the user to. The state,
+// nonce and PKCE verifier are remembered until the provider redirects back.
+func (s *OIDCService) StartLogin(providerName string) (string, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return "", ErrUnknownOIDCProvider
+	}
+
+	values := make([]string, 3)
+	for i := range values {
+		value, err := utils.GenerateRandomToken(32)
+		if err != nil {
+			return "", err
+		}
+		values[i] = value
+	}
+	state, nonce, verifier := values[0], values[1], values[2]
+
+	err := s.repo.CreateOIDCLoginState(&models.OIDCLoginState{
+		StateHash:    utils.HashToken(state),
+		Provider:     providerName,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    s.now().Add(oidcLoginStateTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return provider.AuthCodeURL(state, nonce, verifier)
+}
+
+// CompleteLogin handles the redirect back from the provider and returns a
+// session token. Like UserService.Login, it returns ErrTwoFactorRequired
+// together with the user when a second factor is needed.
+func (s *OIDCService) CompleteLogin(providerName, state, code string) (string, models.User, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return "", models.User{}, ErrUnknownOIDCProvider
+	}
+
+	loginState, found, err := s.repo.ConsumeOIDCLoginState(utils.HashToken(state), providerName, s.now())
+	if err != nil {
+		return "", models.User{}, err
+	}
+	if !found {
+		return "", models.User{}, ErrInvalidOIDCState
+	}
+
+	identity, err := provider.Exchange(code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		log.Printf("oidc login with %s failed: %v", providerName, err)
+		return "", models.User{}, ErrOIDCLoginFailed
+	}
+
+	u, err := s.resolveUser(identity)
+	if err != nil {
+		return "", models.User{}, err
+	}
+
+	if u.TwoFactorEnabled {
+		return "", u, ErrTwoFactorRequired
+	}
+
+	token, err := utils.GenerateToken(u.Email, u.Id, u.TokenVersion)
+	return token, u, err
+}
+
+// resolveUser finds the account of identity. Unknown identities are linked to
+// the account with the same email address, or get a new account, but only
+// when the provider has verified the address.
+func (s *OIDCService) resolveUser(identity models.ExternalIdentity) (models.User, error) {
+	u, err := s.repo.GetUserByExternalIdentity(identity.Provider, identity.Subject)
+	if err == nil {
+		return u, nil
+	}
+
+	if !identity.EmailVerified || identity.Email == "" {
+		return models.User{}, ErrOIDCEmailNotVerified
+	}
+
+	now := s.now()
+	existing, err := s.repo.GetUserByEmail(identity.Email)
+	if err == nil {
+		err = s.repo.LinkExternalIdentity(existing.Id, identity, now)
+	} else {
+		_, err = s.repo.CreateExternalUser(identity, now)
+	}
+	if err != nil {
+		return models.User{}, err
+	}
+
+	return s.repo.GetUserByExternalIdentity(identity.Provider, identity.Subject)
+}
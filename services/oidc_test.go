@@ -0,0 +1,197 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"event-booking/models"
+	"event-booking/services/mocks"
+	"event-booking/testutil"
+	"event-booking/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newTestOIDCService(ctrl *gomock.Controller) (*OIDCService, *mocks.MockOIDCRepository, *mocks.MockIdentityProvider) {
+	mockRepo := mocks.NewMockOIDCRepository(ctrl)
+	mockProvider := mocks.NewMockIdentityProvider(ctrl)
+	mockProvider.EXPECT().Name().Return("corp").AnyTimes()
+	return NewOIDCService(mockRepo, mockProvider), mockRepo, mockProvider
+}
+
+func testIdentity(verified bool) models.ExternalIdentity {
+	return models.ExternalIdentity{Provider: "corp", Subject: "sub-1", Email: "jane@example.com", EmailVerified: verified}
+}
+
+func TestOIDCStartLogin_StoresState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockRepo, mockProvider := newTestOIDCService(ctrl)
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	var stored models.OIDCLoginState
+	mockRepo.EXPECT().CreateOIDCLoginState(gomock.Any()).DoAndReturn(func(s *models.OIDCLoginState) error {
+		stored = *s
+		return nil
+	})
+	mockProvider.EXPECT().AuthCodeURL(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(state, nonce, verifier string) (string, error) {
+			assert.Equal(t, utils.HashToken(state), stored.StateHash)
+			assert.Equal(t, stored.Nonce, nonce)
+			assert.Equal(t, stored.CodeVerifier, verifier)
+			return "https://idp.example.com/authorize", nil
+		})
+
+	redirectURL, err := service.StartLogin("corp")
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://idp.example.com/authorize", redirectURL)
+	assert.Equal(t, "corp", stored.Provider)
+	assert.Equal(t, now.Add(oidcLoginStateTTL), stored.ExpiresAt)
+}
+
+func TestOIDCStartLogin_UnknownProvider(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, _ := newTestOIDCService(ctrl)
+
+	_, err := service.StartLogin("other")
+
+	assert.Equal(t, ErrUnknownOIDCProvider, err)
+}
+
+func TestOIDCCompleteLogin_InvalidState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockRepo, _ := newTestOIDCService(ctrl)
+
+	mockRepo.EXPECT().ConsumeOIDCLoginState(utils.HashToken("state"), "corp", gomock.Any()).Return(models.OIDCLoginState{}, false, nil)
+
+	_, _, err := service.CompleteLogin("corp", "state", "code")
+
+	assert.Equal(t, ErrInvalidOIDCState, err)
+}
+
+func TestOIDCCompleteLogin_ExistingIdentity(t *testing.T) {
+	testutil.SetupTestEnv(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockRepo, mockProvider := newTestOIDCService(ctrl)
+
+	mockRepo.EXPECT().ConsumeOIDCLoginState(utils.HashToken("state"), "corp", gomock.Any()).
+		Return(models.OIDCLoginState{Nonce: "nonce", CodeVerifier: "verifier"}, true, nil)
+	mockProvider.EXPECT().Exchange("code", "verifier", "nonce").Return(testIdentity(false), nil)
+	mockRepo.EXPECT().GetUserByExternalIdentity("corp", "sub-1").Return(models.User{Id: 3, Email: "jane@example.com"}, nil)
+
+	token, u, err := service.CompleteLogin("corp", "state", "code")
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), u.Id)
+	userId, err := utils.VerifyToken(&token)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), userId)
+}
+
+func TestOIDCCompleteLogin_LinksByVerifiedEmail(t *testing.T) {
+	testutil.SetupTestEnv(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockRepo, mockProvider := newTestOIDCService(ctrl)
+
+	mockRepo.EXPECT().ConsumeOIDCLoginState(gomock.Any(), "corp", gomock.Any()).
+		Return(models.OIDCLoginState{Nonce: "nonce", CodeVerifier: "verifier"}, true, nil)
+	mockProvider.EXPECT().Exchange("code", "verifier", "nonce").Return(testIdentity(true), nil)
+	gomock.InOrder(
+		mockRepo.EXPECT().GetUserByExternalIdentity("corp", "sub-1").Return(models.User{}, errors.New("no rows")),
+		mockRepo.EXPECT().GetUserByEmail("jane@example.com").Return(models.User{Id: 3}, nil),
+		mockRepo.EXPECT().LinkExternalIdentity(int64(3), testIdentity(true), gomock.Any()).Return(nil),
+		mockRepo.EXPECT().GetUserByExternalIdentity("corp", "sub-1").Return(models.User{Id: 3, Email: "jane@example.com", Verified: true}, nil),
+	)
+
+	_, u, err := service.CompleteLogin("corp", "state", "code")
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), u.Id)
+}
+
+func TestOIDCCompleteLogin_CreatesUser(t *testing.T) {
+	testutil.SetupTestEnv(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockRepo, mockProvider := newTestOIDCService(ctrl)
+
+	mockRepo.EXPECT().ConsumeOIDCLoginState(gomock.Any(), "corp", gomock.Any()).
+		Return(models.OIDCLoginState{Nonce: "nonce", CodeVerifier: "verifier"}, true, nil)
+	mockProvider.EXPECT().Exchange("code", "verifier", "nonce").Return(testIdentity(true), nil)
+	gomock.InOrder(
+		mockRepo.EXPECT().GetUserByExternalIdentity("corp", "sub-1").Return(models.User{}, errors.New("no rows")),
+		mockRepo.EXPECT().GetUserByEmail("jane@example.com").Return(models.User{}, errors.New("no rows")),
+		mockRepo.EXPECT().CreateExternalUser(testIdentity(true), gomock.Any()).Return(int64(4), nil),
+		mockRepo.EXPECT().GetUserByExternalIdentity("corp", "sub-1").Return(models.User{Id: 4, Email: "jane@example.com", Verified: true}, nil),
+	)
+
+	token, u, err := service.CompleteLogin("corp", "state", "code")
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), u.Id)
+	assert.NotEmpty(t, token)
+}
+
+func TestOIDCCompleteLogin_UnverifiedEmailIsNotLinked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockRepo, mockProvider := newTestOIDCService(ctrl)
+
+	mockRepo.EXPECT().ConsumeOIDCLoginState(gomock.Any(), "corp", gomock.Any()).
+		Return(models.OIDCLoginState{Nonce: "nonce", CodeVerifier: "verifier"}, true, nil)
+	mockProvider.EXPECT().Exchange("code", "verifier", "nonce").Return(testIdentity(false), nil)
+	mockRepo.EXPECT().GetUserByExternalIdentity("corp", "sub-1").Return(models.User{}, errors.New("no rows"))
+
+	_, _, err := service.CompleteLogin("corp", "state", "code")
+
+	assert.Equal(t, ErrOIDCEmailNotVerified, err)
+}
+
+func TestOIDCCompleteLogin_TwoFactorRequired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockRepo, mockProvider := newTestOIDCService(ctrl)
+
+	mockRepo.EXPECT().ConsumeOIDCLoginState(gomock.Any(), "corp", gomock.Any()).
+		Return(models.OIDCLoginState{Nonce: "nonce", CodeVerifier: "verifier"}, true, nil)
+	mockProvider.EXPECT().Exchange("code", "verifier", "nonce").Return(testIdentity(true), nil)
+	mockRepo.EXPECT().GetUserByExternalIdentity("corp", "sub-1").Return(models.User{Id: 3, TwoFactorEnabled: true}, nil)
+
+	token, u, err := service.CompleteLogin("corp", "state", "code")
+
+	assert.Equal(t, ErrTwoFactorRequired, err)
+	assert.Empty(t, token)
+	assert.Equal(t, int64(3), u.Id)
+}
+
+func TestOIDCCompleteLogin_ExchangeFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockRepo, mockProvider := newTestOIDCService(ctrl)
+
+	mockRepo.EXPECT().ConsumeOIDCLoginState(gomock.Any(), "corp", gomock.Any()).
+		Return(models.OIDCLoginState{Nonce: "nonce", CodeVerifier: "verifier"}, true, nil)
+	mockProvider.EXPECT().Exchange("code", "verifier", "nonce").Return(models.ExternalIdentity{}, errors.New("invalid_grant"))
+
+	_, _, err := service.CompleteLogin("corp", "state", "code")
+
+	assert.Equal(t, ErrOIDCLoginFailed, err)
+}
@@ -0,0 +1,366 @@
+package services
+
+import (
+	"errors"
+	"event-booking/models"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+)
+
+// PaymentProvider takes payments for orders.
+type PaymentProvider interface {
+	// CreateIntent starts a payment. Calls with the same reference return
+	// the same intent.
+	CreateIntent(reference string, amount int64, currency string) (models.PaymentIntent, error)
+	Confirm(intentId, paymentMethod string) (models.PaymentIntent, error)
+	Refund(intentId string, amount int64) error
+	// VerifyWebhook checks the signature of a webhook call and returns the
+	// intent it reports on.
+	VerifyWebhook(payload []byte, signature string) (models.PaymentIntent, error)
+}
+
+type OrderRepository interface {
+	GetEventById(int64, int64) (models.Event, error)
+	GetTicketType(int64, int64, int64) (models.TicketType, error)
+	GetRegisteredEventById(int64, int64, int64) (models.RegisterEvent, error)
+	GetGroup(int64, int64) (models.RegistrationGroup, error)
+	CancelRegistration(int64, int64) error
+	CancelGroupRegistrations(int64, int64) error
+	ConfirmRegistration(int64, int64, string) (bool, error)
+	ConfirmGroup(int64, int64, string) (bool, error)
+	AwaitsPayment(int64, int64, int64) (bool, error)
+	CreateOrder(*models.Order) error
+	GetOrder(int64, int64) (models.Order, error)
+	GetOpenOrder(int64, int64) (models.Order, error)
+	GetOpenGroupOrder(int64, int64) (models.Order, error)
+	GetOrderByPaymentIntent(string) (models.Order, error)
+	SetOrderStatus(int64, string, string, time.Time) (bool, error)
+}
+
+// Order statuses. An order is pending until the payment succeeds or fails;
+// failed payments can be retried.
+const (
+	OrderPending   = "pending"
+	OrderPaid      = "paid"
+	OrderFailed    = "failed"
+	OrderCancelled = "cancelled"
+	OrderRefunded  = "refunded"
+)
+
+// orderTransitions lists the statuses an order can move to from each
+// status. Cancelled orders are refunded when their payment still succeeds.
+var orderTransitions = map[string][]string{
+	OrderPending:   {OrderPaid, OrderFailed, OrderCancelled},
+	OrderFailed:    {OrderPaid, OrderCancelled},
+	OrderPaid:      {OrderRefunded},
+	OrderCancelled: {OrderRefunded},
+}
+
+var ErrOrderNotFound = errors.New("Order not found")
+var ErrNothingToPay = errors.New("Registration does not need a payment")
+var ErrPaidWithGroup = errors.New("Registration is paid for by the booker of its group")
+var ErrOrderClosed = errors.New("Order can no longer be paid")
+var ErrOrderChanged = errors.New("Order was changed by another request, please retry")
+var ErrInvalidOrderTransition = errors.New("Order status cannot change")
+var ErrPaymentFailed = errors.New("Payment failed")
+var ErrPaymentProvider = errors.New("Payment provider is unavailable")
+var ErrInvalidWebhook = errors.New("Webhook signature is invalid")
+
+// OrderService takes payments for registrations with a paid ticket type.
+// Such registrations await payment and are only confirmed once it
+// succeeds.
+type OrderService struct {
+	repo     OrderRepository
+	provider PaymentProvider
+	now      func() time.Time
+}
+
+func NewOrderService(repo OrderRepository, provider PaymentProvider) *OrderService {
+	return &OrderService{
+		repo:     repo,
+		provider: provider,
+		now:      time.Now,
+	}
+}
+
+// Checkout returns the order for userId's registration awaiting payment,
+// creating it and its payment intent on the first call.
+func (s *OrderService) Checkout(orgId, userId, eventId int64) (models.Order, error) {
+	registration, err := s.repo.GetRegisteredEventById(orgId, userId, eventId)
+	if err != nil {
+		return models.Order{}, ErrRegisterEventNotFound
+	}
+	if registration.Status != RegistrationAwaitingPayment {
+		return models.Order{}, ErrNothingToPay
+	}
+	if registration.GroupId != 0 {
+		return models.Order{}, ErrPaidWithGroup
+	}
+
+	order, err := s.repo.GetOpenOrder(orgId, registration.Id)
+	if err == nil {
+		return order, nil
+	}
+
+	ticketType, err := s.repo.GetTicketType(orgId, eventId, registration.TicketTypeId)
+	if err != nil {
+		return models.Order{}, err
+	}
+
+	intent, err := s.provider.CreateIntent(fmt.Sprintf("registration-%d", registration.Id), registration.Amount, ticketType.Currency)
+	if err != nil {
+		log.Printf("creating payment for registration %d failed: %v", registration.Id, err)
+		return models.Order{}, ErrPaymentProvider
+	}
+
+	now := s.now()
+	order = models.Order{
+		RegistrationId:  registration.Id,
+		UserId:          userId,
+		EventId:         eventId,
+		OrganizationId:  orgId,
+		Amount:          intent.Amount,
+		Currency:        intent.Currency,
+		Status:          OrderPending,
+		PaymentIntentId: intent.Id,
+		ClientSecret:    intent.ClientSecret,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	err = s.repo.CreateOrder(&order)
+	if err != nil {
+		return models.Order{}, err
+	}
+	return order, nil
+}
+
+// CheckoutGroup returns the order for the registrations of a group booked by
+// bookerId that await payment, creating it and its payment intent on the
+// first call. The order is for the total of the registrations; cancelling
+// one of them before paying cancels the order, and the next checkout
+// creates one for the new total.
+func (s *OrderService) CheckoutGroup(orgId, bookerId, groupId int64) (models.Order, error) {
+	group, err := s.repo.GetGroup(orgId, groupId)
+	if err != nil || group.BookerId != bookerId {
+		return models.Order{}, ErrGroupNotFound
+	}
+
+	var total, ticketTypeId int64
+	for _, registration := range group.Registrations {
+		if registration.Status == RegistrationAwaitingPayment {
+			total += registration.Amount
+			ticketTypeId = registration.TicketTypeId
+		}
+	}
+	if total == 0 {
+		return models.Order{}, ErrNothingToPay
+	}
+
+	order, err := s.repo.GetOpenGroupOrder(orgId, group.Id)
+	if err == nil {
+		return order, nil
+	}
+
+	ticketType, err := s.repo.GetTicketType(orgId, group.EventId, ticketTypeId)
+	if err != nil {
+		return models.Order{}, err
+	}
+
+	// The total is part of the reference, so a group whose total changed
+	// gets a new payment intent.
+	intent, err := s.provider.CreateIntent(fmt.Sprintf("group-%d-%d", group.Id, total), total, ticketType.Currency)
+	if err != nil {
+		log.Printf("creating payment for group %d failed: %v", group.Id, err)
+		return models.Order{}, ErrPaymentProvider
+	}
+
+	now := s.now()
+	order = models.Order{
+		GroupId:         group.Id,
+		UserId:          bookerId,
+		EventId:         group.EventId,
+		OrganizationId:  orgId,
+		Amount:          intent.Amount,
+		Currency:        intent.Currency,
+		Status:          OrderPending,
+		PaymentIntentId: intent.Id,
+		ClientSecret:    intent.ClientSecret,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	err = s.repo.CreateOrder(&order)
+	if err != nil {
+		return models.Order{}, err
+	}
+	return order, nil
+}
+
+// GetOrder returns an order of userId.
+func (s *OrderService) GetOrder(orgId, userId, id int64) (models.Order, error) {
+	order, err := s.repo.GetOrder(orgId, id)
+	if err != nil || order.UserId != userId {
+		return models.Order{}, ErrOrderNotFound
+	}
+	return order, nil
+}
+
+// Confirm pays an order of userId with paymentMethod. Paying an order that
+// is already paid returns it unchanged.
+func (s *OrderService) Confirm(orgId, userId, id int64, paymentMethod string) (models.Order, error) {
+	order, err := s.GetOrder(orgId, userId, id)
+	if err != nil {
+		return models.Order{}, err
+	}
+	if order.Status == OrderPaid {
+		return order, nil
+	}
+	if order.Status != OrderPending && order.Status != OrderFailed {
+		return order, ErrOrderClosed
+	}
+
+	intent, err := s.provider.Confirm(order.PaymentIntentId, paymentMethod)
+	if err != nil {
+		log.Printf("confirming payment of order %d failed: %v", order.Id, err)
+		return order, ErrPaymentProvider
+	}
+
+	order, err = s.apply(order, intent)
+	if err != nil {
+		return order, err
+	}
+	if order.Status == OrderFailed {
+		return order, ErrPaymentFailed
+	}
+	return order, nil
+}
+
+// HandleWebhook applies a payment update sent by the provider. Updates
+// that were already applied are ignored, so the provider may retry them.
+func (s *OrderService) HandleWebhook(payload []byte, signature string) error {
+	intent, err := s.provider.VerifyWebhook(payload, signature)
+	if err != nil {
+		return ErrInvalidWebhook
+	}
+
+	order, err := s.repo.GetOrderByPaymentIntent(intent.Id)
+	if err != nil {
+		return ErrOrderNotFound
+	}
+
+	_, err = s.apply(order, intent)
+	if errors.Is(err, ErrEventFull) {
+		return nil
+	}
+	return err
+}
+
+// apply moves the order to the status of its payment intent.
+func (s *OrderService) apply(order models.Order, intent models.PaymentIntent) (models.Order, error) {
+	switch intent.Status {
+	case models.PaymentIntentSucceeded:
+		return s.paid(order)
+	case models.PaymentIntentFailed:
+		if order.Status == OrderPending {
+			return order, s.transition(&order, OrderFailed)
+		}
+	}
+	return order, nil
+}
+
+// paid marks the order as paid and confirms its registration, or all
+// registrations of its group. When the event filled up while the order was
+// being paid, or the registration was cancelled in the meantime, the
+// payment is refunded instead.
+func (s *OrderService) paid(order models.Order) (models.Order, error) {
+	switch order.Status {
+	case OrderCancelled:
+		return order, s.refund(&order)
+	case OrderPaid, OrderRefunded:
+		// A call that failed after the order changed is completed when the
+		// provider retries it.
+		awaiting, err := s.repo.AwaitsPayment(order.OrganizationId, order.RegistrationId, order.GroupId)
+		if err != nil || !awaiting {
+			return order, err
+		}
+		if order.Status == OrderRefunded {
+			return order, s.cancelRegistrations(order)
+		}
+	default:
+		err := s.transition(&order, OrderPaid)
+		if err != nil {
+			return order, err
+		}
+	}
+
+	event, err := s.repo.GetEventById(order.OrganizationId, order.EventId)
+	if err != nil {
+		return order, err
+	}
+	status := RegistrationApproved
+	if event.RequiresApproval {
+		status = RegistrationPending
+	}
+
+	var confirmed bool
+	if order.GroupId != 0 {
+		confirmed, err = s.repo.ConfirmGroup(order.OrganizationId, order.GroupId, status)
+	} else {
+		confirmed, err = s.repo.ConfirmRegistration(order.OrganizationId, order.RegistrationId, status)
+	}
+	if err != nil {
+		return order, err
+	}
+	if confirmed {
+		return order, nil
+	}
+
+	err = s.refund(&order)
+	if err != nil {
+		return order, err
+	}
+	err = s.cancelRegistrations(order)
+	if err != nil {
+		return order, err
+	}
+	return order, ErrEventFull
+}
+
+// cancelRegistrations cancels the registration or group of the order.
+func (s *OrderService) cancelRegistrations(order models.Order) error {
+	if order.GroupId != 0 {
+		return s.repo.CancelGroupRegistrations(order.OrganizationId, order.GroupId)
+	}
+	return s.repo.CancelRegistration(order.OrganizationId, order.RegistrationId)
+}
+
+func (s *OrderService) refund(order *models.Order) error {
+	err := s.provider.Refund(order.PaymentIntentId, order.Amount)
+	if err != nil {
+		log.Printf("refunding order %d failed: %v", order.Id, err)
+		return ErrPaymentProvider
+	}
+	return s.transition(order, OrderRefunded)
+}
+
+// transition moves the order to status unless another request changed it
+// first.
+func (s *OrderService) transition(order *models.Order, status string) error {
+	if !slices.Contains(orderTransitions[order.Status], status) {
+		return fmt.Errorf("%w: from %s to %s", ErrInvalidOrderTransition, order.Status, status)
+	}
+
+	now := s.now()
+	updated, err := s.repo.SetOrderStatus(order.Id, order.Status, status, now)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return ErrOrderChanged
+	}
+
+	order.Status = status
+	order.UpdatedAt = now
+	return nil
+}
@@ -0,0 +1,427 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"event-booking/models"
+	"event-booking/payment"
+	"event-booking/services/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func createTestAwaitingRegistration() models.RegisterEvent {
+	return models.RegisterEvent{
+		Id: 100, UserId: 10, EventId: 1, OrganizationId: testOrgId, TicketTypeId: 2, Amount: 2500,
+		Status: RegistrationAwaitingPayment,
+	}
+}
+
+// createTestOrder starts a checkout for the test registration with the fake
+// provider and returns the order it stored.
+func createTestOrder(t *testing.T, mockRepo *mocks.MockOrderRepository, service *OrderService) models.Order {
+	mockRepo.EXPECT().GetRegisteredEventById(testOrgId, int64(10), int64(1)).Return(createTestAwaitingRegistration(), nil)
+	mockRepo.EXPECT().GetOpenOrder(testOrgId, int64(100)).Return(models.Order{}, errors.New("no rows"))
+	mockRepo.EXPECT().GetTicketType(testOrgId, int64(1), int64(2)).Return(createTestTicketTypes()[1], nil)
+	mockRepo.EXPECT().CreateOrder(gomock.Any()).DoAndReturn(func(o *models.Order) error {
+		o.Id = 7
+		return nil
+	})
+
+	order, err := service.Checkout(testOrgId, 10, 1)
+	require.NoError(t, err)
+	return order
+}
+
+func TestCheckout_CreatesOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	service := NewOrderService(mockRepo, payment.NewFakeProvider("secret"))
+
+	order := createTestOrder(t, mockRepo, service)
+
+	assert.Equal(t, int64(7), order.Id)
+	assert.Equal(t, int64(2500), order.Amount)
+	assert.Equal(t, "EUR", order.Currency)
+	assert.Equal(t, OrderPending, order.Status)
+	assert.True(t, strings.HasPrefix(order.PaymentIntentId, "pi_fake_"))
+	assert.NotEmpty(t, order.ClientSecret)
+}
+
+func TestCheckout_NothingToPay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	service := NewOrderService(mockRepo, payment.NewFakeProvider("secret"))
+
+	mockRepo.EXPECT().GetRegisteredEventById(testOrgId, int64(10), int64(1)).Return(createTestRegisteredEvent(100, 10, 1), nil)
+
+	_, err := service.Checkout(testOrgId, 10, 1)
+
+	assert.Equal(t, ErrNothingToPay, err)
+}
+
+func TestCheckout_ProviderUnavailable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	mockProvider := mocks.NewMockPaymentProvider(ctrl)
+	service := NewOrderService(mockRepo, mockProvider)
+
+	mockRepo.EXPECT().GetRegisteredEventById(testOrgId, int64(10), int64(1)).Return(createTestAwaitingRegistration(), nil)
+	mockRepo.EXPECT().GetOpenOrder(testOrgId, int64(100)).Return(models.Order{}, errors.New("no rows"))
+	mockRepo.EXPECT().GetTicketType(testOrgId, int64(1), int64(2)).Return(createTestTicketTypes()[1], nil)
+	mockProvider.EXPECT().CreateIntent("registration-100", int64(2500), "EUR").Return(models.PaymentIntent{}, errors.New("timeout"))
+
+	_, err := service.Checkout(testOrgId, 10, 1)
+
+	assert.Equal(t, ErrPaymentProvider, err)
+}
+
+func TestConfirm_PaymentSucceeds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	service := NewOrderService(mockRepo, payment.NewFakeProvider("secret"))
+	order := createTestOrder(t, mockRepo, service)
+
+	mockRepo.EXPECT().GetOrder(testOrgId, int64(7)).Return(order, nil)
+	mockRepo.EXPECT().SetOrderStatus(int64(7), OrderPending, OrderPaid, gomock.Any()).Return(true, nil)
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().ConfirmRegistration(testOrgId, int64(100), RegistrationApproved).Return(true, nil)
+
+	paid, err := service.Confirm(testOrgId, 10, 7, "pm_card_visa")
+
+	require.NoError(t, err)
+	assert.Equal(t, OrderPaid, paid.Status)
+}
+
+func TestConfirm_RequiresApprovalAfterPayment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	service := NewOrderService(mockRepo, payment.NewFakeProvider("secret"))
+	order := createTestOrder(t, mockRepo, service)
+
+	event := createTestEvent(1, 5)
+	event.RequiresApproval = true
+
+	mockRepo.EXPECT().GetOrder(testOrgId, int64(7)).Return(order, nil)
+	mockRepo.EXPECT().SetOrderStatus(int64(7), OrderPending, OrderPaid, gomock.Any()).Return(true, nil)
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(event, nil)
+	mockRepo.EXPECT().ConfirmRegistration(testOrgId, int64(100), RegistrationPending).Return(true, nil)
+
+	_, err := service.Confirm(testOrgId, 10, 7, "pm_card_visa")
+
+	require.NoError(t, err)
+}
+
+func TestConfirm_PaymentDeclined(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	service := NewOrderService(mockRepo, payment.NewFakeProvider("secret"))
+	order := createTestOrder(t, mockRepo, service)
+
+	mockRepo.EXPECT().GetOrder(testOrgId, int64(7)).Return(order, nil)
+	mockRepo.EXPECT().SetOrderStatus(int64(7), OrderPending, OrderFailed, gomock.Any()).Return(true, nil)
+
+	failed, err := service.Confirm(testOrgId, 10, 7, payment.MethodDeclined)
+
+	assert.Equal(t, ErrPaymentFailed, err)
+	assert.Equal(t, OrderFailed, failed.Status)
+}
+
+func TestConfirm_EventFilledUpIsRefunded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	provider := payment.NewFakeProvider("secret")
+	service := NewOrderService(mockRepo, provider)
+	order := createTestOrder(t, mockRepo, service)
+
+	mockRepo.EXPECT().GetOrder(testOrgId, int64(7)).Return(order, nil)
+	mockRepo.EXPECT().SetOrderStatus(int64(7), OrderPending, OrderPaid, gomock.Any()).Return(true, nil)
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().ConfirmRegistration(testOrgId, int64(100), RegistrationApproved).Return(false, nil)
+	mockRepo.EXPECT().SetOrderStatus(int64(7), OrderPaid, OrderRefunded, gomock.Any()).Return(true, nil)
+	mockRepo.EXPECT().CancelRegistration(testOrgId, int64(100)).Return(nil)
+
+	refunded, err := service.Confirm(testOrgId, 10, 7, "pm_card_visa")
+
+	assert.Equal(t, ErrEventFull, err)
+	assert.Equal(t, OrderRefunded, refunded.Status)
+	assert.Equal(t, int64(2500), provider.Refunded(order.PaymentIntentId))
+}
+
+func createTestAwaitingGroup() models.RegistrationGroup {
+	group := createTestGroup()
+	for i := range group.Registrations {
+		group.Registrations[i].Status = RegistrationAwaitingPayment
+		group.Registrations[i].TicketTypeId = 2
+		group.Registrations[i].Amount = 2500
+	}
+	return group
+}
+
+func TestCheckoutGroup_OneOrderForTheTotal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	service := NewOrderService(mockRepo, payment.NewFakeProvider("secret"))
+
+	mockRepo.EXPECT().GetGroup(testOrgId, int64(7)).Return(createTestAwaitingGroup(), nil)
+	mockRepo.EXPECT().GetOpenGroupOrder(testOrgId, int64(7)).Return(models.Order{}, errors.New("no rows"))
+	mockRepo.EXPECT().GetTicketType(testOrgId, int64(1), int64(2)).Return(createTestTicketTypes()[1], nil)
+	mockRepo.EXPECT().CreateOrder(gomock.Any()).DoAndReturn(func(o *models.Order) error {
+		o.Id = 9
+		return nil
+	})
+
+	order, err := service.CheckoutGroup(testOrgId, 10, 7)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), order.GroupId)
+	assert.Zero(t, order.RegistrationId)
+	assert.Equal(t, int64(10), order.UserId)
+	assert.Equal(t, int64(5000), order.Amount)
+	assert.Equal(t, "EUR", order.Currency)
+}
+
+func TestCheckoutGroup_OnlyBooker(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	service := NewOrderService(mockRepo, payment.NewFakeProvider("secret"))
+
+	mockRepo.EXPECT().GetGroup(testOrgId, int64(7)).Return(createTestAwaitingGroup(), nil)
+
+	_, err := service.CheckoutGroup(testOrgId, 11, 7)
+
+	assert.Equal(t, ErrGroupNotFound, err)
+}
+
+func TestCheckout_GroupRegistration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	service := NewOrderService(mockRepo, payment.NewFakeProvider("secret"))
+
+	registration := createTestAwaitingRegistration()
+	registration.GroupId = 7
+	mockRepo.EXPECT().GetRegisteredEventById(testOrgId, int64(10), int64(1)).Return(registration, nil)
+
+	_, err := service.Checkout(testOrgId, 10, 1)
+
+	assert.Equal(t, ErrPaidWithGroup, err)
+}
+
+func TestConfirm_GroupOrderConfirmsGroup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	provider := payment.NewFakeProvider("secret")
+	service := NewOrderService(mockRepo, provider)
+
+	intent, err := provider.CreateIntent("group-7-5000", 5000, "EUR")
+	require.NoError(t, err)
+	order := models.Order{
+		Id: 9, GroupId: 7, UserId: 10, EventId: 1, OrganizationId: testOrgId, Amount: 5000, Currency: "EUR",
+		Status: OrderPending, PaymentIntentId: intent.Id,
+	}
+
+	mockRepo.EXPECT().GetOrder(testOrgId, int64(9)).Return(order, nil)
+	mockRepo.EXPECT().SetOrderStatus(int64(9), OrderPending, OrderPaid, gomock.Any()).Return(true, nil)
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().ConfirmGroup(testOrgId, int64(7), RegistrationApproved).Return(false, nil)
+	mockRepo.EXPECT().SetOrderStatus(int64(9), OrderPaid, OrderRefunded, gomock.Any()).Return(true, nil)
+	mockRepo.EXPECT().CancelGroupRegistrations(testOrgId, int64(7)).Return(nil)
+
+	refunded, err := service.Confirm(testOrgId, 10, 9, "pm_card_visa")
+
+	assert.Equal(t, ErrEventFull, err, "Groups that no longer fit are refunded as a whole")
+	assert.Equal(t, OrderRefunded, refunded.Status)
+	assert.Equal(t, int64(5000), provider.Refunded(intent.Id))
+}
+
+func TestConfirm_OtherUsersOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	service := NewOrderService(mockRepo, payment.NewFakeProvider("secret"))
+
+	mockRepo.EXPECT().GetOrder(testOrgId, int64(7)).Return(models.Order{Id: 7, UserId: 10, Status: OrderPending}, nil)
+
+	_, err := service.Confirm(testOrgId, 20, 7, "pm_card_visa")
+
+	assert.Equal(t, ErrOrderNotFound, err)
+}
+
+func TestConfirm_CancelledOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	service := NewOrderService(mockRepo, payment.NewFakeProvider("secret"))
+
+	mockRepo.EXPECT().GetOrder(testOrgId, int64(7)).Return(models.Order{Id: 7, UserId: 10, Status: OrderCancelled}, nil)
+
+	_, err := service.Confirm(testOrgId, 10, 7, "pm_card_visa")
+
+	assert.Equal(t, ErrOrderClosed, err)
+}
+
+func TestHandleWebhook_PaysOrderOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	provider := payment.NewFakeProvider("secret")
+	service := NewOrderService(mockRepo, provider)
+	order := createTestOrder(t, mockRepo, service)
+
+	_, err := provider.Confirm(order.PaymentIntentId, "pm_card_visa")
+	require.NoError(t, err)
+	payload, signature, err := provider.Webhook(order.PaymentIntentId)
+	require.NoError(t, err)
+
+	mockRepo.EXPECT().GetOrderByPaymentIntent(order.PaymentIntentId).Return(order, nil)
+	mockRepo.EXPECT().SetOrderStatus(int64(7), OrderPending, OrderPaid, gomock.Any()).Return(true, nil)
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().ConfirmRegistration(testOrgId, int64(100), RegistrationApproved).Return(true, nil)
+
+	require.NoError(t, service.HandleWebhook(payload, signature))
+
+	order.Status = OrderPaid
+	mockRepo.EXPECT().GetOrderByPaymentIntent(order.PaymentIntentId).Return(order, nil)
+	mockRepo.EXPECT().AwaitsPayment(testOrgId, int64(100), int64(0)).Return(false, nil)
+
+	assert.NoError(t, service.HandleWebhook(payload, signature), "Repeated webhooks are ignored")
+}
+
+func TestHandleWebhook_RetriesConfirmingPaidOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	provider := payment.NewFakeProvider("secret")
+	service := NewOrderService(mockRepo, provider)
+	order := createTestOrder(t, mockRepo, service)
+
+	_, err := provider.Confirm(order.PaymentIntentId, "pm_card_visa")
+	require.NoError(t, err)
+	payload, signature, err := provider.Webhook(order.PaymentIntentId)
+	require.NoError(t, err)
+
+	mockRepo.EXPECT().GetOrderByPaymentIntent(order.PaymentIntentId).Return(order, nil)
+	mockRepo.EXPECT().SetOrderStatus(int64(7), OrderPending, OrderPaid, gomock.Any()).Return(true, nil)
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(models.Event{}, errors.New("database is locked"))
+
+	require.Error(t, service.HandleWebhook(payload, signature))
+
+	order.Status = OrderPaid
+	mockRepo.EXPECT().GetOrderByPaymentIntent(order.PaymentIntentId).Return(order, nil)
+	mockRepo.EXPECT().AwaitsPayment(testOrgId, int64(100), int64(0)).Return(true, nil)
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().ConfirmRegistration(testOrgId, int64(100), RegistrationApproved).Return(true, nil)
+
+	assert.NoError(t, service.HandleWebhook(payload, signature))
+}
+
+func TestHandleWebhook_RetriesCancellingRefundedOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	provider := payment.NewFakeProvider("secret")
+	service := NewOrderService(mockRepo, provider)
+	order := createTestOrder(t, mockRepo, service)
+
+	_, err := provider.Confirm(order.PaymentIntentId, "pm_card_visa")
+	require.NoError(t, err)
+	payload, signature, err := provider.Webhook(order.PaymentIntentId)
+	require.NoError(t, err)
+
+	order.Status = OrderRefunded
+	mockRepo.EXPECT().GetOrderByPaymentIntent(order.PaymentIntentId).Return(order, nil)
+	mockRepo.EXPECT().AwaitsPayment(testOrgId, int64(100), int64(0)).Return(true, nil)
+	mockRepo.EXPECT().CancelRegistration(testOrgId, int64(100)).Return(nil)
+
+	assert.NoError(t, service.HandleWebhook(payload, signature))
+	assert.Zero(t, provider.Refunded(order.PaymentIntentId), "The order is not refunded twice")
+}
+
+func TestHandleWebhook_PaymentAfterCancellationIsRefunded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	provider := payment.NewFakeProvider("secret")
+	service := NewOrderService(mockRepo, provider)
+	order := createTestOrder(t, mockRepo, service)
+
+	_, err := provider.Confirm(order.PaymentIntentId, "pm_card_visa")
+	require.NoError(t, err)
+	payload, signature, err := provider.Webhook(order.PaymentIntentId)
+	require.NoError(t, err)
+
+	order.Status = OrderCancelled
+	mockRepo.EXPECT().GetOrderByPaymentIntent(order.PaymentIntentId).Return(order, nil)
+	mockRepo.EXPECT().SetOrderStatus(int64(7), OrderCancelled, OrderRefunded, gomock.Any()).Return(true, nil)
+
+	require.NoError(t, service.HandleWebhook(payload, signature))
+	assert.Equal(t, order.Amount, provider.Refunded(order.PaymentIntentId))
+}
+
+func TestHandleWebhook_InvalidSignature(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewOrderService(mocks.NewMockOrderRepository(ctrl), payment.NewFakeProvider("secret"))
+
+	err := service.HandleWebhook([]byte(`{"id":"pi_fake_1","status":"succeeded"}`), "forged")
+
+	assert.Equal(t, ErrInvalidWebhook, err)
+}
+
+func TestTransition_ConcurrentChange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	service := NewOrderService(mockRepo, payment.NewFakeProvider("secret"))
+
+	order := models.Order{Id: 7, Status: OrderPending}
+	mockRepo.EXPECT().SetOrderStatus(int64(7), OrderPending, OrderPaid, gomock.Any()).Return(false, nil)
+
+	err := service.transition(&order, OrderPaid)
+
+	assert.Equal(t, ErrOrderChanged, err)
+	assert.Equal(t, OrderPending, order.Status)
+}
+
+func TestTransition_NotAllowed(t *testing.T) {
+	service := NewOrderService(nil, nil)
+	order := models.Order{Id: 7, Status: OrderRefunded}
+
+	err := service.transition(&order, OrderPaid)
+
+	assert.ErrorIs(t, err, ErrInvalidOrderTransition)
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"errors"
+	"event-booking/models"
+	"slices"
+	"time"
+)
+
+type OrganizationRepository interface {
+	GetUserByEmail(string) (models.User, error)
+	CreateOrganization(*models.Organization, int64) error
+	UpdateOrganization(*models.Organization) error
+	GetMemberships(int64) ([]models.Membership, error)
+	GetMembership(int64, int64) (models.Membership, error)
+	GetMembers(int64) ([]models.OrganizationMember, error)
+	GetMember(int64, int64) (models.OrganizationMember, error)
+	AddMember(*models.OrganizationMember) error
+	UpdateMemberRole(int64, int64, string) error
+	RemoveMember(int64, int64) (bool, error)
+	CountOwners(int64) (int, error)
+	GetPastAttendance(int64, time.Time) ([]models.EventAttendance, error)
+	GetAttendeeAttendance(int64, time.Time) ([]models.AttendeeAttendance, error)
+}
+
+// Roles of organization members. Members can see the organization's events
+// and register for them, admins also create events and manage members, and
+// owners also manage roles.
+const (
+	RoleOwner  = "owner"
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+)
+
+var OrganizationRoles = []string{RoleOwner, RoleAdmin, RoleMember}
+
+var ErrOrganizationNotFound = errors.New("Organization not found")
+var ErrOrganizationMemberNotFound = errors.New("Organization member not found")
+var ErrAlreadyOrganizationMember = errors.New("User is already a member of this organization")
+var ErrInvalidOrganizationRole = errors.New("Role must be one of owner, admin or member")
+var ErrLastOrganizationOwner = errors.New("An organization needs at least one owner")
+
+type OrganizationService struct {
+	repo OrganizationRepository
+	now  func() time.Time
+}
+
+func NewOrganizationService(repo OrganizationRepository) *OrganizationService {
+	return &OrganizationService{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+// Create creates an organization with userId as its owner.
+func (s *OrganizationService) Create(userId int64, name string) (models.Membership, error) {
+	org := models.Organization{
+		Name:            name,
+		NoShowThreshold: DefaultNoShowThreshold,
+		CreatedAt:       s.now(),
+	}
+	err := s.repo.CreateOrganization(&org, userId)
+	if err != nil {
+		return models.Membership{}, err
+	}
+	return models.Membership{Organization: org, Role: RoleOwner}, nil
+}
+
+func (s *OrganizationService) List(userId int64) ([]models.Membership, error) {
+	return s.repo.GetMemberships(userId)
+}
+
+// Authorize returns the membership of userId in orgId. Organizations the
+// user does not belong to are reported as not found, so their existence is
+// not revealed. With roles, the member must have one of them.
+func (s *OrganizationService) Authorize(orgId, userId int64, roles ...string) (models.Membership, error) {
+	membership, err := s.repo.GetMembership(orgId, userId)
+	if err != nil {
+		return models.Membership{}, ErrOrganizationNotFound
+	}
+	if len(roles) > 0 && !slices.Contains(roles, membership.Role) {
+		return models.Membership{}, ErrForbidden
+	}
+	return membership, nil
+}
+
+func (s *OrganizationService) Rename(userId, orgId int64, name string) (models.Membership, error) {
+	membership, err := s.Authorize(orgId, userId, RoleOwner, RoleAdmin)
+	if err != nil {
+		return models.Membership{}, err
+	}
+
+	membership.Name = name
+	err = s.repo.UpdateOrganization(&membership.Organization)
+	if err != nil {
+		return models.Membership{}, err
+	}
+	return membership, nil
+}
+
+func (s *OrganizationService) Members(userId, orgId int64) ([]models.OrganizationMember, error) {
+	_, err := s.Authorize(orgId, userId)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetMembers(orgId)
+}
+
+// AddMember adds the user with email to the organization. Only owners can
+// add other owners.
+func (s *OrganizationService) AddMember(userId, orgId int64, email, role string) (models.OrganizationMember, error) {
+	if !slices.Contains(OrganizationRoles, role) {
+		return models.OrganizationMember{}, ErrInvalidOrganizationRole
+	}
+
+	membership, err := s.Authorize(orgId, userId, RoleOwner, RoleAdmin)
+	if err != nil {
+		return models.OrganizationMember{}, err
+	}
+	if role == RoleOwner && membership.Role != RoleOwner {
+		return models.OrganizationMember{}, ErrForbidden
+	}
+
+	u, err := s.repo.GetUserByEmail(email)
+	if err != nil {
+		return models.OrganizationMember{}, ErrUserNotFound
+	}
+
+	_, err = s.repo.GetMember(orgId, u.Id)
+	if err == nil {
+		return models.OrganizationMember{}, ErrAlreadyOrganizationMember
+	}
+
+	member := models.OrganizationMember{
+		OrganizationId: orgId,
+		UserId:         u.Id,
+		Email:          u.Email,
+		Role:           role,
+		CreatedAt:      s.now(),
+	}
+	err = s.repo.AddMember(&member)
+	if err != nil {
+		return models.OrganizationMember{}, err
+	}
+	return member, nil
+}
+
+// UpdateMemberRole changes the role of memberId. Only owners can change
+// roles, and the last owner cannot step down.
+func (s *OrganizationService) UpdateMemberRole(userId, orgId, memberId int64, role string) (models.OrganizationMember, error) {
+	if !slices.Contains(OrganizationRoles, role) {
+		return models.OrganizationMember{}, ErrInvalidOrganizationRole
+	}
+
+	_, err := s.Authorize(orgId, userId, RoleOwner)
+	if err != nil {
+		return models.OrganizationMember{}, err
+	}
+
+	member, err := s.repo.GetMember(orgId, memberId)
+	if err != nil {
+		return models.OrganizationMember{}, ErrOrganizationMemberNotFound
+	}
+
+	if member.Role == RoleOwner && role != RoleOwner {
+		err = s.requireAnotherOwner(orgId)
+		if err != nil {
+			return models.OrganizationMember{}, err
+		}
+	}
+
+	err = s.repo.UpdateMemberRole(orgId, memberId, role)
+	if err != nil {
+		return models.OrganizationMember{}, err
+	}
+	member.Role = role
+	return member, nil
+}
+
+// RemoveMember removes memberId from the organization. Members can always
+// leave; admins can remove members and admins, and owners anyone.
+func (s *OrganizationService) RemoveMember(userId, orgId, memberId int64) error {
+	membership, err := s.Authorize(orgId, userId)
+	if err != nil {
+		return err
+	}
+
+	member, err := s.repo.GetMember(orgId, memberId)
+	if err != nil {
+		return ErrOrganizationMemberNotFound
+	}
+
+	if memberId != userId {
+		if membership.Role == RoleMember || (member.Role == RoleOwner && membership.Role != RoleOwner) {
+			return ErrForbidden
+		}
+	}
+
+	if member.Role == RoleOwner {
+		err = s.requireAnotherOwner(orgId)
+		if err != nil {
+			return err
+		}
+	}
+
+	removed, err := s.repo.RemoveMember(orgId, memberId)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrOrganizationMemberNotFound
+	}
+	return nil
+}
+
+func (s *OrganizationService) requireAnotherOwner(orgId int64) error {
+	owners, err := s.repo.CountOwners(orgId)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return ErrLastOrganizationOwner
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"event-booking/models"
+	"event-booking/services/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func createTestMembership(orgId int64, role string) models.Membership {
+	return models.Membership{
+		Organization: models.Organization{Id: orgId, Name: "Acme"},
+		Role:         role,
+	}
+}
+
+func TestCreateOrganization_CreatorIsOwner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrganizationRepository(ctrl)
+	service := NewOrganizationService(mockRepo)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	mockRepo.EXPECT().CreateOrganization(gomock.Any(), int64(10)).DoAndReturn(func(org *models.Organization, ownerId int64) error {
+		assert.Equal(t, "Acme", org.Name)
+		assert.Equal(t, now, org.CreatedAt)
+		assert.Equal(t, int64(DefaultNoShowThreshold), org.NoShowThreshold)
+		org.Id = 4
+		return nil
+	})
+
+	membership, err := service.Create(10, "Acme")
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), membership.Id)
+	assert.Equal(t, RoleOwner, membership.Role)
+}
+
+func TestAuthorize_NonMemberSeesNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrganizationRepository(ctrl)
+	service := NewOrganizationService(mockRepo)
+
+	mockRepo.EXPECT().GetMembership(int64(4), int64(10)).Return(models.Membership{}, errors.New("no rows"))
+
+	_, err := service.Authorize(4, 10)
+
+	assert.Equal(t, ErrOrganizationNotFound, err)
+}
+
+func TestAuthorize_RequiresRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrganizationRepository(ctrl)
+	service := NewOrganizationService(mockRepo)
+
+	mockRepo.EXPECT().GetMembership(int64(4), int64(10)).Return(createTestMembership(4, RoleMember), nil).Times(2)
+
+	_, err := service.Authorize(4, 10, RoleOwner, RoleAdmin)
+	assert.Equal(t, ErrForbidden, err)
+
+	membership, err := service.Authorize(4, 10)
+	require.NoError(t, err)
+	assert.Equal(t, RoleMember, membership.Role)
+}
+
+func TestAddMember_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrganizationRepository(ctrl)
+	service := NewOrganizationService(mockRepo)
+
+	mockRepo.EXPECT().GetMembership(int64(4), int64(10)).Return(createTestMembership(4, RoleAdmin), nil)
+	mockRepo.EXPECT().GetUserByEmail("new@example.com").Return(models.User{Id: 11, Email: "new@example.com"}, nil)
+	mockRepo.EXPECT().GetMember(int64(4), int64(11)).Return(models.OrganizationMember{}, errors.New("no rows"))
+	mockRepo.EXPECT().AddMember(gomock.Any()).DoAndReturn(func(m *models.OrganizationMember) error {
+		assert.Equal(t, int64(4), m.OrganizationId)
+		assert.Equal(t, int64(11), m.UserId)
+		assert.Equal(t, RoleMember, m.Role)
+		return nil
+	})
+
+	member, err := service.AddMember(10, 4, "new@example.com", RoleMember)
+
+	require.NoError(t, err)
+	assert.Equal(t, "new@example.com", member.Email)
+}
+
+func TestAddMember_AdminCannotAddOwner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrganizationRepository(ctrl)
+	service := NewOrganizationService(mockRepo)
+
+	mockRepo.EXPECT().GetMembership(int64(4), int64(10)).Return(createTestMembership(4, RoleAdmin), nil)
+
+	_, err := service.AddMember(10, 4, "new@example.com", RoleOwner)
+
+	assert.Equal(t, ErrForbidden, err)
+}
+
+func TestAddMember_InvalidRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewOrganizationService(mocks.NewMockOrganizationRepository(ctrl))
+
+	_, err := service.AddMember(10, 4, "new@example.com", "superuser")
+
+	assert.Equal(t, ErrInvalidOrganizationRole, err)
+}
+
+func TestAddMember_AlreadyMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrganizationRepository(ctrl)
+	service := NewOrganizationService(mockRepo)
+
+	mockRepo.EXPECT().GetMembership(int64(4), int64(10)).Return(createTestMembership(4, RoleOwner), nil)
+	mockRepo.EXPECT().GetUserByEmail("new@example.com").Return(models.User{Id: 11}, nil)
+	mockRepo.EXPECT().GetMember(int64(4), int64(11)).Return(models.OrganizationMember{UserId: 11}, nil)
+
+	_, err := service.AddMember(10, 4, "new@example.com", RoleMember)
+
+	assert.Equal(t, ErrAlreadyOrganizationMember, err)
+}
+
+func TestUpdateMemberRole_LastOwnerCannotStepDown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrganizationRepository(ctrl)
+	service := NewOrganizationService(mockRepo)
+
+	mockRepo.EXPECT().GetMembership(int64(4), int64(10)).Return(createTestMembership(4, RoleOwner), nil)
+	mockRepo.EXPECT().GetMember(int64(4), int64(10)).Return(models.OrganizationMember{UserId: 10, Role: RoleOwner}, nil)
+	mockRepo.EXPECT().CountOwners(int64(4)).Return(1, nil)
+
+	_, err := service.UpdateMemberRole(10, 4, 10, RoleMember)
+
+	assert.Equal(t, ErrLastOrganizationOwner, err)
+}
+
+func TestUpdateMemberRole_OnlyOwners(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrganizationRepository(ctrl)
+	service := NewOrganizationService(mockRepo)
+
+	mockRepo.EXPECT().GetMembership(int64(4), int64(10)).Return(createTestMembership(4, RoleAdmin), nil)
+
+	_, err := service.UpdateMemberRole(10, 4, 11, RoleAdmin)
+
+	assert.Equal(t, ErrForbidden, err)
+}
+
+func TestRemoveMember_MemberCanLeave(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrganizationRepository(ctrl)
+	service := NewOrganizationService(mockRepo)
+
+	mockRepo.EXPECT().GetMembership(int64(4), int64(10)).Return(createTestMembership(4, RoleMember), nil)
+	mockRepo.EXPECT().GetMember(int64(4), int64(10)).Return(models.OrganizationMember{UserId: 10, Role: RoleMember}, nil)
+	mockRepo.EXPECT().RemoveMember(int64(4), int64(10)).Return(true, nil)
+
+	err := service.RemoveMember(10, 4, 10)
+
+	require.NoError(t, err)
+}
+
+func TestRemoveMember_MemberCannotRemoveOthers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrganizationRepository(ctrl)
+	service := NewOrganizationService(mockRepo)
+
+	mockRepo.EXPECT().GetMembership(int64(4), int64(10)).Return(createTestMembership(4, RoleMember), nil)
+	mockRepo.EXPECT().GetMember(int64(4), int64(11)).Return(models.OrganizationMember{UserId: 11, Role: RoleMember}, nil)
+
+	err := service.RemoveMember(10, 4, 11)
+
+	assert.Equal(t, ErrForbidden, err)
+}
+
+func TestRemoveMember_AdminCannotRemoveOwner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrganizationRepository(ctrl)
+	service := NewOrganizationService(mockRepo)
+
+	mockRepo.EXPECT().GetMembership(int64(4), int64(10)).Return(createTestMembership(4, RoleAdmin), nil)
+	mockRepo.EXPECT().GetMember(int64(4), int64(11)).Return(models.OrganizationMember{UserId: 11, Role: RoleOwner}, nil)
+
+	err := service.RemoveMember(10, 4, 11)
+
+	assert.Equal(t, ErrForbidden, err)
+}
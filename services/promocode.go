@@ -0,0 +1,172 @@
+package services
+
+import (
+	"errors"
+	"event-booking/models"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Types of promo code discounts.
+const (
+	DiscountPercentage = "percentage"
+	DiscountFixed      = "fixed"
+)
+
+var ErrPromoCodeNotFound = errors.New("Promo code not found")
+var ErrInvalidPromoCode = errors.New("Promo code is invalid")
+var ErrPromoCodeExists = errors.New("The event already has this promo code")
+var ErrPromoCodeInUse = errors.New("Promo code has been used and cannot be deleted")
+var ErrPromoCodeNotApplicable = errors.New("Promo code is not valid for this ticket")
+var ErrPromoCodeUsedUp = errors.New("Promo code has been used up")
+
+// PromoCodes lists the promo codes of an event to its owner and
+// co-organizers.
+func (s *EventService) PromoCodes(orgId, eventId, userId int64) ([]models.PromoCode, error) {
+	_, err := s.authorizeEvent(orgId, eventId, userId, EventRoleOwner, EventRoleCoOrganizer)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetPromoCodes(orgId, eventId)
+}
+
+// CreatePromoCode adds a promo code to an event. The owner and
+// co-organizers can manage promo codes.
+func (s *EventService) CreatePromoCode(orgId, eventId, userId int64, p *models.PromoCode) error {
+	_, err := s.authorizeEvent(orgId, eventId, userId, EventRoleOwner, EventRoleCoOrganizer)
+	if err != nil {
+		return err
+	}
+
+	err = s.normalizePromoCode(orgId, eventId, p)
+	if err != nil {
+		return err
+	}
+	_, err = s.repo.GetPromoCodeByCode(orgId, eventId, p.Code)
+	if err == nil {
+		return ErrPromoCodeExists
+	}
+
+	p.Id = 0
+	p.EventId = eventId
+	p.OrganizationId = orgId
+	p.Used = 0
+	return s.repo.CreatePromoCode(p)
+}
+
+// UpdatePromoCode changes a promo code. Its usage limit cannot drop below
+// the number of times it was used.
+func (s *EventService) UpdatePromoCode(orgId, eventId, userId int64, p *models.PromoCode) error {
+	_, err := s.authorizeEvent(orgId, eventId, userId, EventRoleOwner, EventRoleCoOrganizer)
+	if err != nil {
+		return err
+	}
+
+	existing, err := s.repo.GetPromoCode(orgId, eventId, p.Id)
+	if err != nil {
+		return ErrPromoCodeNotFound
+	}
+
+	err = s.normalizePromoCode(orgId, eventId, p)
+	if err != nil {
+		return err
+	}
+	if p.MaxUses != 0 && p.MaxUses < existing.Used {
+		return fmt.Errorf("%w: the code was already used %d times", ErrInvalidPromoCode, existing.Used)
+	}
+	other, err := s.repo.GetPromoCodeByCode(orgId, eventId, p.Code)
+	if err == nil && other.Id != p.Id {
+		return ErrPromoCodeExists
+	}
+
+	p.EventId = eventId
+	p.OrganizationId = orgId
+	p.Used = existing.Used
+	return s.repo.UpdatePromoCode(p)
+}
+
+// DeletePromoCode deletes a promo code nobody used. Used codes can be ended
+// by setting validUntil instead.
+func (s *EventService) DeletePromoCode(orgId, eventId, userId, id int64) error {
+	_, err := s.authorizeEvent(orgId, eventId, userId, EventRoleOwner, EventRoleCoOrganizer)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.repo.GetPromoCode(orgId, eventId, id)
+	if err != nil {
+		return ErrPromoCodeNotFound
+	}
+
+	deleted, err := s.repo.DeletePromoCode(orgId, eventId, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrPromoCodeInUse
+	}
+	return nil
+}
+
+// normalizePromoCode upper-cases the code and checks it against the
+// event's ticket types.
+func (s *EventService) normalizePromoCode(orgId, eventId int64, p *models.PromoCode) error {
+	p.Code = normalizeCode(p.Code)
+	valid := p.Code != "" && !strings.ContainsFunc(p.Code, func(r rune) bool {
+		return !(r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_')
+	})
+	if !valid {
+		return fmt.Errorf("%w: codes can only contain letters, digits, - and _", ErrInvalidPromoCode)
+	}
+	if p.DiscountType == DiscountPercentage && p.DiscountValue > 100 {
+		return fmt.Errorf("%w: a percentage discount can be at most 100", ErrInvalidPromoCode)
+	}
+	if p.ValidFrom != nil && p.ValidUntil != nil && !p.ValidUntil.After(*p.ValidFrom) {
+		return fmt.Errorf("%w: the code must expire after it becomes valid", ErrInvalidPromoCode)
+	}
+
+	ticketTypes, err := s.repo.GetTicketTypes(orgId, eventId)
+	if err != nil {
+		return err
+	}
+	p.TicketTypeIds = slices.Compact(slices.Sorted(slices.Values(p.TicketTypeIds)))
+	if p.TicketTypeIds == nil {
+		p.TicketTypeIds = []int64{}
+	}
+	for _, id := range p.TicketTypeIds {
+		if !slices.ContainsFunc(ticketTypes, func(t models.TicketType) bool { return t.Id == id }) {
+			return fmt.Errorf("%w: unknown ticket type %d", ErrInvalidPromoCode, id)
+		}
+	}
+	return nil
+}
+
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// applyPromoCode returns the price of ticketType after the discount of p.
+func applyPromoCode(p models.PromoCode, ticketType models.TicketType, now time.Time) (int64, error) {
+	if ticketType.Price == 0 {
+		return 0, ErrPromoCodeNotApplicable
+	}
+	if p.ValidFrom != nil && now.Before(*p.ValidFrom) {
+		return 0, ErrPromoCodeNotApplicable
+	}
+	if p.ValidUntil != nil && !now.Before(*p.ValidUntil) {
+		return 0, ErrPromoCodeNotApplicable
+	}
+	if len(p.TicketTypeIds) > 0 && !slices.Contains(p.TicketTypeIds, ticketType.Id) {
+		return 0, ErrPromoCodeNotApplicable
+	}
+	if p.MaxUses > 0 && p.Used >= p.MaxUses {
+		return 0, ErrPromoCodeUsedUp
+	}
+
+	if p.DiscountType == DiscountPercentage {
+		return ticketType.Price - ticketType.Price*p.DiscountValue/100, nil
+	}
+	return max(ticketType.Price-p.DiscountValue, 0), nil
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"event-booking/models"
+	"event-booking/services/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreatePromoCode_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepository(ctrl)
+	service := NewEventService(mockRepo)
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 10), nil)
+	mockRepo.EXPECT().GetTicketTypes(testOrgId, int64(1)).Return(createTestTicketTypes(), nil)
+	mockRepo.EXPECT().GetPromoCodeByCode(testOrgId, int64(1), "SPRING-10").Return(models.PromoCode{}, errors.New("not found"))
+	mockRepo.EXPECT().CreatePromoCode(gomock.Any()).DoAndReturn(func(p *models.PromoCode) error {
+		p.Id = 4
+		return nil
+	})
+
+	promoCode := models.PromoCode{Code: " spring-10 ", DiscountType: DiscountPercentage, DiscountValue: 10, TicketTypeIds: []int64{2, 2}}
+	err := service.CreatePromoCode(testOrgId, 1, 10, &promoCode)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), promoCode.Id)
+	assert.Equal(t, "SPRING-10", promoCode.Code, "Codes are matched case-insensitively")
+	assert.Equal(t, []int64{2}, promoCode.TicketTypeIds)
+}
+
+func TestCreatePromoCode_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepository(ctrl)
+	service := NewEventService(mockRepo)
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 10), nil).Times(4)
+	mockRepo.EXPECT().GetTicketTypes(testOrgId, int64(1)).Return(createTestTicketTypes(), nil)
+
+	start := time.Now()
+	end := start.Add(-time.Hour)
+	invalid := []models.PromoCode{
+		{Code: "SPRING 10", DiscountType: DiscountFixed, DiscountValue: 500},
+		{Code: "HALF", DiscountType: DiscountPercentage, DiscountValue: 150},
+		{Code: "EARLY", DiscountType: DiscountFixed, DiscountValue: 500, ValidFrom: &start, ValidUntil: &end},
+		{Code: "VIP", DiscountType: DiscountFixed, DiscountValue: 500, TicketTypeIds: []int64{9}},
+	}
+	for _, promoCode := range invalid {
+		err := service.CreatePromoCode(testOrgId, 1, 10, &promoCode)
+		assert.ErrorIs(t, err, ErrInvalidPromoCode, promoCode.Code)
+	}
+}
+
+func TestCreatePromoCode_Duplicate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepository(ctrl)
+	service := NewEventService(mockRepo)
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 10), nil)
+	mockRepo.EXPECT().GetTicketTypes(testOrgId, int64(1)).Return(createTestTicketTypes(), nil)
+	mockRepo.EXPECT().GetPromoCodeByCode(testOrgId, int64(1), "SPRING").Return(models.PromoCode{Id: 3}, nil)
+
+	err := service.CreatePromoCode(testOrgId, 1, 10, &models.PromoCode{Code: "spring", DiscountType: DiscountFixed, DiscountValue: 500})
+
+	assert.Equal(t, ErrPromoCodeExists, err)
+}
+
+func TestUpdatePromoCode_LimitBelowUsage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepository(ctrl)
+	service := NewEventService(mockRepo)
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 10), nil)
+	mockRepo.EXPECT().GetPromoCode(testOrgId, int64(1), int64(3)).Return(models.PromoCode{Id: 3, Code: "SPRING", Used: 5}, nil)
+	mockRepo.EXPECT().GetTicketTypes(testOrgId, int64(1)).Return(createTestTicketTypes(), nil)
+
+	err := service.UpdatePromoCode(testOrgId, 1, 10, &models.PromoCode{Id: 3, Code: "SPRING", DiscountType: DiscountFixed, DiscountValue: 500, MaxUses: 4})
+
+	assert.ErrorIs(t, err, ErrInvalidPromoCode)
+}
+
+func TestDeletePromoCode_Used(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepository(ctrl)
+	service := NewEventService(mockRepo)
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 10), nil)
+	mockRepo.EXPECT().GetPromoCode(testOrgId, int64(1), int64(3)).Return(models.PromoCode{Id: 3, Used: 1}, nil)
+	mockRepo.EXPECT().DeletePromoCode(testOrgId, int64(1), int64(3)).Return(false, nil)
+
+	err := service.DeletePromoCode(testOrgId, 1, 10, 3)
+
+	assert.Equal(t, ErrPromoCodeInUse, err)
+}
+
+func TestApplyPromoCode(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Hour)
+	regular := createTestTicketTypes()[1]
+
+	tests := []struct {
+		name      string
+		promoCode models.PromoCode
+		amount    int64
+		err       error
+	}{
+		{"percentage", models.PromoCode{DiscountType: DiscountPercentage, DiscountValue: 20}, 2000, nil},
+		{"fixed", models.PromoCode{DiscountType: DiscountFixed, DiscountValue: 500}, 2000, nil},
+		{"fixed above price", models.PromoCode{DiscountType: DiscountFixed, DiscountValue: 9000}, 0, nil},
+		{"other ticket type", models.PromoCode{DiscountType: DiscountFixed, DiscountValue: 500, TicketTypeIds: []int64{1}}, 0, ErrPromoCodeNotApplicable},
+		{"not valid yet", models.PromoCode{DiscountType: DiscountFixed, DiscountValue: 500, ValidFrom: &later}, 0, ErrPromoCodeNotApplicable},
+		{"expired", models.PromoCode{DiscountType: DiscountFixed, DiscountValue: 500, ValidUntil: &now}, 0, ErrPromoCodeNotApplicable},
+		{"used up", models.PromoCode{DiscountType: DiscountFixed, DiscountValue: 500, MaxUses: 2, Used: 2}, 0, ErrPromoCodeUsedUp},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			amount, err := applyPromoCode(test.promoCode, regular, now)
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.amount, amount)
+		})
+	}
+}
+
+func TestRegisterEvent_WithPromoCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	mockRepo.EXPECT().GetUserById(int64(10)).Return(createVerifiedTestUser(10), nil)
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().GetRegisteredEventById(testOrgId, int64(10), int64(1)).Return(models.RegisterEvent{}, errors.New("not found"))
+	mockRepo.EXPECT().GetTicketTypes(testOrgId, int64(1)).Return(createTestTicketTypes(), nil)
+	mockRepo.EXPECT().GetPromoCodeByCode(testOrgId, int64(1), "FREE").Return(models.PromoCode{Id: 3, Code: "FREE", DiscountType: DiscountPercentage, DiscountValue: 100}, nil)
+	mockRepo.EXPECT().GetRegistrationQuestions(testOrgId, int64(1)).Return([]models.RegistrationQuestion{}, nil)
+	mockRepo.EXPECT().RegisterEvent(gomock.Any()).Return(true, nil)
+
+	registration, err := service.RegisterEvent(testOrgId, 10, 1, 2, "free", nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), registration.PromoCodeId)
+	assert.Equal(t, int64(0), registration.Amount)
+	assert.Equal(t, RegistrationApproved, registration.Status, "Fully discounted tickets need no payment")
+}
+
+func TestRegisterEvent_PromoCodeUsedUpConcurrently(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	promoCode := models.PromoCode{Id: 3, Code: "LAST", DiscountType: DiscountFixed, DiscountValue: 500, MaxUses: 1}
+	mockRepo.EXPECT().GetUserById(int64(10)).Return(createVerifiedTestUser(10), nil)
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().GetRegisteredEventById(testOrgId, int64(10), int64(1)).Return(models.RegisterEvent{}, errors.New("not found")).Times(2)
+	mockRepo.EXPECT().GetTicketTypes(testOrgId, int64(1)).Return(createTestTicketTypes(), nil)
+	mockRepo.EXPECT().GetPromoCodeByCode(testOrgId, int64(1), "LAST").Return(promoCode, nil)
+	mockRepo.EXPECT().GetRegistrationQuestions(testOrgId, int64(1)).Return([]models.RegistrationQuestion{}, nil)
+	mockRepo.EXPECT().RegisterEvent(gomock.Any()).DoAndReturn(func(r *models.RegisterEvent) (bool, error) {
+		assert.Equal(t, int64(2000), r.Amount)
+		return false, nil
+	})
+	promoCode.Used = 1
+	mockRepo.EXPECT().GetPromoCode(testOrgId, int64(1), int64(3)).Return(promoCode, nil)
+
+	_, err := service.RegisterEvent(testOrgId, 10, 1, 2, "LAST", nil)
+
+	assert.Equal(t, ErrPromoCodeUsedUp, err)
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"errors"
+	"event-booking/models"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Types of registration questions.
+const (
+	QuestionText         = "text"
+	QuestionSingleChoice = "single_choice"
+	QuestionMultiChoice  = "multi_choice"
+)
+
+// maxRegistrationQuestions limits the questions of an event and
+// maxTextAnswerLength the length of answers to text questions.
+const (
+	maxRegistrationQuestions = 50
+	maxTextAnswerLength      = 1000
+)
+
+var ErrInvalidRegistrationQuestions = errors.New("Registration questions are invalid")
+var ErrInvalidAnswers = errors.New("Answers are invalid")
+
+// RegistrationQuestions returns the questions asked when registering for an
+// event to anyone who can see the event.
+func (s *EventService) RegistrationQuestions(orgId, eventId, userId int64) ([]models.RegistrationQuestion, error) {
+	_, err := s.GetEventById(orgId, eventId, userId)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetRegistrationQuestions(orgId, eventId)
+}
+
+// SetRegistrationQuestions replaces the questions of an event. Questions
+// sent with the id of an existing question update it and keep its answers,
+// so their type cannot change; existing questions that are left out are
+// deleted with their answers. The owner and co-organizers can change the
+// questions.
+func (s *EventService) SetRegistrationQuestions(orgId, eventId, userId int64, questions []models.RegistrationQuestion) ([]models.RegistrationQuestion, error) {
+	_, err := s.authorizeEvent(orgId, eventId, userId, EventRoleOwner, EventRoleCoOrganizer)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetRegistrationQuestions(orgId, eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateRegistrationQuestions(existing, questions)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range questions {
+		questions[i].EventId = eventId
+		questions[i].OrganizationId = orgId
+		questions[i].Label = strings.TrimSpace(questions[i].Label)
+		if questions[i].Type == QuestionText {
+			questions[i].Options = nil
+		}
+	}
+
+	err = s.repo.ReplaceRegistrationQuestions(orgId, eventId, questions)
+	if err != nil {
+		return nil, err
+	}
+	return questions, nil
+}
+
+func validateRegistrationQuestions(existing, questions []models.RegistrationQuestion) error {
+	if len(questions) > maxRegistrationQuestions {
+		return fmt.Errorf("%w: an event can have at most %d questions", ErrInvalidRegistrationQuestions, maxRegistrationQuestions)
+	}
+
+	seen := map[int64]bool{}
+	for _, q := range questions {
+		if strings.TrimSpace(q.Label) == "" {
+			return fmt.Errorf("%w: every question needs a label", ErrInvalidRegistrationQuestions)
+		}
+		if q.Id != 0 {
+			i := slices.IndexFunc(existing, func(e models.RegistrationQuestion) bool { return e.Id == q.Id })
+			if i < 0 || seen[q.Id] {
+				return fmt.Errorf("%w: unknown question id %d", ErrInvalidRegistrationQuestions, q.Id)
+			}
+			if existing[i].Type != q.Type {
+				return fmt.Errorf("%w: the type of question %q cannot be changed, add a new question instead", ErrInvalidRegistrationQuestions, q.Label)
+			}
+			seen[q.Id] = true
+		}
+
+		switch q.Type {
+		case QuestionText:
+			if len(q.Options) > 0 {
+				return fmt.Errorf("%w: text question %q cannot have options", ErrInvalidRegistrationQuestions, q.Label)
+			}
+		case QuestionSingleChoice, QuestionMultiChoice:
+			if len(q.Options) < 2 {
+				return fmt.Errorf("%w: choice question %q needs at least two options", ErrInvalidRegistrationQuestions, q.Label)
+			}
+			if len(slices.Compact(slices.Sorted(slices.Values(q.Options)))) != len(q.Options) {
+				return fmt.Errorf("%w: options of %q must be unique", ErrInvalidRegistrationQuestions, q.Label)
+			}
+		default:
+			return fmt.Errorf("%w: unknown question type %q", ErrInvalidRegistrationQuestions, q.Type)
+		}
+	}
+	return nil
+}
+
+// validateAnswers checks answers against the questions of an event and
+// returns them in the order of the questions. Unanswered optional questions
+// are left out.
+func validateAnswers(questions []models.RegistrationQuestion, answers []models.RegistrationAnswer) ([]models.RegistrationAnswer, error) {
+	byQuestion := map[int64]models.RegistrationAnswer{}
+	for _, answer := range answers {
+		if !slices.ContainsFunc(questions, func(q models.RegistrationQuestion) bool { return q.Id == answer.QuestionId }) {
+			return nil, fmt.Errorf("%w: unknown question id %d", ErrInvalidAnswers, answer.QuestionId)
+		}
+		if _, ok := byQuestion[answer.QuestionId]; ok {
+			return nil, fmt.Errorf("%w: question %d is answered more than once", ErrInvalidAnswers, answer.QuestionId)
+		}
+		byQuestion[answer.QuestionId] = answer
+	}
+
+	valid := []models.RegistrationAnswer{}
+	for _, q := range questions {
+		answer := byQuestion[q.Id]
+		answer.QuestionId = q.Id
+		answer.Value = strings.TrimSpace(answer.Value)
+
+		switch q.Type {
+		case QuestionText, QuestionSingleChoice:
+			if len(answer.Values) > 0 {
+				return nil, fmt.Errorf("%w: %q takes a single value", ErrInvalidAnswers, q.Label)
+			}
+			if answer.Value == "" {
+				if q.Required {
+					return nil, fmt.Errorf("%w: %q is required", ErrInvalidAnswers, q.Label)
+				}
+				continue
+			}
+			if q.Type == QuestionText && len(answer.Value) > maxTextAnswerLength {
+				return nil, fmt.Errorf("%w: %q can be at most %d characters", ErrInvalidAnswers, q.Label, maxTextAnswerLength)
+			}
+			if q.Type == QuestionSingleChoice && !slices.Contains(q.Options, answer.Value) {
+				return nil, fmt.Errorf("%w: %q is not an option of %q", ErrInvalidAnswers, answer.Value, q.Label)
+			}
+		case QuestionMultiChoice:
+			if answer.Value != "" {
+				return nil, fmt.Errorf("%w: %q takes a list of values", ErrInvalidAnswers, q.Label)
+			}
+			if len(answer.Values) == 0 {
+				if q.Required {
+					return nil, fmt.Errorf("%w: %q is required", ErrInvalidAnswers, q.Label)
+				}
+				continue
+			}
+			for i, value := range answer.Values {
+				if !slices.Contains(q.Options, value) {
+					return nil, fmt.Errorf("%w: %q is not an option of %q", ErrInvalidAnswers, value, q.Label)
+				}
+				if slices.Contains(answer.Values[:i], value) {
+					return nil, fmt.Errorf("%w: %q is chosen more than once for %q", ErrInvalidAnswers, value, q.Label)
+				}
+			}
+		}
+		valid = append(valid, answer)
+	}
+	return valid, nil
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"event-booking/models"
+	"event-booking/services/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func createTestQuestions() []models.RegistrationQuestion {
+	return []models.RegistrationQuestion{
+		{Id: 1, Label: "Dietary requirements", Type: QuestionText},
+		{Id: 2, Label: "T-shirt size", Type: QuestionSingleChoice, Options: []string{"S", "M", "L"}, Required: true},
+		{Id: 3, Label: "Sessions", Type: QuestionMultiChoice, Options: []string{"Morning", "Afternoon"}},
+	}
+}
+
+func TestSetRegistrationQuestions_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepository(ctrl)
+	service := NewEventService(mockRepo)
+
+	questions := []models.RegistrationQuestion{
+		{Id: 2, Label: " T-shirt size ", Type: QuestionSingleChoice, Options: []string{"S", "M"}},
+		{Label: "Allergies", Type: QuestionText},
+	}
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 10), nil)
+	mockRepo.EXPECT().GetRegistrationQuestions(testOrgId, int64(1)).Return(createTestQuestions(), nil)
+	mockRepo.EXPECT().ReplaceRegistrationQuestions(testOrgId, int64(1), gomock.Any()).Return(nil)
+
+	saved, err := service.SetRegistrationQuestions(testOrgId, 1, 10, questions)
+
+	require.NoError(t, err)
+	require.Len(t, saved, 2)
+	assert.Equal(t, "T-shirt size", saved[0].Label)
+	assert.Equal(t, int64(1), saved[1].EventId)
+}
+
+func TestSetRegistrationQuestions_Invalid(t *testing.T) {
+	tests := map[string]models.RegistrationQuestion{
+		"choice without options":    {Label: "Size", Type: QuestionSingleChoice, Options: []string{"M"}},
+		"text with options":         {Label: "Notes", Type: QuestionText, Options: []string{"a", "b"}},
+		"duplicate options":         {Label: "Size", Type: QuestionMultiChoice, Options: []string{"M", "M"}},
+		"question of another event": {Id: 99, Label: "Size", Type: QuestionText},
+		"blank label":               {Label: "  ", Type: QuestionText},
+		"type of existing question": {Id: 1, Label: "Dietary requirements", Type: QuestionMultiChoice, Options: []string{"Vegan", "None"}},
+	}
+
+	for name, question := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockEventRepository(ctrl)
+			service := NewEventService(mockRepo)
+
+			mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 10), nil)
+			mockRepo.EXPECT().GetRegistrationQuestions(testOrgId, int64(1)).Return(createTestQuestions(), nil)
+
+			_, err := service.SetRegistrationQuestions(testOrgId, 1, 10, []models.RegistrationQuestion{question})
+
+			assert.ErrorIs(t, err, ErrInvalidRegistrationQuestions)
+		})
+	}
+}
+
+func TestValidateAnswers(t *testing.T) {
+	answers, err := validateAnswers(createTestQuestions(), []models.RegistrationAnswer{
+		{QuestionId: 3, Values: []string{"Afternoon", "Morning"}},
+		{QuestionId: 2, Value: " M "},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []models.RegistrationAnswer{
+		{QuestionId: 2, Value: "M"},
+		{QuestionId: 3, Values: []string{"Afternoon", "Morning"}},
+	}, answers, "Answers are ordered like the questions and optional ones may be skipped")
+}
+
+func TestValidateAnswers_Invalid(t *testing.T) {
+	tests := map[string][]models.RegistrationAnswer{
+		"missing required answer": {{QuestionId: 1, Value: "None"}},
+		"unknown option":          {{QuestionId: 2, Value: "XXL"}},
+		"unknown question":        {{QuestionId: 2, Value: "M"}, {QuestionId: 9, Value: "?"}},
+		"answered twice":          {{QuestionId: 2, Value: "M"}, {QuestionId: 2, Value: "L"}},
+		"list for single choice":  {{QuestionId: 2, Values: []string{"M"}}},
+		"value for multi choice":  {{QuestionId: 2, Value: "M"}, {QuestionId: 3, Value: "Morning"}},
+		"option chosen twice":     {{QuestionId: 2, Value: "M"}, {QuestionId: 3, Values: []string{"Morning", "Morning"}}},
+	}
+
+	for name, answers := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := validateAnswers(createTestQuestions(), answers)
+
+			assert.ErrorIs(t, err, ErrInvalidAnswers)
+		})
+	}
+}
+
+func TestRegisterEvent_StoresAnswers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	mockRepo.EXPECT().GetUserById(int64(10)).Return(createVerifiedTestUser(10), nil)
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().GetRegisteredEventById(testOrgId, int64(10), int64(1)).Return(models.RegisterEvent{}, errors.New("not found"))
+	mockRepo.EXPECT().GetTicketTypes(testOrgId, int64(1)).Return([]models.TicketType{}, nil)
+	mockRepo.EXPECT().GetRegistrationQuestions(testOrgId, int64(1)).Return(createTestQuestions(), nil)
+	mockRepo.EXPECT().RegisterEvent(gomock.Any()).DoAndReturn(func(r *models.RegisterEvent) (bool, error) {
+		assert.Equal(t, []models.RegistrationAnswer{{QuestionId: 2, Value: "L"}}, r.Answers)
+		return true, nil
+	})
+
+	_, err := service.RegisterEvent(testOrgId, 10, 1, 0, "", []models.RegistrationAnswer{{QuestionId: 2, Value: "L"}})
+
+	require.NoError(t, err)
+}
+
+func TestRegisterEvent_InvalidAnswers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	mockRepo.EXPECT().GetUserById(int64(10)).Return(createVerifiedTestUser(10), nil)
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().GetRegisteredEventById(testOrgId, int64(10), int64(1)).Return(models.RegisterEvent{}, errors.New("not found"))
+	mockRepo.EXPECT().GetTicketTypes(testOrgId, int64(1)).Return([]models.TicketType{}, nil)
+	mockRepo.EXPECT().GetRegistrationQuestions(testOrgId, int64(1)).Return(createTestQuestions(), nil)
+
+	_, err := service.RegisterEvent(testOrgId, 10, 1, 0, "", nil)
+
+	assert.ErrorIs(t, err, ErrInvalidAnswers)
+}
+
+func TestExportAttendees(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	registeredAt := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	registrations := []models.RegisterEvent{{
+		Id: 100, UserId: 10, Email: "ada@example.com", Status: RegistrationApproved, CreatedAt: registeredAt,
+		Answers: []models.RegistrationAnswer{
+			{QuestionId: 1, Value: "=HYPERLINK(\"http://evil\")"},
+			{QuestionId: 3, Values: []string{"Morning", "Afternoon"}},
+		},
+	}}
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().GetRegistrationQuestions(testOrgId, int64(1)).Return(createTestQuestions(), nil)
+	mockRepo.EXPECT().GetTicketTypes(testOrgId, int64(1)).Return([]models.TicketType{}, nil)
+	mockRepo.EXPECT().GetRegistrations(testOrgId, int64(1), RegistrationApproved).Return(registrations, nil)
+
+	records, err := service.ExportAttendees(testOrgId, 1, 5)
+
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Registration ID", "User ID", "Email", "Registered At", "Ticket Type", "Dietary requirements", "T-shirt size", "Sessions"},
+		{"100", "10", "ada@example.com", "2026-03-01T09:30:00Z", "", "'=HYPERLINK(\"http://evil\")", "", "Morning; Afternoon"},
+	}, records)
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"errors"
+	"event-booking/models"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimitStore keeps token buckets. Implementations must run update
+// atomically for a key, so shared stores can be used by several instances.
+// Buckets that have not been touched for ttl may be dropped.
+type RateLimitStore interface {
+	UpdateBucket(key string, now time.Time, ttl time.Duration, update func(*models.RateLimitBucket)) error
+}
+
+// RateLimit allows Requests requests per Window. Unused capacity refills
+// continuously, so up to Requests requests can be made in a burst.
+type RateLimit struct {
+	Requests int
+	Window   time.Duration
+}
+
+func (l RateLimit) Enabled() bool {
+	return l.Requests > 0 && l.Window > 0
+}
+
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+var ErrInvalidRateLimit = errors.New("Rate limit must look like 100/1m")
+
+// ParseRateLimit parses limits such as "100/1m" or "5/30s". "off" and the
+// empty string disable the limit.
+func ParseRateLimit(s string) (RateLimit, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "off" {
+		return RateLimit{}, nil
+	}
+
+	requests, window, ok := strings.Cut(s, "/")
+	if !ok {
+		return RateLimit{}, ErrInvalidRateLimit
+	}
+
+	n, err := strconv.Atoi(requests)
+	if err != nil || n <= 0 {
+		return RateLimit{}, ErrInvalidRateLimit
+	}
+
+	d, err := time.ParseDuration(window)
+	if err != nil || d <= 0 {
+		return RateLimit{}, ErrInvalidRateLimit
+	}
+
+	return RateLimit{Requests: n, Window: d}, nil
+}
+
+func (l RateLimit) String() string {
+	return fmt.Sprintf("%d/%s", l.Requests, l.Window)
+}
+
+type RateLimitService struct {
+	store RateLimitStore
+	now   func() time.Time
+}
+
+func NewRateLimitService(store RateLimitStore) *RateLimitService {
+	return &RateLimitService{
+		store: store,
+		now:   time.Now,
+	}
+}
+
+// Allow takes a token from the bucket for key and reports whether the
+// request may proceed.
+func (s *RateLimitService) Allow(key string, limit RateLimit) (RateLimitResult, error) {
+	now := s.now()
+	capacity := float64(limit.Requests)
+	perSecond := capacity / limit.Window.Seconds()
+
+	var result RateLimitResult
+	err := s.store.UpdateBucket(key, now, limit.Window, func(bucket *models.RateLimitBucket) {
+		tokens := capacity
+		if !bucket.UpdatedAt.IsZero() {
+			elapsed := now.Sub(bucket.UpdatedAt).Seconds()
+			tokens = math.Min(capacity, bucket.Tokens+max(elapsed, 0)*perSecond)
+		}
+
+		result.Allowed = tokens >= 1
+		if result.Allowed {
+			tokens--
+		} else {
+			result.RetryAfter = secondsToDuration((1 - tokens) / perSecond)
+		}
+
+		bucket.Tokens = tokens
+		bucket.UpdatedAt = now
+
+		result.Limit = limit.Requests
+		result.Remaining = int(math.Floor(tokens))
+		result.Reset = secondsToDuration((capacity - tokens) / perSecond)
+	})
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+
+	return result, nil
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"event-booking/models"
+	"event-booking/services/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// expectBucket makes the mock store apply updates to bucket.
+func expectBucket(mockStore *mocks.MockRateLimitStore, key string, bucket *models.RateLimitBucket) {
+	mockStore.EXPECT().UpdateBucket(key, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ string, _ time.Time, _ time.Duration, update func(*models.RateLimitBucket)) error {
+			update(bucket)
+			return nil
+		}).AnyTimes()
+}
+
+func TestParseRateLimit(t *testing.T) {
+	limit, err := ParseRateLimit("100/1m")
+	require.NoError(t, err)
+	assert.Equal(t, RateLimit{Requests: 100, Window: time.Minute}, limit)
+
+	limit, err = ParseRateLimit("off")
+	require.NoError(t, err)
+	assert.False(t, limit.Enabled())
+
+	for _, invalid := range []string{"100", "0/1m", "-1/1m", "abc/1m", "10/0s", "10/minute"} {
+		_, err = ParseRateLimit(invalid)
+		assert.Equal(t, ErrInvalidRateLimit, err, invalid)
+	}
+}
+
+func TestRateLimitAllow_BurstThenReject(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mocks.NewMockRateLimitStore(ctrl)
+	service := NewRateLimitService(mockStore)
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	var bucket models.RateLimitBucket
+	expectBucket(mockStore, "api:user:1", &bucket)
+	limit := RateLimit{Requests: 3, Window: 3 * time.Second}
+
+	for i := 2; i >= 0; i-- {
+		result, err := service.Allow("api:user:1", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 3, result.Limit)
+		assert.Equal(t, i, result.Remaining)
+	}
+
+	result, err := service.Allow("api:user:1", limit)
+
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+	assert.Equal(t, time.Second, result.RetryAfter)
+	assert.Equal(t, 3*time.Second, result.Reset)
+}
+
+func TestRateLimitAllow_Refills(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mocks.NewMockRateLimitStore(ctrl)
+	service := NewRateLimitService(mockStore)
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	bucket := models.RateLimitBucket{Tokens: 0, UpdatedAt: now}
+	expectBucket(mockStore, "api:ip:127.0.0.1", &bucket)
+	limit := RateLimit{Requests: 60, Window: time.Minute}
+
+	now = now.Add(500 * time.Millisecond)
+	result, err := service.Allow("api:ip:127.0.0.1", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 500*time.Millisecond, result.RetryAfter)
+
+	now = now.Add(time.Hour)
+	result, err = service.Allow("api:ip:127.0.0.1", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 59, result.Remaining)
+}
+
+func TestRateLimitAllow_StoreError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mocks.NewMockRateLimitStore(ctrl)
+	service := NewRateLimitService(mockStore)
+	expectedError := errors.New("store unavailable")
+
+	mockStore.EXPECT().UpdateBucket("api:user:1", gomock.Any(), time.Minute, gomock.Any()).Return(expectedError)
+
+	_, err := service.Allow("api:user:1", RateLimit{Requests: 10, Window: time.Minute})
+
+	require.Error(t, err)
+	assert.Equal(t, expectedError, err)
+}
@@ -3,46 +3,593 @@ package services
 import (
 	"errors"
 	"event-booking/models"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type RegisterRepository interface {
-	GetEventById(int64) (models.Event, error)
-	GetRegisteredEventById(int64, int64) (models.RegisterEvent, error)
-	RegisterEvent(int64, int64) error
-	DeleteRegisteredEvent(int64) error
+	GetEventById(int64, int64) (models.Event, error)
+	CanAccessEvent(int64, int64, int64) (bool, error)
+	GetEventMember(int64, int64, int64) (models.EventMember, error)
+	GetRegistrationQuestions(int64, int64) ([]models.RegistrationQuestion, error)
+	GetTicketTypes(int64, int64) ([]models.TicketType, error)
+	GetPromoCode(int64, int64, int64) (models.PromoCode, error)
+	GetPromoCodeByCode(int64, int64, string) (models.PromoCode, error)
+	GetUserById(int64) (models.User, error)
+	GetRegisteredEventById(int64, int64, int64) (models.RegisterEvent, error)
+	GetRegistration(int64, int64) (models.RegisterEvent, error)
+	GetRegistrations(int64, int64, string) ([]models.RegisterEvent, error)
+	RegisterEvent(*models.RegisterEvent) (bool, error)
+	SetRegistrationStatus(int64, int64, []int64, string) (bool, error)
+	GetCancellationPolicy(int64, int64) (models.CancellationPolicy, error)
+	GetPaidOrder(int64, int64) (models.Order, error)
+	RecordCancellation(*models.Cancellation) (bool, error)
+	RecordRefund(*models.Cancellation) error
+	SetRefundStatus(models.Cancellation) error
+	GetCancellations(int64, int64) ([]models.Cancellation, error)
+	RetryRefund(int64, int64) (bool, error)
+	CheckIn(int64, int64, int64, int64, time.Time) (bool, error)
+	GetOrganization(int64) (models.Organization, error)
+	GetAttendeeAttendance(int64, time.Time) ([]models.AttendeeAttendance, error)
+	GetOrganizationMemberByEmail(int64, string) (models.OrganizationMember, error)
+	RegisterGroup(*models.RegistrationGroup) (bool, error)
+	GetGroup(int64, int64) (models.RegistrationGroup, error)
+	GetGroups(int64, int64) ([]models.RegistrationGroup, error)
 }
 
 type EventRegisterService struct {
-	repo RegisterRepository
+	repo     RegisterRepository
+	provider PaymentProvider
+	now      func() time.Time
+}
+
+// Registration statuses. Registrations with a paid ticket await payment and
+// registrations for events requiring approval start pending; only approved
+// registrations take a seat.
+const (
+	RegistrationAwaitingPayment = "awaiting_payment"
+	RegistrationPending         = "pending"
+	RegistrationApproved        = "approved"
+	RegistrationRejected        = "rejected"
+	RegistrationCancelled       = "cancelled"
+)
+
+var RegistrationStatuses = []string{
+	RegistrationAwaitingPayment, RegistrationPending, RegistrationApproved, RegistrationRejected, RegistrationCancelled,
 }
 
 var ErrRegisterEventNotFound = errors.New("Event registration could not be retrieved")
+var ErrAlreadyRegistered = errors.New("You're already registered for this event")
+var ErrEventFull = errors.New("Event is fully booked")
+var ErrRegistrationNotPending = errors.New("Only pending registrations can be approved or rejected")
+var ErrInvalidRegistrationStatus = errors.New("Registration status is invalid")
 
-func NewEventRegisterService(repo RegisterRepository) *EventRegisterService {
+// NewEventRegisterService creates the service. The payment provider
+// refunds paid registrations when they are cancelled.
+func NewEventRegisterService(repo RegisterRepository, provider PaymentProvider) *EventRegisterService {
 	return &EventRegisterService{
-		repo: repo,
+		repo:     repo,
+		provider: provider,
+		now:      time.Now,
+	}
+}
+
+// RegisterEvent registers userId for an event of organization orgId with a
+// ticket of type ticketTypeId, which is 0 for events without ticket types,
+// an optional promo code and answers to the event's registration
+// questions. Only invitees can register for private events. Registrations
+// with a paid ticket await payment, see OrderService. Otherwise the
+// registration is pending when the event requires approval and takes one of
+// the event's seats when not.
+func (s *EventRegisterService) RegisterEvent(orgId, userId, eventId, ticketTypeId int64, promoCode string, answers []models.RegistrationAnswer) (models.RegisterEvent, error) {
+	user, err := s.repo.GetUserById(userId)
+	if err != nil {
+		return models.RegisterEvent{}, err
+	}
+	if !user.Verified {
+		return models.RegisterEvent{}, ErrEmailNotVerified
+	}
+
+	event, err := s.repo.GetEventById(orgId, eventId)
+	if err != nil {
+		return models.RegisterEvent{}, ErrEventNotFound
+	}
+
+	err = checkEventVisible(s.repo, event, userId)
+	if err != nil {
+		return models.RegisterEvent{}, err
+	}
+
+	_, err = s.repo.GetRegisteredEventById(orgId, userId, eventId)
+	if err == nil {
+		return models.RegisterEvent{}, ErrAlreadyRegistered
+	}
+
+	ticketTypes, err := s.repo.GetTicketTypes(orgId, eventId)
+	if err != nil {
+		return models.RegisterEvent{}, err
+	}
+	ticketType, err := chooseTicketType(ticketTypes, ticketTypeId, s.now())
+	if err != nil {
+		return models.RegisterEvent{}, err
+	}
+	amount, promoCodeId, err := s.price(orgId, eventId, ticketType, promoCode)
+	if err != nil {
+		return models.RegisterEvent{}, err
+	}
+
+	questions, err := s.repo.GetRegistrationQuestions(orgId, eventId)
+	if err != nil {
+		return models.RegisterEvent{}, err
+	}
+	answers, err = validateAnswers(questions, answers)
+	if err != nil {
+		return models.RegisterEvent{}, err
+	}
+
+	registration := models.RegisterEvent{
+		UserId:         userId,
+		EventId:        eventId,
+		OrganizationId: orgId,
+		TicketTypeId:   ticketType.Id,
+		PromoCodeId:    promoCodeId,
+		Amount:         amount,
+		Status:         RegistrationApproved,
+		CreatedAt:      s.now(),
+		Answers:        answers,
+	}
+	if event.RequiresApproval {
+		registration.Status = RegistrationPending
+	}
+	if amount > 0 {
+		registration.Status = RegistrationAwaitingPayment
+	}
+
+	registered, err := s.repo.RegisterEvent(&registration)
+	if err != nil {
+		return models.RegisterEvent{}, err
+	}
+	if !registered {
+		// Another request may have registered the user in the meantime.
+		_, err = s.repo.GetRegisteredEventById(orgId, userId, eventId)
+		if err == nil {
+			return models.RegisterEvent{}, ErrAlreadyRegistered
+		}
+	}
+	if !registered && promoCodeId != 0 {
+		code, err := s.repo.GetPromoCode(orgId, eventId, promoCodeId)
+		if err == nil && code.MaxUses > 0 && code.Used >= code.MaxUses {
+			return models.RegisterEvent{}, ErrPromoCodeUsedUp
+		}
+	}
+	if !registered && event.Capacity == 0 && ticketType.Id != 0 {
+		return models.RegisterEvent{}, ErrTicketTypeSoldOut
+	}
+	if !registered {
+		return models.RegisterEvent{}, ErrEventFull
+	}
+	return registration, nil
+}
+
+// price returns what a ticket of ticketType costs with promoCode, and the id
+// of the promo code.
+func (s *EventRegisterService) price(orgId, eventId int64, ticketType models.TicketType, promoCode string) (int64, int64, error) {
+	if promoCode == "" {
+		return ticketType.Price, 0, nil
+	}
+
+	code, err := s.repo.GetPromoCodeByCode(orgId, eventId, normalizeCode(promoCode))
+	if err != nil {
+		return 0, 0, ErrPromoCodeNotApplicable
+	}
+	amount, err := applyPromoCode(code, ticketType, s.now())
+	if err != nil {
+		return 0, 0, err
+	}
+	return amount, code.Id, nil
+}
+
+// Registrations lists the registrations of an event, optionally only those
+// with the given status, to its owner and co-organizers. Each registration
+// carries the past no-shows of its user, see rankByNoShows.
+func (s *EventRegisterService) Registrations(orgId, eventId, userId int64, status string) ([]models.RegisterEvent, error) {
+	if status != "" && !slices.Contains(RegistrationStatuses, status) {
+		return nil, ErrInvalidRegistrationStatus
+	}
+
+	_, err := authorizeEventRole(s.repo, orgId, eventId, userId, EventRoleOwner, EventRoleCoOrganizer)
+	if err != nil {
+		return nil, err
+	}
+	registrations, err := s.repo.GetRegistrations(orgId, eventId, status)
+	if err != nil {
+		return nil, err
+	}
+	err = s.rankByNoShows(orgId, registrations)
+	if err != nil {
+		return nil, err
+	}
+	return registrations, nil
+}
+
+// ExportAttendees returns the approved registrations of an event as CSV
+// records: a header followed by one row per attendee with their answers.
+func (s *EventRegisterService) ExportAttendees(orgId, eventId, userId int64) ([][]string, error) {
+	_, err := authorizeEventRole(s.repo, orgId, eventId, userId, EventRoleOwner, EventRoleCoOrganizer)
+	if err != nil {
+		return nil, err
+	}
+
+	questions, err := s.repo.GetRegistrationQuestions(orgId, eventId)
+	if err != nil {
+		return nil, err
+	}
+	ticketTypes, err := s.repo.GetTicketTypes(orgId, eventId)
+	if err != nil {
+		return nil, err
+	}
+	registrations, err := s.repo.GetRegistrations(orgId, eventId, RegistrationApproved)
+	if err != nil {
+		return nil, err
+	}
+
+	header := []string{"Registration ID", "User ID", "Email", "Registered At", "Ticket Type"}
+	for _, q := range questions {
+		header = append(header, csvField(q.Label))
+	}
+	records := [][]string{header}
+
+	for _, registration := range registrations {
+		record := []string{
+			strconv.FormatInt(registration.Id, 10),
+			strconv.FormatInt(registration.UserId, 10),
+			csvField(registration.Email),
+			registration.CreatedAt.UTC().Format(time.RFC3339),
+			"",
+		}
+		i := slices.IndexFunc(ticketTypes, func(t models.TicketType) bool { return t.Id == registration.TicketTypeId })
+		if i >= 0 {
+			record[4] = csvField(ticketTypes[i].Name)
+		}
+		for _, q := range questions {
+			i := slices.IndexFunc(registration.Answers, func(a models.RegistrationAnswer) bool { return a.QuestionId == q.Id })
+			if i < 0 {
+				record = append(record, "")
+				continue
+			}
+			answer := registration.Answers[i]
+			if q.Type == QuestionMultiChoice {
+				record = append(record, csvField(strings.Join(answer.Values, "; ")))
+			} else {
+				record = append(record, csvField(answer.Value))
+			}
+		}
+		records = append(records, record)
 	}
+	return records, nil
 }
 
-func (s *EventRegisterService) RegisterEvent(userId, eventId int64) error {
-	_, err := s.repo.GetEventById(eventId)
+// csvField keeps spreadsheets from evaluating values entered by users as
+// formulas.
+func csvField(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// ApproveRegistrations approves pending registrations of an event. Either
+// all of them are approved or, when they do not fit into the remaining
+// capacity, none.
+func (s *EventRegisterService) ApproveRegistrations(orgId, eventId, userId int64, ids []int64) error {
+	return s.decide(orgId, eventId, userId, ids, RegistrationApproved)
+}
+
+// RejectRegistrations rejects pending registrations of an event. Paid
+// orders of the rejected registrations are refunded in full.
+func (s *EventRegisterService) RejectRegistrations(orgId, eventId, userId int64, ids []int64) error {
+	return s.decide(orgId, eventId, userId, ids, RegistrationRejected)
+}
+
+// PromoteRegistrations approves up to count registrations from the waitlist
+// of an event, which are its pending registrations in the order they were
+// made. When the organization deprioritizes no-shows, registrations of
+// repeat no-shows are promoted after all others, see rankByNoShows.
+// Registrations that do not fit into the event or their ticket type are
+// skipped. The promoted registrations are returned; ErrEventFull is
+// returned when none fit.
+func (s *EventRegisterService) PromoteRegistrations(orgId, eventId, userId int64, count int) ([]models.RegisterEvent, error) {
+	_, err := authorizeEventRole(s.repo, orgId, eventId, userId, EventRoleOwner, EventRoleCoOrganizer)
 	if err != nil {
-		return ErrEventNotFound
+		return nil, err
 	}
 
-	return s.repo.RegisterEvent(userId, eventId)
+	waitlist, err := s.repo.GetRegistrations(orgId, eventId, RegistrationPending)
+	if err != nil {
+		return nil, err
+	}
+	err = s.rankByNoShows(orgId, waitlist)
+	if err != nil {
+		return nil, err
+	}
+
+	promoted := []models.RegisterEvent{}
+	for _, registration := range waitlist {
+		if len(promoted) == count {
+			break
+		}
+		approved, err := s.repo.SetRegistrationStatus(orgId, eventId, []int64{registration.Id}, RegistrationApproved)
+		if err != nil {
+			return promoted, err
+		}
+		if !approved {
+			continue
+		}
+		registration.Status = RegistrationApproved
+		promoted = append(promoted, registration)
+	}
+	if len(promoted) == 0 && len(waitlist) > 0 {
+		return promoted, ErrEventFull
+	}
+	return promoted, nil
 }
 
-func (s *EventRegisterService) CancelEvent(userId, eventId int64) error {
-	_, err := s.repo.GetEventById(eventId)
+func (s *EventRegisterService) decide(orgId, eventId, userId int64, ids []int64, status string) error {
+	_, err := authorizeEventRole(s.repo, orgId, eventId, userId, EventRoleOwner, EventRoleCoOrganizer)
 	if err != nil {
-		return ErrEventNotFound
+		return err
 	}
 
-	registeredEvent, err := s.repo.GetRegisteredEventById(userId, eventId)
+	registrations, err := s.repo.GetRegistrations(orgId, eventId, "")
 	if err != nil {
-		return ErrRegisterEventNotFound
+		return err
+	}
+	ids = slices.Compact(slices.Sorted(slices.Values(ids)))
+	for _, id := range ids {
+		i := slices.IndexFunc(registrations, func(r models.RegisterEvent) bool { return r.Id == id })
+		if i < 0 {
+			return ErrRegisterEventNotFound
+		}
+		if registrations[i].Status != RegistrationPending {
+			return ErrRegistrationNotPending
+		}
 	}
 
-	return s.repo.DeleteRegisteredEvent(registeredEvent.Id)
+	updated, err := s.repo.SetRegistrationStatus(orgId, eventId, ids, status)
+	if err != nil {
+		return err
+	}
+	if !updated && status == RegistrationApproved {
+		return ErrEventFull
+	}
+	if !updated {
+		return ErrRegistrationNotPending
+	}
+	if status == RegistrationRejected {
+		return s.refundRejected(orgId, eventId, registrations, ids)
+	}
+	return nil
+}
+
+// refundRejected refunds the paid orders of rejected registrations in full,
+// whatever the event's cancellation policy says.
+func (s *EventRegisterService) refundRejected(orgId, eventId int64, registrations []models.RegisterEvent, ids []int64) error {
+	now := s.now()
+	var failed bool
+	for _, registration := range registrations {
+		if !slices.Contains(ids, registration.Id) {
+			continue
+		}
+		order, err := s.repo.GetPaidOrder(orgId, registration.Id)
+		if err != nil || refundable(order, registration) <= 0 {
+			continue
+		}
+
+		refund := models.Cancellation{
+			RegistrationId: registration.Id,
+			UserId:         registration.UserId,
+			EventId:        eventId,
+			OrganizationId: orgId,
+			OrderId:        order.Id,
+			RefundAmount:   refundable(order, registration),
+			Currency:       order.Currency,
+			RefundStatus:   RefundPending,
+			CancelledAt:    now,
+		}
+		err = s.repo.RecordRefund(&refund)
+		if err != nil {
+			return err
+		}
+		err = s.refund(&refund, order.PaymentIntentId)
+		if errors.Is(err, ErrRefundFailed) {
+			failed = true
+		} else if err != nil {
+			return err
+		}
+	}
+
+	if failed {
+		return ErrRejectionRefundFailed
+	}
+	return nil
+}
+
+// CancelEvent cancels the registration of userId and refunds its paid order
+// as far as the event's cancellation policy allows. The cancellation is
+// kept as a record; when the refund fails, the registration stays cancelled
+// and the record shows the failed refund.
+func (s *EventRegisterService) CancelEvent(orgId, userId, eventId int64) (models.Cancellation, error) {
+	event, err := s.repo.GetEventById(orgId, eventId)
+	if err != nil {
+		return models.Cancellation{}, ErrEventNotFound
+	}
+
+	registeredEvent, err := s.repo.GetRegisteredEventById(orgId, userId, eventId)
+	if err != nil {
+		return models.Cancellation{}, ErrRegisterEventNotFound
+	}
+
+	return s.cancel(event, registeredEvent)
+}
+
+// CancelAllRegistrations cancels the active registrations of an event its
+// owner is about to delete. Paid orders are refunded in full, since the
+// attendees did not choose to cancel, and refunds of earlier cancellations
+// that failed are retried. When a refund fails, the remaining registrations
+// are left active and the event is kept, so that the deletion can be
+// retried.
+func (s *EventRegisterService) CancelAllRegistrations(orgId, eventId, userId int64) ([]models.Cancellation, error) {
+	event, err := authorizeEventRole(s.repo, orgId, eventId, userId, EventRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	registrations, err := s.repo.GetRegistrations(orgId, eventId, "")
+	if err != nil {
+		return nil, err
+	}
+
+	cancellations := []models.Cancellation{}
+	for _, registration := range registrations {
+		if registration.Status != RegistrationAwaitingPayment && registration.Status != RegistrationPending &&
+			registration.Status != RegistrationApproved {
+			continue
+		}
+		cancellation, err := s.cancelRefunding(event, registration, true)
+		if errors.Is(err, ErrRegisterEventNotFound) {
+			continue
+		}
+		if errors.Is(err, ErrRefundFailed) {
+			return cancellations, ErrEventRefundFailed
+		}
+		if err != nil {
+			return cancellations, err
+		}
+		cancellations = append(cancellations, cancellation)
+	}
+
+	retried, err := s.retryFailedRefunds(event)
+	cancellations = append(cancellations, retried...)
+	return cancellations, err
+}
+
+// retryFailedRefunds refunds the cancellations of event whose refund failed
+// once more. Each one is retried by one request only.
+func (s *EventRegisterService) retryFailedRefunds(event models.Event) ([]models.Cancellation, error) {
+	orgId := event.OrganizationId
+	cancellations, err := s.repo.GetCancellations(orgId, event.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	retried := []models.Cancellation{}
+	for _, cancellation := range cancellations {
+		if cancellation.RefundStatus != RefundFailed {
+			continue
+		}
+		order, err := s.repo.GetPaidOrder(orgId, cancellation.RegistrationId)
+		if err != nil {
+			return retried, err
+		}
+		claimed, err := s.repo.RetryRefund(orgId, cancellation.Id)
+		if err != nil {
+			return retried, err
+		}
+		if !claimed {
+			continue
+		}
+
+		err = s.refund(&cancellation, order.PaymentIntentId)
+		retried = append(retried, cancellation)
+		if errors.Is(err, ErrRefundFailed) {
+			return retried, ErrEventRefundFailed
+		}
+		if err != nil {
+			return retried, err
+		}
+	}
+	return retried, nil
+}
+
+// cancel cancels the registration for event and refunds its paid order as
+// far as the event's cancellation policy allows, see CancelEvent.
+func (s *EventRegisterService) cancel(event models.Event, registration models.RegisterEvent) (models.Cancellation, error) {
+	return s.cancelRefunding(event, registration, false)
+}
+
+// cancelRefunding cancels the registration for event and refunds its paid
+// order, in full or following the event's cancellation policy.
+func (s *EventRegisterService) cancelRefunding(event models.Event, registration models.RegisterEvent, inFull bool) (models.Cancellation, error) {
+	orgId := event.OrganizationId
+	now := s.now()
+	cancellation := models.Cancellation{
+		RegistrationId: registration.Id,
+		UserId:         registration.UserId,
+		EventId:        event.Id,
+		OrganizationId: orgId,
+		RefundStatus:   RefundNone,
+		CancelledAt:    now,
+	}
+	order, err := s.repo.GetPaidOrder(orgId, registration.Id)
+	if err == nil {
+		cancellation.OrderId = order.Id
+		cancellation.Currency = order.Currency
+		cancellation.RefundAmount = refundable(order, registration)
+		if !inFull {
+			policy, err := s.repo.GetCancellationPolicy(orgId, event.Id)
+			if err != nil {
+				return models.Cancellation{}, err
+			}
+			cancellation.RefundAmount = refundAmount(policy, cancellation.RefundAmount, event.DateTime, now)
+		}
+		if cancellation.RefundAmount > 0 {
+			cancellation.RefundStatus = RefundPending
+		}
+	}
+
+	cancelled, err := s.repo.RecordCancellation(&cancellation)
+	if err != nil {
+		return models.Cancellation{}, err
+	}
+	if !cancelled {
+		return models.Cancellation{}, ErrRegisterEventNotFound
+	}
+	if cancellation.RefundStatus == RefundNone {
+		return cancellation, nil
+	}
+
+	return cancellation, s.refund(&cancellation, order.PaymentIntentId)
+}
+
+// refundable returns how much of order can be refunded for registration:
+// what is left of it, or the registration's share of a group order.
+func refundable(order models.Order, registration models.RegisterEvent) int64 {
+	left := order.Amount - order.RefundedAmount
+	if order.GroupId != 0 {
+		return min(registration.Amount, left)
+	}
+	return left
+}
+
+// refund pays back the refund amount of cancellation through the payment
+// provider and stores the outcome.
+func (s *EventRegisterService) refund(cancellation *models.Cancellation, intentId string) error {
+	cancellation.RefundStatus = RefundSucceeded
+	refundErr := s.provider.Refund(intentId, cancellation.RefundAmount)
+	if refundErr != nil {
+		log.Printf("refunding cancellation %d failed: %v", cancellation.Id, refundErr)
+		cancellation.RefundStatus = RefundFailed
+	}
+
+	err := s.repo.SetRefundStatus(*cancellation)
+	if err != nil {
+		return err
+	}
+	if refundErr != nil {
+		return ErrRefundFailed
+	}
+	return nil
 }
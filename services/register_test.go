@@ -20,23 +20,43 @@ func createTestRegisteredEvent(id, userId, eventId int64) models.RegisterEvent {
 	}
 }
 
+func createVerifiedTestUser(id int64) models.User {
+	return models.User{
+		Id:       id,
+		Email:    "user@example.com",
+		Verified: true,
+	}
+}
+
 func TestRegisterEvent_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockRegisterRepository(ctrl)
-	service := NewEventRegisterService(mockRepo)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
 
 	userId := int64(10)
 	eventId := int64(1)
 	event := createTestEvent(eventId, 5)
 
-	mockRepo.EXPECT().GetEventById(eventId).Return(event, nil)
-	mockRepo.EXPECT().RegisterEvent(userId, eventId).Return(nil)
-
-	err := service.RegisterEvent(userId, eventId)
+	mockRepo.EXPECT().GetUserById(userId).Return(createVerifiedTestUser(userId), nil)
+	mockRepo.EXPECT().GetEventById(testOrgId, eventId).Return(event, nil)
+	mockRepo.EXPECT().GetRegisteredEventById(testOrgId, userId, eventId).Return(models.RegisterEvent{}, errors.New("not found"))
+	mockRepo.EXPECT().GetTicketTypes(testOrgId, eventId).Return([]models.TicketType{}, nil)
+	mockRepo.EXPECT().GetRegistrationQuestions(testOrgId, eventId).Return([]models.RegistrationQuestion{}, nil)
+	mockRepo.EXPECT().RegisterEvent(gomock.Any()).DoAndReturn(func(r *models.RegisterEvent) (bool, error) {
+		assert.Equal(t, userId, r.UserId)
+		assert.Equal(t, eventId, r.EventId)
+		assert.Equal(t, testOrgId, r.OrganizationId)
+		r.Id = 100
+		return true, nil
+	})
+
+	registration, err := service.RegisterEvent(testOrgId, userId, eventId, 0, "", nil)
 
 	require.NoError(t, err)
+	assert.Equal(t, int64(100), registration.Id)
+	assert.Equal(t, RegistrationApproved, registration.Status)
 }
 
 func TestRegisterEvent_EventNotFound(t *testing.T) {
@@ -44,14 +64,15 @@ func TestRegisterEvent_EventNotFound(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockRegisterRepository(ctrl)
-	service := NewEventRegisterService(mockRepo)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
 
 	userId := int64(10)
 	eventId := int64(999)
 
-	mockRepo.EXPECT().GetEventById(eventId).Return(models.Event{}, errors.New("not found"))
+	mockRepo.EXPECT().GetUserById(userId).Return(createVerifiedTestUser(userId), nil)
+	mockRepo.EXPECT().GetEventById(testOrgId, eventId).Return(models.Event{}, errors.New("not found"))
 
-	err := service.RegisterEvent(userId, eventId)
+	_, err := service.RegisterEvent(testOrgId, userId, eventId, 0, "", nil)
 
 	require.Error(t, err)
 	assert.Equal(t, ErrEventNotFound, err)
@@ -62,17 +83,21 @@ func TestRegisterEvent_RepositoryError(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockRegisterRepository(ctrl)
-	service := NewEventRegisterService(mockRepo)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
 
 	userId := int64(10)
 	eventId := int64(1)
 	event := createTestEvent(eventId, 5)
 	expectedError := errors.New("registration insert failed")
 
-	mockRepo.EXPECT().GetEventById(eventId).Return(event, nil)
-	mockRepo.EXPECT().RegisterEvent(userId, eventId).Return(expectedError)
+	mockRepo.EXPECT().GetUserById(userId).Return(createVerifiedTestUser(userId), nil)
+	mockRepo.EXPECT().GetEventById(testOrgId, eventId).Return(event, nil)
+	mockRepo.EXPECT().GetRegisteredEventById(testOrgId, userId, eventId).Return(models.RegisterEvent{}, errors.New("not found"))
+	mockRepo.EXPECT().GetTicketTypes(testOrgId, eventId).Return([]models.TicketType{}, nil)
+	mockRepo.EXPECT().GetRegistrationQuestions(testOrgId, eventId).Return([]models.RegistrationQuestion{}, nil)
+	mockRepo.EXPECT().RegisterEvent(gomock.Any()).Return(false, expectedError)
 
-	err := service.RegisterEvent(userId, eventId)
+	_, err := service.RegisterEvent(testOrgId, userId, eventId, 0, "", nil)
 
 	require.Error(t, err)
 	assert.Equal(t, expectedError, err)
@@ -83,19 +108,37 @@ func TestRegisterEvent_AlreadyRegistered(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockRegisterRepository(ctrl)
-	service := NewEventRegisterService(mockRepo)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
 
 	userId := int64(10)
 	eventId := int64(1)
 	event := createTestEvent(eventId, 5)
 
-	mockRepo.EXPECT().GetEventById(eventId).Return(event, nil)
-	mockRepo.EXPECT().RegisterEvent(userId, eventId).Return(errors.New("UNIQUE constraint failed"))
+	mockRepo.EXPECT().GetUserById(userId).Return(createVerifiedTestUser(userId), nil)
+	mockRepo.EXPECT().GetEventById(testOrgId, eventId).Return(event, nil)
+	mockRepo.EXPECT().GetRegisteredEventById(testOrgId, userId, eventId).Return(createTestRegisteredEvent(100, userId, eventId), nil)
+
+	_, err := service.RegisterEvent(testOrgId, userId, eventId, 0, "", nil)
+
+	assert.Equal(t, ErrAlreadyRegistered, err)
+}
+
+func TestRegisterEvent_UnverifiedUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	userId := int64(10)
+	eventId := int64(1)
 
-	err := service.RegisterEvent(userId, eventId)
+	mockRepo.EXPECT().GetUserById(userId).Return(models.User{Id: userId, Verified: false}, nil)
+
+	_, err := service.RegisterEvent(testOrgId, userId, eventId, 0, "", nil)
 
 	require.Error(t, err)
-	assert.Contains(t, err.Error(), "UNIQUE")
+	assert.Equal(t, ErrEmailNotVerified, err)
 }
 
 func TestCancelEvent_Success(t *testing.T) {
@@ -103,20 +146,27 @@ func TestCancelEvent_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockRegisterRepository(ctrl)
-	service := NewEventRegisterService(mockRepo)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
 
 	userId := int64(10)
 	eventId := int64(1)
 	event := createTestEvent(eventId, 5)
 	registeredEvent := createTestRegisteredEvent(100, userId, eventId)
 
-	mockRepo.EXPECT().GetEventById(eventId).Return(event, nil)
-	mockRepo.EXPECT().GetRegisteredEventById(userId, eventId).Return(registeredEvent, nil)
-	mockRepo.EXPECT().DeleteRegisteredEvent(registeredEvent.Id).Return(nil)
+	mockRepo.EXPECT().GetEventById(testOrgId, eventId).Return(event, nil)
+	mockRepo.EXPECT().GetRegisteredEventById(testOrgId, userId, eventId).Return(registeredEvent, nil)
+	mockRepo.EXPECT().GetPaidOrder(testOrgId, registeredEvent.Id).Return(models.Order{}, errors.New("not found"))
+	mockRepo.EXPECT().RecordCancellation(gomock.Any()).DoAndReturn(func(c *models.Cancellation) (bool, error) {
+		c.Id = 3
+		return true, nil
+	})
 
-	err := service.CancelEvent(userId, eventId)
+	cancellation, err := service.CancelEvent(testOrgId, userId, eventId)
 
 	require.NoError(t, err)
+	assert.Equal(t, int64(3), cancellation.Id)
+	assert.Equal(t, registeredEvent.Id, cancellation.RegistrationId)
+	assert.Equal(t, RefundNone, cancellation.RefundStatus)
 }
 
 func TestCancelEvent_EventNotFound(t *testing.T) {
@@ -124,14 +174,14 @@ func TestCancelEvent_EventNotFound(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockRegisterRepository(ctrl)
-	service := NewEventRegisterService(mockRepo)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
 
 	userId := int64(10)
 	eventId := int64(999)
 
-	mockRepo.EXPECT().GetEventById(eventId).Return(models.Event{}, errors.New("not found"))
+	mockRepo.EXPECT().GetEventById(testOrgId, eventId).Return(models.Event{}, errors.New("not found"))
 
-	err := service.CancelEvent(userId, eventId)
+	_, err := service.CancelEvent(testOrgId, userId, eventId)
 
 	require.Error(t, err)
 	assert.Equal(t, ErrEventNotFound, err)
@@ -142,16 +192,16 @@ func TestCancelEvent_RegistrationNotFound(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockRegisterRepository(ctrl)
-	service := NewEventRegisterService(mockRepo)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
 
 	userId := int64(10)
 	eventId := int64(1)
 	event := createTestEvent(eventId, 5)
 
-	mockRepo.EXPECT().GetEventById(eventId).Return(event, nil)
-	mockRepo.EXPECT().GetRegisteredEventById(userId, eventId).Return(models.RegisterEvent{}, errors.New("not found"))
+	mockRepo.EXPECT().GetEventById(testOrgId, eventId).Return(event, nil)
+	mockRepo.EXPECT().GetRegisteredEventById(testOrgId, userId, eventId).Return(models.RegisterEvent{}, errors.New("not found"))
 
-	err := service.CancelEvent(userId, eventId)
+	_, err := service.CancelEvent(testOrgId, userId, eventId)
 
 	require.Error(t, err)
 	assert.Equal(t, ErrRegisterEventNotFound, err)
@@ -162,20 +212,236 @@ func TestCancelEvent_DeleteError(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockRegisterRepository(ctrl)
-	service := NewEventRegisterService(mockRepo)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
 
 	userId := int64(10)
 	eventId := int64(1)
 	event := createTestEvent(eventId, 5)
 	registeredEvent := createTestRegisteredEvent(100, userId, eventId)
-	expectedError := errors.New("delete failed")
+	expectedError := errors.New("cancel failed")
 
-	mockRepo.EXPECT().GetEventById(eventId).Return(event, nil)
-	mockRepo.EXPECT().GetRegisteredEventById(userId, eventId).Return(registeredEvent, nil)
-	mockRepo.EXPECT().DeleteRegisteredEvent(registeredEvent.Id).Return(expectedError)
+	mockRepo.EXPECT().GetEventById(testOrgId, eventId).Return(event, nil)
+	mockRepo.EXPECT().GetRegisteredEventById(testOrgId, userId, eventId).Return(registeredEvent, nil)
+	mockRepo.EXPECT().GetPaidOrder(testOrgId, registeredEvent.Id).Return(models.Order{}, errors.New("not found"))
+	mockRepo.EXPECT().RecordCancellation(gomock.Any()).Return(false, expectedError)
 
-	err := service.CancelEvent(userId, eventId)
+	_, err := service.CancelEvent(testOrgId, userId, eventId)
 
 	require.Error(t, err)
 	assert.Equal(t, expectedError, err)
 }
+
+func TestRegisterEvent_RequiresApprovalIsPending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	event := createTestEvent(1, 5)
+	event.RequiresApproval = true
+
+	mockRepo.EXPECT().GetUserById(int64(10)).Return(createVerifiedTestUser(10), nil)
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(event, nil)
+	mockRepo.EXPECT().GetRegisteredEventById(testOrgId, int64(10), int64(1)).Return(models.RegisterEvent{}, errors.New("not found"))
+	mockRepo.EXPECT().GetTicketTypes(testOrgId, int64(1)).Return([]models.TicketType{}, nil)
+	mockRepo.EXPECT().GetRegistrationQuestions(testOrgId, int64(1)).Return([]models.RegistrationQuestion{}, nil)
+	mockRepo.EXPECT().RegisterEvent(gomock.Any()).DoAndReturn(func(r *models.RegisterEvent) (bool, error) {
+		assert.Equal(t, RegistrationPending, r.Status)
+		return true, nil
+	})
+
+	registration, err := service.RegisterEvent(testOrgId, 10, 1, 0, "", nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, RegistrationPending, registration.Status)
+}
+
+func TestRegisterEvent_EventFull(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	event := createTestEvent(1, 5)
+	event.Capacity = 1
+
+	mockRepo.EXPECT().GetUserById(int64(10)).Return(createVerifiedTestUser(10), nil)
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(event, nil)
+	mockRepo.EXPECT().GetRegisteredEventById(testOrgId, int64(10), int64(1)).Return(models.RegisterEvent{}, errors.New("not found")).Times(2)
+	mockRepo.EXPECT().GetTicketTypes(testOrgId, int64(1)).Return([]models.TicketType{}, nil)
+	mockRepo.EXPECT().GetRegistrationQuestions(testOrgId, int64(1)).Return([]models.RegistrationQuestion{}, nil)
+	mockRepo.EXPECT().RegisterEvent(gomock.Any()).Return(false, nil)
+
+	_, err := service.RegisterEvent(testOrgId, 10, 1, 0, "", nil)
+
+	assert.Equal(t, ErrEventFull, err)
+}
+
+func TestRegisterEvent_AlreadyRegisteredConcurrently(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	mockRepo.EXPECT().GetUserById(int64(10)).Return(createVerifiedTestUser(10), nil)
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	gomock.InOrder(
+		mockRepo.EXPECT().GetRegisteredEventById(testOrgId, int64(10), int64(1)).Return(models.RegisterEvent{}, errors.New("not found")),
+		mockRepo.EXPECT().GetRegisteredEventById(testOrgId, int64(10), int64(1)).Return(models.RegisterEvent{Id: 100}, nil),
+	)
+	mockRepo.EXPECT().GetTicketTypes(testOrgId, int64(1)).Return([]models.TicketType{}, nil)
+	mockRepo.EXPECT().GetRegistrationQuestions(testOrgId, int64(1)).Return([]models.RegistrationQuestion{}, nil)
+	mockRepo.EXPECT().RegisterEvent(gomock.Any()).Return(false, nil)
+
+	_, err := service.RegisterEvent(testOrgId, 10, 1, 0, "", nil)
+
+	assert.Equal(t, ErrAlreadyRegistered, err)
+}
+
+func TestApproveRegistrations_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	pending := []models.RegisterEvent{
+		{Id: 100, EventId: 1, UserId: 10, Status: RegistrationPending},
+		{Id: 101, EventId: 1, UserId: 11, Status: RegistrationPending},
+	}
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().GetRegistrations(testOrgId, int64(1), "").Return(pending, nil)
+	mockRepo.EXPECT().SetRegistrationStatus(testOrgId, int64(1), []int64{100, 101}, RegistrationApproved).Return(true, nil)
+
+	err := service.ApproveRegistrations(testOrgId, 1, 5, []int64{101, 100, 101})
+
+	require.NoError(t, err)
+}
+
+func TestApproveRegistrations_ExceedsCapacity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	pending := []models.RegisterEvent{{Id: 100, EventId: 1, UserId: 10, Status: RegistrationPending}}
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().GetRegistrations(testOrgId, int64(1), "").Return(pending, nil)
+	mockRepo.EXPECT().SetRegistrationStatus(testOrgId, int64(1), []int64{100}, RegistrationApproved).Return(false, nil)
+
+	err := service.ApproveRegistrations(testOrgId, 1, 5, []int64{100})
+
+	assert.Equal(t, ErrEventFull, err)
+}
+
+func TestRejectRegistrations_RefundsPaidOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	mockProvider := mocks.NewMockPaymentProvider(ctrl)
+	service := NewEventRegisterService(mockRepo, mockProvider)
+
+	pending := []models.RegisterEvent{
+		{Id: 100, EventId: 1, UserId: 10, Status: RegistrationPending},
+		{Id: 101, EventId: 1, UserId: 11, Status: RegistrationPending},
+	}
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().GetRegistrations(testOrgId, int64(1), "").Return(pending, nil)
+	mockRepo.EXPECT().SetRegistrationStatus(testOrgId, int64(1), []int64{100, 101}, RegistrationRejected).Return(true, nil)
+	mockRepo.EXPECT().GetPaidOrder(testOrgId, int64(100)).Return(createTestPaidOrder(), nil)
+	mockRepo.EXPECT().GetPaidOrder(testOrgId, int64(101)).Return(models.Order{}, errors.New("no rows"))
+	mockRepo.EXPECT().RecordRefund(gomock.Any()).DoAndReturn(func(c *models.Cancellation) error {
+		assert.Equal(t, int64(100), c.RegistrationId)
+		assert.Equal(t, int64(7), c.OrderId)
+		assert.Equal(t, int64(2500), c.RefundAmount, "Rejected registrations are refunded in full")
+		assert.Equal(t, RefundPending, c.RefundStatus)
+		c.Id = 30
+		return nil
+	})
+	mockProvider.EXPECT().Refund("pi_fake_1", int64(2500)).Return(nil)
+	mockRepo.EXPECT().SetRefundStatus(gomock.Any()).DoAndReturn(func(c models.Cancellation) error {
+		assert.Equal(t, int64(30), c.Id)
+		assert.Equal(t, RefundSucceeded, c.RefundStatus)
+		return nil
+	})
+
+	err := service.RejectRegistrations(testOrgId, 1, 5, []int64{100, 101})
+
+	require.NoError(t, err)
+}
+
+func TestRejectRegistrations_RefundFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	mockProvider := mocks.NewMockPaymentProvider(ctrl)
+	service := NewEventRegisterService(mockRepo, mockProvider)
+
+	pending := []models.RegisterEvent{{Id: 100, EventId: 1, UserId: 10, Status: RegistrationPending}}
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().GetRegistrations(testOrgId, int64(1), "").Return(pending, nil)
+	mockRepo.EXPECT().SetRegistrationStatus(testOrgId, int64(1), []int64{100}, RegistrationRejected).Return(true, nil)
+	mockRepo.EXPECT().GetPaidOrder(testOrgId, int64(100)).Return(createTestPaidOrder(), nil)
+	mockRepo.EXPECT().RecordRefund(gomock.Any()).Return(nil)
+	mockProvider.EXPECT().Refund("pi_fake_1", int64(2500)).Return(errors.New("provider down"))
+	mockRepo.EXPECT().SetRefundStatus(gomock.Any()).DoAndReturn(func(c models.Cancellation) error {
+		assert.Equal(t, RefundFailed, c.RefundStatus)
+		return nil
+	})
+
+	err := service.RejectRegistrations(testOrgId, 1, 5, []int64{100})
+
+	assert.Equal(t, ErrRejectionRefundFailed, err)
+}
+
+func TestRejectRegistrations_NotPending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	registrations := []models.RegisterEvent{{Id: 100, EventId: 1, UserId: 10, Status: RegistrationApproved}}
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().GetRegistrations(testOrgId, int64(1), "").Return(registrations, nil)
+
+	err := service.RejectRegistrations(testOrgId, 1, 5, []int64{100})
+	assert.Equal(t, ErrRegistrationNotPending, err)
+}
+
+func TestRejectRegistrations_CheckInStaffForbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().GetEventMember(testOrgId, int64(1), int64(20)).Return(models.EventMember{Role: EventRoleCheckInStaff}, nil)
+
+	err := service.RejectRegistrations(testOrgId, 1, 20, []int64{100})
+
+	assert.Equal(t, ErrForbidden, err)
+}
+
+func TestRegistrations_InvalidStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewEventRegisterService(mocks.NewMockRegisterRepository(ctrl), mocks.NewMockPaymentProvider(ctrl))
+
+	_, err := service.Registrations(testOrgId, 1, 5, "waiting")
+
+	assert.Equal(t, ErrInvalidRegistrationStatus, err)
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"errors"
+	"event-booking/models"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+var ErrTicketTypeNotFound = errors.New("Ticket type not found")
+var ErrInvalidTicketType = errors.New("Ticket type is invalid")
+var ErrTicketTypeInUse = errors.New("Ticket type has registrations and cannot be deleted")
+var ErrTicketTypeRequired = errors.New("Choose a ticket type to register for this event")
+var ErrTicketTypeSoldOut = errors.New("Ticket type is sold out")
+var ErrTicketNotOnSale = errors.New("Ticket type is not on sale")
+
+// TicketTypes returns the ticket types of an event the caller may already
+// see, with how many tickets are left of each.
+func (s *EventService) TicketTypes(event models.Event) ([]models.TicketAvailability, error) {
+	ticketTypes, err := s.repo.GetTicketTypes(event.OrganizationId, event.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	availability := []models.TicketAvailability{}
+	for _, t := range ticketTypes {
+		availability = append(availability, ticketAvailability(t, now))
+	}
+	return availability, nil
+}
+
+func ticketAvailability(t models.TicketType, now time.Time) models.TicketAvailability {
+	available := max(t.Quantity-t.Sold, 0)
+	return models.TicketAvailability{
+		TicketType: t,
+		Available:  available,
+		OnSale:     available > 0 && ticketOnSale(t, now),
+	}
+}
+
+// ticketOnSale reports whether now is inside the sale window of t.
+func ticketOnSale(t models.TicketType, now time.Time) bool {
+	if t.SalesStart != nil && now.Before(*t.SalesStart) {
+		return false
+	}
+	if t.SalesEnd != nil && !now.Before(*t.SalesEnd) {
+		return false
+	}
+	return true
+}
+
+// CreateTicketType adds a ticket type to an event. The owner and
+// co-organizers can manage ticket types.
+func (s *EventService) CreateTicketType(orgId, eventId, userId int64, t *models.TicketType) error {
+	_, err := s.authorizeEvent(orgId, eventId, userId, EventRoleOwner, EventRoleCoOrganizer)
+	if err != nil {
+		return err
+	}
+
+	err = normalizeTicketType(t)
+	if err != nil {
+		return err
+	}
+
+	t.Id = 0
+	t.EventId = eventId
+	t.OrganizationId = orgId
+	t.Sold = 0
+	return s.repo.CreateTicketType(t)
+}
+
+// UpdateTicketType changes a ticket type. Its quantity cannot drop below the
+// tickets already sold.
+func (s *EventService) UpdateTicketType(orgId, eventId, userId int64, t *models.TicketType) error {
+	_, err := s.authorizeEvent(orgId, eventId, userId, EventRoleOwner, EventRoleCoOrganizer)
+	if err != nil {
+		return err
+	}
+
+	existing, err := s.repo.GetTicketType(orgId, eventId, t.Id)
+	if err != nil {
+		return ErrTicketTypeNotFound
+	}
+
+	err = normalizeTicketType(t)
+	if err != nil {
+		return err
+	}
+	if t.Quantity < existing.Sold {
+		return fmt.Errorf("%w: %d tickets are already sold", ErrInvalidTicketType, existing.Sold)
+	}
+
+	t.EventId = eventId
+	t.OrganizationId = orgId
+	t.Sold = existing.Sold
+	return s.repo.UpdateTicketType(t)
+}
+
+// DeleteTicketType deletes a ticket type nobody registered with.
+func (s *EventService) DeleteTicketType(orgId, eventId, userId, id int64) error {
+	_, err := s.authorizeEvent(orgId, eventId, userId, EventRoleOwner, EventRoleCoOrganizer)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.repo.GetTicketType(orgId, eventId, id)
+	if err != nil {
+		return ErrTicketTypeNotFound
+	}
+
+	deleted, err := s.repo.DeleteTicketType(orgId, eventId, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrTicketTypeInUse
+	}
+	return nil
+}
+
+func normalizeTicketType(t *models.TicketType) error {
+	t.Name = strings.TrimSpace(t.Name)
+	t.Currency = strings.ToUpper(t.Currency)
+	if t.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidTicketType)
+	}
+	if t.SalesStart != nil && t.SalesEnd != nil && !t.SalesEnd.After(*t.SalesStart) {
+		return fmt.Errorf("%w: sales must end after they start", ErrInvalidTicketType)
+	}
+	return nil
+}
+
+// chooseTicketType returns the ticket type a registration for the event
+// uses. Events with ticket types require one that is on sale; events
+// without take registrations without a ticket type, returned as the zero
+// TicketType.
+func chooseTicketType(ticketTypes []models.TicketType, ticketTypeId int64, now time.Time) (models.TicketType, error) {
+	if len(ticketTypes) == 0 {
+		if ticketTypeId != 0 {
+			return models.TicketType{}, ErrTicketTypeNotFound
+		}
+		return models.TicketType{}, nil
+	}
+	if ticketTypeId == 0 {
+		return models.TicketType{}, ErrTicketTypeRequired
+	}
+
+	i := slices.IndexFunc(ticketTypes, func(t models.TicketType) bool { return t.Id == ticketTypeId })
+	if i < 0 {
+		return models.TicketType{}, ErrTicketTypeNotFound
+	}
+
+	availability := ticketAvailability(ticketTypes[i], now)
+	if availability.Available == 0 {
+		return models.TicketType{}, ErrTicketTypeSoldOut
+	}
+	if !availability.OnSale {
+		return models.TicketType{}, ErrTicketNotOnSale
+	}
+	return ticketTypes[i], nil
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"event-booking/models"
+	"event-booking/services/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func createTestTicketTypes() []models.TicketType {
+	return []models.TicketType{
+		{Id: 1, EventId: 1, Name: "Early bird", Price: 1500, Currency: "EUR", Quantity: 10, Sold: 10},
+		{Id: 2, EventId: 1, Name: "Regular", Price: 2500, Currency: "EUR", Quantity: 100, Sold: 40},
+	}
+}
+
+func TestTicketTypes_Availability(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepository(ctrl)
+	service := NewEventService(mockRepo)
+
+	ticketTypes := createTestTicketTypes()
+	salesStart := time.Now().Add(time.Hour)
+	ticketTypes = append(ticketTypes, models.TicketType{Id: 3, Name: "VIP", Price: 9900, Currency: "EUR", Quantity: 5, SalesStart: &salesStart})
+
+	event := createTestEvent(1, 10)
+	mockRepo.EXPECT().GetTicketTypes(testOrgId, int64(1)).Return(ticketTypes, nil)
+
+	availability, err := service.TicketTypes(event)
+
+	require.NoError(t, err)
+	require.Len(t, availability, 3)
+	assert.Equal(t, int64(0), availability[0].Available)
+	assert.False(t, availability[0].OnSale, "Sold out ticket types are not on sale")
+	assert.Equal(t, int64(60), availability[1].Available)
+	assert.True(t, availability[1].OnSale)
+	assert.Equal(t, int64(5), availability[2].Available)
+	assert.False(t, availability[2].OnSale, "Sales have not started yet")
+}
+
+func TestCreateTicketType_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepository(ctrl)
+	service := NewEventService(mockRepo)
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 10), nil)
+	mockRepo.EXPECT().CreateTicketType(gomock.Any()).DoAndReturn(func(t *models.TicketType) error {
+		t.Id = 7
+		return nil
+	})
+
+	ticketType := models.TicketType{Id: 99, Name: " Regular ", Price: 2500, Currency: "eur", Quantity: 100, Sold: 3}
+	err := service.CreateTicketType(testOrgId, 1, 10, &ticketType)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), ticketType.Id)
+	assert.Equal(t, "Regular", ticketType.Name)
+	assert.Equal(t, "EUR", ticketType.Currency)
+	assert.Equal(t, int64(1), ticketType.EventId)
+	assert.Equal(t, int64(0), ticketType.Sold)
+}
+
+func TestCreateTicketType_SalesEndBeforeStart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepository(ctrl)
+	service := NewEventService(mockRepo)
+
+	salesStart := time.Now().Add(24 * time.Hour)
+	salesEnd := salesStart.Add(-time.Hour)
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 10), nil)
+
+	err := service.CreateTicketType(testOrgId, 1, 10, &models.TicketType{
+		Name: "Regular", Currency: "EUR", Quantity: 10, SalesStart: &salesStart, SalesEnd: &salesEnd,
+	})
+
+	assert.ErrorIs(t, err, ErrInvalidTicketType)
+}
+
+func TestCreateTicketType_CheckInStaffForbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepository(ctrl)
+	service := NewEventService(mockRepo)
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 10), nil)
+	mockRepo.EXPECT().GetEventMember(testOrgId, int64(1), int64(20)).Return(models.EventMember{Role: EventRoleCheckInStaff}, nil)
+
+	err := service.CreateTicketType(testOrgId, 1, 20, &models.TicketType{Name: "Regular", Currency: "EUR", Quantity: 10})
+
+	assert.Equal(t, ErrForbidden, err)
+}
+
+func TestUpdateTicketType_QuantityBelowSold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepository(ctrl)
+	service := NewEventService(mockRepo)
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 10), nil)
+	mockRepo.EXPECT().GetTicketType(testOrgId, int64(1), int64(2)).Return(createTestTicketTypes()[1], nil)
+
+	err := service.UpdateTicketType(testOrgId, 1, 10, &models.TicketType{Id: 2, Name: "Regular", Currency: "EUR", Quantity: 39})
+
+	assert.ErrorIs(t, err, ErrInvalidTicketType)
+}
+
+func TestUpdateTicketType_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepository(ctrl)
+	service := NewEventService(mockRepo)
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 10), nil)
+	mockRepo.EXPECT().GetTicketType(testOrgId, int64(1), int64(9)).Return(models.TicketType{}, errors.New("no rows"))
+
+	err := service.UpdateTicketType(testOrgId, 1, 10, &models.TicketType{Id: 9, Name: "Regular", Currency: "EUR", Quantity: 10})
+
+	assert.Equal(t, ErrTicketTypeNotFound, err)
+}
+
+func TestDeleteTicketType_InUse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockEventRepository(ctrl)
+	service := NewEventService(mockRepo)
+
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 10), nil)
+	mockRepo.EXPECT().GetTicketType(testOrgId, int64(1), int64(2)).Return(createTestTicketTypes()[1], nil)
+	mockRepo.EXPECT().DeleteTicketType(testOrgId, int64(1), int64(2)).Return(false, nil)
+
+	err := service.DeleteTicketType(testOrgId, 1, 10, 2)
+
+	assert.Equal(t, ErrTicketTypeInUse, err)
+}
+
+func TestChooseTicketType(t *testing.T) {
+	now := time.Now()
+	salesEnd := now.Add(-time.Minute)
+	ended := models.TicketType{Id: 3, Quantity: 10, SalesEnd: &salesEnd}
+
+	tests := map[string]struct {
+		ticketTypes  []models.TicketType
+		ticketTypeId int64
+		want         int64
+		err          error
+	}{
+		"no ticket types":         {nil, 0, 0, nil},
+		"ticket type of no event": {nil, 2, 0, ErrTicketTypeNotFound},
+		"ticket type missing":     {createTestTicketTypes(), 0, 0, ErrTicketTypeRequired},
+		"unknown ticket type":     {createTestTicketTypes(), 9, 0, ErrTicketTypeNotFound},
+		"sold out":                {createTestTicketTypes(), 1, 0, ErrTicketTypeSoldOut},
+		"sales ended":             {append(createTestTicketTypes(), ended), 3, 0, ErrTicketNotOnSale},
+		"available":               {createTestTicketTypes(), 2, 2, nil},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := chooseTicketType(test.ticketTypes, test.ticketTypeId, now)
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.want, got.Id)
+		})
+	}
+}
+
+func TestRegisterEvent_WithTicketType(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	mockRepo.EXPECT().GetUserById(int64(10)).Return(createVerifiedTestUser(10), nil)
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().GetRegisteredEventById(testOrgId, int64(10), int64(1)).Return(models.RegisterEvent{}, errors.New("not found"))
+	mockRepo.EXPECT().GetTicketTypes(testOrgId, int64(1)).Return(createTestTicketTypes(), nil)
+	mockRepo.EXPECT().GetRegistrationQuestions(testOrgId, int64(1)).Return([]models.RegistrationQuestion{}, nil)
+	mockRepo.EXPECT().RegisterEvent(gomock.Any()).DoAndReturn(func(r *models.RegisterEvent) (bool, error) {
+		assert.Equal(t, int64(2), r.TicketTypeId)
+		return true, nil
+	})
+
+	registration, err := service.RegisterEvent(testOrgId, 10, 1, 2, "", nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), registration.TicketTypeId)
+	assert.Equal(t, RegistrationAwaitingPayment, registration.Status, "Paid tickets are confirmed by their payment")
+}
+
+func TestRegisterEvent_TicketTypeSoldOutConcurrently(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRegisterRepository(ctrl)
+	service := NewEventRegisterService(mockRepo, mocks.NewMockPaymentProvider(ctrl))
+
+	mockRepo.EXPECT().GetUserById(int64(10)).Return(createVerifiedTestUser(10), nil)
+	mockRepo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	mockRepo.EXPECT().GetRegisteredEventById(testOrgId, int64(10), int64(1)).Return(models.RegisterEvent{}, errors.New("not found")).Times(2)
+	mockRepo.EXPECT().GetTicketTypes(testOrgId, int64(1)).Return(createTestTicketTypes(), nil)
+	mockRepo.EXPECT().GetRegistrationQuestions(testOrgId, int64(1)).Return([]models.RegistrationQuestion{}, nil)
+	mockRepo.EXPECT().RegisterEvent(gomock.Any()).Return(false, nil)
+
+	_, err := service.RegisterEvent(testOrgId, 10, 1, 2, "", nil)
+
+	assert.Equal(t, ErrTicketTypeSoldOut, err)
+}
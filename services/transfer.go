@@ -0,0 +1,274 @@
+package services
+
+import (
+	"errors"
+	"event-booking/models"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+type RegistrationTransferRepository interface {
+	GetEventById(int64, int64) (models.Event, error)
+	CanAccessEvent(int64, int64, int64) (bool, error)
+	GetRegistration(int64, int64) (models.RegisterEvent, error)
+	GetRegisteredEventById(int64, int64, int64) (models.RegisterEvent, error)
+	GetUserById(int64) (models.User, error)
+	GetUserByEmail(string) (models.User, error)
+	GetMembership(int64, int64) (models.Membership, error)
+	CreateTransfer(*models.RegistrationTransfer) (bool, error)
+	GetTransfer(int64, int64) (models.RegistrationTransfer, error)
+	GetPendingTransfer(int64, int64) (models.RegistrationTransfer, error)
+	GetIncomingTransfers(int64, int64) ([]models.RegistrationTransfer, error)
+	SetTransferStatus(int64, int64, string, time.Time) (bool, error)
+	AcceptTransfer(models.RegistrationTransfer, time.Time) (bool, error)
+}
+
+// Transfer statuses. A transfer is pending until the recipient accepts or
+// declines it, or the sender cancels it.
+const (
+	TransferPending   = "pending"
+	TransferAccepted  = "accepted"
+	TransferDeclined  = "declined"
+	TransferCancelled = "cancelled"
+)
+
+var ErrTransferNotFound = errors.New("Transfer not found")
+var ErrTransferNotPending = errors.New("Transfer is no longer pending")
+var ErrTransferPending = errors.New("The registration already has a pending transfer")
+var ErrRegistrationNotTransferable = errors.New("Only approved registrations for upcoming events that were not checked in can be transferred")
+var ErrTransferRecipientNotFound = errors.New("Recipient must be a member of the organization")
+var ErrTransferToSelf = errors.New("You cannot transfer a registration to yourself")
+var ErrRecipientAlreadyRegistered = errors.New("Recipient is already registered for this event")
+
+// RegistrationTransferService lets attendees hand their registration to
+// another member of the organization, who has to accept it. Every step is
+// recorded in the audit trail.
+type RegistrationTransferService struct {
+	repo   RegistrationTransferRepository
+	audit  *AuditService
+	mailer Mailer
+	appURL string
+	now    func() time.Time
+}
+
+func NewRegistrationTransferService(repo RegistrationTransferRepository, audit *AuditService, mailer Mailer, appURL string) *RegistrationTransferService {
+	return &RegistrationTransferService{
+		repo:   repo,
+		audit:  audit,
+		mailer: mailer,
+		appURL: strings.TrimSuffix(appURL, "/"),
+		now:    time.Now,
+	}
+}
+
+// Transfer offers the registration of userId to the member of the
+// organization with email. The registration keeps its seat and stays with
+// userId until the recipient accepts. The recipient is notified by email.
+func (s *RegistrationTransferService) Transfer(orgId, userId, registrationId int64, email string) (models.RegistrationTransfer, error) {
+	registration, event, err := s.transferable(orgId, registrationId, userId)
+	if err != nil {
+		return models.RegistrationTransfer{}, err
+	}
+
+	recipient, err := s.repo.GetUserByEmail(strings.TrimSpace(email))
+	if err != nil {
+		return models.RegistrationTransfer{}, ErrTransferRecipientNotFound
+	}
+	if recipient.Id == userId {
+		return models.RegistrationTransfer{}, ErrTransferToSelf
+	}
+	_, err = s.repo.GetMembership(orgId, recipient.Id)
+	if err != nil {
+		return models.RegistrationTransfer{}, ErrTransferRecipientNotFound
+	}
+	err = checkEventVisible(s.repo, event, recipient.Id)
+	if errors.Is(err, ErrEventNotFound) {
+		return models.RegistrationTransfer{}, ErrTransferRecipientNotFound
+	}
+	if err != nil {
+		return models.RegistrationTransfer{}, err
+	}
+	_, err = s.repo.GetRegisteredEventById(orgId, recipient.Id, event.Id)
+	if err == nil {
+		return models.RegistrationTransfer{}, ErrRecipientAlreadyRegistered
+	}
+
+	transfer := models.RegistrationTransfer{
+		RegistrationId: registration.Id,
+		EventId:        event.Id,
+		OrganizationId: orgId,
+		FromUserId:     userId,
+		ToUserId:       recipient.Id,
+		ToEmail:        recipient.Email,
+		Status:         TransferPending,
+		CreatedAt:      s.now(),
+	}
+	created, err := s.repo.CreateTransfer(&transfer)
+	if err != nil {
+		return models.RegistrationTransfer{}, err
+	}
+	if !created {
+		return models.RegistrationTransfer{}, ErrTransferPending
+	}
+	s.record(AuditTransferRequested, userId, transfer)
+
+	subject := "A seat at " + event.Name + " is waiting for you"
+	body := fmt.Sprintf("A colleague wants to hand you their registration for %s on %s.\n\n"+
+		"Accept or decline it at %s/me/transfers.",
+		event.Name, event.DateTime.UTC().Format(time.RFC1123), s.appURL)
+	err = s.mailer.Send(recipient.Email, subject, body)
+	if err != nil {
+		log.Printf("sending transfer %d to recipient failed: %v", transfer.Id, err)
+	}
+	return transfer, nil
+}
+
+// CancelTransfer withdraws the pending transfer of a registration of
+// userId.
+func (s *RegistrationTransferService) CancelTransfer(orgId, userId, registrationId int64) error {
+	registration, err := s.repo.GetRegistration(orgId, registrationId)
+	if err != nil || registration.UserId != userId {
+		return ErrRegisterEventNotFound
+	}
+
+	transfer, err := s.repo.GetPendingTransfer(orgId, registrationId)
+	if err != nil {
+		return ErrTransferNotFound
+	}
+	cancelled, err := s.repo.SetTransferStatus(orgId, transfer.Id, TransferCancelled, s.now())
+	if err != nil {
+		return err
+	}
+	if !cancelled {
+		return ErrTransferNotPending
+	}
+	s.record(AuditTransferCancelled, userId, transfer)
+	return nil
+}
+
+// IncomingTransfers lists the pending transfers offered to userId.
+func (s *RegistrationTransferService) IncomingTransfers(orgId, userId int64) ([]models.RegistrationTransfer, error) {
+	return s.repo.GetIncomingTransfers(orgId, userId)
+}
+
+// Accept makes userId the holder of the registration of a transfer offered
+// to them. Like registering, accepting needs a verified email address. The
+// sender's ticket stops working; the recipient gets a new one.
+func (s *RegistrationTransferService) Accept(orgId, userId, transferId int64) (models.RegisterEvent, error) {
+	transfer, err := s.incoming(orgId, userId, transferId)
+	if err != nil {
+		return models.RegisterEvent{}, err
+	}
+
+	user, err := s.repo.GetUserById(userId)
+	if err != nil {
+		return models.RegisterEvent{}, err
+	}
+	if !user.Verified {
+		return models.RegisterEvent{}, ErrEmailNotVerified
+	}
+
+	now := s.now()
+	_, _, err = s.transferable(orgId, transfer.RegistrationId, transfer.FromUserId)
+	if errors.Is(err, ErrRegistrationNotTransferable) || errors.Is(err, ErrRegisterEventNotFound) {
+		s.end(transfer, now)
+		return models.RegisterEvent{}, ErrRegistrationNotTransferable
+	}
+	if err != nil {
+		return models.RegisterEvent{}, err
+	}
+
+	accepted, err := s.repo.AcceptTransfer(transfer, now)
+	if err != nil {
+		return models.RegisterEvent{}, err
+	}
+	if !accepted {
+		_, err = s.repo.GetRegisteredEventById(orgId, userId, transfer.EventId)
+		if err == nil {
+			return models.RegisterEvent{}, ErrAlreadyRegistered
+		}
+		current, err := s.repo.GetTransfer(orgId, transfer.Id)
+		if err == nil && current.Status != TransferPending {
+			return models.RegisterEvent{}, ErrTransferNotPending
+		}
+		s.end(transfer, now)
+		return models.RegisterEvent{}, ErrRegistrationNotTransferable
+	}
+	s.record(AuditTransferAccepted, userId, transfer)
+
+	return s.repo.GetRegistration(orgId, transfer.RegistrationId)
+}
+
+// Decline turns down a transfer offered to userId. The registration stays
+// with the sender.
+func (s *RegistrationTransferService) Decline(orgId, userId, transferId int64) error {
+	transfer, err := s.incoming(orgId, userId, transferId)
+	if err != nil {
+		return err
+	}
+
+	declined, err := s.repo.SetTransferStatus(orgId, transfer.Id, TransferDeclined, s.now())
+	if err != nil {
+		return err
+	}
+	if !declined {
+		return ErrTransferNotPending
+	}
+	s.record(AuditTransferDeclined, userId, transfer)
+	return nil
+}
+
+// transferable returns the registration of userId and its event when the
+// registration can still change hands.
+func (s *RegistrationTransferService) transferable(orgId, registrationId, userId int64) (models.RegisterEvent, models.Event, error) {
+	registration, err := s.repo.GetRegistration(orgId, registrationId)
+	if err != nil || registration.UserId != userId {
+		return models.RegisterEvent{}, models.Event{}, ErrRegisterEventNotFound
+	}
+
+	event, err := s.repo.GetEventById(orgId, registration.EventId)
+	if err != nil {
+		return models.RegisterEvent{}, models.Event{}, ErrEventNotFound
+	}
+
+	if registration.Status != RegistrationApproved || registration.CheckedInAt != nil || !s.now().Before(event.DateTime) {
+		return models.RegisterEvent{}, models.Event{}, ErrRegistrationNotTransferable
+	}
+	return registration, event, nil
+}
+
+// incoming returns the pending transfer with id offered to userId.
+func (s *RegistrationTransferService) incoming(orgId, userId, id int64) (models.RegistrationTransfer, error) {
+	transfer, err := s.repo.GetTransfer(orgId, id)
+	if err != nil || transfer.ToUserId != userId {
+		return models.RegistrationTransfer{}, ErrTransferNotFound
+	}
+	if transfer.Status != TransferPending {
+		return models.RegistrationTransfer{}, ErrTransferNotPending
+	}
+	return transfer, nil
+}
+
+// end cancels a transfer whose registration can no longer change hands.
+func (s *RegistrationTransferService) end(transfer models.RegistrationTransfer, now time.Time) {
+	_, err := s.repo.SetTransferStatus(transfer.OrganizationId, transfer.Id, TransferCancelled, now)
+	if err != nil {
+		log.Printf("could not cancel transfer %d: %v", transfer.Id, err)
+	}
+}
+
+func (s *RegistrationTransferService) record(action string, userId int64, transfer models.RegistrationTransfer) {
+	err := s.audit.Record(&models.AuditEvent{
+		Action:  action,
+		UserId:  userId,
+		Subject: fmt.Sprintf("registration:%d", transfer.RegistrationId),
+		Details: fmt.Sprintf("transfer=%d event=%d from=%d to=%d",
+			transfer.Id, transfer.EventId, transfer.FromUserId, transfer.ToUserId),
+		CreatedAt: s.now(),
+	})
+	if err != nil {
+		log.Printf("could not record transfer %d in audit trail: %v", transfer.Id, err)
+	}
+}
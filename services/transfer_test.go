@@ -0,0 +1,274 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"event-booking/models"
+	"event-booking/services/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type transferTestDeps struct {
+	repo   *mocks.MockRegistrationTransferRepository
+	audit  *mocks.MockAuditRepository
+	mailer *mocks.MockMailer
+}
+
+func newTestTransferService(ctrl *gomock.Controller) (*RegistrationTransferService, transferTestDeps) {
+	deps := transferTestDeps{
+		repo:   mocks.NewMockRegistrationTransferRepository(ctrl),
+		audit:  mocks.NewMockAuditRepository(ctrl),
+		mailer: mocks.NewMockMailer(ctrl),
+	}
+	service := NewRegistrationTransferService(deps.repo, NewAuditService(deps.audit), deps.mailer, "https://app.example.com/")
+	return service, deps
+}
+
+func createTestTransfer() models.RegistrationTransfer {
+	return models.RegistrationTransfer{
+		Id: 3, RegistrationId: 100, EventId: 1, OrganizationId: testOrgId, FromUserId: 10, ToUserId: 11,
+		Status: TransferPending,
+	}
+}
+
+func TestTransfer_OffersRegistrationToRecipient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, deps := newTestTransferService(ctrl)
+	recipient := models.User{Id: 11, Email: "colleague@example.com", Verified: true}
+
+	deps.repo.EXPECT().GetRegistration(testOrgId, int64(100)).Return(createTestApprovedRegistration(), nil)
+	deps.repo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	deps.repo.EXPECT().GetUserByEmail("colleague@example.com").Return(recipient, nil)
+	deps.repo.EXPECT().GetMembership(testOrgId, int64(11)).Return(createTestMembership(testOrgId, RoleMember), nil)
+	deps.repo.EXPECT().GetRegisteredEventById(testOrgId, int64(11), int64(1)).Return(models.RegisterEvent{}, errors.New("no rows"))
+	deps.repo.EXPECT().CreateTransfer(gomock.Any()).DoAndReturn(func(transfer *models.RegistrationTransfer) (bool, error) {
+		assert.Equal(t, int64(100), transfer.RegistrationId)
+		assert.Equal(t, int64(10), transfer.FromUserId)
+		assert.Equal(t, int64(11), transfer.ToUserId)
+		assert.Equal(t, TransferPending, transfer.Status)
+		transfer.Id = 3
+		return true, nil
+	})
+	deps.audit.EXPECT().CreateAuditEvent(gomock.Any()).DoAndReturn(func(e *models.AuditEvent) error {
+		assert.Equal(t, AuditTransferRequested, e.Action)
+		assert.Equal(t, int64(10), e.UserId)
+		assert.Equal(t, "registration:100", e.Subject)
+		assert.Equal(t, "transfer=3 event=1 from=10 to=11", e.Details)
+		return nil
+	})
+	deps.mailer.EXPECT().Send("colleague@example.com", gomock.Any(), gomock.Any()).DoAndReturn(func(to, subject, body string) error {
+		assert.Contains(t, body, "https://app.example.com/me/transfers")
+		return nil
+	})
+
+	transfer, err := service.Transfer(testOrgId, 10, 100, " colleague@example.com ")
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), transfer.Id)
+}
+
+func TestTransfer_OnlyOwnRegistrations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, deps := newTestTransferService(ctrl)
+
+	deps.repo.EXPECT().GetRegistration(testOrgId, int64(100)).Return(createTestApprovedRegistration(), nil)
+
+	_, err := service.Transfer(testOrgId, 12, 100, "colleague@example.com")
+
+	assert.Equal(t, ErrRegisterEventNotFound, err)
+}
+
+func TestTransfer_NotTransferable(t *testing.T) {
+	checkedIn := time.Now()
+	tests := []struct {
+		name   string
+		modify func(*models.RegisterEvent, *models.Event)
+	}{
+		{"pending", func(r *models.RegisterEvent, e *models.Event) { r.Status = RegistrationPending }},
+		{"checked in", func(r *models.RegisterEvent, e *models.Event) { r.CheckedInAt = &checkedIn }},
+		{"started", func(r *models.RegisterEvent, e *models.Event) { e.DateTime = time.Now().Add(-time.Hour) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service, deps := newTestTransferService(ctrl)
+			registration := createTestApprovedRegistration()
+			event := createTestEvent(1, 5)
+			tt.modify(&registration, &event)
+
+			deps.repo.EXPECT().GetRegistration(testOrgId, int64(100)).Return(registration, nil)
+			deps.repo.EXPECT().GetEventById(testOrgId, int64(1)).Return(event, nil)
+
+			_, err := service.Transfer(testOrgId, 10, 100, "colleague@example.com")
+
+			assert.Equal(t, ErrRegistrationNotTransferable, err)
+		})
+	}
+}
+
+func TestTransfer_RecipientOutsideOrganization(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, deps := newTestTransferService(ctrl)
+
+	deps.repo.EXPECT().GetRegistration(testOrgId, int64(100)).Return(createTestApprovedRegistration(), nil)
+	deps.repo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	deps.repo.EXPECT().GetUserByEmail("outsider@example.com").Return(models.User{Id: 11}, nil)
+	deps.repo.EXPECT().GetMembership(testOrgId, int64(11)).Return(models.Membership{}, errors.New("no rows"))
+
+	_, err := service.Transfer(testOrgId, 10, 100, "outsider@example.com")
+
+	assert.Equal(t, ErrTransferRecipientNotFound, err)
+}
+
+func TestTransfer_AlreadyPending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, deps := newTestTransferService(ctrl)
+
+	deps.repo.EXPECT().GetRegistration(testOrgId, int64(100)).Return(createTestApprovedRegistration(), nil)
+	deps.repo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	deps.repo.EXPECT().GetUserByEmail("colleague@example.com").Return(models.User{Id: 11}, nil)
+	deps.repo.EXPECT().GetMembership(testOrgId, int64(11)).Return(createTestMembership(testOrgId, RoleMember), nil)
+	deps.repo.EXPECT().GetRegisteredEventById(testOrgId, int64(11), int64(1)).Return(models.RegisterEvent{}, errors.New("no rows"))
+	deps.repo.EXPECT().CreateTransfer(gomock.Any()).Return(false, nil)
+
+	_, err := service.Transfer(testOrgId, 10, 100, "colleague@example.com")
+
+	assert.Equal(t, ErrTransferPending, err)
+}
+
+func TestAcceptTransfer_HandsOverRegistration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, deps := newTestTransferService(ctrl)
+	transferred := createTestApprovedRegistration()
+	transferred.UserId = 11
+
+	deps.repo.EXPECT().GetTransfer(testOrgId, int64(3)).Return(createTestTransfer(), nil)
+	deps.repo.EXPECT().GetUserById(int64(11)).Return(createVerifiedTestUser(11), nil)
+	deps.repo.EXPECT().GetRegistration(testOrgId, int64(100)).Return(createTestApprovedRegistration(), nil)
+	deps.repo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	deps.repo.EXPECT().AcceptTransfer(createTestTransfer(), gomock.Any()).Return(true, nil)
+	deps.audit.EXPECT().CreateAuditEvent(gomock.Any()).DoAndReturn(func(e *models.AuditEvent) error {
+		assert.Equal(t, AuditTransferAccepted, e.Action)
+		assert.Equal(t, int64(11), e.UserId)
+		return nil
+	})
+	deps.repo.EXPECT().GetRegistration(testOrgId, int64(100)).Return(transferred, nil)
+
+	registration, err := service.Accept(testOrgId, 11, 3)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), registration.UserId)
+}
+
+func TestAcceptTransfer_OnlyRecipient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, deps := newTestTransferService(ctrl)
+
+	deps.repo.EXPECT().GetTransfer(testOrgId, int64(3)).Return(createTestTransfer(), nil)
+
+	_, err := service.Accept(testOrgId, 12, 3)
+
+	assert.Equal(t, ErrTransferNotFound, err)
+}
+
+func TestAcceptTransfer_RecipientRegisteredMeanwhile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, deps := newTestTransferService(ctrl)
+
+	deps.repo.EXPECT().GetTransfer(testOrgId, int64(3)).Return(createTestTransfer(), nil)
+	deps.repo.EXPECT().GetUserById(int64(11)).Return(createVerifiedTestUser(11), nil)
+	deps.repo.EXPECT().GetRegistration(testOrgId, int64(100)).Return(createTestApprovedRegistration(), nil)
+	deps.repo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	deps.repo.EXPECT().AcceptTransfer(createTestTransfer(), gomock.Any()).Return(false, nil)
+	deps.repo.EXPECT().GetRegisteredEventById(testOrgId, int64(11), int64(1)).Return(createTestRegisteredEvent(101, 11, 1), nil)
+
+	_, err := service.Accept(testOrgId, 11, 3)
+
+	assert.Equal(t, ErrAlreadyRegistered, err)
+}
+
+func TestAcceptTransfer_CancelledRegistrationEndsTransfer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, deps := newTestTransferService(ctrl)
+	cancelled := createTestApprovedRegistration()
+	cancelled.Status = RegistrationCancelled
+
+	deps.repo.EXPECT().GetTransfer(testOrgId, int64(3)).Return(createTestTransfer(), nil)
+	deps.repo.EXPECT().GetUserById(int64(11)).Return(createVerifiedTestUser(11), nil)
+	deps.repo.EXPECT().GetRegistration(testOrgId, int64(100)).Return(cancelled, nil)
+	deps.repo.EXPECT().GetEventById(testOrgId, int64(1)).Return(createTestEvent(1, 5), nil)
+	deps.repo.EXPECT().SetTransferStatus(testOrgId, int64(3), TransferCancelled, gomock.Any()).Return(true, nil)
+
+	_, err := service.Accept(testOrgId, 11, 3)
+
+	assert.Equal(t, ErrRegistrationNotTransferable, err)
+}
+
+func TestAcceptTransfer_EmailNotVerified(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, deps := newTestTransferService(ctrl)
+
+	deps.repo.EXPECT().GetTransfer(testOrgId, int64(3)).Return(createTestTransfer(), nil)
+	deps.repo.EXPECT().GetUserById(int64(11)).Return(models.User{Id: 11}, nil)
+
+	_, err := service.Accept(testOrgId, 11, 3)
+
+	assert.Equal(t, ErrEmailNotVerified, err)
+}
+
+func TestDeclineTransfer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, deps := newTestTransferService(ctrl)
+
+	deps.repo.EXPECT().GetTransfer(testOrgId, int64(3)).Return(createTestTransfer(), nil)
+	deps.repo.EXPECT().SetTransferStatus(testOrgId, int64(3), TransferDeclined, gomock.Any()).Return(true, nil)
+	deps.audit.EXPECT().CreateAuditEvent(gomock.Any()).DoAndReturn(func(e *models.AuditEvent) error {
+		assert.Equal(t, AuditTransferDeclined, e.Action)
+		return nil
+	})
+
+	err := service.Decline(testOrgId, 11, 3)
+
+	require.NoError(t, err)
+}
+
+func TestCancelTransfer_NoPendingTransfer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, deps := newTestTransferService(ctrl)
+
+	deps.repo.EXPECT().GetRegistration(testOrgId, int64(100)).Return(createTestApprovedRegistration(), nil)
+	deps.repo.EXPECT().GetPendingTransfer(testOrgId, int64(100)).Return(models.RegistrationTransfer{}, errors.New("no rows"))
+
+	err := service.CancelTransfer(testOrgId, 10, 100)
+
+	assert.Equal(t, ErrTransferNotFound, err)
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"errors"
+	"event-booking/models"
+	"event-booking/utils"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type TwoFactorRepository interface {
+	GetUserById(int64) (models.User, error)
+	GetTwoFactor(int64) (models.TwoFactor, error)
+	SaveTwoFactorSecret(int64, string) error
+	EnableTwoFactor(int64, int64, []string) error
+	DisableTwoFactor(int64) error
+	MarkTOTPStepUsed(int64, int64) (bool, error)
+	UseRecoveryCode(int64, string, time.Time) (bool, error)
+}
+
+type TwoFactorService struct {
+	repo                 TwoFactorRepository
+	issuer               string
+	requireForOrganizers bool
+	now                  func() time.Time
+}
+
+const (
+	mfaChallengeTTL   = 5 * time.Minute
+	recoveryCodeCount = 10
+)
+
+var ErrTwoFactorAlreadyEnabled = errors.New("Two-factor authentication is already enabled")
+var ErrTwoFactorNotSetUp = errors.New("Two-factor authentication has not been set up")
+var ErrInvalidTwoFactorCode = errors.New("Two-factor authentication code is invalid")
+var ErrInvalidMFAChallenge = errors.New("Login challenge is invalid or has expired")
+var ErrTwoFactorRequiredForOrganizers = errors.New("Organizers must enable two-factor authentication")
+
+var totpCodePattern = regexp.MustCompile(`^[0-9]{6}$`)
+
+// NewTwoFactorService creates the service. When requireForOrganizers is set,
+// RequireForOrganizer rejects users who have not enabled two-factor
+// authentication.
+func NewTwoFactorService(repo TwoFactorRepository, issuer string, requireForOrganizers bool) *TwoFactorService {
+	return &TwoFactorService{
+		repo:                 repo,
+		issuer:               issuer,
+		requireForOrganizers: requireForOrganizers,
+		now:                  time.Now,
+	}
+}
+
+// Setup starts enrollment by generating a new secret. It returns the secret
+// and the otpauth:// URI to render as a QR code.
+func (s *TwoFactorService) Setup(userId int64) (string, string, error) {
+	u, err := s.repo.GetUserById(userId)
+	if err != nil {
+		return "", "", ErrUserNotFound
+	}
+	if u.TwoFactorEnabled {
+		return "", "", ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return "", "", err
+	}
+
+	err = s.repo.SaveTwoFactorSecret(userId, secret)
+	if err != nil {
+		return "", "", err
+	}
+
+	return secret, utils.TOTPProvisioningURI(s.issuer, u.Email, secret), nil
+}
+
+// Enable confirms enrollment with a code from the authenticator app and
+// returns freshly generated recovery codes. The codes are only stored hashed,
+// so this is the only time they can be shown.
+func (s *TwoFactorService) Enable(userId int64, code string) ([]string, error) {
+	twoFactor, err := s.repo.GetTwoFactor(userId)
+	if err != nil {
+		return nil, ErrTwoFactorNotSetUp
+	}
+	if twoFactor.Enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	step, ok := utils.ValidateTOTP(twoFactor.Secret, code, s.now())
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		codes[i], err = generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		hashes[i] = utils.HashToken(codes[i])
+	}
+
+	err = s.repo.EnableTwoFactor(userId, step, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Disable turns two-factor authentication off after checking the password
+// and a current code or recovery code.
+func (s *TwoFactorService) Disable(userId int64, password, code string) error {
+	u, err := s.repo.GetUserById(userId)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	if !utils.CheckPasswordHash(password, u.Password) {
+		return ErrIncorrectPassword
+	}
+
+	err = s.verifyCode(userId, code)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.DisableTwoFactor(userId)
+}
+
+// IssueChallenge returns the token a client exchanges, together with a code,
+// for a session token after passing the password step of login.
+func (s *TwoFactorService) IssueChallenge(userId int64) (string, error) {
+	return utils.GenerateMFAChallengeToken(userId, s.now().Add(mfaChallengeTTL))
+}
+
+// CompleteLogin finishes a two-step login and returns a session token and
+// the user who logged in.
+func (s *TwoFactorService) CompleteLogin(challenge, code string) (string, models.User, error) {
+	userId, err := utils.VerifyMFAChallengeToken(challenge)
+	if err != nil {
+		return "", models.User{}, ErrInvalidMFAChallenge
+	}
+
+	err = s.verifyCode(userId, code)
+	if err != nil {
+		return "", models.User{}, err
+	}
+
+	u, err := s.repo.GetUserById(userId)
+	if err != nil {
+		return "", models.User{}, ErrUserNotFound
+	}
+
+	token, err := utils.GenerateToken(u.Email, u.Id, u.TokenVersion)
+	return token, u, err
+}
+
+// RequireForOrganizer enforces the organizer policy for userId.
+func (s *TwoFactorService) RequireForOrganizer(userId int64) error {
+	if !s.requireForOrganizers {
+		return nil
+	}
+
+	twoFactor, err := s.repo.GetTwoFactor(userId)
+	if err != nil || !twoFactor.Enabled {
+		return ErrTwoFactorRequiredForOrganizers
+	}
+	return nil
+}
+
+// verifyCode accepts either a TOTP code, which may only be used once, or an
+// unused recovery code.
+func (s *TwoFactorService) verifyCode(userId int64, code string) error {
+	twoFactor, err := s.repo.GetTwoFactor(userId)
+	if err != nil || !twoFactor.Enabled {
+		return ErrTwoFactorNotSetUp
+	}
+
+	code = strings.TrimSpace(code)
+	if totpCodePattern.MatchString(code) {
+		step, ok := utils.ValidateTOTP(twoFactor.Secret, code, s.now())
+		if !ok {
+			return ErrInvalidTwoFactorCode
+		}
+
+		accepted, err := s.repo.MarkTOTPStepUsed(userId, step)
+		if err != nil {
+			return err
+		}
+		if !accepted {
+			return ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+
+	used, err := s.repo.UseRecoveryCode(userId, utils.HashToken(strings.ToLower(code)), s.now())
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+func generateRecoveryCode() (string, error) {
+	token, err := utils.GenerateRandomToken(8)
+	if err != nil {
+		return "", err
+	}
+
+	// Recovery codes are typed by hand, so only lowercase letters and digits
+	// are used and the code is split into two groups.
+	code := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		default:
+			return 'x'
+		}
+	}, token)
+	return code[:5] + "-" + code[5:10], nil
+}
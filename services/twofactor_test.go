@@ -0,0 +1,267 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"event-booking/models"
+	"event-booking/services/mocks"
+	"event-booking/testutil"
+	"event-booking/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const testTOTPSecret = "JBSWY3DPEHPK3PXP"
+
+func newTestTwoFactorService(repo TwoFactorRepository, now time.Time, requireForOrganizers bool) *TwoFactorService {
+	service := NewTwoFactorService(repo, "Event Booking", requireForOrganizers)
+	service.now = func() time.Time { return now }
+	return service
+}
+
+func currentTestCode(t *testing.T, now time.Time) string {
+	t.Helper()
+
+	code, err := utils.TOTPCode(testTOTPSecret, utils.TOTPStep(now))
+	require.NoError(t, err)
+	return code
+}
+
+func TestTwoFactorSetup_ReturnsProvisioningURI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockTwoFactorRepository(ctrl)
+	service := newTestTwoFactorService(mockRepo, time.Now(), false)
+
+	var storedSecret string
+	mockRepo.EXPECT().GetUserById(int64(1)).Return(models.User{Id: 1, Email: "test@example.com"}, nil)
+	mockRepo.EXPECT().SaveTwoFactorSecret(int64(1), gomock.Any()).DoAndReturn(func(_ int64, secret string) error {
+		storedSecret = secret
+		return nil
+	})
+
+	secret, uri, err := service.Setup(1)
+
+	require.NoError(t, err)
+	assert.Equal(t, storedSecret, secret)
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/"))
+	assert.Contains(t, uri, "secret="+secret)
+}
+
+func TestTwoFactorSetup_AlreadyEnabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockTwoFactorRepository(ctrl)
+	service := newTestTwoFactorService(mockRepo, time.Now(), false)
+
+	mockRepo.EXPECT().GetUserById(int64(1)).Return(models.User{Id: 1, TwoFactorEnabled: true}, nil)
+
+	_, _, err := service.Setup(1)
+
+	require.Error(t, err)
+	assert.Equal(t, ErrTwoFactorAlreadyEnabled, err)
+}
+
+func TestTwoFactorEnable_ReturnsRecoveryCodes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Unix(1700000000, 0)
+	mockRepo := mocks.NewMockTwoFactorRepository(ctrl)
+	service := newTestTwoFactorService(mockRepo, now, false)
+
+	var storedHashes []string
+	mockRepo.EXPECT().GetTwoFactor(int64(1)).Return(models.TwoFactor{UserId: 1, Secret: testTOTPSecret}, nil)
+	mockRepo.EXPECT().EnableTwoFactor(int64(1), utils.TOTPStep(now), gomock.Any()).DoAndReturn(func(_, _ int64, hashes []string) error {
+		storedHashes = hashes
+		return nil
+	})
+
+	codes, err := service.Enable(1, currentTestCode(t, now))
+
+	require.NoError(t, err)
+	require.Len(t, codes, 10)
+	require.Len(t, storedHashes, 10)
+	for i, code := range codes {
+		assert.Regexp(t, `^[a-z0-9]{5}-[a-z0-9]{5}$`, code)
+		assert.Equal(t, utils.HashToken(code), storedHashes[i], "Recovery codes should be stored hashed")
+	}
+}
+
+func TestTwoFactorEnable_InvalidCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockTwoFactorRepository(ctrl)
+	service := newTestTwoFactorService(mockRepo, time.Unix(1700000000, 0), false)
+
+	mockRepo.EXPECT().GetTwoFactor(int64(1)).Return(models.TwoFactor{UserId: 1, Secret: testTOTPSecret}, nil)
+
+	_, err := service.Enable(1, "000000")
+
+	require.Error(t, err)
+	assert.Equal(t, ErrInvalidTwoFactorCode, err)
+}
+
+func TestTwoFactorEnable_NotSetUp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockTwoFactorRepository(ctrl)
+	service := newTestTwoFactorService(mockRepo, time.Now(), false)
+
+	mockRepo.EXPECT().GetTwoFactor(int64(1)).Return(models.TwoFactor{}, errors.New("sql: no rows in result set"))
+
+	_, err := service.Enable(1, "123456")
+
+	require.Error(t, err)
+	assert.Equal(t, ErrTwoFactorNotSetUp, err)
+}
+
+func TestTwoFactorCompleteLogin_WithTOTP(t *testing.T) {
+	testutil.SetupTestEnv(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	mockRepo := mocks.NewMockTwoFactorRepository(ctrl)
+	service := newTestTwoFactorService(mockRepo, now, false)
+
+	challenge, err := service.IssueChallenge(1)
+	require.NoError(t, err)
+
+	mockRepo.EXPECT().GetTwoFactor(int64(1)).Return(models.TwoFactor{UserId: 1, Secret: testTOTPSecret, Enabled: true}, nil)
+	mockRepo.EXPECT().MarkTOTPStepUsed(int64(1), utils.TOTPStep(now)).Return(true, nil)
+	mockRepo.EXPECT().GetUserById(int64(1)).Return(models.User{Id: 1, Email: "test@example.com", TokenVersion: 2}, nil)
+
+	token, user, err := service.CompleteLogin(challenge, currentTestCode(t, now))
+
+	require.NoError(t, err)
+	assert.Equal(t, "test@example.com", user.Email)
+	userId, tokenVersion, err := utils.ParseSessionToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), userId)
+	assert.Equal(t, int64(2), tokenVersion)
+}
+
+func TestTwoFactorCompleteLogin_ReplayedCode(t *testing.T) {
+	testutil.SetupTestEnv(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	mockRepo := mocks.NewMockTwoFactorRepository(ctrl)
+	service := newTestTwoFactorService(mockRepo, now, false)
+
+	challenge, err := service.IssueChallenge(1)
+	require.NoError(t, err)
+
+	mockRepo.EXPECT().GetTwoFactor(int64(1)).Return(models.TwoFactor{UserId: 1, Secret: testTOTPSecret, Enabled: true}, nil)
+	mockRepo.EXPECT().MarkTOTPStepUsed(int64(1), utils.TOTPStep(now)).Return(false, nil)
+
+	token, _, err := service.CompleteLogin(challenge, currentTestCode(t, now))
+
+	require.Error(t, err)
+	assert.Equal(t, ErrInvalidTwoFactorCode, err)
+	assert.Empty(t, token)
+}
+
+func TestTwoFactorCompleteLogin_WithRecoveryCode(t *testing.T) {
+	testutil.SetupTestEnv(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockTwoFactorRepository(ctrl)
+	service := newTestTwoFactorService(mockRepo, time.Now(), false)
+
+	challenge, err := service.IssueChallenge(1)
+	require.NoError(t, err)
+
+	mockRepo.EXPECT().GetTwoFactor(int64(1)).Return(models.TwoFactor{UserId: 1, Secret: testTOTPSecret, Enabled: true}, nil)
+	mockRepo.EXPECT().UseRecoveryCode(int64(1), utils.HashToken("abcde-12345"), gomock.Any()).Return(true, nil)
+	mockRepo.EXPECT().GetUserById(int64(1)).Return(models.User{Id: 1, Email: "test@example.com"}, nil)
+
+	token, _, err := service.CompleteLogin(challenge, " ABCDE-12345 ")
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, token)
+}
+
+func TestTwoFactorCompleteLogin_InvalidChallenge(t *testing.T) {
+	testutil.SetupTestEnv(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockTwoFactorRepository(ctrl)
+	service := newTestTwoFactorService(mockRepo, time.Now(), false)
+
+	sessionToken, err := utils.GenerateToken("test@example.com", 1, 0)
+	require.NoError(t, err)
+
+	_, _, err = service.CompleteLogin(sessionToken, "123456")
+
+	require.Error(t, err)
+	assert.Equal(t, ErrInvalidMFAChallenge, err)
+}
+
+func TestTwoFactorDisable_WrongPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockTwoFactorRepository(ctrl)
+	service := newTestTwoFactorService(mockRepo, time.Now(), false)
+
+	mockRepo.EXPECT().GetUserById(int64(1)).Return(createTestUserWithPassword(t, 1, "test@example.com", "password123"), nil)
+
+	err := service.Disable(1, "wrongpassword", "123456")
+
+	require.Error(t, err)
+	assert.Equal(t, ErrIncorrectPassword, err)
+}
+
+func TestTwoFactorDisable_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Unix(1700000000, 0)
+	mockRepo := mocks.NewMockTwoFactorRepository(ctrl)
+	service := newTestTwoFactorService(mockRepo, now, false)
+
+	mockRepo.EXPECT().GetUserById(int64(1)).Return(createTestUserWithPassword(t, 1, "test@example.com", "password123"), nil)
+	mockRepo.EXPECT().GetTwoFactor(int64(1)).Return(models.TwoFactor{UserId: 1, Secret: testTOTPSecret, Enabled: true}, nil)
+	mockRepo.EXPECT().MarkTOTPStepUsed(int64(1), utils.TOTPStep(now)).Return(true, nil)
+	mockRepo.EXPECT().DisableTwoFactor(int64(1)).Return(nil)
+
+	err := service.Disable(1, "password123", currentTestCode(t, now))
+
+	require.NoError(t, err)
+}
+
+func TestRequireForOrganizer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockTwoFactorRepository(ctrl)
+	disabledPolicy := newTestTwoFactorService(mockRepo, time.Now(), false)
+	enabledPolicy := newTestTwoFactorService(mockRepo, time.Now(), true)
+
+	mockRepo.EXPECT().GetTwoFactor(int64(1)).Return(models.TwoFactor{UserId: 1, Enabled: true}, nil)
+	mockRepo.EXPECT().GetTwoFactor(int64(2)).Return(models.TwoFactor{UserId: 2, Enabled: false}, nil)
+	mockRepo.EXPECT().GetTwoFactor(int64(3)).Return(models.TwoFactor{}, errors.New("sql: no rows in result set"))
+
+	assert.NoError(t, disabledPolicy.RequireForOrganizer(2))
+	assert.NoError(t, enabledPolicy.RequireForOrganizer(1))
+	assert.Equal(t, ErrTwoFactorRequiredForOrganizers, enabledPolicy.RequireForOrganizer(2))
+	assert.Equal(t, ErrTwoFactorRequiredForOrganizers, enabledPolicy.RequireForOrganizer(3))
+}
@@ -4,21 +4,64 @@ import (
 	"errors"
 	"event-booking/models"
 	"event-booking/utils"
+	"fmt"
+	"net/url"
+	"time"
 )
 
 type UserRepository interface {
 	CreateUser(*models.User) (int64, error)
 	ValidateCredentials(*models.User) (bool, error)
-	GetUsers() ([]models.User, error)
+	GetUsers(int64) ([]models.OrganizationMember, error)
+	GetUserById(int64) (models.User, error)
+	GetUserByEmail(string) (models.User, error)
+	CreateEmailVerification(int64, string, string, time.Time) error
+	VerifyEmail(int64, string, time.Time) (bool, error)
+	HasEmailVerification(int64, string, time.Time) (bool, error)
+	CreatePasswordReset(int64, string, time.Time, time.Time) error
+	CountPasswordResetsSince(int64, time.Time) (int, error)
+	HasPasswordReset(string, time.Time) (bool, error)
+	ResetPassword(string, string, time.Time) (bool, error)
+	UpdatePassword(int64, string) error
+	HasPaidRegistrations(int64) (bool, error)
+	DeleteUser(int64, time.Time) (bool, error)
+	GetProfile(int64) (models.Profile, error)
+	IsEventOrganizer(int64) (bool, error)
+	SaveProfile(*models.Profile) error
 }
 
 type UserService struct {
-	repo UserRepository
+	repo   UserRepository
+	mailer Mailer
+	appURL string
+	now    func() time.Time
 }
 
-func NewUserService(repo UserRepository) *UserService {
+const emailVerificationTTL = 24 * time.Hour
+
+const (
+	passwordResetTTL    = time.Hour
+	passwordResetLimit  = 3
+	passwordResetWindow = time.Hour
+)
+
+var ErrEmailNotVerified = errors.New("Email address has not been verified")
+var ErrInvalidVerificationToken = errors.New("Verification token is invalid or has expired")
+var ErrVerificationEmailNotSent = errors.New("Verification email could not be sent")
+var ErrInvalidResetToken = errors.New("Password reset token is invalid or has expired")
+var ErrSessionRevoked = errors.New("Session is no longer valid")
+var ErrIncorrectPassword = errors.New("Current password is incorrect")
+var ErrEmailTaken = errors.New("Email address is already in use")
+var ErrUserNotFound = errors.New("User could not be retrieved")
+var ErrTwoFactorRequired = errors.New("Two-factor authentication code required")
+var ErrAccountHasPaidRegistrations = errors.New("Paid registrations have to be cancelled and refunded before the account can be deleted")
+
+func NewUserService(repo UserRepository, mailer Mailer, appURL string) *UserService {
 	return &UserService{
-		repo: repo,
+		repo:   repo,
+		mailer: mailer,
+		appURL: appURL,
+		now:    time.Now,
 	}
 }
 
@@ -29,6 +72,12 @@ func (s *UserService) CreateUser(u *models.User) error {
 	}
 
 	u.Id = id
+	u.Verified = false
+
+	err = s.sendVerificationEmail(u.Id, u.Email)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrVerificationEmailNotSent, err)
+	}
 	return nil
 }
 
@@ -40,8 +89,14 @@ func (s *UserService) Login(u *models.User) (string, error) {
 	if !isValid {
 		return "", errors.New("Invalid Credentials")
 	}
+	if !u.Verified {
+		return "", ErrEmailNotVerified
+	}
+	if u.TwoFactorEnabled {
+		return "", ErrTwoFactorRequired
+	}
 
-	token, err := utils.GenerateToken(u.Email, u.Id)
+	token, err := utils.GenerateToken(u.Email, u.Id, u.TokenVersion)
 	if err != nil {
 		return "", err
 	}
@@ -49,7 +104,265 @@ func (s *UserService) Login(u *models.User) (string, error) {
 	return token, nil
 }
 
-func (s *UserService) GetUsers() ([]models.User, error) {
-	users, err := s.repo.GetUsers()
+// GetUsers lists the members of organization orgId.
+func (s *UserService) GetUsers(orgId int64) ([]models.OrganizationMember, error) {
+	users, err := s.repo.GetUsers(orgId)
 	return users, err
 }
+
+// VerifyEmail makes the address of the verification token the user's email.
+// When another account took the address in the meantime, ErrEmailTaken is
+// returned.
+func (s *UserService) VerifyEmail(token string) error {
+	userId, tokenId, err := utils.VerifyEmailVerificationToken(token)
+	if err != nil {
+		return ErrInvalidVerificationToken
+	}
+
+	now := s.now()
+	verified, err := s.repo.VerifyEmail(userId, tokenId, now)
+	if err != nil {
+		return err
+	}
+	if !verified {
+		pending, err := s.repo.HasEmailVerification(userId, tokenId, now)
+		if err != nil {
+			return err
+		}
+		if pending {
+			return ErrEmailTaken
+		}
+		return ErrInvalidVerificationToken
+	}
+	return nil
+}
+
+// ResendVerification sends a new verification link. Unknown and already
+// verified addresses are ignored so callers cannot probe for accounts.
+func (s *UserService) ResendVerification(email string) error {
+	u, err := s.repo.GetUserByEmail(email)
+	if err != nil || u.Verified {
+		return nil
+	}
+
+	err = s.sendVerificationEmail(u.Id, u.Email)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrVerificationEmailNotSent, err)
+	}
+	return nil
+}
+
+func (s *UserService) sendVerificationEmail(userId int64, email string) error {
+	tokenId, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		return err
+	}
+
+	expiresAt := s.now().Add(emailVerificationTTL)
+	err = s.repo.CreateEmailVerification(userId, email, tokenId, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	token, err := utils.GenerateEmailVerificationToken(userId, tokenId, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	link := s.appURL + "/verify-email?token=" + url.QueryEscape(token)
+	body := fmt.Sprintf("Please confirm your email address by opening the link below.\n\n%s\n\nThe link expires in 24 hours.", link)
+	return s.mailer.Send(email, "Verify your email address", body)
+}
+
+// RequestPasswordReset mails a single-use reset token to the account owner.
+// Unknown addresses and addresses that exceeded the hourly limit are ignored
+// so callers cannot probe for accounts.
+func (s *UserService) RequestPasswordReset(email string) error {
+	u, err := s.repo.GetUserByEmail(email)
+	if err != nil {
+		return nil
+	}
+
+	now := s.now()
+	count, err := s.repo.CountPasswordResetsSince(u.Id, now.Add(-passwordResetWindow))
+	if err != nil {
+		return err
+	}
+	if count >= passwordResetLimit {
+		return nil
+	}
+
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.CreatePasswordReset(u.Id, utils.HashToken(token), now.Add(passwordResetTTL), now)
+	if err != nil {
+		return err
+	}
+
+	link := s.appURL + "/password/reset?token=" + url.QueryEscape(token)
+	body := fmt.Sprintf("We received a request to reset your password. Use the token below or open the link to choose a new password.\n\n%s\n\n%s\n\nThe token expires in 1 hour. If you did not request a reset you can ignore this email.", token, link)
+	return s.mailer.Send(u.Email, "Reset your password", body)
+}
+
+// CheckResetToken checks that a reset token can still be used, so the reset
+// link can be opened before choosing the new password.
+func (s *UserService) CheckResetToken(token string) error {
+	ok, err := s.repo.HasPasswordReset(utils.HashToken(token), s.now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidResetToken
+	}
+	return nil
+}
+
+// ResetPassword sets a new password using a reset token and revokes all
+// existing sessions and API keys of the user.
+func (s *UserService) ResetPassword(token, newPassword string) error {
+	ok, err := s.repo.ResetPassword(utils.HashToken(token), newPassword, s.now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidResetToken
+	}
+	return nil
+}
+
+// ValidateSession checks that a session token has not been revoked since it
+// was issued.
+func (s *UserService) ValidateSession(userId, tokenVersion int64) error {
+	u, err := s.repo.GetUserById(userId)
+	if err != nil {
+		return ErrSessionRevoked
+	}
+	if u.TokenVersion != tokenVersion {
+		return ErrSessionRevoked
+	}
+	return nil
+}
+
+// ChangePassword replaces the password of userId after checking the current
+// one. All existing sessions and API keys are revoked and a fresh token is
+// returned.
+func (s *UserService) ChangePassword(userId int64, currentPassword, newPassword string) (string, error) {
+	u, err := s.repo.GetUserById(userId)
+	if err != nil {
+		return "", ErrUserNotFound
+	}
+
+	if !utils.CheckPasswordHash(currentPassword, u.Password) {
+		return "", ErrIncorrectPassword
+	}
+
+	err = s.repo.UpdatePassword(userId, newPassword)
+	if err != nil {
+		return "", err
+	}
+
+	return utils.GenerateToken(u.Email, u.Id, u.TokenVersion+1)
+}
+
+// ChangeEmail sends a verification link to newEmail. The address on the
+// account only changes once that link has been opened.
+func (s *UserService) ChangeEmail(userId int64, currentPassword, newEmail string) error {
+	u, err := s.repo.GetUserById(userId)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	if !utils.CheckPasswordHash(currentPassword, u.Password) {
+		return ErrIncorrectPassword
+	}
+
+	_, err = s.repo.GetUserByEmail(newEmail)
+	if err == nil {
+		return ErrEmailTaken
+	}
+
+	err = s.sendVerificationEmail(userId, newEmail)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrVerificationEmailNotSent, err)
+	}
+	return nil
+}
+
+// DeleteAccount anonymises the user after checking their password. Their
+// registrations and those for the events they own are cancelled, and the
+// events are removed. The last owner of an organization has to hand it over
+// first, and paid registrations have to be cancelled first, so that they are
+// refunded.
+func (s *UserService) DeleteAccount(userId int64, currentPassword string) error {
+	u, err := s.repo.GetUserById(userId)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	if !utils.CheckPasswordHash(currentPassword, u.Password) {
+		return ErrIncorrectPassword
+	}
+
+	deleted, err := s.repo.DeleteUser(userId, s.now())
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		paid, err := s.repo.HasPaidRegistrations(userId)
+		if err != nil {
+			return err
+		}
+		if paid {
+			return ErrAccountHasPaidRegistrations
+		}
+		return ErrLastOrganizationOwner
+	}
+	return nil
+}
+
+func (s *UserService) GetAccount(userId int64) (models.Account, error) {
+	u, err := s.repo.GetUserById(userId)
+	if err != nil {
+		return models.Account{}, ErrUserNotFound
+	}
+
+	profile, err := s.repo.GetProfile(userId)
+	if err != nil {
+		return models.Account{}, ErrUserNotFound
+	}
+
+	return models.Account{
+		Id:               u.Id,
+		Email:            u.Email,
+		Verified:         u.Verified,
+		TwoFactorEnabled: u.TwoFactorEnabled,
+		Profile:          profile,
+	}, nil
+}
+
+// GetProfile returns the public profile of a user, which never includes their
+// email address. Only organizers have a public profile, so attendees cannot
+// be looked up.
+func (s *UserService) GetProfile(userId int64) (models.Profile, error) {
+	organizer, err := s.repo.IsEventOrganizer(userId)
+	if err != nil {
+		return models.Profile{}, err
+	}
+	if !organizer {
+		return models.Profile{}, ErrUserNotFound
+	}
+
+	profile, err := s.repo.GetProfile(userId)
+	if err != nil {
+		return models.Profile{}, ErrUserNotFound
+	}
+	return profile, nil
+}
+
+func (s *UserService) UpdateProfile(userId int64, profile *models.Profile) error {
+	profile.UserId = userId
+	return s.repo.SaveProfile(profile)
+}
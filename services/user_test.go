@@ -2,17 +2,24 @@ package services
 
 import (
 	"errors"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"event-booking/models"
 	"event-booking/services/mocks"
 	"event-booking/testutil"
+	"event-booking/utils"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
 )
 
+const testAppURL = "http://localhost:8000"
+
 func createTestUser(id int64, email, password string) models.User {
 	return models.User{
 		Id:       id,
@@ -26,17 +33,21 @@ func TestCreateUser_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockUserRepository(ctrl)
-	service := NewUserService(mockRepo)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	service := NewUserService(mockRepo, mockMailer, testAppURL)
 
 	user := createTestUser(0, "test@example.com", "password123")
 	expectedId := int64(100)
 
 	mockRepo.EXPECT().CreateUser(&user).Return(expectedId, nil)
+	mockRepo.EXPECT().CreateEmailVerification(expectedId, "test@example.com", gomock.Any(), gomock.Any()).Return(nil)
+	mockMailer.EXPECT().Send("test@example.com", gomock.Any(), gomock.Any()).Return(nil)
 
 	err := service.CreateUser(&user)
 
 	require.NoError(t, err)
 	assert.Equal(t, expectedId, user.Id)
+	assert.False(t, user.Verified)
 }
 
 func TestCreateUser_RepositoryError(t *testing.T) {
@@ -44,7 +55,7 @@ func TestCreateUser_RepositoryError(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockUserRepository(ctrl)
-	service := NewUserService(mockRepo)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
 
 	user := createTestUser(0, "test@example.com", "password123")
 	expectedError := errors.New("email already exists")
@@ -63,7 +74,7 @@ func TestCreateUser_DuplicateEmail(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockUserRepository(ctrl)
-	service := NewUserService(mockRepo)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
 
 	user := createTestUser(0, "existing@example.com", "password123")
 
@@ -82,11 +93,14 @@ func TestLogin_Success_ValidCredentials(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockUserRepository(ctrl)
-	service := NewUserService(mockRepo)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
 
 	user := createTestUser(0, "test@example.com", "password123")
 
-	mockRepo.EXPECT().ValidateCredentials(&user).Return(true, nil)
+	mockRepo.EXPECT().ValidateCredentials(&user).DoAndReturn(func(u *models.User) (bool, error) {
+		u.Verified = true
+		return true, nil
+	})
 
 	token, err := service.Login(&user)
 
@@ -102,7 +116,7 @@ func TestLogin_InvalidCredentials(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockUserRepository(ctrl)
-	service := NewUserService(mockRepo)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
 
 	user := createTestUser(0, "test@example.com", "wrongpassword")
 
@@ -122,7 +136,7 @@ func TestLogin_RepositoryError(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockUserRepository(ctrl)
-	service := NewUserService(mockRepo)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
 
 	user := createTestUser(0, "test@example.com", "password123")
 	expectedError := errors.New("database connection failed")
@@ -143,7 +157,7 @@ func TestLogin_UserNotFound(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockUserRepository(ctrl)
-	service := NewUserService(mockRepo)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
 
 	user := createTestUser(0, "nonexistent@example.com", "password123")
 
@@ -160,16 +174,16 @@ func TestGetUsers_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockUserRepository(ctrl)
-	service := NewUserService(mockRepo)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
 
-	expectedUsers := []models.User{
-		createTestUser(1, "user1@example.com", "hashed1"),
-		createTestUser(2, "user2@example.com", "hashed2"),
+	expectedUsers := []models.OrganizationMember{
+		{OrganizationId: testOrgId, UserId: 1, Email: "user1@example.com", Role: RoleOwner},
+		{OrganizationId: testOrgId, UserId: 2, Email: "user2@example.com", Role: RoleMember},
 	}
 
-	mockRepo.EXPECT().GetUsers().Return(expectedUsers, nil)
+	mockRepo.EXPECT().GetUsers(testOrgId).Return(expectedUsers, nil)
 
-	result, err := service.GetUsers()
+	result, err := service.GetUsers(testOrgId)
 
 	require.NoError(t, err)
 	assert.Equal(t, expectedUsers, result)
@@ -181,11 +195,11 @@ func TestGetUsers_EmptyList(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockUserRepository(ctrl)
-	service := NewUserService(mockRepo)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
 
-	mockRepo.EXPECT().GetUsers().Return([]models.User{}, nil)
+	mockRepo.EXPECT().GetUsers(testOrgId).Return([]models.OrganizationMember{}, nil)
 
-	result, err := service.GetUsers()
+	result, err := service.GetUsers(testOrgId)
 
 	require.NoError(t, err)
 	assert.Empty(t, result)
@@ -196,14 +210,621 @@ func TestGetUsers_RepositoryError(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockUserRepository(ctrl)
-	service := NewUserService(mockRepo)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
 
 	expectedError := errors.New("database connection failed")
-	mockRepo.EXPECT().GetUsers().Return([]models.User{}, expectedError)
+	mockRepo.EXPECT().GetUsers(testOrgId).Return([]models.OrganizationMember{}, expectedError)
 
-	result, err := service.GetUsers()
+	result, err := service.GetUsers(testOrgId)
 
 	require.Error(t, err)
 	assert.Equal(t, expectedError, err)
 	assert.Empty(t, result)
 }
+
+func TestCreateUser_VerificationEmailFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	service := NewUserService(mockRepo, mockMailer, testAppURL)
+
+	user := createTestUser(0, "test@example.com", "password123")
+
+	mockRepo.EXPECT().CreateUser(&user).Return(int64(1), nil)
+	mockRepo.EXPECT().CreateEmailVerification(int64(1), "test@example.com", gomock.Any(), gomock.Any()).Return(nil)
+	mockMailer.EXPECT().Send("test@example.com", gomock.Any(), gomock.Any()).Return(errors.New("smtp down"))
+
+	err := service.CreateUser(&user)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrVerificationEmailNotSent)
+	assert.Equal(t, int64(1), user.Id)
+}
+
+func TestLogin_UnverifiedEmail(t *testing.T) {
+	testutil.SetupTestEnv(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
+
+	user := createTestUser(0, "test@example.com", "password123")
+
+	mockRepo.EXPECT().ValidateCredentials(&user).Return(true, nil)
+
+	token, err := service.Login(&user)
+
+	require.Error(t, err)
+	assert.Equal(t, ErrEmailNotVerified, err)
+	assert.Empty(t, token)
+}
+
+// sendAndCaptureVerificationToken creates a user and returns the token that
+// was mailed to them.
+func sendAndCaptureVerificationToken(t *testing.T, mockRepo *mocks.MockUserRepository, mockMailer *mocks.MockMailer, service *UserService) (string, string) {
+	t.Helper()
+
+	var tokenId, body string
+	mockRepo.EXPECT().CreateUser(gomock.Any()).Return(int64(7), nil)
+	mockRepo.EXPECT().CreateEmailVerification(int64(7), "test@example.com", gomock.Any(), gomock.Any()).DoAndReturn(func(_ int64, _, id string, _ time.Time) error {
+		tokenId = id
+		return nil
+	})
+	mockMailer.EXPECT().Send("test@example.com", gomock.Any(), gomock.Any()).DoAndReturn(func(_, _, b string) error {
+		body = b
+		return nil
+	})
+
+	user := createTestUser(0, "test@example.com", "password123")
+	require.NoError(t, service.CreateUser(&user))
+
+	start := strings.Index(body, "token=")
+	require.NotEqual(t, -1, start, "Email should contain a verification link")
+	rest := body[start+len("token="):]
+	end := strings.IndexAny(rest, " \n")
+	if end == -1 {
+		end = len(rest)
+	}
+	token, err := url.QueryUnescape(rest[:end])
+	require.NoError(t, err)
+	assert.Contains(t, body, testAppURL+"/verify-email?token=")
+
+	return token, tokenId
+}
+
+func TestVerifyEmail_Success(t *testing.T) {
+	testutil.SetupTestEnv(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	service := NewUserService(mockRepo, mockMailer, testAppURL)
+
+	token, tokenId := sendAndCaptureVerificationToken(t, mockRepo, mockMailer, service)
+	mockRepo.EXPECT().VerifyEmail(int64(7), tokenId, gomock.Any()).Return(true, nil)
+
+	err := service.VerifyEmail(token)
+
+	require.NoError(t, err)
+}
+
+func TestVerifyEmail_AlreadyUsed(t *testing.T) {
+	testutil.SetupTestEnv(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	service := NewUserService(mockRepo, mockMailer, testAppURL)
+
+	token, tokenId := sendAndCaptureVerificationToken(t, mockRepo, mockMailer, service)
+	mockRepo.EXPECT().VerifyEmail(int64(7), tokenId, gomock.Any()).Return(false, nil)
+	mockRepo.EXPECT().HasEmailVerification(int64(7), tokenId, gomock.Any()).Return(false, nil)
+
+	err := service.VerifyEmail(token)
+
+	require.Error(t, err)
+	assert.Equal(t, ErrInvalidVerificationToken, err)
+}
+
+func TestVerifyEmail_AddressTakenMeanwhile(t *testing.T) {
+	testutil.SetupTestEnv(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	service := NewUserService(mockRepo, mockMailer, testAppURL)
+
+	token, tokenId := sendAndCaptureVerificationToken(t, mockRepo, mockMailer, service)
+	mockRepo.EXPECT().VerifyEmail(int64(7), tokenId, gomock.Any()).Return(false, nil)
+	mockRepo.EXPECT().HasEmailVerification(int64(7), tokenId, gomock.Any()).Return(true, nil)
+
+	err := service.VerifyEmail(token)
+
+	assert.Equal(t, ErrEmailTaken, err)
+}
+
+func TestVerifyEmail_InvalidToken(t *testing.T) {
+	testutil.SetupTestEnv(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
+
+	err := service.VerifyEmail("not-a-token")
+
+	require.Error(t, err)
+	assert.Equal(t, ErrInvalidVerificationToken, err)
+}
+
+func TestVerifyEmail_RejectsSessionToken(t *testing.T) {
+	testutil.SetupTestEnv(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
+
+	sessionToken, err := utils.GenerateToken("test@example.com", 7, 0)
+	require.NoError(t, err)
+
+	err = service.VerifyEmail(sessionToken)
+
+	require.Error(t, err)
+	assert.Equal(t, ErrInvalidVerificationToken, err)
+}
+
+func TestResendVerification_UnverifiedUser(t *testing.T) {
+	testutil.SetupTestEnv(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	service := NewUserService(mockRepo, mockMailer, testAppURL)
+
+	mockRepo.EXPECT().GetUserByEmail("test@example.com").Return(createTestUser(3, "test@example.com", "hashed"), nil)
+	mockRepo.EXPECT().CreateEmailVerification(int64(3), "test@example.com", gomock.Any(), gomock.Any()).Return(nil)
+	mockMailer.EXPECT().Send("test@example.com", gomock.Any(), gomock.Any()).Return(nil)
+
+	err := service.ResendVerification("test@example.com")
+
+	require.NoError(t, err)
+}
+
+func TestResendVerification_VerifiedOrUnknownUserIsIgnored(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
+
+	verified := createTestUser(3, "verified@example.com", "hashed")
+	verified.Verified = true
+	mockRepo.EXPECT().GetUserByEmail("verified@example.com").Return(verified, nil)
+	mockRepo.EXPECT().GetUserByEmail("unknown@example.com").Return(models.User{}, errors.New("sql: no rows in result set"))
+
+	require.NoError(t, service.ResendVerification("verified@example.com"))
+	require.NoError(t, service.ResendVerification("unknown@example.com"))
+}
+
+func TestRequestPasswordReset_SendsHashedToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	service := NewUserService(mockRepo, mockMailer, testAppURL)
+
+	var storedHash, body string
+	mockRepo.EXPECT().GetUserByEmail("test@example.com").Return(createTestUser(3, "test@example.com", "hashed"), nil)
+	mockRepo.EXPECT().CountPasswordResetsSince(int64(3), gomock.Any()).Return(0, nil)
+	mockRepo.EXPECT().CreatePasswordReset(int64(3), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ int64, hash string, expiresAt, createdAt time.Time) error {
+		storedHash = hash
+		assert.Equal(t, time.Hour, expiresAt.Sub(createdAt))
+		return nil
+	})
+	mockMailer.EXPECT().Send("test@example.com", gomock.Any(), gomock.Any()).DoAndReturn(func(_, _, b string) error {
+		body = b
+		return nil
+	})
+
+	err := service.RequestPasswordReset("test@example.com")
+
+	require.NoError(t, err)
+	require.NotEmpty(t, storedHash)
+	assert.NotContains(t, body, storedHash, "Only the raw token should be mailed")
+
+	token := strings.Split(body, "\n\n")[1]
+	assert.Equal(t, storedHash, utils.HashToken(token))
+}
+
+func TestRequestPasswordReset_UnknownEmailIsIgnored(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
+
+	mockRepo.EXPECT().GetUserByEmail("unknown@example.com").Return(models.User{}, errors.New("sql: no rows in result set"))
+
+	err := service.RequestPasswordReset("unknown@example.com")
+
+	require.NoError(t, err)
+}
+
+func TestRequestPasswordReset_RateLimited(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	mockRepo.EXPECT().GetUserByEmail("test@example.com").Return(createTestUser(3, "test@example.com", "hashed"), nil)
+	mockRepo.EXPECT().CountPasswordResetsSince(int64(3), now.Add(-time.Hour)).Return(3, nil)
+
+	err := service.RequestPasswordReset("test@example.com")
+
+	require.NoError(t, err)
+}
+
+func TestResetPassword_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
+
+	mockRepo.EXPECT().ResetPassword(utils.HashToken("reset-token"), "newpassword123", gomock.Any()).Return(true, nil)
+
+	err := service.ResetPassword("reset-token", "newpassword123")
+
+	require.NoError(t, err)
+}
+
+func TestResetPassword_InvalidToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
+
+	mockRepo.EXPECT().ResetPassword(utils.HashToken("bad-token"), "newpassword123", gomock.Any()).Return(false, nil)
+
+	err := service.ResetPassword("bad-token", "newpassword123")
+
+	require.Error(t, err)
+	assert.Equal(t, ErrInvalidResetToken, err)
+}
+
+func TestCheckResetToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
+
+	mockRepo.EXPECT().HasPasswordReset(utils.HashToken("reset-token"), gomock.Any()).Return(true, nil)
+	mockRepo.EXPECT().HasPasswordReset(utils.HashToken("bad-token"), gomock.Any()).Return(false, nil)
+
+	require.NoError(t, service.CheckResetToken("reset-token"))
+	assert.Equal(t, ErrInvalidResetToken, service.CheckResetToken("bad-token"))
+}
+
+func TestValidateSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
+
+	user := createTestUser(3, "test@example.com", "hashed")
+	user.TokenVersion = 2
+	mockRepo.EXPECT().GetUserById(int64(3)).Return(user, nil).Times(2)
+	mockRepo.EXPECT().GetUserById(int64(4)).Return(models.User{}, errors.New("sql: no rows in result set"))
+
+	assert.NoError(t, service.ValidateSession(3, 2))
+	assert.Equal(t, ErrSessionRevoked, service.ValidateSession(3, 1))
+	assert.Equal(t, ErrSessionRevoked, service.ValidateSession(4, 0))
+}
+
+func createTestUserWithPassword(t *testing.T, id int64, email, password string) models.User {
+	t.Helper()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	require.NoError(t, err)
+	return createTestUser(id, email, string(hash))
+}
+
+func TestChangePassword_Success(t *testing.T) {
+	testutil.SetupTestEnv(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
+
+	user := createTestUserWithPassword(t, 3, "test@example.com", "password123")
+	user.TokenVersion = 4
+	mockRepo.EXPECT().GetUserById(int64(3)).Return(user, nil)
+	mockRepo.EXPECT().UpdatePassword(int64(3), "newpassword123").Return(nil)
+
+	token, err := service.ChangePassword(3, "password123", "newpassword123")
+
+	require.NoError(t, err)
+	userId, tokenVersion, err := utils.ParseSessionToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), userId)
+	assert.Equal(t, int64(5), tokenVersion, "New token should match the bumped token version")
+}
+
+func TestChangePassword_WrongCurrentPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
+
+	mockRepo.EXPECT().GetUserById(int64(3)).Return(createTestUserWithPassword(t, 3, "test@example.com", "password123"), nil)
+
+	token, err := service.ChangePassword(3, "wrongpassword", "newpassword123")
+
+	require.Error(t, err)
+	assert.Equal(t, ErrIncorrectPassword, err)
+	assert.Empty(t, token)
+}
+
+func TestChangeEmail_SendsVerificationToNewAddress(t *testing.T) {
+	testutil.SetupTestEnv(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	service := NewUserService(mockRepo, mockMailer, testAppURL)
+
+	mockRepo.EXPECT().GetUserById(int64(3)).Return(createTestUserWithPassword(t, 3, "old@example.com", "password123"), nil)
+	mockRepo.EXPECT().GetUserByEmail("new@example.com").Return(models.User{}, errors.New("sql: no rows in result set"))
+	mockRepo.EXPECT().CreateEmailVerification(int64(3), "new@example.com", gomock.Any(), gomock.Any()).Return(nil)
+	mockMailer.EXPECT().Send("new@example.com", gomock.Any(), gomock.Any()).Return(nil)
+
+	err := service.ChangeEmail(3, "password123", "new@example.com")
+
+	require.NoError(t, err)
+}
+
+func TestChangeEmail_AddressTaken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
+
+	mockRepo.EXPECT().GetUserById(int64(3)).Return(createTestUserWithPassword(t, 3, "old@example.com", "password123"), nil)
+	mockRepo.EXPECT().GetUserByEmail("new@example.com").Return(createTestUser(4, "new@example.com", "hashed"), nil)
+
+	err := service.ChangeEmail(3, "password123", "new@example.com")
+
+	require.Error(t, err)
+	assert.Equal(t, ErrEmailTaken, err)
+}
+
+func TestChangeEmail_WrongCurrentPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
+
+	mockRepo.EXPECT().GetUserById(int64(3)).Return(createTestUserWithPassword(t, 3, "old@example.com", "password123"), nil)
+
+	err := service.ChangeEmail(3, "wrongpassword", "new@example.com")
+
+	require.Error(t, err)
+	assert.Equal(t, ErrIncorrectPassword, err)
+}
+
+func TestDeleteAccount_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
+
+	mockRepo.EXPECT().GetUserById(int64(3)).Return(createTestUserWithPassword(t, 3, "test@example.com", "password123"), nil)
+	mockRepo.EXPECT().DeleteUser(int64(3), gomock.Any()).Return(true, nil)
+
+	err := service.DeleteAccount(3, "password123")
+
+	require.NoError(t, err)
+}
+
+func TestDeleteAccount_LastOrganizationOwner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
+
+	mockRepo.EXPECT().GetUserById(int64(3)).Return(createTestUserWithPassword(t, 3, "test@example.com", "password123"), nil)
+	mockRepo.EXPECT().DeleteUser(int64(3), gomock.Any()).Return(false, nil)
+	mockRepo.EXPECT().HasPaidRegistrations(int64(3)).Return(false, nil)
+
+	err := service.DeleteAccount(3, "password123")
+
+	assert.Equal(t, ErrLastOrganizationOwner, err)
+}
+
+func TestDeleteAccount_PaidRegistrations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
+
+	mockRepo.EXPECT().GetUserById(int64(3)).Return(createTestUserWithPassword(t, 3, "test@example.com", "password123"), nil)
+	mockRepo.EXPECT().DeleteUser(int64(3), gomock.Any()).Return(false, nil)
+	mockRepo.EXPECT().HasPaidRegistrations(int64(3)).Return(true, nil)
+
+	err := service.DeleteAccount(3, "password123")
+
+	assert.Equal(t, ErrAccountHasPaidRegistrations, err)
+}
+
+func TestDeleteAccount_WrongPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
+
+	mockRepo.EXPECT().GetUserById(int64(3)).Return(createTestUserWithPassword(t, 3, "test@example.com", "password123"), nil)
+
+	err := service.DeleteAccount(3, "wrongpassword")
+
+	require.Error(t, err)
+	assert.Equal(t, ErrIncorrectPassword, err)
+}
+
+func TestDeleteAccount_UserNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
+
+	mockRepo.EXPECT().GetUserById(int64(3)).Return(models.User{}, errors.New("sql: no rows in result set"))
+
+	err := service.DeleteAccount(3, "password123")
+
+	require.Error(t, err)
+	assert.Equal(t, ErrUserNotFound, err)
+}
+
+func TestGetAccount_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
+
+	user := createTestUser(3, "test@example.com", "hashed")
+	user.Verified = true
+	profile := models.Profile{UserId: 3, DisplayName: "Test User", TimeZone: "Europe/Berlin"}
+	mockRepo.EXPECT().GetUserById(int64(3)).Return(user, nil)
+	mockRepo.EXPECT().GetProfile(int64(3)).Return(profile, nil)
+
+	account, err := service.GetAccount(3)
+
+	require.NoError(t, err)
+	assert.Equal(t, models.Account{Id: 3, Email: "test@example.com", Verified: true, Profile: profile}, account)
+}
+
+func TestGetAccount_UserNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
+
+	mockRepo.EXPECT().GetUserById(int64(3)).Return(models.User{}, errors.New("sql: no rows in result set"))
+
+	_, err := service.GetAccount(3)
+
+	require.Error(t, err)
+	assert.Equal(t, ErrUserNotFound, err)
+}
+
+func TestGetProfile_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
+
+	mockRepo.EXPECT().IsEventOrganizer(int64(9)).Return(true, nil)
+	mockRepo.EXPECT().GetProfile(int64(9)).Return(models.Profile{}, errors.New("sql: no rows in result set"))
+
+	profile, err := service.GetProfile(9)
+
+	require.Error(t, err)
+	assert.Equal(t, ErrUserNotFound, err)
+	assert.Equal(t, models.Profile{}, profile)
+}
+
+func TestGetProfile_OnlyOrganizers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
+
+	mockRepo.EXPECT().IsEventOrganizer(int64(4)).Return(false, nil)
+	mockRepo.EXPECT().IsEventOrganizer(int64(3)).Return(true, nil)
+	mockRepo.EXPECT().GetProfile(int64(3)).Return(models.Profile{UserId: 3, DisplayName: "Organizer"}, nil)
+
+	_, err := service.GetProfile(4)
+	assert.Equal(t, ErrUserNotFound, err, "Attendees have no public profile")
+
+	profile, err := service.GetProfile(3)
+	require.NoError(t, err)
+	assert.Equal(t, "Organizer", profile.DisplayName)
+}
+
+func TestUpdateProfile_UsesAuthenticatedUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
+
+	profile := models.Profile{UserId: 99, DisplayName: "Test User"}
+	mockRepo.EXPECT().SaveProfile(&models.Profile{UserId: 3, DisplayName: "Test User"}).Return(nil)
+
+	err := service.UpdateProfile(3, &profile)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), profile.UserId)
+}
+
+func TestLogin_TwoFactorRequired(t *testing.T) {
+	testutil.SetupTestEnv(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	service := NewUserService(mockRepo, mocks.NewMockMailer(ctrl), testAppURL)
+
+	user := createTestUser(0, "test@example.com", "password123")
+
+	mockRepo.EXPECT().ValidateCredentials(&user).DoAndReturn(func(u *models.User) (bool, error) {
+		u.Verified = true
+		u.TwoFactorEnabled = true
+		return true, nil
+	})
+
+	token, err := service.Login(&user)
+
+	require.Error(t, err)
+	assert.Equal(t, ErrTwoFactorRequired, err)
+	assert.Empty(t, token, "No session token should be issued before the second factor")
+}
@@ -3,45 +3,291 @@ package utils
 import (
 	"errors"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var secretKey = os.Getenv("JWT_SECRET")
+const (
+	emailVerificationPurpose = "email_verification"
+	mfaChallengePurpose      = "mfa_challenge"
+	eventInvitePurpose       = "event_invite"
+	ticketPurpose            = "ticket"
 
-func GenerateToken(email string, userId int64) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"email":  email,
-		"userId": userId,
-		"exp":    time.Now().Add(time.Hour * 2).Unix(),
-	})
+	defaultTokenIssuer   = "event-booking"
+	defaultTokenAudience = "event-booking-api"
+
+	// tokenLeeway absorbs clock skew between the issuer and the verifier.
+	tokenLeeway = 30 * time.Second
+)
+
+var (
+	ErrInvalidTokenClaims = errors.New("Invalid token claims")
+	ErrMissingBearerToken = errors.New("Missing bearer token")
+)
+
+// Claims are the claims of every token issued by the API. Purpose is empty
+// for session tokens.
+type Claims struct {
+	Email        string `json:"email,omitempty"`
+	UserId       int64  `json:"userId"`
+	TokenVersion int64  `json:"tokenVersion,omitempty"`
+	Purpose      string `json:"purpose,omitempty"`
+	// EventId and OrganizationId are only set on event invitations and
+	// tickets, RegistrationId only on tickets.
+	EventId        int64 `json:"eventId,omitempty"`
+	OrganizationId int64 `json:"orgId,omitempty"`
+	RegistrationId int64 `json:"registrationId,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// tokenIssuer and tokenAudience are read on every call so they pick up the
+// environment loaded by main.
+func tokenIssuer() string {
+	if issuer := os.Getenv("JWT_ISSUER"); issuer != "" {
+		return issuer
+	}
+	return defaultTokenIssuer
+}
+
+func tokenAudience() string {
+	if audience := os.Getenv("JWT_AUDIENCE"); audience != "" {
+		return audience
+	}
+	return defaultTokenAudience
+}
 
-	return token.SignedString([]byte(secretKey))
+// GenerateToken issues a session token. tokenVersion is compared with the
+// user's current version on every request so sessions can be revoked.
+func GenerateToken(email string, userId int64, tokenVersion int64) (string, error) {
+	return signToken(&Claims{
+		Email:            email,
+		UserId:           userId,
+		TokenVersion:     tokenVersion,
+		RegisteredClaims: registeredClaims(time.Now().Add(time.Hour * 2)),
+	})
 }
 
 func VerifyToken(token *string) (int64, error) {
-	parsedToken, err := jwt.Parse(*token, func(token *jwt.Token) (interface{}, error) {
-		if token.Method != jwt.SigningMethodHS256 {
-			return nil, errors.New("Unexpected signing method")
+	userId, _, err := ParseSessionToken(*token)
+	return userId, err
+}
+
+// ParseSessionToken returns the user id and token version of a session token.
+func ParseSessionToken(token string) (int64, int64, error) {
+	claims, err := parseToken(token)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Purpose-bound tokens such as email verification links must not be
+	// accepted as session tokens.
+	if claims.Purpose != "" {
+		return 0, 0, ErrInvalidTokenClaims
+	}
+
+	return claims.UserId, claims.TokenVersion, nil
+}
+
+// ParseBearerToken extracts the token from an Authorization header. A bare
+// token without a scheme is accepted for older clients.
+func ParseBearerToken(header string) (string, error) {
+	header = strings.TrimSpace(header)
+	scheme, token, found := strings.Cut(header, " ")
+	if !found {
+		if strings.EqualFold(header, "Bearer") {
+			return "", ErrMissingBearerToken
 		}
-		return []byte(secretKey), nil
-	})
+		token = header
+	} else if !strings.EqualFold(scheme, "Bearer") {
+		return "", ErrMissingBearerToken
+	}
+
+	token = strings.TrimSpace(token)
+	if token == "" || strings.ContainsAny(token, " \t") {
+		return "", ErrMissingBearerToken
+	}
+	return token, nil
+}
 
+// GenerateEmailVerificationToken signs a token proving ownership of the email
+// address of userId. tokenId identifies the token so it can only be used once.
+func GenerateEmailVerificationToken(userId int64, tokenId string, expiresAt time.Time) (string, error) {
+	claims := purposeClaims(emailVerificationPurpose, userId, expiresAt)
+	claims.ID = tokenId
+	return signToken(claims)
+}
+
+func VerifyEmailVerificationToken(token string) (int64, string, error) {
+	claims, err := verifyPurposeToken(emailVerificationPurpose, token)
+	if err != nil {
+		return 0, "", err
+	}
+
+	if claims.ID == "" {
+		return 0, "", ErrInvalidTokenClaims
+	}
+
+	return claims.UserId, claims.ID, nil
+}
+
+// GenerateMFAChallengeToken signs a short-lived token proving that userId has
+// passed the password step of a two-step login.
+func GenerateMFAChallengeToken(userId int64, expiresAt time.Time) (string, error) {
+	return signToken(purposeClaims(mfaChallengePurpose, userId, expiresAt))
+}
+
+func VerifyMFAChallengeToken(token string) (int64, error) {
+	claims, err := verifyPurposeToken(mfaChallengePurpose, token)
 	if err != nil {
 		return 0, err
 	}
+	return claims.UserId, nil
+}
+
+// EventInvite is an invitation to an event of an organization. Email is empty
+// for invite links that anyone in the organization may use.
+type EventInvite struct {
+	InviterId      int64
+	OrganizationId int64
+	EventId        int64
+	Email          string
+}
+
+// GenerateEventInviteToken signs an invitation issued by invite.InviterId.
+func GenerateEventInviteToken(invite EventInvite, expiresAt time.Time) (string, error) {
+	claims := purposeClaims(eventInvitePurpose, invite.InviterId, expiresAt)
+	claims.OrganizationId = invite.OrganizationId
+	claims.EventId = invite.EventId
+	claims.Email = invite.Email
+	return signToken(claims)
+}
+
+func VerifyEventInviteToken(token string) (EventInvite, error) {
+	claims, err := verifyPurposeToken(eventInvitePurpose, token)
+	if err != nil {
+		return EventInvite{}, err
+	}
+
+	if claims.EventId <= 0 || claims.OrganizationId <= 0 {
+		return EventInvite{}, ErrInvalidTokenClaims
+	}
+
+	return EventInvite{
+		InviterId:      claims.UserId,
+		OrganizationId: claims.OrganizationId,
+		EventId:        claims.EventId,
+		Email:          claims.Email,
+	}, nil
+}
+
+// Ticket is the registration of UserId for an event of an organization.
+type Ticket struct {
+	RegistrationId int64
+	UserId         int64
+	OrganizationId int64
+	EventId        int64
+}
+
+// GenerateTicketToken signs a ticket, which is shown as a QR code at the
+// door.
+func GenerateTicketToken(ticket Ticket, expiresAt time.Time) (string, error) {
+	claims := purposeClaims(ticketPurpose, ticket.UserId, expiresAt)
+	claims.RegistrationId = ticket.RegistrationId
+	claims.OrganizationId = ticket.OrganizationId
+	claims.EventId = ticket.EventId
+	return signToken(claims)
+}
+
+func VerifyTicketToken(token string) (Ticket, error) {
+	claims, err := verifyPurposeToken(ticketPurpose, token)
+	if err != nil {
+		return Ticket{}, err
+	}
+
+	if claims.RegistrationId <= 0 || claims.EventId <= 0 || claims.OrganizationId <= 0 {
+		return Ticket{}, ErrInvalidTokenClaims
+	}
+
+	return Ticket{
+		RegistrationId: claims.RegistrationId,
+		UserId:         claims.UserId,
+		OrganizationId: claims.OrganizationId,
+		EventId:        claims.EventId,
+	}, nil
+}
+
+func registeredClaims(expiresAt time.Time) jwt.RegisteredClaims {
+	now := time.Now()
+	return jwt.RegisteredClaims{
+		Issuer:    tokenIssuer(),
+		Audience:  jwt.ClaimStrings{tokenAudience()},
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+		NotBefore: jwt.NewNumericDate(now),
+		IssuedAt:  jwt.NewNumericDate(now),
+	}
+}
+
+func purposeClaims(purpose string, userId int64, expiresAt time.Time) *Claims {
+	return &Claims{
+		UserId:           userId,
+		Purpose:          purpose,
+		RegisteredClaims: registeredClaims(expiresAt),
+	}
+}
+
+func signToken(claims jwt.Claims) (string, error) {
+	ring, err := currentKeyRing()
+	if err != nil {
+		return "", err
+	}
+	return ring.sign(claims)
+}
+
+func verifyPurposeToken(purpose, token string) (*Claims, error) {
+	claims, err := parseToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Purpose != purpose {
+		return nil, ErrInvalidTokenClaims
+	}
+
+	return claims, nil
+}
+
+// parseToken verifies the signature and the registered claims of token. A
+// token without a positive userId is rejected.
+func parseToken(token string) (*Claims, error) {
+	ring, err := currentKeyRing()
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &Claims{}
+	parsedToken, err := jwt.ParseWithClaims(token, claims, ring.keyFunc,
+		jwt.WithValidMethods([]string{"HS256", "RS256", "EdDSA"}),
+		jwt.WithIssuer(tokenIssuer()),
+		jwt.WithAudience(tokenAudience()),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(tokenLeeway),
+	)
+
+	if err != nil {
+		return nil, err
+	}
 
 	isValidToken := parsedToken.Valid
 	if !isValidToken {
-		return 0, errors.New("Invalid Token")
+		return nil, errors.New("Invalid Token")
 	}
 
-	claims, ok := parsedToken.Claims.(jwt.MapClaims)
-	if !ok {
-		return 0, errors.New("Invalid token claims")
+	if claims.UserId <= 0 {
+		return nil, ErrInvalidTokenClaims
 	}
 
-	userId := int64(claims["userId"].(float64))
-	return userId, nil
+	return claims, nil
 }
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -17,7 +18,7 @@ func TestGenerateToken_CreatesValidToken(t *testing.T) {
 	email := "test@example.com"
 	userId := int64(123)
 
-	token, err := GenerateToken(email, userId)
+	token, err := GenerateToken(email, userId, 0)
 
 	require.NoError(t, err)
 	assert.NotEmpty(t, token)
@@ -30,7 +31,7 @@ func TestGenerateToken_ContainsCorrectClaims(t *testing.T) {
 	email := "test@example.com"
 	userId := int64(456)
 
-	token, err := GenerateToken(email, userId)
+	token, err := GenerateToken(email, userId, 0)
 	require.NoError(t, err)
 
 	extractedUserId, verifyErr := VerifyToken(&token)
@@ -44,7 +45,7 @@ func TestGenerateToken_HasExpiration(t *testing.T) {
 	email := "test@example.com"
 	userId := int64(789)
 
-	token, err := GenerateToken(email, userId)
+	token, err := GenerateToken(email, userId, 0)
 	require.NoError(t, err)
 
 	// Parse token to check expiration
@@ -67,7 +68,7 @@ func TestVerifyToken_ValidToken(t *testing.T) {
 
 	email := "test@example.com"
 	userId := int64(123)
-	token, _ := GenerateToken(email, userId)
+	token, _ := GenerateToken(email, userId, 0)
 
 	extractedUserId, err := VerifyToken(&token)
 
@@ -139,3 +140,315 @@ func TestVerifyToken_EmptyToken(t *testing.T) {
 	require.Error(t, err)
 	assert.Equal(t, int64(0), userId)
 }
+
+func TestEmailVerificationToken_RoundTrip(t *testing.T) {
+	testutil.SetupTestEnv(t)
+
+	token, err := GenerateEmailVerificationToken(42, "token-id", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	userId, tokenId, err := VerifyEmailVerificationToken(token)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), userId)
+	assert.Equal(t, "token-id", tokenId)
+}
+
+func TestEmailVerificationToken_Expired(t *testing.T) {
+	testutil.SetupTestEnv(t)
+
+	token, err := GenerateEmailVerificationToken(42, "token-id", time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+
+	_, _, err = VerifyEmailVerificationToken(token)
+
+	require.Error(t, err)
+}
+
+func TestEmailVerificationToken_RejectsSessionToken(t *testing.T) {
+	testutil.SetupTestEnv(t)
+
+	token, err := GenerateToken("test@example.com", 42, 0)
+	require.NoError(t, err)
+
+	_, _, err = VerifyEmailVerificationToken(token)
+
+	require.Error(t, err)
+}
+
+func TestVerifyToken_RejectsEmailVerificationToken(t *testing.T) {
+	testutil.SetupTestEnv(t)
+
+	token, err := GenerateEmailVerificationToken(42, "token-id", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	userId, err := VerifyToken(&token)
+
+	require.Error(t, err)
+	assert.Equal(t, int64(0), userId)
+}
+
+func TestParseSessionToken_ReturnsTokenVersion(t *testing.T) {
+	testutil.SetupTestEnv(t)
+
+	token, err := GenerateToken("test@example.com", 42, 3)
+	require.NoError(t, err)
+
+	userId, tokenVersion, err := ParseSessionToken(token)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), userId)
+	assert.Equal(t, int64(3), tokenVersion)
+}
+
+func TestMFAChallengeToken_RoundTrip(t *testing.T) {
+	testutil.SetupTestEnv(t)
+
+	token, err := GenerateMFAChallengeToken(42, time.Now().Add(5*time.Minute))
+	require.NoError(t, err)
+
+	userId, err := VerifyMFAChallengeToken(token)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), userId)
+}
+
+func TestMFAChallengeToken_NotInterchangeable(t *testing.T) {
+	testutil.SetupTestEnv(t)
+
+	challenge, err := GenerateMFAChallengeToken(42, time.Now().Add(5*time.Minute))
+	require.NoError(t, err)
+	verification, err := GenerateEmailVerificationToken(42, "token-id", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	_, err = VerifyToken(&challenge)
+	assert.Error(t, err, "Challenge tokens must not be accepted as session tokens")
+
+	_, _, err = VerifyEmailVerificationToken(challenge)
+	assert.Error(t, err)
+
+	_, err = VerifyMFAChallengeToken(verification)
+	assert.Error(t, err)
+}
+
+func TestEventInviteToken_RoundTrip(t *testing.T) {
+	testutil.SetupTestEnv(t)
+
+	invite := EventInvite{InviterId: 42, OrganizationId: 3, EventId: 7, Email: "guest@example.com"}
+	token, err := GenerateEventInviteToken(invite, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	verified, err := VerifyEventInviteToken(token)
+
+	require.NoError(t, err)
+	assert.Equal(t, invite, verified)
+
+	_, err = VerifyToken(&token)
+	assert.Error(t, err, "Invite tokens must not be accepted as session tokens")
+}
+
+func TestEventInviteToken_RequiresEvent(t *testing.T) {
+	testutil.SetupTestEnv(t)
+
+	token, err := GenerateEventInviteToken(EventInvite{InviterId: 42, OrganizationId: 3}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	_, err = VerifyEventInviteToken(token)
+	assert.ErrorIs(t, err, ErrInvalidTokenClaims)
+
+	challenge, err := GenerateMFAChallengeToken(42, time.Now().Add(5*time.Minute))
+	require.NoError(t, err)
+	_, err = VerifyEventInviteToken(challenge)
+	assert.Error(t, err)
+}
+
+func TestTicketToken_RoundTrip(t *testing.T) {
+	testutil.SetupTestEnv(t)
+
+	ticket := Ticket{RegistrationId: 100, UserId: 42, OrganizationId: 3, EventId: 7}
+	token, err := GenerateTicketToken(ticket, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	verified, err := VerifyTicketToken(token)
+
+	require.NoError(t, err)
+	assert.Equal(t, ticket, verified)
+
+	_, err = VerifyToken(&token)
+	assert.Error(t, err, "Tickets must not be accepted as session tokens")
+}
+
+func TestTicketToken_RejectsOtherTokens(t *testing.T) {
+	testutil.SetupTestEnv(t)
+
+	invite, err := GenerateEventInviteToken(EventInvite{InviterId: 42, OrganizationId: 3, EventId: 7}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	_, err = VerifyTicketToken(invite)
+	assert.Error(t, err, "Invite tokens must not be accepted as tickets")
+
+	token, err := GenerateTicketToken(Ticket{RegistrationId: 100, UserId: 42, OrganizationId: 3, EventId: 7}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	_, err = VerifyTicketToken(token[:len(token)-2] + "xx")
+	assert.Error(t, err, "Tampered tickets are rejected")
+}
+
+// signTestClaims signs arbitrary claims with the configured key so claim
+// validation can be tested independently of the signature.
+func signTestClaims(t *testing.T, claims jwt.MapClaims) string {
+	ring, err := currentKeyRing()
+	require.NoError(t, err)
+
+	token, err := ring.sign(claims)
+	require.NoError(t, err)
+	return token
+}
+
+func validTestClaims() jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"userId": 123,
+		"iss":    defaultTokenIssuer,
+		"aud":    defaultTokenAudience,
+		"iat":    now.Unix(),
+		"nbf":    now.Unix(),
+		"exp":    now.Add(time.Hour).Unix(),
+	}
+}
+
+func TestParseSessionToken_ClaimValidation(t *testing.T) {
+	testutil.SetupTestEnv(t)
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		modify func(jwt.MapClaims)
+		valid  bool
+	}{
+		{"valid", func(c jwt.MapClaims) {}, true},
+		{"missing userId", func(c jwt.MapClaims) { delete(c, "userId") }, false},
+		{"string userId", func(c jwt.MapClaims) { c["userId"] = "123" }, false},
+		{"fractional userId", func(c jwt.MapClaims) { c["userId"] = 1.5 }, false},
+		{"negative userId", func(c jwt.MapClaims) { c["userId"] = -1 }, false},
+		{"object userId", func(c jwt.MapClaims) { c["userId"] = map[string]any{"id": 1} }, false},
+		{"wrong issuer", func(c jwt.MapClaims) { c["iss"] = "someone-else" }, false},
+		{"missing issuer", func(c jwt.MapClaims) { delete(c, "iss") }, false},
+		{"wrong audience", func(c jwt.MapClaims) { c["aud"] = "another-api" }, false},
+		{"audience list", func(c jwt.MapClaims) { c["aud"] = []string{"another-api", defaultTokenAudience} }, true},
+		{"missing expiry", func(c jwt.MapClaims) { delete(c, "exp") }, false},
+		{"not yet valid", func(c jwt.MapClaims) { c["nbf"] = now.Add(time.Minute).Unix() }, false},
+		{"issued in the future", func(c jwt.MapClaims) { c["iat"] = now.Add(time.Minute).Unix() }, false},
+		{"nbf within leeway", func(c jwt.MapClaims) { c["nbf"] = now.Add(10 * time.Second).Unix() }, true},
+		{"expired within leeway", func(c jwt.MapClaims) { c["exp"] = now.Add(-10 * time.Second).Unix() }, true},
+		{"string expiry", func(c jwt.MapClaims) { c["exp"] = "tomorrow" }, false},
+		{"purpose", func(c jwt.MapClaims) { c["purpose"] = mfaChallengePurpose }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validTestClaims()
+			tt.modify(claims)
+			token := signTestClaims(t, claims)
+
+			userId, _, err := ParseSessionToken(token)
+
+			if tt.valid {
+				require.NoError(t, err)
+				assert.Equal(t, int64(123), userId)
+			} else {
+				require.Error(t, err)
+				assert.Equal(t, int64(0), userId)
+			}
+		})
+	}
+}
+
+func TestParseSessionToken_ConfiguredIssuerAndAudience(t *testing.T) {
+	testutil.SetupTestEnv(t)
+	t.Setenv("JWT_ISSUER", "https://auth.example.com")
+	t.Setenv("JWT_AUDIENCE", "events")
+
+	token, err := GenerateToken("test@example.com", 42, 0)
+	require.NoError(t, err)
+
+	userId, _, err := ParseSessionToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), userId)
+
+	t.Setenv("JWT_AUDIENCE", "payments")
+	_, _, err = ParseSessionToken(token)
+	assert.Error(t, err)
+}
+
+func TestParseBearerToken(t *testing.T) {
+	tests := []struct {
+		header string
+		token  string
+		valid  bool
+	}{
+		{"Bearer abc.def.ghi", "abc.def.ghi", true},
+		{"bearer abc.def.ghi", "abc.def.ghi", true},
+		{"  Bearer   abc.def.ghi  ", "abc.def.ghi", true},
+		{"abc.def.ghi", "abc.def.ghi", true},
+		{"", "", false},
+		{"Bearer", "", false},
+		{"Bearer ", "", false},
+		{"Basic dXNlcjpwYXNz", "", false},
+		{"Bearer abc def", "", false},
+	}
+
+	for _, tt := range tests {
+		token, err := ParseBearerToken(tt.header)
+		if tt.valid {
+			require.NoError(t, err, tt.header)
+			assert.Equal(t, tt.token, token, tt.header)
+		} else {
+			assert.Equal(t, ErrMissingBearerToken, err, tt.header)
+		}
+	}
+}
+
+func FuzzParseSessionToken(f *testing.F) {
+	f.Setenv("JWT_SECRET", "test-secret-key-for-testing-only-do-not-use-in-production")
+
+	valid, err := GenerateToken("test@example.com", 1, 0)
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	f.Add(valid)
+	f.Add("")
+	f.Add("notajwt")
+	f.Add("a.b.c")
+	f.Add("eyJhbGciOiJub25lIn0.eyJ1c2VySWQiOjF9.")
+	f.Add("eyJhbGciOiJIUzI1NiJ9.eyJ1c2VySWQiOiJ4In0.c2ln")
+	f.Add("eyJhbGciOiJIUzI1NiIsImtpZCI6e319.e30.")
+	f.Add(valid[:len(valid)-2])
+
+	f.Fuzz(func(t *testing.T, token string) {
+		userId, tokenVersion, err := ParseSessionToken(token)
+		if err != nil {
+			if userId != 0 || tokenVersion != 0 {
+				t.Fatalf("error with non-zero result: %d %d", userId, tokenVersion)
+			}
+			return
+		}
+		if userId <= 0 {
+			t.Fatalf("accepted token with user id %d", userId)
+		}
+	})
+}
+
+func FuzzParseBearerToken(f *testing.F) {
+	f.Add("Bearer abc.def.ghi")
+	f.Add("bearer x")
+	f.Add("Basic abc")
+	f.Add("")
+	f.Add("Bearer  \t ")
+
+	f.Fuzz(func(t *testing.T, header string) {
+		token, err := ParseBearerToken(header)
+		if err == nil && (token == "" || strings.ContainsAny(token, " \t")) {
+			t.Fatalf("invalid token %q accepted from %q", token, header)
+		}
+	})
+}
@@ -0,0 +1,373 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrNoSigningKey       = errors.New("No JWT signing key configured, set JWT_SECRET or JWT_PRIVATE_KEY_FILE")
+	ErrUnsupportedKeyType = errors.New("Unsupported key type, use an RSA or Ed25519 key")
+	ErrKeyCannotSign      = errors.New("The signing key must be a secret or a private key")
+)
+
+// SigningKey is a key used to sign or verify tokens. Keys loaded from a
+// public key can only verify.
+type SigningKey struct {
+	ID     string
+	Method jwt.SigningMethod
+	// RetireAt is when the key stops being accepted. Zero means never.
+	RetireAt  time.Time
+	signKey   any
+	verifyKey any
+}
+
+// NewHMACKey returns an HS256 key. Without an id, one is derived from the
+// secret so every instance sharing the secret agrees on it.
+func NewHMACKey(id string, secret []byte) *SigningKey {
+	if id == "" {
+		sum := sha256.Sum256(append([]byte("kid:"), secret...))
+		id = "hs256-" + hex.EncodeToString(sum[:8])
+	}
+	return &SigningKey{
+		ID:        id,
+		Method:    jwt.SigningMethodHS256,
+		signKey:   secret,
+		verifyKey: secret,
+	}
+}
+
+// ParsePEMKey reads an RSA or Ed25519 private or public key. RSA keys sign
+// with RS256 and Ed25519 keys with EdDSA. Without an id, the RFC 7638
+// thumbprint of the public key is used.
+func ParsePEMKey(id string, data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("No PEM data found")
+	}
+
+	var parsed any
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("Unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newAsymmetricKey(id, parsed)
+}
+
+func newAsymmetricKey(id string, parsed any) (*SigningKey, error) {
+	key := &SigningKey{ID: id}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Method, key.signKey, key.verifyKey = jwt.SigningMethodRS256, k, &k.PublicKey
+	case *rsa.PublicKey:
+		key.Method, key.verifyKey = jwt.SigningMethodRS256, k
+	case ed25519.PrivateKey:
+		key.Method, key.signKey, key.verifyKey = jwt.SigningMethodEdDSA, k, k.Public()
+	case ed25519.PublicKey:
+		key.Method, key.verifyKey = jwt.SigningMethodEdDSA, k
+	default:
+		return nil, ErrUnsupportedKeyType
+	}
+
+	if key.ID == "" {
+		key.ID = key.JWK().thumbprint()
+	}
+	return key, nil
+}
+
+// CanSign reports whether the key holds a secret or a private key.
+func (k *SigningKey) CanSign() bool {
+	return k.signKey != nil
+}
+
+// Public reports whether the key may be published in a JWKS.
+func (k *SigningKey) Public() bool {
+	_, symmetric := k.verifyKey.([]byte)
+	return !symmetric
+}
+
+func (k *SigningKey) activeAt(t time.Time) bool {
+	return k.RetireAt.IsZero() || t.Before(k.RetireAt)
+}
+
+// JWK is a public key in JSON Web Key format.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK returns the public part of the key. It must not be called for HMAC
+// keys.
+func (k *SigningKey) JWK() JWK {
+	jwk := JWK{Kid: k.ID, Use: "sig", Alg: k.Method.Alg()}
+	switch pub := k.verifyKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	}
+	return jwk
+}
+
+// thumbprint computes the RFC 7638 thumbprint, which only covers the
+// required members in lexicographic order.
+func (j JWK) thumbprint() string {
+	var members map[string]string
+	if j.Kty == "RSA" {
+		members = map[string]string{"e": j.E, "kty": j.Kty, "n": j.N}
+	} else {
+		members = map[string]string{"crv": j.Crv, "kty": j.Kty, "x": j.X}
+	}
+
+	// encoding/json sorts map keys, which gives the canonical form.
+	data, _ := json.Marshal(members)
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// KeyRing signs new tokens with one key and accepts tokens signed by any of
+// its keys that has not been retired yet. Rotating keys is done by adding
+// the new key as the signing key and keeping the old one until tokens it
+// signed have expired.
+type KeyRing struct {
+	signing *SigningKey
+	keys    map[string]*SigningKey
+	now     func() time.Time
+}
+
+func NewKeyRing(signing *SigningKey, previous ...*SigningKey) (*KeyRing, error) {
+	if signing == nil {
+		return nil, ErrNoSigningKey
+	}
+	if !signing.CanSign() {
+		return nil, ErrKeyCannotSign
+	}
+
+	ring := &KeyRing{
+		signing: signing,
+		keys:    map[string]*SigningKey{signing.ID: signing},
+		now:     time.Now,
+	}
+	for _, key := range previous {
+		if _, exists := ring.keys[key.ID]; exists {
+			return nil, fmt.Errorf("Duplicate key id %q", key.ID)
+		}
+		ring.keys[key.ID] = key
+	}
+	return ring, nil
+}
+
+func (r *KeyRing) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(r.signing.Method, claims)
+	token.Header["kid"] = r.signing.ID
+	return token.SignedString(r.signing.signKey)
+}
+
+// keyFunc picks the verification key named by the kid header. Tokens
+// issued before key ids were introduced have no kid and are checked against
+// the HMAC keys.
+func (r *KeyRing) keyFunc(token *jwt.Token) (any, error) {
+	now := r.now()
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		set := jwt.VerificationKeySet{}
+		for _, key := range r.keys {
+			if key.Method == jwt.SigningMethodHS256 && key.activeAt(now) {
+				set.Keys = append(set.Keys, key.verifyKey)
+			}
+		}
+		if token.Method != jwt.SigningMethodHS256 || len(set.Keys) == 0 {
+			return nil, errors.New("Unknown signing key")
+		}
+		return set, nil
+	}
+
+	key, ok := r.keys[kid]
+	if !ok || !key.activeAt(now) {
+		return nil, errors.New("Unknown signing key")
+	}
+	// The algorithm comes from the key, never from the token, so a public
+	// key cannot be abused as an HMAC secret.
+	if token.Method != key.Method {
+		return nil, errors.New("Unexpected signing method")
+	}
+	return key.verifyKey, nil
+}
+
+// JWKS returns the public keys that may still be used to verify tokens.
+func (r *KeyRing) JWKS() JWKSet {
+	now := r.now()
+	set := JWKSet{Keys: []JWK{}}
+	for _, key := range r.keys {
+		if key.Public() && key.activeAt(now) {
+			set.Keys = append(set.Keys, key.JWK())
+		}
+	}
+	sort.Slice(set.Keys, func(i, j int) bool {
+		return set.Keys[i].Kid < set.Keys[j].Kid
+	})
+	return set
+}
+
+var (
+	keyRingMu sync.Mutex
+	keyRing   *KeyRing
+)
+
+// SetKeyRing replaces the keys used by the token functions of this package.
+func SetKeyRing(ring *KeyRing) {
+	keyRingMu.Lock()
+	defer keyRingMu.Unlock()
+
+	keyRing = ring
+}
+
+// currentKeyRing returns the configured key ring, loading it from the
+// environment the first time when SetKeyRing was never called.
+func currentKeyRing() (*KeyRing, error) {
+	keyRingMu.Lock()
+	defer keyRingMu.Unlock()
+
+	if keyRing == nil {
+		ring, err := LoadKeyRingFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		keyRing = ring
+	}
+	return keyRing, nil
+}
+
+// PublicJWKS returns the public keys of the configured key ring.
+func PublicJWKS() (JWKSet, error) {
+	ring, err := currentKeyRing()
+	if err != nil {
+		return JWKSet{}, err
+	}
+	return ring.JWKS(), nil
+}
+
+// LoadKeyRingFromEnv builds a key ring from the environment:
+//
+//   - JWT_PRIVATE_KEY_FILE: PEM encoded RSA or Ed25519 private key used for
+//     signing. When set, JWT_SECRET is only used to verify older tokens.
+//   - JWT_KEY_ID: key id of the signing key.
+//   - JWT_SECRET: HS256 secret, used for signing when no private key is set.
+//   - JWT_PREVIOUS_KEY_FILES: comma separated PEM keys that are still
+//     accepted for verification.
+//   - JWT_PREVIOUS_SECRETS: comma separated HS256 secrets that are still
+//     accepted for verification.
+//   - JWT_PREVIOUS_KEYS_UNTIL: RFC 3339 time after which previous keys are
+//     no longer accepted.
+func LoadKeyRingFromEnv() (*KeyRing, error) {
+	var retireAt time.Time
+	if until := os.Getenv("JWT_PREVIOUS_KEYS_UNTIL"); until != "" {
+		var err error
+		retireAt, err = time.Parse(time.RFC3339, until)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid JWT_PREVIOUS_KEYS_UNTIL: %w", err)
+		}
+	}
+
+	var signing *SigningKey
+	var previous []*SigningKey
+
+	secret := os.Getenv("JWT_SECRET")
+	if path := os.Getenv("JWT_PRIVATE_KEY_FILE"); path != "" {
+		key, err := readPEMKey(os.Getenv("JWT_KEY_ID"), path)
+		if err != nil {
+			return nil, err
+		}
+		signing = key
+
+		if secret != "" {
+			legacy := NewHMACKey("", []byte(secret))
+			legacy.RetireAt = retireAt
+			previous = append(previous, legacy)
+		}
+	} else if secret != "" {
+		signing = NewHMACKey(os.Getenv("JWT_KEY_ID"), []byte(secret))
+	}
+
+	for _, path := range splitList(os.Getenv("JWT_PREVIOUS_KEY_FILES")) {
+		key, err := readPEMKey("", path)
+		if err != nil {
+			return nil, err
+		}
+		key.RetireAt = retireAt
+		previous = append(previous, key)
+	}
+	for _, s := range splitList(os.Getenv("JWT_PREVIOUS_SECRETS")) {
+		key := NewHMACKey("", []byte(s))
+		key.RetireAt = retireAt
+		previous = append(previous, key)
+	}
+
+	return NewKeyRing(signing, previous...)
+}
+
+func readPEMKey(id, path string) (*SigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := ParsePEMKey(id, data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return key, nil
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,219 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"event-booking/testutil"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func useKeyRing(t *testing.T, ring *KeyRing) {
+	SetKeyRing(ring)
+	t.Cleanup(func() { SetKeyRing(nil) })
+}
+
+func writePEM(t *testing.T, blockType string, der []byte) string {
+	path := filepath.Join(t.TempDir(), "key.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func newEd25519Key(t *testing.T, id string) *SigningKey {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	key, err := newAsymmetricKey(id, private)
+	require.NoError(t, err)
+	return key
+}
+
+func TestKeyRing_SignsWithKid(t *testing.T) {
+	key := newEd25519Key(t, "ed-1")
+	ring, err := NewKeyRing(key)
+	require.NoError(t, err)
+	useKeyRing(t, ring)
+
+	token, err := GenerateToken("test@example.com", 42, 0)
+	require.NoError(t, err)
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, "ed-1", parsed.Header["kid"])
+	assert.Equal(t, "EdDSA", parsed.Header["alg"])
+
+	userId, err := VerifyToken(&token)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), userId)
+}
+
+func TestKeyRing_RS256(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	path := writePEM(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(private))
+
+	key, err := readPEMKey("", path)
+	require.NoError(t, err)
+	assert.Equal(t, jwt.SigningMethodRS256, key.Method)
+	assert.NotEmpty(t, key.ID)
+
+	ring, err := NewKeyRing(key)
+	require.NoError(t, err)
+	useKeyRing(t, ring)
+
+	token, err := GenerateToken("test@example.com", 7, 0)
+	require.NoError(t, err)
+
+	userId, err := VerifyToken(&token)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), userId)
+}
+
+func TestKeyRing_AcceptsPreviousKeyDuringRotation(t *testing.T) {
+	oldKey := newEd25519Key(t, "old")
+	oldRing, err := NewKeyRing(oldKey)
+	require.NoError(t, err)
+	useKeyRing(t, oldRing)
+
+	token, err := GenerateToken("test@example.com", 1, 0)
+	require.NoError(t, err)
+
+	newKey := newEd25519Key(t, "new")
+	oldKey.RetireAt = time.Now().Add(time.Hour)
+	newRing, err := NewKeyRing(newKey, oldKey)
+	require.NoError(t, err)
+	useKeyRing(t, newRing)
+
+	_, err = VerifyToken(&token)
+	require.NoError(t, err)
+
+	newRing.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	_, err = VerifyToken(&token)
+	assert.Error(t, err, "retired keys must no longer verify")
+}
+
+func TestKeyRing_AcceptsLegacyTokenWithoutKid(t *testing.T) {
+	testutil.SetupTestEnv(t)
+	secret := []byte(os.Getenv("JWT_SECRET"))
+
+	ring, err := NewKeyRing(newEd25519Key(t, "ed-1"), NewHMACKey("", secret))
+	require.NoError(t, err)
+	useKeyRing(t, ring)
+
+	legacy := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"userId": 5,
+		"iss":    defaultTokenIssuer,
+		"aud":    defaultTokenAudience,
+		"exp":    time.Now().Add(time.Hour).Unix(),
+	})
+	token, err := legacy.SignedString(secret)
+	require.NoError(t, err)
+
+	userId, err := VerifyToken(&token)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), userId)
+}
+
+func TestKeyRing_RejectsAlgorithmMismatch(t *testing.T) {
+	key := newEd25519Key(t, "ed-1")
+	ring, err := NewKeyRing(key)
+	require.NoError(t, err)
+	useKeyRing(t, ring)
+
+	// An HS256 token signed with the public key must not be accepted.
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"userId": 1,
+		"exp":    time.Now().Add(time.Hour).Unix(),
+	})
+	forged.Header["kid"] = "ed-1"
+	token, err := forged.SignedString([]byte(key.verifyKey.(ed25519.PublicKey)))
+	require.NoError(t, err)
+
+	_, err = VerifyToken(&token)
+	assert.Error(t, err)
+}
+
+func TestKeyRing_RejectsUnknownKid(t *testing.T) {
+	ring, err := NewKeyRing(newEd25519Key(t, "ed-1"))
+	require.NoError(t, err)
+	useKeyRing(t, ring)
+
+	other, err := NewKeyRing(newEd25519Key(t, "ed-2"))
+	require.NoError(t, err)
+	token, err := other.sign(jwt.MapClaims{"userId": 1, "exp": time.Now().Add(time.Hour).Unix()})
+	require.NoError(t, err)
+
+	_, err = VerifyToken(&token)
+	assert.Error(t, err)
+}
+
+func TestKeyRing_JWKSOnlyPublishesPublicKeys(t *testing.T) {
+	public := newEd25519Key(t, "ed-1")
+	ring, err := NewKeyRing(NewHMACKey("", []byte("secret")), public)
+	require.NoError(t, err)
+
+	jwks := ring.JWKS()
+
+	require.Len(t, jwks.Keys, 1)
+	assert.Equal(t, "ed-1", jwks.Keys[0].Kid)
+	assert.Equal(t, "OKP", jwks.Keys[0].Kty)
+	assert.Equal(t, "Ed25519", jwks.Keys[0].Crv)
+	assert.Equal(t, "EdDSA", jwks.Keys[0].Alg)
+}
+
+func TestParsePEMKey_PublicKeyCannotSign(t *testing.T) {
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	require.NoError(t, err)
+
+	key, err := ParsePEMKey("", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	require.NoError(t, err)
+	assert.False(t, key.CanSign())
+
+	_, err = NewKeyRing(key)
+	assert.Equal(t, ErrKeyCannotSign, err)
+}
+
+func TestLoadKeyRingFromEnv_PrivateKeyDemotesSecret(t *testing.T) {
+	testutil.SetupTestEnv(t)
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	t.Setenv("JWT_PRIVATE_KEY_FILE", writePEM(t, "PRIVATE KEY", der))
+	t.Setenv("JWT_KEY_ID", "current")
+	t.Setenv("JWT_PREVIOUS_KEYS_UNTIL", "2030-01-01T00:00:00Z")
+
+	ring, err := LoadKeyRingFromEnv()
+
+	require.NoError(t, err)
+	assert.Equal(t, "current", ring.signing.ID)
+	assert.Equal(t, jwt.SigningMethodEdDSA, ring.signing.Method)
+	require.Len(t, ring.keys, 2)
+	for id, key := range ring.keys {
+		if id != "current" {
+			assert.Equal(t, jwt.SigningMethodHS256, key.Method)
+			assert.Equal(t, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), key.RetireAt)
+		}
+	}
+}
+
+func TestLoadKeyRingFromEnv_NoKeys(t *testing.T) {
+	t.Setenv("JWT_SECRET", "")
+
+	_, err := LoadKeyRingFromEnv()
+
+	assert.Equal(t, ErrNoSigningKey, err)
+}
@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateRandomToken_Length(t *testing.T) {
+	token, err := GenerateRandomToken(32)
+
+	require.NoError(t, err)
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	require.NoError(t, err)
+	assert.Len(t, decoded, 32)
+}
+
+func TestGenerateRandomToken_Unique(t *testing.T) {
+	first, err := GenerateRandomToken(16)
+	require.NoError(t, err)
+	second, err := GenerateRandomToken(16)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, first, second)
+}
+
+func TestHashToken(t *testing.T) {
+	hash := HashToken("token")
+
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, HashToken("token"))
+	assert.NotEqual(t, hash, HashToken("other"))
+}
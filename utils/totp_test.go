@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the SHA1 key used by the RFC 6238 test vectors.
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestTOTPCode_RFC6238Vectors(t *testing.T) {
+	tests := []struct {
+		unix     int64
+		expected string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tt := range tests {
+		code, err := TOTPCode(rfcSecret, TOTPStep(time.Unix(tt.unix, 0)))
+
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, code, "unix time %d", tt.unix)
+	}
+}
+
+func TestValidateTOTP_AcceptsAdjacentSteps(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	previous, _ := TOTPCode(rfcSecret, TOTPStep(now)-1)
+	next, _ := TOTPCode(rfcSecret, TOTPStep(now)+1)
+
+	step, ok := ValidateTOTP(rfcSecret, previous, now)
+	assert.True(t, ok)
+	assert.Equal(t, TOTPStep(now)-1, step)
+
+	step, ok = ValidateTOTP(rfcSecret, next, now)
+	assert.True(t, ok)
+	assert.Equal(t, TOTPStep(now)+1, step)
+}
+
+func TestValidateTOTP_RejectsOldAndMalformedCodes(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	old, _ := TOTPCode(rfcSecret, TOTPStep(now)-2)
+
+	_, ok := ValidateTOTP(rfcSecret, old, now)
+	assert.False(t, ok)
+
+	_, ok = ValidateTOTP(rfcSecret, "12345", now)
+	assert.False(t, ok)
+
+	_, ok = ValidateTOTP("not base32!", "123456", now)
+	assert.False(t, ok)
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+
+	require.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	code, err := TOTPCode(secret, TOTPStep(time.Now()))
+	require.NoError(t, err)
+	assert.Len(t, code, 6)
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	uri := TOTPProvisioningURI("Event Booking", "user@example.com", "JBSWY3DPEHPK3PXP")
+
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/Event%20Booking:user@example.com?"))
+	assert.Contains(t, uri, "secret=JBSWY3DPEHPK3PXP")
+	assert.Contains(t, uri, "issuer=Event+Booking")
+	assert.Contains(t, uri, "digits=6")
+	assert.Contains(t, uri, "period=30")
+}